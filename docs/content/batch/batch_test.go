@@ -82,3 +82,19 @@ func TestRemoveEntities(t *testing.T) {
 		fmt.Println("Removing", entity)
 	})
 }
+
+func TestTransferEntities(t *testing.T) {
+	server := ecs.NewWorld()
+	client := ecs.NewWorld()
+
+	// Create some entities in the source world.
+	mapper := ecs.NewMap2[Position, Velocity](server)
+	mapper.NewBatch(10, &Position{}, &Velocity{X: 1, Y: -1})
+
+	// Create a filter.
+	filter := ecs.NewFilter2[Position, Velocity](server)
+	// Move all matching entities to the other world. The callback can also be nil.
+	server.TransferEntities(client, filter.Batch(), func(old, new ecs.Entity) {
+		fmt.Println("Moved", old, "to", new)
+	})
+}
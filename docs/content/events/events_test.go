@@ -159,3 +159,87 @@ func TestEventZeroEntity(t *testing.T) {
 	// Emit a game over event
 	world.Event(OnGameOver).Emit(ecs.Entity{})
 }
+
+func TestHooks(t *testing.T) {
+	world := ecs.NewWorld()
+
+	ecs.RegisterHooks(world, ecs.Hooks[Position]{
+		OnAdd: func(entity ecs.Entity, pos *Position) {
+			fmt.Printf("Added %v at (%.1f, %.1f)\n", entity, pos.X, pos.Y)
+		},
+		OnRemove: func(entity ecs.Entity, pos *Position) {
+			fmt.Printf("Removing %v at (%.1f, %.1f)\n", entity, pos.X, pos.Y)
+		},
+	})
+
+	mapper := ecs.NewMap[Position](world)
+	entity := mapper.NewEntity(&Position{X: 1, Y: 2})
+	world.RemoveEntity(entity)
+}
+
+func TestObserveBatch(t *testing.T) {
+	world := ecs.NewWorld()
+
+	ecs.Observe2[Position, Velocity](ecs.OnCreateEntity).
+		DoBatch(func(entities []ecs.Entity, pos []Position, vel []Velocity) {
+			for i := range entities {
+				pos[i].X += vel[i].X
+				pos[i].Y += vel[i].Y
+			}
+		}).
+		Register(world)
+
+	// The observer is called only once for all entities.
+	mapper := ecs.NewMap2[Position, Velocity](world)
+	mapper.NewBatch(100_000, &Position{}, &Velocity{X: 1, Y: 1})
+}
+
+func TestObserveDeferred(t *testing.T) {
+	world := ecs.NewWorld()
+
+	velMap := ecs.NewMap[Velocity](world)
+
+	// Add a Velocity to every entity that gets a Position.
+	ecs.Observe(ecs.OnAddComponents).
+		For(ecs.C[Position]()).
+		Without(ecs.C[Velocity]()).
+		Deferred().
+		Do(func(e ecs.Entity) {
+			velMap.Add(e, &Velocity{})
+		}).
+		Register(world)
+
+	posMap := ecs.NewMap[Position](world)
+	entity := world.NewEntity()
+	// The entity has a Velocity after the operation.
+	posMap.Add(entity, &Position{})
+}
+
+func TestTypedEvent(t *testing.T) {
+	world := ecs.NewWorld()
+
+	// Create an event registry
+	var registry = ecs.EventRegistry{}
+
+	// Define the payload type
+	type Damage struct {
+		Amount float64
+		Source ecs.Entity
+	}
+	// Define the typed event type
+	var OnDamage = ecs.NewTypedEvent[Damage](&registry)
+
+	// Add an observer for the event type
+	ecs.ObserveEvent(OnDamage).
+		With(ecs.C[Position]()).
+		DoWith(func(e ecs.Entity, d *Damage) { /*...*/ }).
+		Register(world)
+
+	// Define the event
+	event := OnDamage.Event(world)
+
+	// Emit the event for an entity, with payload
+	target := ecs.NewMap1[Position](world).NewEntity(&Position{})
+	source := world.NewEntity()
+	event.Emit(target, &Damage{Amount: 10, Source: source})
+}
@@ -0,0 +1,38 @@
+package performance
+
+import (
+	"testing"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+type Selected struct{}
+
+func TestSparseStorage(t *testing.T) {
+	world := ecs.NewWorld()
+
+	// Register the component with sparse storage, before any other use.
+	ecs.RegisterComponent(world, ecs.ComponentOptions[Selected]{Storage: ecs.StorageSparse})
+
+	posMap := ecs.NewMap[Position](world)
+	selMap := ecs.NewMap[Selected](world)
+
+	entity := posMap.NewEntity(&Position{})
+
+	// Toggling does not move the entity between archetypes.
+	selMap.Add(entity, &Selected{})
+	selMap.Remove(entity)
+	selMap.Add(entity, &Selected{})
+
+	filter := ecs.NewFilter1[Position](world).With(ecs.C[Selected]())
+	query := filter.Query()
+	for query.Next() {
+		pos := query.Get()
+		pos.X++
+	}
+}
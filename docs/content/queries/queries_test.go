@@ -54,6 +54,24 @@ func TestQueriesTables(t *testing.T) {
 	}
 }
 
+func TestQueriesParallel(t *testing.T) {
+	// Create a filter.
+	filter := ecs.NewFilter2[Position, Velocity](world)
+	// Obtain a query.
+	query := filter.Query()
+	// Process the query's tables in chunks, using 4 worker goroutines.
+	query.ParallelTables(4, func(chunk *ecs.Chunk, entities []ecs.Entity, positions []Position, velocities []Velocity) {
+		// Iterate over the entities of the chunk.
+		for i := range positions {
+			pos, vel := &positions[i], &velocities[i]
+			pos.X += vel.X
+			pos.Y += vel.Y
+		}
+		// Mark the chunk's components as changed, for change detection.
+		chunk.MarkChanged()
+	})
+}
+
 func TestQueriesLock(t *testing.T) {
 	// Create a filter.
 	filter := ecs.NewFilter1[Altitude](world)
@@ -121,6 +139,16 @@ func TestQueriesWithout2(t *testing.T) {
 	// ...
 }
 
+func TestQueriesAnyOf(t *testing.T) {
+	// Create a filter.
+	filter := ecs.NewFilter1[Position](world).
+		AnyOf(ecs.C[Velocity](), ecs.C[Altitude]()) // Velocity or Altitude, or both.
+
+	// Obtain a query.
+	_ = filter.Query()
+	// ...
+}
+
 func TestQueriesExclusive(t *testing.T) {
 	// Create a filter.
 	filter := ecs.NewFilter1[Position](world).
@@ -132,22 +160,39 @@ func TestQueriesExclusive(t *testing.T) {
 }
 
 func TestQueriesOptional(t *testing.T) {
-	// Create a filter.
-	filter := ecs.NewFilter2[Position, Velocity](world)
-	// Create a component mapper.
-	altMap := ecs.NewMap[Altitude](world)
+	// Create a filter with an optional component.
+	filter := ecs.NewFilter3[Position, Velocity, Altitude](world).
+		Optional(ecs.C[Altitude]())
 
 	// Obtain a query.
 	query := filter.Query()
 	for query.Next() {
-		// Get the current entity.
-		entity := query.Entity()
-		// Check whether the current entity has an Altitude component.
-		if altMap.Has(entity) {
-			alt := altMap.Get(entity)
+		pos, vel, alt := query.GetOptional()
+		pos.X += vel.X
+		pos.Y += vel.Y
+		// Altitude is nil if the current entity does not have it.
+		if alt != nil {
 			alt.Z += 1.0
 		}
-		// Do other stuff...
+	}
+}
+
+func TestQueriesOptionalTables(t *testing.T) {
+	// Create a filter with an optional component.
+	filter := ecs.NewFilter2[Position, Altitude](world).
+		Optional(ecs.C[Altitude]())
+
+	// Obtain a query.
+	query := filter.Query()
+	for query.NextTable() {
+		positions, altitudes := query.GetColumns()
+		// Altitudes are nil if the current table does not have them.
+		if altitudes == nil {
+			continue
+		}
+		for i := range positions {
+			altitudes[i].Z += positions[i].X
+		}
 	}
 }
 
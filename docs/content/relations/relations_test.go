@@ -23,6 +23,10 @@ type ChildOf struct {
 	ecs.RelationMarker
 }
 
+type Likes struct {
+	ecs.MultiRelationMarker
+}
+
 func TestNewEntity(t *testing.T) {
 	// Create a component Mapper
 	mapper := ecs.NewMap2[Position, ChildOf](world)
@@ -124,3 +128,151 @@ func TestFilter2(t *testing.T) {
 	query := filter.Query(ecs.RelIdx(1, parent))
 	_ = query
 }
+
+func TestHierarchy(t *testing.T) {
+	world := ecs.NewWorld()
+	// Create a hierarchy helper for the ChildOf relation.
+	hierarchy := ecs.NewHierarchy[ChildOf](world)
+
+	// Create a small tree.
+	root := world.NewEntity()
+	child := world.NewEntity()
+	hierarchy.Reparent(child, root)
+
+	// Get the parent and the children of entities.
+	_ = hierarchy.Parent(child)
+	hierarchy.Children(root, func(child ecs.Entity) {
+		// ...
+	})
+
+	// Traverse the tree below the root.
+	hierarchy.DepthFirst(root, func(entity ecs.Entity, depth int) bool {
+		// Return false to skip the descendants of the entity.
+		return true
+	})
+
+	// Remove the root and all its descendants.
+	hierarchy.RemoveRecursive(root)
+}
+
+func TestCascade(t *testing.T) {
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap2[Position, ChildOf](world)
+	posMap := ecs.NewMap[Position](world)
+
+	root := mapper.NewEntity(&Position{}, &ChildOf{}, ecs.Rel[ChildOf](ecs.Entity{}))
+	child := mapper.NewEntity(&Position{}, &ChildOf{}, ecs.Rel[ChildOf](root))
+	mapper.NewEntity(&Position{}, &ChildOf{}, ecs.Rel[ChildOf](child))
+
+	// Create a filter that iterates parents before their children.
+	filter := ecs.NewFilter2[Position, ChildOf](world).
+		Cascade(ecs.C[ChildOf]())
+
+	query := filter.Query()
+	for query.Next() {
+		pos, _ := query.Get()
+		parent := query.GetRelation(1)
+		if !parent.IsZero() {
+			// The parent was already processed, so its position is up to date.
+			parentPos := posMap.Get(parent)
+			pos.X += parentPos.X
+			pos.Y += parentPos.Y
+		}
+	}
+}
+
+func TestTargeting(t *testing.T) {
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap2[Position, ChildOf](world)
+
+	parent := world.NewEntity()
+	mapper.NewBatch(10, &Position{}, &ChildOf{}, ecs.Rel[ChildOf](parent))
+
+	// Iterate all entities with a ChildOf relation to the parent.
+	query := world.Targeting(parent, ecs.C[ChildOf]())
+	for query.Next() {
+		_ = query.Entity()
+	}
+}
+
+func TestMultiRelation(t *testing.T) {
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap[Likes](world)
+	filter := ecs.NewFilter1[Likes](world)
+
+	alice := world.NewEntity()
+	bob := world.NewEntity()
+
+	// Create an entity that likes Alice and Bob.
+	entity := mapper.NewEntity(&Likes{}, alice, bob)
+
+	// Get all targets.
+	_ = mapper.GetRelations(entity)
+
+	// Remove and add targets.
+	mapper.RemoveTargets(entity, alice)
+	mapper.AddTargets(entity, alice)
+
+	// Query all entities that like Bob.
+	query := filter.Query(ecs.Rel[Likes](bob))
+	for query.Next() {
+		_ = query.Entity()
+	}
+}
+
+type PartnerOf struct {
+	ecs.RelationMarker
+}
+
+func TestSymmetric(t *testing.T) {
+	world := ecs.NewWorld()
+	// Mark the relation as symmetric, before it is used.
+	ecs.SetSymmetric[PartnerOf](world)
+	mapper := ecs.NewMap[PartnerOf](world)
+
+	alice := world.NewEntity()
+	// Bob becomes Alice's partner, and Alice becomes Bob's partner.
+	bob := mapper.NewEntity(&PartnerOf{}, alice)
+	_ = mapper.GetRelation(alice) // Bob
+
+	// Removing the relation from Bob also removes it from Alice.
+	mapper.Remove(bob)
+}
+
+func TestCleanupPolicy(t *testing.T) {
+	world := ecs.NewWorld()
+	// Remove children when their parent is removed.
+	ecs.SetCleanupPolicy[ChildOf](world, ecs.CleanupDelete)
+	mapper := ecs.NewMap[ChildOf](world)
+
+	parent := world.NewEntity()
+	child := mapper.NewEntity(&ChildOf{}, parent)
+	_ = mapper.NewEntity(&ChildOf{}, child)
+
+	// Removes the parent, its child and its grandchild.
+	world.RemoveEntity(parent)
+}
+
+func TestWildcard(t *testing.T) {
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap2[Position, ChildOf](world)
+
+	parent := world.NewEntity()
+	mapper.NewEntity(&Position{}, &ChildOf{}, ecs.RelIdx(1, parent))
+	mapper.NewEntity(&Position{}, &ChildOf{}, ecs.RelIdx(1, ecs.Entity{}))
+
+	filter := ecs.NewFilter2[Position, ChildOf](world)
+
+	// Query all entities that have a parent.
+	query := filter.Query(ecs.RelWildcard[ChildOf]())
+	for query.Next() {
+		// Get the parent of the current entity.
+		_ = query.GetRelation(1)
+	}
+
+	// Query all entities without a parent.
+	query = filter.Query(ecs.RelIdx(1, ecs.Entity{}))
+	for query.Next() {
+		// ...
+	}
+}
@@ -29,6 +29,18 @@ func TestUnsafeIDs(t *testing.T) {
 	_, _ = posID, velID
 }
 
+func TestUnsafeRegister(t *testing.T) {
+	world := ecs.NewWorld()
+
+	posIndex, velIndex := 0, 1
+	posID := ecs.RegisterComponent(world, ecs.ComponentOptions[Position]{Name: "Position", ID: &posIndex})
+	velID := ecs.RegisterComponent(world, ecs.ComponentOptions[Velocity]{Name: "Velocity", ID: &velIndex})
+	ecs.FreezeComponents(world)
+
+	info, _ := ecs.ComponentInfo(world, velID)
+	_, _ = posID, info.Name
+}
+
 func TestUnsafeNewEntity(t *testing.T) {
 	entity := world.Unsafe().NewEntity(posID, velID)
 	_ = entity
@@ -46,6 +58,20 @@ func TestUnsafeQuery(t *testing.T) {
 	}
 }
 
+func TestUnsafeQueryWhere(t *testing.T) {
+	// Match entities with either Position or Velocity, but not both.
+	filter := ecs.NewUnsafeFilter(world).
+		Where(ecs.Or(
+			ecs.And(ecs.Has(posID), ecs.Not(ecs.Has(velID))),
+			ecs.And(ecs.Has(velID), ecs.Not(ecs.Has(posID))),
+		))
+
+	query := filter.Query()
+	for query.Next() {
+		// ...
+	}
+}
+
 func TestUnsafeGet(t *testing.T) {
 	entity := world.Unsafe().NewEntity(posID, velID)
 
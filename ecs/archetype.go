@@ -25,12 +25,14 @@ type archetype struct {
 }
 
 type archetypeData struct {
-	components   []ID                   // components IDs of the archetype in arbitrary order
-	itemSizes    []uint32               // item size per component index
-	isRelation   []bool                 // whether columns are relations components, indexed by column index
-	freeTables   []tableID              // all inactive/free tables
-	targetTables map[entityID]*tableIDs // all tables per target for cleanup
-	node         nodeID                 // Node ID of the archetype
+	components        []ID                   // components IDs of the archetype in arbitrary order
+	itemSizes         []uint32               // item size per component index
+	isRelation        []bool                 // whether columns are relations components, indexed by column index
+	isMultiRelation   []bool                 // whether columns are multi-target relations components, indexed by column index
+	freeTables        []tableID              // all inactive/free tables
+	targetTables      map[entityID]*tableIDs // all tables per target for cleanup
+	node              nodeID                 // Node ID of the archetype
+	hasMultiRelations bool                   // whether the archetype has any multi-target relation components
 }
 
 // tableIDs helper for faster search and remove operations.
@@ -101,9 +103,17 @@ func newArchetype(
 	}
 
 	numRelations := uint8(0)
+	hasMultiRelations := false
 	isRelation := make([]bool, len(components))
+	isMultiRelation := make([]bool, len(components))
 	relationTables := make([]map[entityID]*tableIDs, len(components))
 	for i, id := range components {
+		if reg.IsMultiRelation[id.id] {
+			// Multi-target relations don't split tables, their targets are stored per entity.
+			isMultiRelation[i] = true
+			hasMultiRelations = true
+			continue
+		}
 		if reg.IsRelation[id.id] {
 			isRelation[i] = true
 			relationTables[i] = map[entityID]*tableIDs{}
@@ -123,11 +133,13 @@ func newArchetype(
 			numRelations:   numRelations,
 			relationTables: relationTables,
 		}, archetypeData{
-			node:         node,
-			components:   components,
-			targetTables: targetTables,
-			itemSizes:    sizes,
-			isRelation:   isRelation,
+			node:              node,
+			components:        components,
+			targetTables:      targetTables,
+			itemSizes:         sizes,
+			isRelation:        isRelation,
+			isMultiRelation:   isMultiRelation,
+			hasMultiRelations: hasMultiRelations,
 		}
 }
 
@@ -169,7 +181,7 @@ func (a *archetype) getTableSlowPath(storage *storage, relations []relationID) (
 	return nil, false
 }
 
-// GetTables return all tables matching the first given relation, if any.
+// GetTables return all tables matching the first given non-wildcard relation, if any.
 // Otherwise, returns all tables of the archetype.
 //
 // Relations do not need to be fully specified.
@@ -177,11 +189,17 @@ func (a *archetype) GetTables(relations []relationID) []tableID {
 	if !a.HasRelations() || len(relations) == 0 {
 		return a.tables.tables
 	}
-	index := a.componentsMap[relations[0].component.id]
-	if tables, ok := a.relationTables[index][relations[0].target.id]; ok {
-		return tables.tables
+	for _, rel := range relations {
+		if rel.target.isWildcard() {
+			continue
+		}
+		index := a.componentsMap[rel.component.id]
+		if tables, ok := a.relationTables[index][rel.target.id]; ok {
+			return tables.tables
+		}
+		return nil
 	}
-	return nil
+	return a.tables.tables
 }
 
 // GetFreeTable returns a free/unused table ID of the archetype,
@@ -315,9 +333,9 @@ func (a *archetype) Stats(storage *storage) stats.Archetype {
 	}
 
 	memPerEntity := int(entitySize)
-	intIDs := make([]uint8, len(ids))
+	intIDs := make([]stats.ComponentIndex, len(ids))
 	for j, id := range ids {
-		intIDs[j] = id.id
+		intIDs[j] = stats.ComponentIndex(id.id)
 		memPerEntity += int(a.itemSizes[j])
 	}
 
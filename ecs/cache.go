@@ -124,6 +124,15 @@ func (c *cache) removeTable(table *table) {
 	}
 }
 
+// removeComponent un-registers all filters that refer to the given component.
+func (c *cache) removeComponent(id ID) {
+	for i := len(c.filters) - 1; i >= 0; i-- {
+		if c.filters[i].filter.references(id) {
+			c.unregister(c.filters[i].filter)
+		}
+	}
+}
+
 // Reset the cache.
 // Un-registers all filters.
 func (c *cache) Reset() {
@@ -0,0 +1,161 @@
+package ecs
+
+// newCascadeEntry creates a temporary cache entry for a cascade query (see [Filter2.Cascade]).
+// It contains all tables matching the filter and relations, ordered by their depth along the given relation component.
+func (s *storage) newCascadeEntry(filter *filter, cache *cacheEntry, relations []relationID, comp ID) *cacheEntry {
+	var tables []tableID
+	if cache != nil {
+		tables = append(tables, cache.tables.tables...)
+	} else {
+		tables = s.getCacheTables(filter, relations)
+	}
+	return &cacheEntry{
+		id:        maxCacheID,
+		filter:    filter,
+		relations: relations,
+		tables:    tableIDs{tables: s.cascadeTables(tables, comp)},
+	}
+}
+
+// cascadeTables returns the given tables ordered by the depth of their entities along the given relation component,
+// using a stable counting sort.
+func (s *storage) cascadeTables(tables []tableID, comp ID) []tableID {
+	depths := make(map[tableID]int, len(tables))
+	counts := []int{}
+	for _, id := range tables {
+		depth := s.tableDepth(id, comp, depths)
+		for len(counts) <= depth {
+			counts = append(counts, 0)
+		}
+		counts[depth]++
+	}
+
+	start := 0
+	for i, cnt := range counts {
+		counts[i] = start
+		start += cnt
+	}
+
+	result := make([]tableID, len(tables))
+	for _, id := range tables {
+		depth := depths[id]
+		result[counts[depth]] = id
+		counts[depth]++
+	}
+	return result
+}
+
+// tableDepth returns the depth of the entities in a table along the given relation component.
+//
+// Tables without the relation component, or with the zero entity as target, have depth zero.
+// Otherwise, the depth is one more than the depth of the target entity.
+// Relation cycles are broken by treating the entity that closes the cycle as having depth zero.
+//
+// Depths are memoized in the given map, with -1 marking tables currently being processed.
+func (s *storage) tableDepth(id tableID, comp ID, depths map[tableID]int) int {
+	if depth, ok := depths[id]; ok {
+		if depth < 0 {
+			return 0
+		}
+		return depth
+	}
+	table := &s.tables[id]
+	column := table.components[comp.id]
+	if column == nil {
+		depths[id] = 0
+		return 0
+	}
+
+	depths[id] = -1
+	depth := 0
+	if !column.target.IsZero() {
+		depth = s.entityDepth(column.target, comp, depths) + 1
+	}
+	depths[id] = depth
+	return depth
+}
+
+// entityDepth returns the depth of an entity along the given relation component.
+func (s *storage) entityDepth(entity Entity, comp ID, depths map[tableID]int) int {
+	return s.tableDepth(s.entities[entity.id].table, comp, depths)
+}
+
+// cascadeEntities returns the entities of all tables matching the filter and relations,
+// ordered by their depth along the given multi-target relation component, using a stable counting sort.
+// As targets of multi-target relations are stored per entity, ordering is done per entity.
+//
+// Also returns a copy of the given entity conditions that marks iteration as ordered.
+func (s *storage) cascadeEntities(filter *filter, cache *cacheEntry, relations []relationID, conditions *entityConditions, comp ID) ([]Entity, *entityConditions) {
+	var tables []tableID
+	if cache != nil {
+		tables = cache.tables.tables
+	} else {
+		tables = s.getCacheTables(filter, relations)
+	}
+
+	m := s.multi[comp.id]
+	depths := map[entityID]int{}
+	counts := []int{}
+	numEntities := 0
+	for _, id := range tables {
+		table := &s.tables[id]
+		for i := range uintptr(table.len) {
+			depth := s.multiDepth(table.GetEntity(i), m, depths)
+			for len(counts) <= depth {
+				counts = append(counts, 0)
+			}
+			counts[depth]++
+		}
+		numEntities += int(table.len)
+	}
+
+	start := 0
+	for i, cnt := range counts {
+		counts[i] = start
+		start += cnt
+	}
+
+	result := make([]Entity, numEntities)
+	for _, id := range tables {
+		table := &s.tables[id]
+		for i := range uintptr(table.len) {
+			entity := table.GetEntity(i)
+			depth := depths[entity.id]
+			result[counts[depth]] = entity
+			counts[depth]++
+		}
+	}
+
+	ordered := entityConditions{}
+	if conditions != nil {
+		ordered = *conditions
+	}
+	ordered.ordered = true
+	return result, &ordered
+}
+
+// multiDepth returns the depth of an entity along a multi-target relation.
+//
+// Entities without targets have depth zero.
+// Otherwise, the depth is one more than the depth of the deepest target entity.
+// Relation cycles are broken by treating the entity that closes the cycle as having depth zero.
+//
+// Depths are memoized in the given map, with -1 marking entities currently being processed.
+func (s *storage) multiDepth(entity Entity, m *multiRelation, depths map[entityID]int) int {
+	if depth, ok := depths[entity.id]; ok {
+		if depth < 0 {
+			return 0
+		}
+		return depth
+	}
+	if m == nil {
+		return 0
+	}
+	depths[entity.id] = -1
+	depth := 0
+	for _, target := range m.Targets(entity) {
+		depth = max(depth, s.multiDepth(target, m, depths)+1)
+	}
+	depths[entity.id] = depth
+	return depth
+}
@@ -0,0 +1,105 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFilterCascade(t *testing.T) {
+	w := NewWorld(16)
+	childMap := NewMap2[Position, ChildOf](w)
+	parentMap := NewMap[ChildOf](w)
+
+	// Create a chain, with tables for deeper entities created first.
+	root := w.NewEntity()
+	chain := make([]Entity, 4)
+	for i := range chain {
+		chain[i] = childMap.NewEntity(&Position{X: float64(i)}, &ChildOf{}, Rel[ChildOf](Entity{}))
+	}
+	for i := len(chain) - 1; i > 0; i-- {
+		parentMap.SetRelation(chain[i], chain[i-1])
+	}
+	parentMap.SetRelation(chain[0], root)
+	other := NewMap[Position](w).NewEntity(&Position{X: 10})
+
+	filter := NewFilter1[Position](w).Cascade(C[ChildOf]())
+	query := filter.Query()
+	expectEqual(t, 5, query.Count())
+	expectEqual(t, other, query.EntityAt(0))
+	entities := []Entity{}
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	expectSlicesEqual(t, append([]Entity{other}, chain...), entities)
+
+	// Relations are considered.
+	filter2 := NewFilter2[Position, ChildOf](w).Cascade(C[ChildOf]())
+	query2 := filter2.Query(RelIdx(1, chain[1]))
+	expectTrue(t, query2.Next())
+	expectEqual(t, chain[2], query2.Entity())
+	expectFalse(t, query2.Next())
+
+	// Registered filters.
+	filter2 = NewFilter2[Position, ChildOf](w).Cascade(C[ChildOf]()).Register()
+	query2 = filter2.Query()
+	entities = entities[:0]
+	for query2.Next() {
+		entities = append(entities, query2.Entity())
+	}
+	expectSlicesEqual(t, chain, entities)
+
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a relation component", ComponentID[Position](w).id), func() {
+		NewFilter1[Position](w).Cascade(C[Position]())
+	})
+}
+
+func TestFilterCascadeHierarchy(t *testing.T) {
+	w := NewWorld(16)
+	h := NewHierarchy[ChildOf](w)
+	_, all := createHierarchy(w, 4)
+
+	filter := NewFilter2[Position, ChildOf](w).Cascade(C[ChildOf]())
+	query := filter.Query()
+	expectEqual(t, len(all)-1, query.Count())
+	lastDepth := 0
+	for query.Next() {
+		depth := 0
+		for p := h.Parent(query.Entity()); !p.IsZero(); p = h.Parent(p) {
+			depth++
+		}
+		expectTrue(t, depth >= lastDepth)
+		lastDepth = depth
+	}
+	expectEqual(t, 4, lastDepth)
+}
+
+func TestFilterCascadeCycle(t *testing.T) {
+	w := NewWorld(16)
+	childMap := NewMap2[Position, ChildOf](w)
+	parentMap := NewMap[ChildOf](w)
+
+	a := childMap.NewEntity(&Position{}, &ChildOf{}, Rel[ChildOf](Entity{}))
+	b := childMap.NewEntity(&Position{}, &ChildOf{}, Rel[ChildOf](a))
+	parentMap.SetRelation(a, b)
+
+	query := NewFilter1[Position](w).Cascade(C[ChildOf]()).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestFilterCascadeMultiRelation(t *testing.T) {
+	w := NewWorld(16)
+	likesMap := NewMap2[Position, Likes](w)
+
+	a := likesMap.NewEntity(&Position{}, &Likes{}, Rel[Likes](Entity{}))
+	b := likesMap.NewEntity(&Position{}, &Likes{}, Rel[Likes](a))
+	c := likesMap.NewEntity(&Position{}, &Likes{}, Rel[Likes](a), Rel[Likes](b))
+	NewMap[Likes](w).AddTargets(a, w.NewEntity())
+
+	query := NewFilter1[Position](w).Cascade(C[Likes]()).Query()
+	entities := []Entity{}
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{a, b, c}, entities)
+}
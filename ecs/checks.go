@@ -8,8 +8,14 @@ func (s *storage) checkRelationComponent(id ID) {
 	}
 }
 
+func (s *storage) checkMultiRelationComponent(id ID) {
+	if !s.registry.IsMultiRelation[id.id] {
+		panic(fmt.Sprintf("component with ID %d is not a multi-target relation component", id.id))
+	}
+}
+
 func (s *storage) checkRelationTarget(target Entity) {
-	if !target.IsZero() && !s.entityPool.Alive(target) {
+	if !target.IsZero() && !target.isWildcard() && !s.entityPool.Alive(target) {
 		panic("can't use a dead entity as relation target, except for the zero entity")
 	}
 }
@@ -0,0 +1,160 @@
+package ecs
+
+// CleanupPolicy determines what happens to entities with a relation
+// when the relation's target entity is removed from the world.
+//
+// Set it per relation component type with [SetCleanupPolicy].
+type CleanupPolicy uint8
+
+const (
+	// CleanupKeep keeps the relation component, with the zero entity as target.
+	// This is the default policy.
+	CleanupKeep CleanupPolicy = iota
+	// CleanupRemove removes the relation component from the entities.
+	CleanupRemove
+	// CleanupDelete removes the entities from the world.
+	// Removal cascades along further relations of the removed entities.
+	CleanupDelete
+)
+
+// SetCleanupPolicy sets the [CleanupPolicy] for the relation component type C.
+//
+// The policy is applied to all entities with a relation of type C to an entity that is removed from the world.
+// Observers are notified about the resulting changes, like [OnRemoveEntity] for entities removed by [CleanupDelete].
+// For multi-target relations (see [MultiRelationMarker]), the policy is only applied when an entity loses its last target.
+//
+// Panics if C is not a relation component.
+func SetCleanupPolicy[C any](w *World, policy CleanupPolicy) {
+	w.checkLocked()
+	id := ComponentID[C](w)
+	w.storage.checkRelationComponent(id)
+	w.storage.registry.Cleanup[id.id] = policy
+	if policy != CleanupKeep {
+		w.storage.registry.hasCleanup = true
+	}
+}
+
+// applyCleanupPolicies applies the cleanup policies for tables with a relation to the given removed target entity.
+// Entities are removed, or relation components are removed from entities, according to the policies.
+//
+// Relations with policy [CleanupKeep] are left to cleanupArchetypes.
+func (s *storage) applyCleanupPolicies(target Entity) {
+	var remove []Entity
+	var rem []ID
+	numArchetypes := len(s.relationArchetypes)
+	for i := range numArchetypes {
+		archetype := &s.archetypes[s.relationArchetypes[i]]
+		tables, ok := archetype.targetTables[target.id]
+		if !ok {
+			continue
+		}
+		for j := len(tables.tables) - 1; j >= 0; j-- {
+			table := &s.tables[tables.tables[j]]
+			if table.Len() == 0 {
+				continue
+			}
+			var policy CleanupPolicy
+			policy, rem = s.getCleanupPolicy(table, target, rem[:0])
+			switch policy {
+			case CleanupDelete:
+				for k := range uintptr(table.len) {
+					remove = append(remove, table.GetEntity(k))
+				}
+			case CleanupRemove:
+				s.removeTableComponents(table, target, rem)
+			}
+		}
+	}
+
+	for _, entity := range remove {
+		// Entities may have been removed already through other relations.
+		if s.entityPool.Alive(entity) {
+			s.RemoveEntity(entity)
+		}
+	}
+}
+
+// getCleanupPolicy returns the cleanup policy to apply to a table, when the given target is removed.
+// Also appends the components to remove for [CleanupRemove].
+func (s *storage) getCleanupPolicy(table *table, target Entity, rem []ID) (CleanupPolicy, []ID) {
+	result := CleanupKeep
+	for _, rel := range table.relationIDs {
+		policy := s.registry.Cleanup[rel.component.id]
+		if policy == CleanupKeep || !s.isRemovedTarget(rel.target, target) {
+			continue
+		}
+		if policy == CleanupDelete {
+			return CleanupDelete, rem
+		}
+		if !containsID(rem, rel.component) {
+			rem = append(rem, rel.component)
+		}
+		result = CleanupRemove
+	}
+	return result, rem
+}
+
+// containsID checks whether the given slice contains the given ID.
+func containsID(ids []ID, id ID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTableComponents removes the given components from all entities in a table,
+// and moves them to the respective table.
+// Relations to the removed target entity that are not removed are reset to the zero entity.
+func (s *storage) removeTableComponents(oldTable *table, target Entity, rem []ID) {
+	oldArchetype := &s.archetypes[oldTable.archetype]
+	mask := oldArchetype.mask
+	node := s.graph.FindRemove(oldArchetype.node, rem, &mask)
+	var arch *archetype
+	if archID, ok := node.GetArchetype(); ok {
+		arch = &s.archetypes[archID]
+	} else {
+		arch = s.createArchetype(node)
+	}
+
+	relations := []relationID{}
+	for _, rel := range oldTable.relationIDs {
+		if !arch.mask.Get(rel.component.id) {
+			continue
+		}
+		if s.isRemovedTarget(rel.target, target) {
+			rel.target = Entity{}
+		}
+		relations = append(relations, rel)
+	}
+	newTable, ok := arch.GetTable(s, relations)
+	if !ok {
+		newTable = s.createTable(arch, relations)
+	}
+	// Get the old table and archetype again, as pointers may have changed.
+	oldTable = &s.tables[oldTable.id]
+	oldMask := &s.archetypes[oldTable.archetype].mask
+	newMask := &arch.mask
+
+	hasCompObs := s.observers.HasObservers(OnRemoveComponents)
+	hasRelObs := s.observers.HasObservers(OnRemoveRelations)
+	if hasCompObs || hasRelObs {
+		l := s.lock()
+		if hasCompObs {
+			s.observers.FireRemoveBatch(OnRemoveComponents, oldTable, oldTable.Len(), oldMask, newMask)
+		}
+		if hasRelObs {
+			s.observers.FireRemoveBatch(OnRemoveRelations, oldTable, oldTable.Len(), oldMask, newMask)
+		}
+		s.unlock(l)
+	}
+
+	s.moveTable(oldTable.id, newTable.id)
+}
+
+// isRemovedTarget checks whether a relation target is the removed target,
+// or another entity that was removed in the same batch.
+func (s *storage) isRemovedTarget(relTarget Entity, target Entity) bool {
+	return relTarget.id == target.id || (!relTarget.IsZero() && !s.entityPool.Alive(relTarget))
+}
@@ -0,0 +1,141 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetCleanupPolicy(t *testing.T) {
+	w := NewWorld(16)
+	SetCleanupPolicy[ChildOf](w, CleanupRemove)
+	expectEqual(t, CleanupRemove, w.storage.registry.Cleanup[ComponentID[ChildOf](w).id])
+	expectTrue(t, w.storage.registry.hasCleanup)
+
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a relation component", ComponentID[Position](w).id), func() {
+		SetCleanupPolicy[Position](w, CleanupDelete)
+	})
+}
+
+func TestCleanupRemove(t *testing.T) {
+	w := NewWorld(16)
+	SetCleanupPolicy[ChildOf](w, CleanupRemove)
+	childMap := NewMap3[Position, ChildOf, ChildOf2](w)
+	child2Map := NewMap[ChildOf2](w)
+
+	removed := 0
+	Observe(OnRemoveComponents).For(C[ChildOf]()).Do(func(e Entity) {
+		removed++
+	}).Register(w)
+	removedRel := 0
+	Observe(OnRemoveRelations).For(C[ChildOf]()).Do(func(e Entity) {
+		removedRel++
+	}).Register(w)
+
+	parent := w.NewEntity()
+	other := w.NewEntity()
+	childMap.NewBatch(5, &Position{X: 1}, &ChildOf{}, &ChildOf2{}, RelIdx(1, parent), RelIdx(2, parent))
+	childMap.NewBatch(5, &Position{X: 2}, &ChildOf{}, &ChildOf2{}, RelIdx(1, parent), RelIdx(2, other))
+	unrelated := child2Map.NewEntity(&ChildOf2{}, other)
+
+	w.RemoveEntity(parent)
+	expectEqual(t, 10, removed)
+	expectEqual(t, 10, removedRel)
+	expectEqual(t, other, child2Map.GetRelation(unrelated))
+
+	query := NewFilter1[Position](w).Query()
+	expectEqual(t, 10, query.Count())
+	for query.Next() {
+		expectFalse(t, NewMap[ChildOf](w).Has(query.Entity()))
+		pos := query.Get()
+		if pos.X == 1 {
+			expectEqual(t, Entity{}, child2Map.GetRelation(query.Entity()))
+		} else {
+			expectEqual(t, other, child2Map.GetRelation(query.Entity()))
+		}
+	}
+}
+
+func TestCleanupDelete(t *testing.T) {
+	w := NewWorld(16)
+	SetCleanupPolicy[ChildOf](w, CleanupDelete)
+	root, all := createHierarchy(w, 3)
+	other := w.NewEntity()
+
+	removed := []Entity{}
+	Observe(OnRemoveEntity).Do(func(e Entity) {
+		removed = append(removed, e)
+	}).Register(w)
+
+	w.RemoveEntity(root)
+	expectEqual(t, len(all), len(removed))
+	for _, e := range all {
+		expectFalse(t, w.Alive(e))
+		expectTrue(t, containsEntity(removed, e))
+	}
+	expectTrue(t, w.Alive(other))
+
+	// Batch removal
+	root, all = createHierarchy(w, 2)
+	posMap := NewMap[Position](w)
+	posMap.Add(root, &Position{})
+	w.RemoveEntities(NewFilter1[Position](w).Without(C[ChildOf]()).Batch(), nil)
+	for _, e := range all {
+		expectFalse(t, w.Alive(e))
+	}
+	expectTrue(t, w.Alive(other))
+}
+
+func TestCleanupDeleteCycle(t *testing.T) {
+	w := NewWorld(16)
+	SetCleanupPolicy[ChildOf](w, CleanupDelete)
+	childMap := NewMap[ChildOf](w)
+
+	a := w.NewEntity()
+	b := childMap.NewEntity(&ChildOf{}, a)
+	childMap.Add(a, &ChildOf{}, b)
+
+	w.RemoveEntity(a)
+	expectFalse(t, w.Alive(b))
+}
+
+func TestCleanupMultiRelation(t *testing.T) {
+	w := NewWorld(16)
+	SetCleanupPolicy[Likes](w, CleanupRemove)
+	likesMap := NewMap[Likes](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	e := likesMap.NewEntity(&Likes{}, a, b)
+
+	w.RemoveEntity(a)
+	expectTrue(t, likesMap.Has(e))
+	expectSlicesEqual(t, []Entity{b}, likesMap.GetRelations(e))
+
+	w.RemoveEntity(b)
+	expectFalse(t, likesMap.Has(e))
+}
+
+func TestCleanupMultiRelationDelete(t *testing.T) {
+	w := NewWorld(16)
+	SetCleanupPolicy[Likes](w, CleanupDelete)
+	SetCleanupPolicy[FriendOf](w, CleanupDelete)
+	mapper := NewMap2[Likes, FriendOf](w)
+	likesMap := NewMap[Likes](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	e1 := mapper.NewEntity(&Likes{}, &FriendOf{}, Rel[Likes](a), Rel[FriendOf](a))
+	e2 := mapper.NewEntity(&Likes{}, &FriendOf{}, Rel[Likes](a), Rel[Likes](b), Rel[FriendOf](b))
+
+	added := 0
+	removed := 0
+	Observe(OnAddRelations).For(C[Likes]()).Do(func(e Entity) { added++ }).Register(w)
+	Observe(OnRemoveRelations).For(C[Likes]()).Do(func(e Entity) { removed++ }).Register(w)
+
+	w.RemoveEntity(a)
+	expectFalse(t, w.Alive(e1))
+	expectTrue(t, w.Alive(e2))
+	expectSlicesEqual(t, []Entity{b}, likesMap.GetRelations(e2))
+	expectEqual(t, 1, added)
+	expectEqual(t, 2, removed)
+}
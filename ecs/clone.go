@@ -0,0 +1,101 @@
+package ecs
+
+import "github.com/mlange-42/ark/ecs/stats"
+
+// Clone creates an independent deep copy of the world.
+//
+// The clone contains the same registered component and resource types with the same IDs,
+// the same archetypes, and all entities with their components and relation targets.
+// Entities keep their IDs and generations, so that stored entities are valid in the clone as well.
+// Entity creation in the clone is deterministic, i.e. it re-issues the same entities as the original world.
+// Change ticks of components are preserved.
+//
+// Component values are copied shallowly, i.e. pointers, slices and maps in them are shared with the original.
+// Use [ComponentOptions.Cloner] to customize how individual component types are copied.
+// Resources are copied shallowly by default.
+// Use [Resource.SetCloneFunc] to customize how individual resources are copied.
+//
+// Observers and registered filters are not cloned.
+// Mappers, filters and other helpers need to be created for the cloned world.
+func (w *World) Clone() *World {
+	s := &w.storage
+	clone := &World{
+		storage:   newStorage(len(s.archetypes), s.config.initialCapacity, s.config.initialCapacityRelations),
+		resources: w.resources.clone(),
+		stats:     &stats.World{},
+	}
+	cs := &clone.storage
+
+	// Register with the same IDs, including gaps left by unregistered components.
+	for i := range s.registry.Count() {
+		cs.AddComponent(idIndex(i))
+	}
+	for _, id := range s.registry.IDs {
+		cs.registry.registerComponentID(s.registry.Types[id], id)
+		cs.registry.IsSymmetric[id] = s.registry.IsSymmetric[id]
+		cs.registry.Cleanup[id] = s.registry.Cleanup[id]
+		cs.registry.Names[id] = s.registry.Names[id]
+		cs.registry.Storage[id] = s.registry.Storage[id]
+		cs.registry.IsTracked[id] = s.registry.IsTracked[id]
+		cs.registry.Cloners[id] = s.registry.Cloners[id]
+		if set := s.sparse[id]; set != nil {
+			cs.sparse[id] = set.Clone(s.registry.Cloners[id])
+		}
+		if m := s.multi[id]; m != nil {
+			cs.multi[id] = m.Clone()
+		}
+	}
+	cs.registry.hasSymmetric = s.registry.hasSymmetric
+	cs.registry.hasCleanup = s.registry.hasCleanup
+	cs.registry.frozen = s.registry.frozen
+	cs.registry.hasSparse = s.registry.hasSparse
+	cs.registry.hasMultiRelations = s.registry.hasMultiRelations
+
+	cs.tick = s.tick
+	cs.entityPool = s.entityPool.Clone()
+	cs.entities = append(cs.entities[:0], s.entities...)
+	cs.isTarget = append(cs.isTarget[:0], s.isTarget...)
+
+	for i := 1; i < len(s.archetypes); i++ {
+		node := cs.graph.findOrCreate(&s.archetypes[i].mask)
+		cs.createArchetype(node)
+	}
+
+	for i := range s.archetypes {
+		archetype := &s.archetypes[i]
+		for _, id := range archetype.tables.tables {
+			cs.cloneTable(&s.tables[id])
+		}
+	}
+
+	return clone
+}
+
+// cloneTable creates a copy of a table from another storage, incl. its entities and components.
+// The storage must have the same archetypes as the table's origin.
+func (s *storage) cloneTable(from *table) {
+	archetype := &s.archetypes[from.archetype]
+	table, ok := archetype.GetTable(s, from.relationIDs)
+	if !ok {
+		relations := append([]relationID(nil), from.relationIDs...)
+		table = s.createTable(archetype, relations)
+	}
+
+	start := table.len
+	table.AddAll(from, from.len)
+	for i := range table.len {
+		entity := table.GetEntity(uintptr(i))
+		s.entities[entity.id] = entityIndex{table: table.id, row: i}
+	}
+
+	for _, id := range table.ids {
+		fn := s.registry.Cloners[id.id]
+		if fn == nil {
+			continue
+		}
+		dst, src := table.Column(id), from.Column(id)
+		for i := range from.len {
+			fn(dst.Get(uintptr(start+i)), src.Get(uintptr(i)))
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestWorldClone(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+	mapper := NewMap2[Position, Velocity](w)
+
+	entities := []Entity{}
+	for i := range 10 {
+		entities = append(entities, mapper.NewEntity(&Position{float64(i), 0}, &Velocity{0, float64(i)}))
+	}
+	for i := range 5 {
+		entities = append(entities, posMap.NewEntity(&Position{float64(i + 10), 0}))
+	}
+	w.RemoveEntity(entities[3])
+	removed := entities[3]
+
+	w2 := w.Clone()
+	posMap2 := NewMap[Position](w2)
+	velMap2 := NewMap[Velocity](w2)
+
+	expectEqual(t, ComponentID[Position](w), ComponentID[Position](w2))
+	expectEqual(t, ComponentID[Velocity](w), ComponentID[Velocity](w2))
+	expectEqual(t, len(w.storage.archetypes), len(w2.storage.archetypes))
+
+	expectFalse(t, w2.Alive(removed))
+	for i, e := range entities {
+		if e == removed {
+			continue
+		}
+		expectTrue(t, w2.Alive(e))
+		expectEqual(t, Position{float64(i), 0}, *posMap2.Get(e))
+		if i < 10 {
+			expectEqual(t, Velocity{0, float64(i)}, *velMap2.Get(e))
+		} else {
+			expectFalse(t, velMap2.Has(e))
+		}
+	}
+
+	// Worlds are independent.
+	posMap.Get(entities[0]).X = 100
+	expectEqual(t, Position{0, 0}, *posMap2.Get(entities[0]))
+	velMap2.Add(entities[10], &Velocity{1, 1})
+	expectFalse(t, velMap.Has(entities[10]))
+	w2.RemoveEntity(entities[1])
+	expectTrue(t, w.Alive(entities[1]))
+
+	// Entity recycling continues independently.
+	e1 := w.NewEntity()
+	e2 := w2.NewEntity()
+	expectEqual(t, removed.id, e1.id)
+	expectEqual(t, entities[1].id, e2.id)
+
+	// Entity creation is deterministic in clones.
+	w3 := w.Clone()
+	expectEqual(t, w.NewEntity(), w3.NewEntity())
+
+	query := NewFilter2[Position, Velocity](w2).Query()
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, 9, cnt)
+}
+
+func TestWorldCloneRelations(t *testing.T) {
+	w := NewWorld(4)
+	childMap := NewMap2[Position, ChildOf](w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+	parent3 := w.NewEntity()
+
+	childMap.NewBatch(5, &Position{}, &ChildOf{}, Rel[ChildOf](parent1))
+	childMap.NewBatch(3, &Position{}, &ChildOf{}, Rel[ChildOf](parent2))
+	childMap.NewBatch(2, &Position{}, &ChildOf{}, Rel[ChildOf](parent3))
+	w.RemoveEntity(parent3)
+
+	w2 := w.Clone()
+	filter := NewFilter1[ChildOf](w2).Register()
+	relMap := NewMap[ChildOf](w2)
+
+	expectEqual(t, 5, countChildren(filter, parent1))
+	expectEqual(t, 3, countChildren(filter, parent2))
+	expectEqual(t, 2, countChildren(filter, Entity{}))
+
+	query := filter.Query()
+	cnt := 0
+	for query.Next() {
+		expectEqual(t, query.GetRelation(0), relMap.GetRelation(query.Entity()))
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+
+	// Cleanup works in the clone, and does not affect the original.
+	w2.RemoveEntity(parent1)
+	expectEqual(t, 7, countChildren(filter, Entity{}))
+	expectTrue(t, w.Alive(parent1))
+
+	filter1 := NewFilter1[ChildOf](w)
+	expectEqual(t, 5, countChildren(filter1, parent1))
+}
+
+func TestWorldCloneMultiRelations(t *testing.T) {
+	w := NewWorld(4)
+	likesMap := NewMap[Likes](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	e := likesMap.NewEntity(&Likes{}, a, b)
+
+	w2 := w.Clone()
+	likesMap2 := NewMap[Likes](w2)
+	expectEqual(t, 2, len(likesMap2.GetRelations(e)))
+
+	w2.RemoveEntity(a)
+	expectSlicesEqual(t, []Entity{b}, likesMap2.GetRelations(e))
+	expectEqual(t, 2, len(likesMap.GetRelations(e)))
+}
+
+func TestWorldCloneChanges(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	NewFilter0(w).Added(C[Position]())
+	posMap.NewBatch(5, &Position{})
+	since := w.AdvanceTick()
+	e := posMap.NewEntity(&Position{})
+
+	w2 := w.Clone()
+	expectEqual(t, w.ChangeTick(), w2.ChangeTick())
+	expectTrue(t, w2.storage.registry.IsTracked[ComponentID[Position](w2).id])
+
+	filter := NewFilter0(w2).Added(C[Position]()).Since(since)
+	query := filter.Query()
+	cnt := 0
+	for query.Next() {
+		expectEqual(t, e, query.Entity())
+		cnt++
+	}
+	expectEqual(t, 1, cnt)
+}
+
+func TestWorldCloneResources(t *testing.T) {
+	w := NewWorld(4)
+	res := NewResource[snapshotResource](w)
+	headRes := NewResource[Heading](w)
+	res.Add(&snapshotResource{Value: 1, Items: []int{1, 2}})
+	headRes.Add(&Heading{1})
+
+	res.SetCloneFunc(func(r *snapshotResource) *snapshotResource {
+		return &snapshotResource{Value: r.Value, Items: append([]int(nil), r.Items...)}
+	})
+
+	w2 := w.Clone()
+	res2 := NewResource[snapshotResource](w2)
+	headRes2 := NewResource[Heading](w2)
+	gridRes2 := NewResource[Grid](w2)
+
+	expectEqual(t, res.id, res2.id)
+	expectEqual(t, headRes.id, headRes2.id)
+	expectFalse(t, gridRes2.Has())
+
+	expectEqual(t, Heading{1}, *headRes2.Get())
+	headRes.Get().H = 2
+	expectEqual(t, Heading{1}, *headRes2.Get())
+
+	res.Get().Items[0] = 5
+	expectEqual(t, 1, res2.Get().Value)
+	expectSlicesEqual(t, []int{1, 2}, res2.Get().Items)
+
+	// Clone functions are cloned, too.
+	w3 := w2.Clone()
+	res3 := NewResource[snapshotResource](w3)
+	res2.Get().Items[0] = 7
+	expectSlicesEqual(t, []int{1, 2}, res3.Get().Items)
+
+	res.SetCloneFunc(nil)
+	w4 := w.Clone()
+	res4 := NewResource[snapshotResource](w4)
+	res.Get().Items[1] = 9
+	expectEqual(t, 9, res4.Get().Items[1])
+}
+
+func TestWorldCloneUnregistered(t *testing.T) {
+	w := NewWorld(4)
+	ComponentID[Position](w)
+	ComponentID[Velocity](w)
+	headID := ComponentID[Heading](w)
+	w.UnregisterComponent(C[Velocity]())
+
+	headMap := NewMap[Heading](w)
+	e := headMap.NewEntity(&Heading{H: 1})
+
+	w2 := w.Clone()
+	expectEqual(t, headID, ComponentID[Heading](w2))
+	expectSlicesEqual(t, ComponentIDs(w), ComponentIDs(w2))
+	expectEqual(t, 1.0, NewMap[Heading](w2).Get(e).H)
+
+	expectEqual(t, ComponentID[Label](w), ComponentID[Label](w2))
+}
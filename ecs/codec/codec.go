@@ -0,0 +1,125 @@
+// Package codec provides a compact, versioned binary serialization format for ecs.World.
+//
+// The format is designed for fast streaming of large worlds over an [io.Writer] and [io.Reader].
+// Use an [Encoder] to write a world, and a [Decoder] to read it into another world.
+//
+// A stream contains, in this order:
+//   - a header with a magic number, the format version, the byte order and the pointer size,
+//   - the registered component types with their names (see ecs.CompInfo), memory sizes,
+//     trivial, relation and multi-target relation flags and storage kinds,
+//   - the state of the entity pool, as obtained by ecs.Unsafe.DumpEntities,
+//   - all non-empty tables, each with its archetype mask, relation targets, entities and component columns,
+//   - the targets of multi-target relation components (see ecs.MultiRelationMarker), per entity.
+//
+// Archetype masks refer to components by their position in the stream, not by their ID,
+// so that component IDs with gaps are encoded compactly.
+//
+// Columns of trivial component types (see ecs.CompInfo) are written as raw memory blobs.
+// Thus, streams can only be read on machines with the same byte order and pointer size as the writing machine.
+// Columns of non-trivial component types are written by a pluggable [ValueCodec], using [GobCodec] by default.
+//
+// Resources are not serialized.
+// Components with sparse storage (see ecs.StorageSparse) are not supported.
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Version is the version of the binary format written by [Encoder].
+// [Decoder] reads only streams of the same version.
+const Version uint16 = 1
+
+// magic number at the start of each stream.
+var magic = [4]byte{'A', 'R', 'K', 'W'}
+
+// Byte order flags.
+const (
+	littleEndian byte = 0
+	bigEndian    byte = 1
+)
+
+// Section markers.
+const (
+	endMarker     byte = 0
+	tableMarker   byte = 1
+	targetsMarker byte = 2
+)
+
+// Limits for lengths read from a stream, so that malformed streams fail with an error instead of huge allocations.
+const (
+	maxNameLength = 1 << 16        // Maximum length of component names.
+	maxEntities   = math.MaxUint32 // Maximum number of entities in the entity pool.
+	maxPrealloc   = 1 << 16        // Maximum number of elements allocated before reading them.
+)
+
+// ValueCodec encodes and decodes columns of non-trivial component types.
+type ValueCodec interface {
+	// Encode writes the given slice of component values.
+	Encode(w io.Writer, values reflect.Value) error
+	// Decode reads component values into the given slice.
+	// The slice has the same length as the slice that was encoded.
+	Decode(r io.Reader, values reflect.Value) error
+}
+
+// GobCodec is a [ValueCodec] based on [encoding/gob].
+// It is the default codec for non-trivial component types.
+//
+// Only exported fields of components are encoded.
+type GobCodec struct{}
+
+// Encode writes the given slice of component values.
+func (GobCodec) Encode(w io.Writer, values reflect.Value) error {
+	return gob.NewEncoder(w).EncodeValue(values)
+}
+
+// Decode reads component values into the given slice.
+func (GobCodec) Decode(r io.Reader, values reflect.Value) error {
+	decoded := reflect.New(values.Type())
+	if err := gob.NewDecoder(r).DecodeValue(decoded); err != nil {
+		return err
+	}
+	reflect.Copy(values, decoded.Elem())
+	return nil
+}
+
+// nativeByteOrder returns the byte order flag of the current machine.
+func nativeByteOrder() byte {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) == 1 {
+		return littleEndian
+	}
+	return bigEndian
+}
+
+// columnBytes returns the memory of a slice value as a byte slice.
+func columnBytes(values reflect.Value) []byte {
+	size := values.Len() * int(values.Type().Elem().Size())
+	if size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(values.UnsafePointer()), size)
+}
+
+// maskBytes returns the number of bytes required for a mask of the given number of components.
+func maskBytes(numComponents int) int {
+	return (numComponents + 7) / 8
+}
+
+// checkStorage returns an error if the world has component types with sparse storage.
+func checkStorage(world *ecs.World) error {
+	for _, id := range ecs.ComponentIDs(world) {
+		info, _ := ecs.ComponentInfo(world, id)
+		if info.Storage == ecs.StorageSparse {
+			return fmt.Errorf("component type %s uses sparse storage, which is not supported", info.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,562 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+type Name struct {
+	Name  string
+	Items []int
+}
+
+type Tag struct{}
+
+type ChildOf struct {
+	ecs.RelationMarker
+}
+
+type Likes struct {
+	ecs.MultiRelationMarker
+}
+
+// nameCodec is a simple custom codec for Name components, for testing.
+type nameCodec struct {
+	encoded int
+}
+
+func (c *nameCodec) Encode(w io.Writer, values reflect.Value) error {
+	for _, name := range values.Interface().([]Name) {
+		if err := binary.Write(w, binary.LittleEndian, uint32(len(name.Name))); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, name.Name); err != nil {
+			return err
+		}
+		c.encoded++
+	}
+	return nil
+}
+
+func (c *nameCodec) Decode(r io.Reader, values reflect.Value) error {
+	names := values.Interface().([]Name)
+	for i := range names {
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return err
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		names[i].Name = string(buf)
+	}
+	return nil
+}
+
+func createWorld() (*ecs.World, []ecs.Entity, ecs.Entity) {
+	w := ecs.NewWorld(8)
+	posMap := ecs.NewMap[Position](w)
+	nameMap := ecs.NewMap3[Position, Name, Tag](w)
+	childMap := ecs.NewMap2[Position, ChildOf](w)
+
+	parent := w.NewEntity()
+	entities := []ecs.Entity{}
+	for i := range 20 {
+		entities = append(entities, posMap.NewEntity(&Position{float64(i), 1}))
+	}
+	for i := range 10 {
+		entities = append(entities, nameMap.NewEntity(&Position{float64(i), 2},
+			&Name{Name: fmt.Sprintf("e%d", i), Items: []int{i}}, &Tag{}))
+	}
+	for i := range 5 {
+		entities = append(entities, childMap.NewEntity(&Position{float64(i), 3}, &ChildOf{}, ecs.Rel[ChildOf](parent)))
+	}
+	w.RemoveEntity(entities[3])
+	w.RemoveEntity(entities[25])
+	entities = append(entities[:25], entities[26:]...)
+	entities = append(entities[:3], entities[4:]...)
+
+	return w, entities, parent
+}
+
+func newTargetWorld() *ecs.World {
+	w := ecs.NewWorld(8)
+	// Register in a different order than in the original world.
+	_ = ecs.ComponentID[ChildOf](w)
+	_ = ecs.ComponentID[Tag](w)
+	_ = ecs.ComponentID[Name](w)
+	_ = ecs.ComponentID[Position](w)
+	return w
+}
+
+func TestCodec(t *testing.T) {
+	w, entities, parent := createWorld()
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+
+	w2 := newTargetWorld()
+	if err := NewDecoder(&buf).Decode(w2); err != nil {
+		t.Fatal(err)
+	}
+
+	posMap := ecs.NewMap[Position](w)
+	nameMap := ecs.NewMap[Name](w)
+	childMap := ecs.NewMap[ChildOf](w)
+	posMap2 := ecs.NewMap[Position](w2)
+	nameMap2 := ecs.NewMap[Name](w2)
+	tagMap2 := ecs.NewMap[Tag](w2)
+	childMap2 := ecs.NewMap[ChildOf](w2)
+
+	if !w2.Alive(parent) {
+		t.Fatal("expected parent to be alive")
+	}
+	for _, e := range entities {
+		if !w2.Alive(e) {
+			t.Fatalf("expected entity %v to be alive", e)
+		}
+		if *posMap.Get(e) != *posMap2.Get(e) {
+			t.Fatalf("expected position %v, got %v", *posMap.Get(e), *posMap2.Get(e))
+		}
+		if nameMap.Has(e) {
+			if !reflect.DeepEqual(*nameMap.Get(e), *nameMap2.Get(e)) {
+				t.Fatalf("expected name %v, got %v", *nameMap.Get(e), *nameMap2.Get(e))
+			}
+			if !tagMap2.Has(e) {
+				t.Fatal("expected entity to have a tag")
+			}
+		} else if nameMap2.Has(e) {
+			t.Fatal("expected entity to have no name")
+		}
+		if childMap.Has(e) {
+			if childMap2.GetRelation(e) != parent {
+				t.Fatalf("expected relation target %v, got %v", parent, childMap2.GetRelation(e))
+			}
+		} else if childMap2.Has(e) {
+			t.Fatal("expected entity to have no relation")
+		}
+	}
+
+	// Entity creation continues as in the original world.
+	if w.NewEntity() != w2.NewEntity() {
+		t.Fatal("expected the same entity to be created")
+	}
+
+	query := ecs.NewFilter0(w2).Query()
+	if query.Count() != len(entities)+2 {
+		t.Fatalf("expected %d entities, got %d", len(entities)+2, query.Count())
+	}
+	query.Close()
+}
+
+func TestCodecMultiRelation(t *testing.T) {
+	w := ecs.NewWorld(8)
+	likesMap := ecs.NewMap[Likes](w)
+	a := w.NewEntity()
+	b := w.NewEntity()
+	e1 := likesMap.NewEntity(&Likes{}, a, b)
+	e2 := likesMap.NewEntity(&Likes{}, ecs.Entity{})
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	w2 := ecs.NewWorld(8)
+	likesMap2 := ecs.NewMap[Likes](w2)
+	if err := NewDecoder(bytes.NewReader(data)).Decode(w2); err != nil {
+		t.Fatal(err)
+	}
+	if targets := likesMap2.GetRelations(e1); len(targets) != 2 {
+		t.Fatalf("expected 2 relation targets, got %v", targets)
+	}
+	if targets := likesMap2.GetRelations(e2); len(targets) != 0 {
+		t.Fatalf("expected no relation targets, got %v", targets)
+	}
+
+	type Likes struct {
+		ecs.RelationMarker
+	}
+	w3 := ecs.NewWorld(8)
+	_ = ecs.ComponentID[Likes](w3)
+	err := NewDecoder(bytes.NewReader(data)).Decode(w3)
+	if err == nil || err.Error() != "component type codec.Likes is a multi-target relation: false, but true in the stream" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCodecCustom(t *testing.T) {
+	w, entities, _ := createWorld()
+	codec := &nameCodec{}
+
+	buf := bytes.Buffer{}
+	enc := NewEncoder(&buf)
+	enc.SetCodec(codec)
+	if err := enc.Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	if codec.encoded != 9 {
+		t.Fatalf("expected 9 encoded names, got %d", codec.encoded)
+	}
+
+	w2 := newTargetWorld()
+	dec := NewDecoder(&buf)
+	dec.SetCodec(codec)
+	if err := dec.Decode(w2); err != nil {
+		t.Fatal(err)
+	}
+
+	nameMap := ecs.NewMap[Name](w)
+	nameMap2 := ecs.NewMap[Name](w2)
+	for _, e := range entities {
+		if !nameMap.Has(e) {
+			continue
+		}
+		if nameMap.Get(e).Name != nameMap2.Get(e).Name {
+			t.Fatalf("expected name %s, got %s", nameMap.Get(e).Name, nameMap2.Get(e).Name)
+		}
+		if nameMap2.Get(e).Items != nil {
+			t.Fatal("expected items to be nil")
+		}
+	}
+}
+
+func TestCodecEmpty(t *testing.T) {
+	w := ecs.NewWorld()
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	w2 := ecs.NewWorld()
+	if err := NewDecoder(&buf).Decode(w2); err != nil {
+		t.Fatal(err)
+	}
+	if w.NewEntity() != w2.NewEntity() {
+		t.Fatal("expected the same entity to be created")
+	}
+}
+
+func TestCodecIDGaps(t *testing.T) {
+	w := ecs.NewWorld(8)
+	nameID := 5
+	ecs.RegisterComponent(w, ecs.ComponentOptions[Name]{ID: &nameID})
+	_ = ecs.ComponentID[Tag](w)
+	w.UnregisterComponent(ecs.C[Tag]())
+	_ = ecs.ComponentID[ChildOf](w)
+	_ = ecs.ComponentID[Tag](w)
+	w.UnregisterComponent(ecs.C[ChildOf]())
+
+	nameMap := ecs.NewMap2[Position, Name](w)
+	e1 := nameMap.NewEntity(&Position{X: 1}, &Name{Name: "a"})
+	e2 := ecs.NewMap[Tag](w).NewEntity(&Tag{})
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+
+	w2 := newTargetWorld()
+	if err := NewDecoder(&buf).Decode(w2); err != nil {
+		t.Fatal(err)
+	}
+
+	nameMap2 := ecs.NewMap2[Position, Name](w2)
+	pos, name := nameMap2.Get(e1)
+	if pos.X != 1 || name.Name != "a" {
+		t.Fatalf("unexpected components %v, %v", *pos, *name)
+	}
+	if !ecs.NewMap[Tag](w2).Has(e2) || ecs.NewMap[Position](w2).Has(e2) {
+		t.Fatal("expected entity to have only a tag")
+	}
+}
+
+func TestCodecNames(t *testing.T) {
+	w := ecs.NewWorld(8)
+	ecs.RegisterComponent(w, ecs.ComponentOptions[Position]{Name: "pos"})
+	e := ecs.NewMap[Position](w).NewEntity(&Position{X: 1, Y: 2})
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	type Pos struct {
+		X float64
+		Y float64
+	}
+	w2 := ecs.NewWorld(8)
+	ecs.RegisterComponent(w2, ecs.ComponentOptions[Pos]{Name: "pos"})
+	if err := NewDecoder(bytes.NewReader(data)).Decode(w2); err != nil {
+		t.Fatal(err)
+	}
+	if pos := ecs.NewMap[Pos](w2).Get(e); pos.X != 1 || pos.Y != 2 {
+		t.Fatalf("unexpected position %v", *pos)
+	}
+
+	w3 := ecs.NewWorld(8)
+	ecs.RegisterComponent(w3, ecs.ComponentOptions[Pos]{Name: "pos"})
+	ecs.RegisterComponent(w3, ecs.ComponentOptions[Position]{Name: "pos"})
+	err := NewDecoder(bytes.NewReader(data)).Decode(w3)
+	if err == nil || err.Error() != "component name pos is used by multiple component types in the world" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	w, _, _ := createWorld()
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	err := NewDecoder(bytes.NewReader([]byte("ARKX\x01\x00\x00\x08"))).Decode(ecs.NewWorld())
+	if err == nil || err.Error() != `invalid stream, expected magic number "ARKW"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wrongVersion := append([]byte{}, data...)
+	wrongVersion[4] = 99
+	err = NewDecoder(bytes.NewReader(wrongVersion)).Decode(ecs.NewWorld())
+	if err == nil || err.Error() != "unsupported format version 99, expected 1" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wrongPlatform := append([]byte{}, data...)
+	wrongPlatform[7] = 3
+	err = NewDecoder(bytes.NewReader(wrongPlatform)).Decode(ecs.NewWorld())
+	if err == nil || err.Error() != "stream was written on a platform with different byte order or pointer size" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w2 := ecs.NewWorld()
+	_ = ecs.ComponentID[Position](w2)
+	err = NewDecoder(bytes.NewReader(data)).Decode(w2)
+	if err == nil || err.Error() != "component type codec.Name is not registered in the world" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = NewDecoder(bytes.NewReader(data[:len(data)-1])).Decode(newTargetWorld())
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected unexpected EOF, got %v", err)
+	}
+
+	err = NewDecoder(bytes.NewReader(data[:len(data)-20])).Decode(newTargetWorld())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	type Selected struct{}
+	sparse := ecs.NewWorld()
+	ecs.RegisterComponent(sparse, ecs.ComponentOptions[Selected]{Name: "selected", Storage: ecs.StorageSparse})
+	err = NewEncoder(&bytes.Buffer{}).Encode(sparse)
+	if err == nil || err.Error() != "component type selected uses sparse storage, which is not supported" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = NewDecoder(bytes.NewReader(data)).Decode(sparse)
+	if err == nil || err.Error() != "component type selected uses sparse storage, which is not supported" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCodecMismatch(t *testing.T) {
+	w, _, _ := createWorld()
+
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	type NonTrivial struct {
+		X float64
+		Y *float64
+	}
+	w2 := ecs.NewWorld()
+	_ = ecs.ComponentID[ChildOf](w2)
+	_ = ecs.ComponentID[Tag](w2)
+	_ = ecs.ComponentID[Name](w2)
+	ecs.RegisterComponent(w2, ecs.ComponentOptions[NonTrivial]{Name: "codec.Position"})
+	err := NewDecoder(bytes.NewReader(data)).Decode(w2)
+	if err == nil || err.Error() != "component type codec.Position is trivial: false, but true in the stream" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type NoRelation struct{}
+	w3 := ecs.NewWorld()
+	ecs.RegisterComponent(w3, ecs.ComponentOptions[NoRelation]{Name: "codec.ChildOf"})
+	_ = ecs.ComponentID[Tag](w3)
+	_ = ecs.ComponentID[Name](w3)
+	_ = ecs.ComponentID[Position](w3)
+	err = NewDecoder(bytes.NewReader(data)).Decode(w3)
+	if err == nil || err.Error() != "component type codec.ChildOf is a relation: false, but true in the stream" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCodecMalformed(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(ecs.NewWorld()); err != nil {
+		t.Fatal(err)
+	}
+	header := buf.Bytes()[:8]
+	stream := func(values ...uint64) []byte {
+		data := append([]byte{}, header...)
+		for _, v := range values {
+			data = binary.AppendUvarint(data, v)
+		}
+		return data
+	}
+	world := func() *ecs.World {
+		w := ecs.NewWorld()
+		_ = ecs.ComponentID[Position](w)
+		return w
+	}
+
+	err := NewDecoder(bytes.NewReader(stream(1, 1<<40))).Decode(world())
+	if err == nil || err.Error() != "component name length 1099511627776 exceeds the maximum of 65536" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := stream(2)
+	for range 2 {
+		data = binary.AppendUvarint(data, uint64(len("codec.Position")))
+		data = append(data, "codec.Position"...)
+		data = binary.AppendUvarint(data, 16)
+		data = append(data, 1, 0, 0, 0)
+	}
+	err = NewDecoder(bytes.NewReader(data)).Decode(world())
+	if err == nil || err.Error() != "component type codec.Position occurs multiple times in the stream" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = NewDecoder(bytes.NewReader(stream(0, 1<<40))).Decode(world())
+	if err == nil || err.Error() != "number of entities 1099511627776 exceeds the maximum of 4294967295" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Large counts fail at the end of the stream, without allocating all elements up-front.
+	err = NewDecoder(bytes.NewReader(stream(0, 1<<30))).Decode(world())
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	err = NewDecoder(bytes.NewReader(stream(0, 0, 1))).Decode(world())
+	if err == nil || err.Error() != "number of alive entities 1 exceeds the maximum of 0" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e := ecs.NewWorld().NewEntity()
+	entity, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(stream(0, 1), entity...)
+	data = binary.AppendUvarint(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 0)
+	err = NewDecoder(bytes.NewReader(data)).Decode(world())
+	if err == nil || err.Error() != "invalid alive entity ID 0" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data = append(stream(0, 1), entity...)
+	data = binary.AppendUvarint(data, 0)
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	err = NewDecoder(bytes.NewReader(data)).Decode(world())
+	if err == nil || err.Error() != "invalid entity pool state with next 1 and 1 available entities" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCodecMalformedBlob(t *testing.T) {
+	w := ecs.NewWorld()
+	ecs.NewMap[Name](w).NewEntity(&Name{Name: "a"})
+
+	buf := bytes.Buffer{}
+	enc := NewEncoder(&buf)
+	enc.SetCodec(&nameCodec{})
+	if err := enc.Encode(w); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// The stream ends with the blob size, the 5 bytes of the encoded name, and the end marker.
+	corrupt := binary.AppendUvarint(append([]byte{}, data[:len(data)-7]...), 1<<40)
+	corrupt = append(corrupt, data[len(data)-6:]...)
+
+	w2 := ecs.NewWorld()
+	_ = ecs.ComponentID[Name](w2)
+	dec := NewDecoder(bytes.NewReader(corrupt))
+	dec.SetCodec(&nameCodec{})
+	err := dec.Decode(w2)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected unexpected EOF, got %v", err)
+	}
+
+	w3 := ecs.NewWorld()
+	_ = ecs.ComponentID[Name](w3)
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.SetCodec(&nameCodec{})
+	if err := dec.Decode(w3); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCodecMetadata(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf).Encode(ecs.NewWorld()); err != nil {
+		t.Fatal(err)
+	}
+	component := func(storage byte) []byte {
+		data := append([]byte{}, buf.Bytes()[:8]...)
+		data = binary.AppendUvarint(data, 1)
+		data = binary.AppendUvarint(data, uint64(len("codec.Position")))
+		data = append(data, "codec.Position"...)
+		data = binary.AppendUvarint(data, 16)
+		return append(data, 1, 0, 0, storage)
+	}
+
+	w := ecs.NewWorld()
+	_ = ecs.ComponentID[Position](w)
+	err := NewDecoder(bytes.NewReader(component(byte(ecs.StorageSparse)))).Decode(w)
+	if err == nil || err.Error() != "component type codec.Position uses storage kind 0, but 1 in the stream" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Matching metadata, but the stream ends after the component types.
+	err = NewDecoder(bytes.NewReader(component(byte(ecs.StorageTable)))).Decode(w)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	type Position struct {
+		X float32
+		Y float32
+	}
+	w2 := ecs.NewWorld()
+	ecs.RegisterComponent(w2, ecs.ComponentOptions[Position]{Name: "codec.Position"})
+	err = NewDecoder(bytes.NewReader(component(byte(ecs.StorageTable)))).Decode(w2)
+	if err == nil || err.Error() != "component type codec.Position has size 8, but 16 in the stream" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
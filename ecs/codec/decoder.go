@@ -0,0 +1,423 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"unsafe"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Decoder reads worlds from a binary stream written by an [Encoder].
+//
+// Create one with [NewDecoder].
+type Decoder struct {
+	r          *bufio.Reader
+	codec      ValueCodec
+	buf        []byte
+	ids        []ecs.ID
+	isTrivial  []bool
+	isRelation []bool
+	entities   []ecs.Entity
+	loaded     []bool // Whether entities were already loaded into a table, indexed by entity ID
+}
+
+// NewDecoder creates a new [Decoder] that reads from the given reader.
+//
+// The decoder buffers its input, and may read data from the reader beyond the encoded world.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:     bufio.NewReader(r),
+		codec: GobCodec{},
+	}
+}
+
+// SetCodec sets the [ValueCodec] used for non-trivial component types.
+// Must be compatible with the codec used for encoding. The default is [GobCodec].
+func (d *Decoder) SetCodec(codec ValueCodec) {
+	d.codec = codec
+}
+
+// Decode reads a world from the stream into the given world.
+//
+// The world must be fresh or reset, and all component types of the stream must be registered in it.
+// Component IDs may differ from those in the encoded world, as types are matched by their names.
+// Names default to the Go type names, and can be customized with ecs.ComponentOptions.
+// Entities keep their IDs and generations.
+//
+// Returns an error if the stream is malformed, if its version or platform does not match,
+// if component types are missing in the world, have ambiguous names,
+// or have different sizes, flags or storage kinds than in the stream,
+// or if the world has component types with sparse storage (see ecs.StorageSparse).
+// Panics if the world is not fresh or reset.
+func (d *Decoder) Decode(world *ecs.World) error {
+	if err := d.readHeader(); err != nil {
+		return err
+	}
+	if err := d.readComponents(world); err != nil {
+		return err
+	}
+	dump, err := d.readEntities()
+	if err != nil {
+		return err
+	}
+	world.Unsafe().LoadEntities(&dump)
+	d.entities = dump.Entities
+	d.loaded = make([]bool, len(dump.Entities))
+
+	for {
+		marker, err := d.r.ReadByte()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		if marker == endMarker {
+			return nil
+		}
+		switch marker {
+		case tableMarker:
+			err = d.readTable(world)
+		case targetsMarker:
+			err = d.readTargets(world)
+		default:
+			return fmt.Errorf("invalid table marker %d", marker)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readHeader reads and checks the stream header.
+func (d *Decoder) readHeader() error {
+	header, err := d.read(len(magic) + 4)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(header[:len(magic)], magic[:]) {
+		return fmt.Errorf("invalid stream, expected magic number %q", magic[:])
+	}
+	header = header[len(magic):]
+	if version := binary.LittleEndian.Uint16(header); version != Version {
+		return fmt.Errorf("unsupported format version %d, expected %d", version, Version)
+	}
+	if header[2] != nativeByteOrder() || header[3] != byte(unsafe.Sizeof(uintptr(0))) {
+		return fmt.Errorf("stream was written on a platform with different byte order or pointer size")
+	}
+	return nil
+}
+
+// readComponents reads the component types and maps them to the world's component IDs.
+func (d *Decoder) readComponents(world *ecs.World) error {
+	count, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+
+	if err := checkStorage(world); err != nil {
+		return err
+	}
+
+	types := map[string]ecs.ID{}
+	for _, id := range ecs.ComponentIDs(world) {
+		info, _ := ecs.ComponentInfo(world, id)
+		if _, ok := types[info.Name]; ok {
+			return fmt.Errorf("component name %s is used by multiple component types in the world", info.Name)
+		}
+		types[info.Name] = id
+	}
+
+	// Each component type of the stream must be registered in the world, and can occur only once.
+	// Thus, the number of registered component types bounds the allocations.
+	capacity := min(count, uint64(len(types)))
+	d.ids = make([]ecs.ID, 0, capacity)
+	d.isTrivial = make([]bool, 0, capacity)
+	d.isRelation = make([]bool, 0, capacity)
+	used := map[ecs.ID]bool{}
+	for range count {
+		nameLen, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return err
+		}
+		if nameLen > maxNameLength {
+			return fmt.Errorf("component name length %d exceeds the maximum of %d", nameLen, maxNameLength)
+		}
+		nameBytes, err := d.read(int(nameLen))
+		if err != nil {
+			return err
+		}
+		name := string(nameBytes)
+		size, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return err
+		}
+		flags, err := d.read(4)
+		if err != nil {
+			return err
+		}
+
+		id, ok := types[name]
+		if !ok {
+			return fmt.Errorf("component type %s is not registered in the world", name)
+		}
+		if used[id] {
+			return fmt.Errorf("component type %s occurs multiple times in the stream", name)
+		}
+		used[id] = true
+		info, _ := ecs.ComponentInfo(world, id)
+		if uint64(info.Type.Size()) != size {
+			return fmt.Errorf("component type %s has size %d, but %d in the stream", name, info.Type.Size(), size)
+		}
+		isTrivial, isRelation, isMulti := flags[0] != 0, flags[1] != 0, flags[2] != 0
+		if isTrivial != info.IsTrivial {
+			return fmt.Errorf("component type %s is trivial: %t, but %t in the stream", name, info.IsTrivial, isTrivial)
+		}
+		if isRelation != info.IsRelation {
+			return fmt.Errorf("component type %s is a relation: %t, but %t in the stream", name, info.IsRelation, isRelation)
+		}
+		if isMulti != info.IsMultiRelation {
+			return fmt.Errorf("component type %s is a multi-target relation: %t, but %t in the stream", name, info.IsMultiRelation, isMulti)
+		}
+		if storage := ecs.StorageKind(flags[3]); storage != info.Storage {
+			return fmt.Errorf("component type %s uses storage kind %d, but %d in the stream", name, info.Storage, storage)
+		}
+		d.ids = append(d.ids, id)
+		d.isTrivial = append(d.isTrivial, isTrivial)
+		d.isRelation = append(d.isRelation, isRelation)
+	}
+	return nil
+}
+
+// readEntities reads the state of the entity pool.
+func (d *Decoder) readEntities() (ecs.EntityDump, error) {
+	dump := ecs.EntityDump{}
+
+	count, err := d.readCount(maxEntities, "entities")
+	if err != nil {
+		return dump, err
+	}
+	dump.Entities = make([]ecs.Entity, 0, min(count, maxPrealloc))
+	for range count {
+		var entity ecs.Entity
+		if err := d.readEntity(&entity); err != nil {
+			return dump, err
+		}
+		dump.Entities = append(dump.Entities, entity)
+	}
+
+	count, err = d.readCount(uint64(len(dump.Entities)), "alive entities")
+	if err != nil {
+		return dump, err
+	}
+	dump.Alive = make([]uint32, count)
+	for i := range dump.Alive {
+		if dump.Alive[i], err = d.readUint32(); err != nil {
+			return dump, err
+		}
+		if idx := dump.Alive[i]; int(idx) >= len(dump.Entities) || dump.Entities[idx].ID() != idx {
+			return dump, fmt.Errorf("invalid alive entity ID %d", idx)
+		}
+	}
+
+	if dump.Next, err = d.readUint32(); err != nil {
+		return dump, err
+	}
+	if dump.Available, err = d.readUint32(); err != nil {
+		return dump, err
+	}
+	if int(dump.Available) > len(dump.Entities) || (dump.Available > 0 && int(dump.Next) >= len(dump.Entities)) {
+		return dump, fmt.Errorf("invalid entity pool state with next %d and %d available entities", dump.Next, dump.Available)
+	}
+	return dump, nil
+}
+
+// readTable reads a table and loads it into the world.
+func (d *Decoder) readTable(world *ecs.World) error {
+	mask, err := d.read(maskBytes(len(d.ids)))
+	if err != nil {
+		return err
+	}
+	indices := []int{}
+	for i := range d.ids {
+		if mask[i/8]&(1<<(i%8)) != 0 {
+			indices = append(indices, i)
+		}
+	}
+
+	if len(indices) == 0 {
+		return fmt.Errorf("invalid table without components")
+	}
+
+	ids := make([]ecs.ID, len(indices))
+	targets := make([]ecs.Entity, len(indices))
+	for i, idx := range indices {
+		ids[i] = d.ids[idx]
+		if d.isRelation[idx] {
+			if err := d.readEntity(&targets[i]); err != nil {
+				return err
+			}
+			if info, _ := ecs.ComponentInfo(world, ids[i]); !info.IsMultiRelation {
+				if err := d.checkTarget(world, targets[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	count, err := d.readCount(uint64(len(d.entities)), "table entities")
+	if err != nil {
+		return err
+	}
+	entities := make([]ecs.Entity, count)
+	for i := range entities {
+		if err := d.readEntityID(&entities[i]); err != nil {
+			return err
+		}
+		entity := entities[i]
+		if !world.Alive(entity) {
+			return fmt.Errorf("can't load dead entity %d into a table", entity.ID())
+		}
+		if d.loaded[entity.ID()] {
+			return fmt.Errorf("entity %d is contained in multiple tables", entity.ID())
+		}
+		d.loaded[entity.ID()] = true
+	}
+
+	columns := world.Unsafe().LoadTable(ids, targets, entities)
+	for i, idx := range indices {
+		if d.isTrivial[idx] {
+			if _, err := io.ReadFull(d.r, columnBytes(columns[i])); err != nil {
+				return err
+			}
+			continue
+		}
+		size, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return err
+		}
+		// Values are decoded directly from the stream, so that the blob size does not determine allocations.
+		blob := io.LimitReader(d.r, int64(min(size, math.MaxInt64)))
+		if err := d.codec.Decode(blob, columns[i]); err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		// Skip the rest of the blob, if the codec did not consume it entirely.
+		if _, err := io.Copy(io.Discard, blob); err != nil {
+			return err
+		}
+		if blob.(*io.LimitedReader).N > 0 {
+			return io.ErrUnexpectedEOF
+		}
+	}
+	return nil
+}
+
+// readTargets reads the targets of a multi-target relation component for an entity and loads them into the world.
+func (d *Decoder) readTargets(world *ecs.World) error {
+	pos, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+	if pos >= uint64(len(d.ids)) {
+		return fmt.Errorf("invalid component position %d", pos)
+	}
+	id := d.ids[pos]
+	info, _ := ecs.ComponentInfo(world, id)
+	if !info.IsMultiRelation {
+		return fmt.Errorf("component type %s can't have multiple relation targets", info.Name)
+	}
+
+	var entity ecs.Entity
+	if err := d.readEntityID(&entity); err != nil {
+		return err
+	}
+	if !world.Alive(entity) || !world.Unsafe().Has(entity, id) {
+		return fmt.Errorf("entity %d has no component of type %s to set relation targets for", entity.ID(), info.Name)
+	}
+	count, err := d.readCount(uint64(len(d.entities)), "relation targets")
+	if err != nil {
+		return err
+	}
+	targets := make([]ecs.Entity, count)
+	for i := range targets {
+		if err := d.readEntity(&targets[i]); err != nil {
+			return err
+		}
+		if err := d.checkTarget(world, targets[i]); err != nil {
+			return err
+		}
+	}
+	world.Unsafe().LoadTargets(id, entity, targets)
+	return nil
+}
+
+// readEntityID reads an entity ID and looks up the entity in the loaded entity pool.
+func (d *Decoder) readEntityID(entity *ecs.Entity) error {
+	id, err := d.readUint32()
+	if err != nil {
+		return err
+	}
+	if int(id) >= len(d.entities) {
+		return fmt.Errorf("invalid entity ID %d", id)
+	}
+	*entity = d.entities[id]
+	return nil
+}
+
+// checkTarget returns an error if the given relation target is neither zero nor alive.
+func (d *Decoder) checkTarget(world *ecs.World, target ecs.Entity) error {
+	if !target.IsZero() && !world.Alive(target) {
+		return fmt.Errorf("invalid relation target entity %d", target.ID())
+	}
+	return nil
+}
+
+// readCount reads a number of elements, and returns an error if it exceeds the given maximum.
+func (d *Decoder) readCount(max uint64, what string) (int, error) {
+	count, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, err
+	}
+	if count > max {
+		return 0, fmt.Errorf("number of %s %d exceeds the maximum of %d", what, count, max)
+	}
+	return int(count), nil
+}
+
+// readEntity reads the binary representation of an entity.
+func (d *Decoder) readEntity(entity *ecs.Entity) error {
+	data, err := d.read(8)
+	if err != nil {
+		return err
+	}
+	return entity.UnmarshalBinary(data)
+}
+
+// readUint32 reads a little-endian uint32.
+func (d *Decoder) readUint32() (uint32, error) {
+	data, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(data), nil
+}
+
+// read reads the given number of bytes into the internal buffer, and returns it.
+func (d *Decoder) read(n int) ([]byte, error) {
+	if cap(d.buf) < n {
+		d.buf = make([]byte, n)
+	}
+	d.buf = d.buf[:n]
+	if _, err := io.ReadFull(d.r, d.buf); err != nil {
+		return nil, err
+	}
+	return d.buf, nil
+}
@@ -0,0 +1,214 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"unsafe"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Encoder writes worlds to a binary stream.
+//
+// Create one with [NewEncoder].
+type Encoder struct {
+	w          *bufio.Writer
+	codec      ValueCodec
+	buf        []byte
+	blob       bytes.Buffer
+	positions  []int  // Stream positions of components, indexed by component ID
+	isTrivial  []bool // Indexed by stream position
+	isRelation []bool // Indexed by stream position
+	numComps   int
+}
+
+// NewEncoder creates a new [Encoder] that writes to the given writer.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:     bufio.NewWriter(w),
+		codec: GobCodec{},
+	}
+}
+
+// SetCodec sets the [ValueCodec] used for non-trivial component types.
+// The default is [GobCodec].
+func (e *Encoder) SetCodec(codec ValueCodec) {
+	e.codec = codec
+}
+
+// Encode writes the given world to the stream.
+//
+// The world is locked while its tables are encoded.
+//
+// Returns an error if the world has component types with sparse storage (see ecs.StorageSparse).
+func (e *Encoder) Encode(world *ecs.World) error {
+	if err := checkStorage(world); err != nil {
+		return err
+	}
+	e.writeHeader()
+	e.writeComponents(world)
+	e.writeEntities(world.Unsafe().DumpEntities())
+	if err := e.flushBuffer(); err != nil {
+		return err
+	}
+
+	var err error
+	world.Unsafe().DumpTables(func(ids []ecs.ID, targets []ecs.Entity, entities []ecs.Entity, columns []reflect.Value) {
+		if err != nil || len(ids) == 0 {
+			return
+		}
+		err = e.writeTable(ids, targets, entities, columns)
+	})
+	if err != nil {
+		return err
+	}
+
+	world.Unsafe().DumpTargets(func(comp ecs.ID, entity ecs.Entity, targets []ecs.Entity) {
+		if err != nil {
+			return
+		}
+		err = e.writeTargets(comp, entity, targets)
+	})
+	if err != nil {
+		return err
+	}
+
+	e.buf = append(e.buf, endMarker)
+	if err := e.flushBuffer(); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// writeHeader writes the stream header.
+func (e *Encoder) writeHeader() {
+	e.buf = append(e.buf, magic[:]...)
+	e.buf = binary.LittleEndian.AppendUint16(e.buf, Version)
+	e.buf = append(e.buf, nativeByteOrder(), byte(unsafe.Sizeof(uintptr(0))))
+}
+
+// writeComponents writes the registered component types.
+//
+// Components are written in the order of their IDs.
+// As IDs may have gaps, components are referred to by their position in the stream rather than by ID.
+func (e *Encoder) writeComponents(world *ecs.World) {
+	ids := ecs.ComponentIDs(world)
+	e.numComps = len(ids)
+	e.isTrivial = make([]bool, len(ids))
+	e.isRelation = make([]bool, len(ids))
+	e.positions = e.positions[:0]
+	if len(ids) > 0 {
+		e.positions = make([]int, int(ids[len(ids)-1].Index())+1)
+	}
+
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(ids)))
+	for i, id := range ids {
+		info, _ := ecs.ComponentInfo(world, id)
+		e.positions[id.Index()] = i
+		e.isTrivial[i] = info.IsTrivial
+		e.isRelation[i] = info.IsRelation
+
+		name := info.Name
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(name)))
+		e.buf = append(e.buf, name...)
+		e.buf = binary.AppendUvarint(e.buf, uint64(info.Type.Size()))
+		e.buf = append(e.buf, boolByte(info.IsTrivial), boolByte(info.IsRelation),
+			boolByte(info.IsMultiRelation), byte(info.Storage))
+	}
+}
+
+// writeEntities writes the state of the entity pool.
+func (e *Encoder) writeEntities(dump ecs.EntityDump) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(dump.Entities)))
+	for _, entity := range dump.Entities {
+		e.buf = appendEntity(e.buf, entity)
+	}
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(dump.Alive)))
+	for _, id := range dump.Alive {
+		e.buf = binary.LittleEndian.AppendUint32(e.buf, id)
+	}
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, dump.Next)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, dump.Available)
+}
+
+// writeTable writes a table, including its mask, relation targets, entities and columns.
+func (e *Encoder) writeTable(ids []ecs.ID, targets []ecs.Entity, entities []ecs.Entity, columns []reflect.Value) error {
+	e.buf = append(e.buf, tableMarker)
+
+	start := len(e.buf)
+	e.buf = append(e.buf, make([]byte, maskBytes(e.numComps))...)
+	for _, id := range ids {
+		pos := e.positions[id.Index()]
+		e.buf[start+pos/8] |= 1 << (pos % 8)
+	}
+	for i, id := range ids {
+		if e.isRelation[e.positions[id.Index()]] {
+			e.buf = appendEntity(e.buf, targets[i])
+		}
+	}
+
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(entities)))
+	for _, entity := range entities {
+		e.buf = binary.LittleEndian.AppendUint32(e.buf, entity.ID())
+	}
+	if err := e.flushBuffer(); err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if e.isTrivial[e.positions[id.Index()]] {
+			if _, err := e.w.Write(columnBytes(columns[i])); err != nil {
+				return err
+			}
+			continue
+		}
+		e.blob.Reset()
+		if err := e.codec.Encode(&e.blob, columns[i]); err != nil {
+			return err
+		}
+		e.buf = binary.AppendUvarint(e.buf, uint64(e.blob.Len()))
+		if err := e.flushBuffer(); err != nil {
+			return err
+		}
+		if _, err := e.w.Write(e.blob.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTargets writes the targets of a multi-target relation component for an entity.
+func (e *Encoder) writeTargets(comp ecs.ID, entity ecs.Entity, targets []ecs.Entity) error {
+	e.buf = append(e.buf, targetsMarker)
+	e.buf = binary.AppendUvarint(e.buf, uint64(e.positions[comp.Index()]))
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, entity.ID())
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(targets)))
+	for _, target := range targets {
+		e.buf = appendEntity(e.buf, target)
+	}
+	return e.flushBuffer()
+}
+
+// flushBuffer writes the internal buffer to the stream and resets it.
+func (e *Encoder) flushBuffer() error {
+	_, err := e.w.Write(e.buf)
+	e.buf = e.buf[:0]
+	return err
+}
+
+// appendEntity appends the binary representation of an entity.
+func appendEntity(buf []byte, entity ecs.Entity) []byte {
+	buf, _ = entity.AppendBinary(buf) // Can't fail.
+	return buf
+}
+
+// boolByte converts a bool to a byte.
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
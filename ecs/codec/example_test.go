@@ -0,0 +1,36 @@
+package codec_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mlange-42/ark/ecs"
+	"github.com/mlange-42/ark/ecs/codec"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+func Example() {
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap[Position](world)
+	entity := mapper.NewEntity(&Position{X: 1, Y: 2})
+
+	// Encode the world, e.g. into a file.
+	buf := bytes.Buffer{}
+	if err := codec.NewEncoder(&buf).Encode(world); err != nil {
+		panic(err)
+	}
+
+	// Register all component types in the target world before decoding.
+	newWorld := ecs.NewWorld()
+	newMapper := ecs.NewMap[Position](newWorld)
+	if err := codec.NewDecoder(&buf).Decode(newWorld); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(*newMapper.Get(entity))
+	// Output: {1 2}
+}
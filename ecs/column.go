@@ -13,9 +13,17 @@ type column struct {
 	elemType   reflect.Type   // element type of the column
 	typePtr    unsafe.Pointer // pointer to the element type's rtype
 	target     Entity         // target entity if for a relation component
+	ticks      []uint32       // per-row change ticks, nil if changes are not tracked
+	addedTicks []uint32       // per-row ticks of component addition, nil if changes are not tracked
+	tick       uint32         // highest change tick of any row
+	allTick    uint32         // tick at which all rows were marked as changed
+	addedTick  uint32         // highest addition tick of any row
 	index      uint32         // index of the column in the containing table
 	isRelation bool           // whether this column is for a relation component
 	isTrivial  bool           // Whether the column's type is trivial , i.e. without pointers.
+	isTracked  bool           // Whether changes are tracked, see [storage.trackChanges]
+
+	isMultiRelation bool // whether this column is for a multi-target relation component
 }
 
 // newColumn creates a new column for a given type and capacity.
@@ -37,6 +45,27 @@ func newColumn(index uint32, tp reflect.Type, itemSize uintptr, isRelation bool,
 	}
 }
 
+// adjustCapacity changes the capacity of the column, keeping the first len items.
+// Does not check whether the change is necessary or feasible.
+func (c *column) adjustCapacity(len uint32, cap uint32) {
+	c.adjustTicks(len, cap)
+	old := c.data
+	c.data = reflect.MakeSlice(reflect.SliceOf(c.elemType), int(cap), int(cap))
+	if c.isTrivial {
+		newPtr := c.data.Index(0).Addr().UnsafePointer()
+		if len > 0 {
+			copyPtr(c.pointer, newPtr, uintptr(len)*c.itemSize)
+		}
+		c.pointer = newPtr
+	} else {
+		c.pointer = c.data.Index(0).Addr().UnsafePointer()
+		if len > 0 {
+			// TODO: use typedmemmove?
+			reflect.Copy(c.data, old)
+		}
+	}
+}
+
 // Get returns a pointer to the component at the given index.
 func (c *column) Get(index uintptr) unsafe.Pointer {
 	return unsafe.Add(c.pointer, index*c.itemSize)
@@ -46,6 +75,7 @@ func (c *column) Get(index uintptr) unsafe.Pointer {
 // Column length must be increased before.
 func (c *column) CopyToEnd(from *column, ownLen uint32, count uint32) {
 	start := ownLen - count
+	c.copyTicksToEnd(from, start, count)
 	if c.isTrivial {
 		src := from.Get(0)
 		dst := c.Get(uintptr(start))
@@ -57,6 +87,7 @@ func (c *column) CopyToEnd(from *column, ownLen uint32, count uint32) {
 
 // Set overwrites the component at the given index.
 func (c *column) Set(index uint32, src *column, srcIndex uint32) {
+	c.copyTicks(index, src, srcIndex)
 	if c.itemSize == 0 {
 		return
 	}
@@ -117,9 +148,10 @@ func (c *column) zeroRangeNonTrivial(start, length uint32) {
 	}
 }
 
-// Reset the column. Zeroes the memory.
+// Reset the column. Zeroes the memory and the change ticks.
 func (c *column) Reset(ownLen uint32) {
 	c.ZeroRange(0, ownLen)
+	c.resetTicks(ownLen)
 }
 
 // entityColumn storage for entities in an table.
@@ -0,0 +1,453 @@
+package ecs
+
+// commandKind is the type of a recorded command.
+type commandKind uint8
+
+const (
+	cmdNewEntity commandKind = iota
+	cmdRemoveEntity
+	cmdExchange
+	cmdSetRelations
+)
+
+// command is a single recorded operation of a [CommandBuffer].
+//
+// Component IDs, relations and values are stored as ranges into the buffer's flat slices.
+type command struct {
+	entity     Entity
+	addStart   uint32
+	addEnd     uint32
+	remStart   uint32
+	remEnd     uint32
+	relStart   uint32
+	relEnd     uint32
+	valueStart uint32
+	valueEnd   uint32
+	kind       commandKind
+}
+
+// commandValue refers to a component value stored in a typed [commandStore].
+type commandValue struct {
+	store commandStore
+	index uint32
+}
+
+// commandStore stores component values recorded by typed command builders like [CommandMap].
+type commandStore interface {
+	// apply writes the value at the given index into the entity's component.
+	apply(w *World, entity Entity, index uint32)
+	// reset clears all stored values.
+	reset()
+}
+
+// commandGroup is a helper for applying entity creation grouped by target table.
+type commandGroup struct {
+	table    tableID
+	commands []uint32
+	single   bool // Whether the group's command can't be grouped with others
+}
+
+// CommandBuffer records structural changes for deferred application.
+//
+// Structural changes like entity creation and removal, adding and removing components,
+// and changing relation targets are not possible while the [World] is locked, e.g. during query iteration.
+// A CommandBuffer can be used to record these operations while the world is locked,
+// and to apply them in one go via [CommandBuffer.Apply] afterwards.
+//
+// Recording operations is possible for locked worlds.
+// Only [CommandBuffer.Apply] requires the world to be unlocked.
+//
+// Commands are recorded with the ID-based API, similar to [Unsafe].
+// For typed commands with component values, see [CommandMap], [CommandMap2] and [CommandExchange2].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+type CommandBuffer struct {
+	world     *World
+	commands  []command
+	ids       []ID
+	relations []relationID
+	values    []commandValue
+	stores    []commandStore
+	groups    []commandGroup
+}
+
+// NewCommandBuffer creates a new [CommandBuffer] for the given world.
+func NewCommandBuffer(world *World) *CommandBuffer {
+	return &CommandBuffer{
+		world: world,
+	}
+}
+
+// Len returns the number of recorded commands.
+func (b *CommandBuffer) Len() int {
+	return len(b.commands)
+}
+
+// NewEntity records the creation of a new entity with the given components.
+func (b *CommandBuffer) NewEntity(ids ...ID) {
+	b.NewEntityRel(ids)
+}
+
+// NewEntityRel records the creation of a new entity with the given components and relation targets.
+func (b *CommandBuffer) NewEntityRel(ids []ID, relations ...Relation) {
+	b.record(cmdNewEntity, Entity{}, ids, nil, relations)
+}
+
+// RemoveEntity records the removal of an entity.
+func (b *CommandBuffer) RemoveEntity(entity Entity) {
+	b.record(cmdRemoveEntity, entity, nil, nil, nil)
+}
+
+// Add records adding the given components to an entity.
+func (b *CommandBuffer) Add(entity Entity, ids ...ID) {
+	b.Exchange(entity, ids, nil)
+}
+
+// AddRel records adding the given components and relation targets to an entity.
+func (b *CommandBuffer) AddRel(entity Entity, ids []ID, relations ...Relation) {
+	b.Exchange(entity, ids, nil, relations...)
+}
+
+// Remove records removing the given components from an entity.
+func (b *CommandBuffer) Remove(entity Entity, ids ...ID) {
+	b.Exchange(entity, nil, ids)
+}
+
+// Exchange records adding and removing the given components on an entity.
+func (b *CommandBuffer) Exchange(entity Entity, add []ID, remove []ID, relations ...Relation) {
+	if len(add) == 0 && len(remove) == 0 {
+		panic("at least one component required to add or remove")
+	}
+	b.record(cmdExchange, entity, add, remove, relations)
+}
+
+// SetRelations records setting relation targets for an entity.
+func (b *CommandBuffer) SetRelations(entity Entity, relations ...Relation) {
+	if len(relations) == 0 {
+		panic("no relations specified")
+	}
+	b.record(cmdSetRelations, entity, nil, nil, relations)
+}
+
+// Reset discards all recorded commands without applying them.
+func (b *CommandBuffer) Reset() {
+	b.commands = b.commands[:0]
+	b.ids = b.ids[:0]
+	b.relations = b.relations[:0]
+	b.values = b.values[:0]
+	for _, s := range b.stores {
+		s.reset()
+	}
+}
+
+// Apply applies all recorded commands to the world, in the order they were recorded, and resets the buffer.
+//
+// Consecutive entity creations are grouped by their target table.
+// They use the same batch operations as e.g. [Map2.NewBatchFn], and observers are notified in bulk.
+// Thus, entities created by such a run of commands may be created in a different order than recorded.
+// Relation targets of created entities must be alive when the creation is applied, like for immediate creation.
+//
+// Consecutive commands that add, remove or exchange the same components and relations
+// on entities that move between the same tables are grouped as well, and observers are notified in bulk.
+// Other commands for entities that are not alive anymore when they are applied are skipped.
+//
+// The buffer is also reset if applying a command panics, so that commands are never applied twice.
+//
+// Panics if the world is locked.
+func (b *CommandBuffer) Apply() {
+	b.world.checkLocked()
+	b.applyCommands()
+	b.world.flushEvents()
+}
+
+// applyCommands applies all recorded commands and resets the buffer, also in case of a panic.
+func (b *CommandBuffer) applyCommands() {
+	defer b.Reset()
+
+	for i := 0; i < len(b.commands); {
+		cmd := &b.commands[i]
+		if cmd.kind == cmdNewEntity {
+			end := i + 1
+			for end < len(b.commands) && b.commands[end].kind == cmdNewEntity {
+				end++
+			}
+			b.applyNewEntities(i, end)
+			i = end
+			continue
+		}
+		if !b.world.Alive(cmd.entity) {
+			i++
+			continue
+		}
+		switch cmd.kind {
+		case cmdRemoveEntity:
+			b.world.storage.RemoveEntity(cmd.entity)
+		case cmdExchange:
+			i = b.applyExchanges(i)
+			continue
+		case cmdSetRelations:
+			b.world.setRelations(cmd.entity, b.relations[cmd.relStart:cmd.relEnd])
+		}
+		i++
+	}
+}
+
+// record adds a command with relations given for the unsafe API.
+func (b *CommandBuffer) record(kind commandKind, entity Entity, add []ID, remove []ID, relations []Relation) {
+	relStart := uint32(len(b.relations))
+	b.relations = relationSlice(relations).ToRelationIDsForUnsafe(b.world, b.relations)
+	b.recordIDs(kind, entity, add, remove, relStart)
+}
+
+// recordTyped adds a command with relations given for the typed components to add,
+// as used by typed command builders like [CommandMap2].
+func (b *CommandBuffer) recordTyped(kind commandKind, entity Entity, add []ID, remove []ID, mask *bitMask, relations []Relation) {
+	relStart := uint32(len(b.relations))
+	b.relations = relationSlice(relations).ToRelations(b.world, mask, add, b.relations, false)
+	b.recordIDs(kind, entity, add, remove, relStart)
+}
+
+// recordIDs adds a command. Its relations must already be appended to the buffer's relations, starting at relStart.
+func (b *CommandBuffer) recordIDs(kind commandKind, entity Entity, add []ID, remove []ID, relStart uint32) {
+	cmd := command{
+		kind:   kind,
+		entity: entity,
+	}
+	cmd.addStart = uint32(len(b.ids))
+	b.ids = append(b.ids, add...)
+	cmd.addEnd = uint32(len(b.ids))
+
+	cmd.remStart = cmd.addEnd
+	b.ids = append(b.ids, remove...)
+	cmd.remEnd = uint32(len(b.ids))
+
+	cmd.relStart = relStart
+	cmd.relEnd = uint32(len(b.relations))
+
+	cmd.valueStart, cmd.valueEnd = uint32(len(b.values)), uint32(len(b.values))
+
+	b.commands = append(b.commands, cmd)
+}
+
+// recordValue adds a component value to the last recorded command.
+func (b *CommandBuffer) recordValue(store commandStore, index uint32) {
+	b.values = append(b.values, commandValue{store: store, index: index})
+	b.commands[len(b.commands)-1].valueEnd = uint32(len(b.values))
+}
+
+// registerStore registers a typed value store, so that it is reset together with the buffer.
+func (b *CommandBuffer) registerStore(store commandStore) {
+	b.stores = append(b.stores, store)
+}
+
+// applyValues writes all recorded component values of a command to the given entity.
+func (b *CommandBuffer) applyValues(cmd *command, entity Entity) {
+	for _, v := range b.values[cmd.valueStart:cmd.valueEnd] {
+		v.store.apply(b.world, entity, v.index)
+	}
+}
+
+// applyNewEntities creates the entities of the given range of entity creation commands, grouped by target table.
+func (b *CommandBuffer) applyNewEntities(start, end int) {
+	w := b.world
+	s := &w.storage
+	groups := b.groups[:0]
+	for i := start; i < end; i++ {
+		cmd := &b.commands[i]
+		relations := b.relations[cmd.relStart:cmd.relEnd]
+		tableRelations, multiRelations := s.splitRelations(relations)
+		mask := bitMask{}
+		table, _ := s.findOrCreateTableAdd(&s.tables[0], b.ids[cmd.addStart:cmd.addEnd], tableRelations, &mask)
+		found := false
+		// Entities with the same target of an exclusive symmetric relation can't be created in a batch.
+		// Targets of multi-target relations are not determined by the table.
+		canGroup := !s.hasExclusiveSymmetric(relations) && multiRelations == nil
+		for j := range groups {
+			if canGroup && !groups[j].single && groups[j].table == table.id {
+				groups[j].commands = append(groups[j].commands, uint32(i))
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, commandGroup{
+				table:    table.id,
+				commands: []uint32{uint32(i)},
+				single:   !canGroup,
+			})
+		}
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		cmd := &b.commands[group.commands[0]]
+		relations := b.relations[cmd.relStart:cmd.relEnd]
+		tableID, start := w.newEntities(len(group.commands), b.ids[cmd.addStart:cmd.addEnd], relations)
+
+		lock := w.lock()
+		table := &s.tables[tableID]
+		for j, c := range group.commands {
+			b.applyValues(&b.commands[c], table.GetEntity(uintptr(start+j)))
+		}
+
+		mask := &s.archetypes[table.archetype].mask
+		if s.observers.HasObservers(OnCreateEntity) {
+			s.observers.FireCreateEntityBatch(table, start, mask)
+		}
+		if len(relations) > 0 && s.observers.HasObservers(OnAddRelations) {
+			s.observers.FireCreateEntityRelBatch(table, start, mask)
+		}
+		w.unlock(lock)
+	}
+	clear(groups)
+	b.groups = groups[:0]
+}
+
+// applyExchanges applies a run of component exchange commands, starting at the given index.
+// The run consists of consecutive commands with the same components and relations,
+// for alive entities that move between the same tables.
+// Returns the index of the first command after the run.
+func (b *CommandBuffer) applyExchanges(start int) int {
+	w := b.world
+	s := &w.storage
+	first := &b.commands[start]
+	add := b.ids[first.addStart:first.addEnd]
+	remove := b.ids[first.remStart:first.remEnd]
+	relations := b.relations[first.relStart:first.relEnd]
+
+	// Symmetric relations move their targets, and targets of multi-target relations are not determined by the table.
+	// Thus, moved entities might not end up in consecutive rows of the same table.
+	canGroup := !s.involvesSymmetric(remove, relations) && !s.hasMultiRelation(relations)
+	oldTable := s.entities[first.entity.id].table
+
+	var oldMask, newMask *bitMask
+	end := start
+	for end < len(b.commands) {
+		cmd := &b.commands[end]
+		if end > start && (!canGroup || cmd.kind != cmdExchange || !b.sameExchange(first, cmd) ||
+			!w.Alive(cmd.entity) || s.entities[cmd.entity.id].table != oldTable) {
+			break
+		}
+		if len(add) == 0 {
+			w.remove(cmd.entity, remove)
+		} else if len(remove) == 0 {
+			oldMask, newMask = w.add(cmd.entity, add, relations)
+		} else {
+			oldMask, newMask = w.exchange(cmd.entity, add, remove, relations)
+		}
+		b.applyValues(cmd, cmd.entity)
+		end++
+	}
+	if len(add) == 0 {
+		return end
+	}
+
+	// Moved entities are in consecutive rows at the end of their new table.
+	index := s.entities[b.commands[end-1].entity.id]
+	table := &s.tables[index.table]
+	rowEnd := index.row + 1
+	rowStart := rowEnd - uint32(end-start)
+
+	lock := w.lock()
+	if s.observers.HasObservers(OnAddComponents) {
+		s.observers.FireAddBatch(OnAddComponents, table, rowStart, rowEnd, oldMask, newMask)
+	}
+	if len(relations) > 0 && s.observers.HasObservers(OnAddRelations) {
+		s.observers.FireAddBatch(OnAddRelations, table, rowStart, rowEnd, oldMask, newMask)
+	}
+	w.unlock(lock)
+	return end
+}
+
+// sameExchange returns whether two commands add and remove the same components and set the same relations.
+func (b *CommandBuffer) sameExchange(a, other *command) bool {
+	return sameSlices(b.ids[a.addStart:a.addEnd], b.ids[other.addStart:other.addEnd]) &&
+		sameSlices(b.ids[a.remStart:a.remEnd], b.ids[other.remStart:other.remEnd]) &&
+		sameSlices(b.relations[a.relStart:a.relEnd], b.relations[other.relStart:other.relEnd])
+}
+
+// CommandMap is a typed helper for recording commands with component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+type CommandMap[T any] struct {
+	buffer *CommandBuffer
+	store  *commandValues[T]
+	ids    [1]ID
+}
+
+// NewCommandMap creates a new [CommandMap] for the given [CommandBuffer].
+//
+// Panics if T uses sparse storage (see [StorageSparse]).
+func NewCommandMap[T any](buffer *CommandBuffer) *CommandMap[T] {
+	id := ComponentID[T](buffer.world)
+	buffer.world.storage.checkNotSparse(id, "CommandMap")
+	store := &commandValues[T]{id: id}
+	buffer.registerStore(store)
+	return &CommandMap[T]{
+		buffer: buffer,
+		store:  store,
+		ids:    [1]ID{id},
+	}
+}
+
+// NewEntity records the creation of a new entity with the mapped component.
+//
+// If the mapped component is a relationship (see [RelationMarker]),
+// a relation target entity must be provided.
+func (m *CommandMap[T]) NewEntity(comp *T, target ...Entity) {
+	m.buffer.record(cmdNewEntity, Entity{}, m.ids[:], nil, m.targets(target))
+	m.buffer.recordValue(m.store, m.store.add(comp))
+}
+
+// Add records adding the mapped component to the given entity.
+//
+// If the mapped component is a relationship (see [RelationMarker]),
+// a relation target entity must be provided.
+func (m *CommandMap[T]) Add(entity Entity, comp *T, target ...Entity) {
+	m.buffer.record(cmdExchange, entity, m.ids[:], nil, m.targets(target))
+	m.buffer.recordValue(m.store, m.store.add(comp))
+}
+
+// Remove records removing the mapped component from the given entity.
+func (m *CommandMap[T]) Remove(entity Entity) {
+	m.buffer.record(cmdExchange, entity, nil, m.ids[:], nil)
+}
+
+// SetRelation records setting the relation target for the entity and the mapped component.
+func (m *CommandMap[T]) SetRelation(entity Entity, target Entity) {
+	m.buffer.record(cmdSetRelations, entity, nil, nil, []Relation{RelID(m.ids[0], target)})
+}
+
+// targets converts relation target entities to relations.
+func (m *CommandMap[T]) targets(target []Entity) []Relation {
+	if len(target) == 0 {
+		return nil
+	}
+	relations := make([]Relation, len(target))
+	for i, t := range target {
+		relations[i] = RelID(m.ids[0], t)
+	}
+	return relations
+}
+
+// commandValues is a typed [commandStore].
+type commandValues[T any] struct {
+	values []T
+	id     ID
+}
+
+// add stores a value and returns its index.
+func (s *commandValues[T]) add(comp *T) uint32 {
+	s.values = append(s.values, *comp)
+	return uint32(len(s.values) - 1)
+}
+
+func (s *commandValues[T]) apply(w *World, entity Entity, index uint32) {
+	*(*T)(w.storage.getUnchecked(entity, s.id)) = s.values[index]
+	w.storage.markChanged(entity, s.id)
+}
+
+func (s *commandValues[T]) reset() {
+	clear(s.values)
+	s.values = s.values[:0]
+}
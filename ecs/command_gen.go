@@ -0,0 +1,1933 @@
+package ecs
+
+// Code generated by go generate; DO NOT EDIT.
+
+// CommandMap1 is a typed helper for recording commands with 1 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap1[A any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+}
+
+// New creates a new [CommandMap1]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap1[A]) New(buffer *CommandBuffer) *CommandMap1[A] {
+	return NewCommandMap1[A](buffer)
+}
+
+// NewCommandMap1 creates a new [CommandMap1] for the given [CommandBuffer].
+//
+// See also [CommandMap1.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap1[A any](buffer *CommandBuffer) *CommandMap1[A] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap1")
+	}
+	m := &CommandMap1[A]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+	}
+	buffer.registerStore(m.storeA)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap1[A]) NewEntity(a *A, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap1[A]) Add(entity Entity, a *A, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap1[A]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap1[A]) recordValues(a *A) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+}
+
+// CommandMap2 is a typed helper for recording commands with 2 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+type CommandMap2[A any, B any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+}
+
+// New creates a new [CommandMap2]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap2[A, B]) New(buffer *CommandBuffer) *CommandMap2[A, B] {
+	return NewCommandMap2[A, B](buffer)
+}
+
+// NewCommandMap2 creates a new [CommandMap2] for the given [CommandBuffer].
+//
+// See also [CommandMap2.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap2[A any, B any](buffer *CommandBuffer) *CommandMap2[A, B] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap2")
+	}
+	m := &CommandMap2[A, B]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap2[A, B]) NewEntity(a *A, b *B, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap2[A, B]) Add(entity Entity, a *A, b *B, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap2[A, B]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap2[A, B]) recordValues(a *A, b *B) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+}
+
+// CommandMap3 is a typed helper for recording commands with 3 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap3[A any, B any, C any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+}
+
+// New creates a new [CommandMap3]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap3[A, B, C]) New(buffer *CommandBuffer) *CommandMap3[A, B, C] {
+	return NewCommandMap3[A, B, C](buffer)
+}
+
+// NewCommandMap3 creates a new [CommandMap3] for the given [CommandBuffer].
+//
+// See also [CommandMap3.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap3[A any, B any, C any](buffer *CommandBuffer) *CommandMap3[A, B, C] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap3")
+	}
+	m := &CommandMap3[A, B, C]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap3[A, B, C]) NewEntity(a *A, b *B, c *C, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap3[A, B, C]) Add(entity Entity, a *A, b *B, c *C, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap3[A, B, C]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap3[A, B, C]) recordValues(a *A, b *B, c *C) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+}
+
+// CommandMap4 is a typed helper for recording commands with 4 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap4[A any, B any, C any, D any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+}
+
+// New creates a new [CommandMap4]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap4[A, B, C, D]) New(buffer *CommandBuffer) *CommandMap4[A, B, C, D] {
+	return NewCommandMap4[A, B, C, D](buffer)
+}
+
+// NewCommandMap4 creates a new [CommandMap4] for the given [CommandBuffer].
+//
+// See also [CommandMap4.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap4[A any, B any, C any, D any](buffer *CommandBuffer) *CommandMap4[A, B, C, D] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap4")
+	}
+	m := &CommandMap4[A, B, C, D]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap4[A, B, C, D]) NewEntity(a *A, b *B, c *C, d *D, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap4[A, B, C, D]) Add(entity Entity, a *A, b *B, c *C, d *D, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap4[A, B, C, D]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap4[A, B, C, D]) recordValues(a *A, b *B, c *C, d *D) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+}
+
+// CommandMap5 is a typed helper for recording commands with 5 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap5[A any, B any, C any, D any, E any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+}
+
+// New creates a new [CommandMap5]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap5[A, B, C, D, E]) New(buffer *CommandBuffer) *CommandMap5[A, B, C, D, E] {
+	return NewCommandMap5[A, B, C, D, E](buffer)
+}
+
+// NewCommandMap5 creates a new [CommandMap5] for the given [CommandBuffer].
+//
+// See also [CommandMap5.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap5[A any, B any, C any, D any, E any](buffer *CommandBuffer) *CommandMap5[A, B, C, D, E] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap5")
+	}
+	m := &CommandMap5[A, B, C, D, E]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap5[A, B, C, D, E]) NewEntity(a *A, b *B, c *C, d *D, e *E, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap5[A, B, C, D, E]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap5[A, B, C, D, E]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap5[A, B, C, D, E]) recordValues(a *A, b *B, c *C, d *D, e *E) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+}
+
+// CommandMap6 is a typed helper for recording commands with 6 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap6[A any, B any, C any, D any, E any, F any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+}
+
+// New creates a new [CommandMap6]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap6[A, B, C, D, E, F]) New(buffer *CommandBuffer) *CommandMap6[A, B, C, D, E, F] {
+	return NewCommandMap6[A, B, C, D, E, F](buffer)
+}
+
+// NewCommandMap6 creates a new [CommandMap6] for the given [CommandBuffer].
+//
+// See also [CommandMap6.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap6[A any, B any, C any, D any, E any, F any](buffer *CommandBuffer) *CommandMap6[A, B, C, D, E, F] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap6")
+	}
+	m := &CommandMap6[A, B, C, D, E, F]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap6[A, B, C, D, E, F]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap6[A, B, C, D, E, F]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap6[A, B, C, D, E, F]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap6[A, B, C, D, E, F]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+}
+
+// CommandMap7 is a typed helper for recording commands with 7 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap7[A any, B any, C any, D any, E any, F any, G any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+}
+
+// New creates a new [CommandMap7]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap7[A, B, C, D, E, F, G]) New(buffer *CommandBuffer) *CommandMap7[A, B, C, D, E, F, G] {
+	return NewCommandMap7[A, B, C, D, E, F, G](buffer)
+}
+
+// NewCommandMap7 creates a new [CommandMap7] for the given [CommandBuffer].
+//
+// See also [CommandMap7.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap7[A any, B any, C any, D any, E any, F any, G any](buffer *CommandBuffer) *CommandMap7[A, B, C, D, E, F, G] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap7")
+	}
+	m := &CommandMap7[A, B, C, D, E, F, G]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	buffer.registerStore(m.storeG)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap7[A, B, C, D, E, F, G]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, g *G, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap7[A, B, C, D, E, F, G]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap7[A, B, C, D, E, F, G]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap7[A, B, C, D, E, F, G]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+	m.buffer.recordValue(m.storeG, m.storeG.add(g))
+}
+
+// CommandMap8 is a typed helper for recording commands with 8 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap8[A any, B any, C any, D any, E any, F any, G any, H any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+	storeH *commandValues[H]
+}
+
+// New creates a new [CommandMap8]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap8[A, B, C, D, E, F, G, H]) New(buffer *CommandBuffer) *CommandMap8[A, B, C, D, E, F, G, H] {
+	return NewCommandMap8[A, B, C, D, E, F, G, H](buffer)
+}
+
+// NewCommandMap8 creates a new [CommandMap8] for the given [CommandBuffer].
+//
+// See also [CommandMap8.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap8[A any, B any, C any, D any, E any, F any, G any, H any](buffer *CommandBuffer) *CommandMap8[A, B, C, D, E, F, G, H] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+		ComponentID[H](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap8")
+	}
+	m := &CommandMap8[A, B, C, D, E, F, G, H]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+		storeH: &commandValues[H]{id: ids[7]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	buffer.registerStore(m.storeG)
+	buffer.registerStore(m.storeH)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap8[A, B, C, D, E, F, G, H]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap8[A, B, C, D, E, F, G, H]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap8[A, B, C, D, E, F, G, H]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap8[A, B, C, D, E, F, G, H]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+	m.buffer.recordValue(m.storeG, m.storeG.add(g))
+	m.buffer.recordValue(m.storeH, m.storeH.add(h))
+}
+
+// CommandMap9 is a typed helper for recording commands with 9 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap9[A any, B any, C any, D any, E any, F any, G any, H any, I any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+	storeH *commandValues[H]
+	storeI *commandValues[I]
+}
+
+// New creates a new [CommandMap9]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap9[A, B, C, D, E, F, G, H, I]) New(buffer *CommandBuffer) *CommandMap9[A, B, C, D, E, F, G, H, I] {
+	return NewCommandMap9[A, B, C, D, E, F, G, H, I](buffer)
+}
+
+// NewCommandMap9 creates a new [CommandMap9] for the given [CommandBuffer].
+//
+// See also [CommandMap9.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap9[A any, B any, C any, D any, E any, F any, G any, H any, I any](buffer *CommandBuffer) *CommandMap9[A, B, C, D, E, F, G, H, I] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+		ComponentID[H](buffer.world),
+		ComponentID[I](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap9")
+	}
+	m := &CommandMap9[A, B, C, D, E, F, G, H, I]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+		storeH: &commandValues[H]{id: ids[7]},
+		storeI: &commandValues[I]{id: ids[8]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	buffer.registerStore(m.storeG)
+	buffer.registerStore(m.storeH)
+	buffer.registerStore(m.storeI)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap9[A, B, C, D, E, F, G, H, I]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap9[A, B, C, D, E, F, G, H, I]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap9[A, B, C, D, E, F, G, H, I]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap9[A, B, C, D, E, F, G, H, I]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+	m.buffer.recordValue(m.storeG, m.storeG.add(g))
+	m.buffer.recordValue(m.storeH, m.storeH.add(h))
+	m.buffer.recordValue(m.storeI, m.storeI.add(i))
+}
+
+// CommandMap10 is a typed helper for recording commands with 10 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap10[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+	storeH *commandValues[H]
+	storeI *commandValues[I]
+	storeJ *commandValues[J]
+}
+
+// New creates a new [CommandMap10]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap10[A, B, C, D, E, F, G, H, I, J]) New(buffer *CommandBuffer) *CommandMap10[A, B, C, D, E, F, G, H, I, J] {
+	return NewCommandMap10[A, B, C, D, E, F, G, H, I, J](buffer)
+}
+
+// NewCommandMap10 creates a new [CommandMap10] for the given [CommandBuffer].
+//
+// See also [CommandMap10.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap10[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any](buffer *CommandBuffer) *CommandMap10[A, B, C, D, E, F, G, H, I, J] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+		ComponentID[H](buffer.world),
+		ComponentID[I](buffer.world),
+		ComponentID[J](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap10")
+	}
+	m := &CommandMap10[A, B, C, D, E, F, G, H, I, J]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+		storeH: &commandValues[H]{id: ids[7]},
+		storeI: &commandValues[I]{id: ids[8]},
+		storeJ: &commandValues[J]{id: ids[9]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	buffer.registerStore(m.storeG)
+	buffer.registerStore(m.storeH)
+	buffer.registerStore(m.storeI)
+	buffer.registerStore(m.storeJ)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap10[A, B, C, D, E, F, G, H, I, J]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i, j)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap10[A, B, C, D, E, F, G, H, I, J]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i, j)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap10[A, B, C, D, E, F, G, H, I, J]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap10[A, B, C, D, E, F, G, H, I, J]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+	m.buffer.recordValue(m.storeG, m.storeG.add(g))
+	m.buffer.recordValue(m.storeH, m.storeH.add(h))
+	m.buffer.recordValue(m.storeI, m.storeI.add(i))
+	m.buffer.recordValue(m.storeJ, m.storeJ.add(j))
+}
+
+// CommandMap11 is a typed helper for recording commands with 11 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap11[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any, K any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+	storeH *commandValues[H]
+	storeI *commandValues[I]
+	storeJ *commandValues[J]
+	storeK *commandValues[K]
+}
+
+// New creates a new [CommandMap11]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap11[A, B, C, D, E, F, G, H, I, J, K]) New(buffer *CommandBuffer) *CommandMap11[A, B, C, D, E, F, G, H, I, J, K] {
+	return NewCommandMap11[A, B, C, D, E, F, G, H, I, J, K](buffer)
+}
+
+// NewCommandMap11 creates a new [CommandMap11] for the given [CommandBuffer].
+//
+// See also [CommandMap11.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap11[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any, K any](buffer *CommandBuffer) *CommandMap11[A, B, C, D, E, F, G, H, I, J, K] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+		ComponentID[H](buffer.world),
+		ComponentID[I](buffer.world),
+		ComponentID[J](buffer.world),
+		ComponentID[K](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap11")
+	}
+	m := &CommandMap11[A, B, C, D, E, F, G, H, I, J, K]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+		storeH: &commandValues[H]{id: ids[7]},
+		storeI: &commandValues[I]{id: ids[8]},
+		storeJ: &commandValues[J]{id: ids[9]},
+		storeK: &commandValues[K]{id: ids[10]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	buffer.registerStore(m.storeG)
+	buffer.registerStore(m.storeH)
+	buffer.registerStore(m.storeI)
+	buffer.registerStore(m.storeJ)
+	buffer.registerStore(m.storeK)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap11[A, B, C, D, E, F, G, H, I, J, K]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i, j, k)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap11[A, B, C, D, E, F, G, H, I, J, K]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i, j, k)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap11[A, B, C, D, E, F, G, H, I, J, K]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap11[A, B, C, D, E, F, G, H, I, J, K]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+	m.buffer.recordValue(m.storeG, m.storeG.add(g))
+	m.buffer.recordValue(m.storeH, m.storeH.add(h))
+	m.buffer.recordValue(m.storeI, m.storeI.add(i))
+	m.buffer.recordValue(m.storeJ, m.storeJ.add(j))
+	m.buffer.recordValue(m.storeK, m.storeK.add(k))
+}
+
+// CommandMap12 is a typed helper for recording commands with 12 component values in a [CommandBuffer].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandMap2] for a usage example.
+type CommandMap12[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any, K any, L any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+	storeH *commandValues[H]
+	storeI *commandValues[I]
+	storeJ *commandValues[J]
+	storeK *commandValues[K]
+	storeL *commandValues[L]
+}
+
+// New creates a new [CommandMap12]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L]) New(buffer *CommandBuffer) *CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L] {
+	return NewCommandMap12[A, B, C, D, E, F, G, H, I, J, K, L](buffer)
+}
+
+// NewCommandMap12 creates a new [CommandMap12] for the given [CommandBuffer].
+//
+// See also [CommandMap12.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandMap12[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any, K any, L any](buffer *CommandBuffer) *CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+		ComponentID[H](buffer.world),
+		ComponentID[I](buffer.world),
+		ComponentID[J](buffer.world),
+		ComponentID[K](buffer.world),
+		ComponentID[L](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandMap12")
+	}
+	m := &CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+		storeH: &commandValues[H]{id: ids[7]},
+		storeI: &commandValues[I]{id: ids[8]},
+		storeJ: &commandValues[J]{id: ids[9]},
+		storeK: &commandValues[K]{id: ids[10]},
+		storeL: &commandValues[L]{id: ids[11]},
+	}
+	buffer.registerStore(m.storeA)
+	buffer.registerStore(m.storeB)
+	buffer.registerStore(m.storeC)
+	buffer.registerStore(m.storeD)
+	buffer.registerStore(m.storeE)
+	buffer.registerStore(m.storeF)
+	buffer.registerStore(m.storeG)
+	buffer.registerStore(m.storeH)
+	buffer.registerStore(m.storeI)
+	buffer.registerStore(m.storeJ)
+	buffer.registerStore(m.storeK)
+	buffer.registerStore(m.storeL)
+	return m
+}
+
+// NewEntity records the creation of a new entity with the mapped components.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L]) NewEntity(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K, l *L, rel ...Relation) {
+	m.buffer.recordTyped(cmdNewEntity, Entity{}, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i, j, k, l)
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (m *CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K, l *L, rel ...Relation) {
+	m.buffer.recordTyped(cmdExchange, entity, m.ids, nil, &m.mask, rel)
+	m.recordValues(a, b, c, d, e, f, g, h, i, j, k, l)
+}
+
+// Remove records removing the mapped components from the given entity.
+func (m *CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L]) Remove(entity Entity) {
+	m.buffer.recordIDs(cmdExchange, entity, nil, m.ids, uint32(len(m.buffer.relations)))
+}
+
+// recordValues adds the component values to the last recorded command.
+func (m *CommandMap12[A, B, C, D, E, F, G, H, I, J, K, L]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, i *I, j *J, k *K, l *L) {
+	m.buffer.recordValue(m.storeA, m.storeA.add(a))
+	m.buffer.recordValue(m.storeB, m.storeB.add(b))
+	m.buffer.recordValue(m.storeC, m.storeC.add(c))
+	m.buffer.recordValue(m.storeD, m.storeD.add(d))
+	m.buffer.recordValue(m.storeE, m.storeE.add(e))
+	m.buffer.recordValue(m.storeF, m.storeF.add(f))
+	m.buffer.recordValue(m.storeG, m.storeG.add(g))
+	m.buffer.recordValue(m.storeH, m.storeH.add(h))
+	m.buffer.recordValue(m.storeI, m.storeI.add(i))
+	m.buffer.recordValue(m.storeJ, m.storeJ.add(j))
+	m.buffer.recordValue(m.storeK, m.storeK.add(k))
+	m.buffer.recordValue(m.storeL, m.storeL.add(l))
+}
+
+// CommandExchange1 is a typed helper for recording component exchanges with 1 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange1.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange1[A any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+}
+
+// New creates a new [CommandExchange1]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange1[A]) New(buffer *CommandBuffer) *CommandExchange1[A] {
+	return NewCommandExchange1[A](buffer)
+}
+
+// NewCommandExchange1 creates a new [CommandExchange1] for the given [CommandBuffer].
+//
+// See also [CommandExchange1.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange1[A any](buffer *CommandBuffer) *CommandExchange1[A] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange1")
+	}
+	ex := &CommandExchange1[A]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+	}
+	buffer.registerStore(ex.storeA)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange1] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange1[A]) Removes(components ...Comp) *CommandExchange1[A] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange1[A]) Add(entity Entity, a *A, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a)
+}
+
+// Remove records removing the components previously specified with [CommandExchange1.Removes] from the given entity.
+func (ex *CommandExchange1[A]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange1.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange1[A]) Exchange(entity Entity, a *A, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange1[A]) recordValues(a *A) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+}
+
+// CommandExchange2 is a typed helper for recording component exchanges with 2 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange2.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+type CommandExchange2[A any, B any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+}
+
+// New creates a new [CommandExchange2]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange2[A, B]) New(buffer *CommandBuffer) *CommandExchange2[A, B] {
+	return NewCommandExchange2[A, B](buffer)
+}
+
+// NewCommandExchange2 creates a new [CommandExchange2] for the given [CommandBuffer].
+//
+// See also [CommandExchange2.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange2[A any, B any](buffer *CommandBuffer) *CommandExchange2[A, B] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange2")
+	}
+	ex := &CommandExchange2[A, B]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange2] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange2[A, B]) Removes(components ...Comp) *CommandExchange2[A, B] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange2[A, B]) Add(entity Entity, a *A, b *B, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b)
+}
+
+// Remove records removing the components previously specified with [CommandExchange2.Removes] from the given entity.
+func (ex *CommandExchange2[A, B]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange2.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange2[A, B]) Exchange(entity Entity, a *A, b *B, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange2[A, B]) recordValues(a *A, b *B) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+}
+
+// CommandExchange3 is a typed helper for recording component exchanges with 3 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange3.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange3[A any, B any, C any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+}
+
+// New creates a new [CommandExchange3]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange3[A, B, C]) New(buffer *CommandBuffer) *CommandExchange3[A, B, C] {
+	return NewCommandExchange3[A, B, C](buffer)
+}
+
+// NewCommandExchange3 creates a new [CommandExchange3] for the given [CommandBuffer].
+//
+// See also [CommandExchange3.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange3[A any, B any, C any](buffer *CommandBuffer) *CommandExchange3[A, B, C] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange3")
+	}
+	ex := &CommandExchange3[A, B, C]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	buffer.registerStore(ex.storeC)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange3] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange3[A, B, C]) Removes(components ...Comp) *CommandExchange3[A, B, C] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange3[A, B, C]) Add(entity Entity, a *A, b *B, c *C, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b, c)
+}
+
+// Remove records removing the components previously specified with [CommandExchange3.Removes] from the given entity.
+func (ex *CommandExchange3[A, B, C]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange3.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange3[A, B, C]) Exchange(entity Entity, a *A, b *B, c *C, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b, c)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange3[A, B, C]) recordValues(a *A, b *B, c *C) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+	ex.buffer.recordValue(ex.storeC, ex.storeC.add(c))
+}
+
+// CommandExchange4 is a typed helper for recording component exchanges with 4 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange4.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange4[A any, B any, C any, D any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+}
+
+// New creates a new [CommandExchange4]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange4[A, B, C, D]) New(buffer *CommandBuffer) *CommandExchange4[A, B, C, D] {
+	return NewCommandExchange4[A, B, C, D](buffer)
+}
+
+// NewCommandExchange4 creates a new [CommandExchange4] for the given [CommandBuffer].
+//
+// See also [CommandExchange4.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange4[A any, B any, C any, D any](buffer *CommandBuffer) *CommandExchange4[A, B, C, D] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange4")
+	}
+	ex := &CommandExchange4[A, B, C, D]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	buffer.registerStore(ex.storeC)
+	buffer.registerStore(ex.storeD)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange4] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange4[A, B, C, D]) Removes(components ...Comp) *CommandExchange4[A, B, C, D] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange4[A, B, C, D]) Add(entity Entity, a *A, b *B, c *C, d *D, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b, c, d)
+}
+
+// Remove records removing the components previously specified with [CommandExchange4.Removes] from the given entity.
+func (ex *CommandExchange4[A, B, C, D]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange4.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange4[A, B, C, D]) Exchange(entity Entity, a *A, b *B, c *C, d *D, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b, c, d)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange4[A, B, C, D]) recordValues(a *A, b *B, c *C, d *D) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+	ex.buffer.recordValue(ex.storeC, ex.storeC.add(c))
+	ex.buffer.recordValue(ex.storeD, ex.storeD.add(d))
+}
+
+// CommandExchange5 is a typed helper for recording component exchanges with 5 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange5.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange5[A any, B any, C any, D any, E any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+}
+
+// New creates a new [CommandExchange5]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange5[A, B, C, D, E]) New(buffer *CommandBuffer) *CommandExchange5[A, B, C, D, E] {
+	return NewCommandExchange5[A, B, C, D, E](buffer)
+}
+
+// NewCommandExchange5 creates a new [CommandExchange5] for the given [CommandBuffer].
+//
+// See also [CommandExchange5.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange5[A any, B any, C any, D any, E any](buffer *CommandBuffer) *CommandExchange5[A, B, C, D, E] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange5")
+	}
+	ex := &CommandExchange5[A, B, C, D, E]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	buffer.registerStore(ex.storeC)
+	buffer.registerStore(ex.storeD)
+	buffer.registerStore(ex.storeE)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange5] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange5[A, B, C, D, E]) Removes(components ...Comp) *CommandExchange5[A, B, C, D, E] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange5[A, B, C, D, E]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e)
+}
+
+// Remove records removing the components previously specified with [CommandExchange5.Removes] from the given entity.
+func (ex *CommandExchange5[A, B, C, D, E]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange5.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange5[A, B, C, D, E]) Exchange(entity Entity, a *A, b *B, c *C, d *D, e *E, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange5[A, B, C, D, E]) recordValues(a *A, b *B, c *C, d *D, e *E) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+	ex.buffer.recordValue(ex.storeC, ex.storeC.add(c))
+	ex.buffer.recordValue(ex.storeD, ex.storeD.add(d))
+	ex.buffer.recordValue(ex.storeE, ex.storeE.add(e))
+}
+
+// CommandExchange6 is a typed helper for recording component exchanges with 6 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange6.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange6[A any, B any, C any, D any, E any, F any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+}
+
+// New creates a new [CommandExchange6]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange6[A, B, C, D, E, F]) New(buffer *CommandBuffer) *CommandExchange6[A, B, C, D, E, F] {
+	return NewCommandExchange6[A, B, C, D, E, F](buffer)
+}
+
+// NewCommandExchange6 creates a new [CommandExchange6] for the given [CommandBuffer].
+//
+// See also [CommandExchange6.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange6[A any, B any, C any, D any, E any, F any](buffer *CommandBuffer) *CommandExchange6[A, B, C, D, E, F] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange6")
+	}
+	ex := &CommandExchange6[A, B, C, D, E, F]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	buffer.registerStore(ex.storeC)
+	buffer.registerStore(ex.storeD)
+	buffer.registerStore(ex.storeE)
+	buffer.registerStore(ex.storeF)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange6] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange6[A, B, C, D, E, F]) Removes(components ...Comp) *CommandExchange6[A, B, C, D, E, F] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange6[A, B, C, D, E, F]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e, f)
+}
+
+// Remove records removing the components previously specified with [CommandExchange6.Removes] from the given entity.
+func (ex *CommandExchange6[A, B, C, D, E, F]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange6.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange6[A, B, C, D, E, F]) Exchange(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e, f)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange6[A, B, C, D, E, F]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+	ex.buffer.recordValue(ex.storeC, ex.storeC.add(c))
+	ex.buffer.recordValue(ex.storeD, ex.storeD.add(d))
+	ex.buffer.recordValue(ex.storeE, ex.storeE.add(e))
+	ex.buffer.recordValue(ex.storeF, ex.storeF.add(f))
+}
+
+// CommandExchange7 is a typed helper for recording component exchanges with 7 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange7.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange7[A any, B any, C any, D any, E any, F any, G any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+}
+
+// New creates a new [CommandExchange7]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange7[A, B, C, D, E, F, G]) New(buffer *CommandBuffer) *CommandExchange7[A, B, C, D, E, F, G] {
+	return NewCommandExchange7[A, B, C, D, E, F, G](buffer)
+}
+
+// NewCommandExchange7 creates a new [CommandExchange7] for the given [CommandBuffer].
+//
+// See also [CommandExchange7.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange7[A any, B any, C any, D any, E any, F any, G any](buffer *CommandBuffer) *CommandExchange7[A, B, C, D, E, F, G] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange7")
+	}
+	ex := &CommandExchange7[A, B, C, D, E, F, G]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	buffer.registerStore(ex.storeC)
+	buffer.registerStore(ex.storeD)
+	buffer.registerStore(ex.storeE)
+	buffer.registerStore(ex.storeF)
+	buffer.registerStore(ex.storeG)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange7] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange7[A, B, C, D, E, F, G]) Removes(components ...Comp) *CommandExchange7[A, B, C, D, E, F, G] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange7[A, B, C, D, E, F, G]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e, f, g)
+}
+
+// Remove records removing the components previously specified with [CommandExchange7.Removes] from the given entity.
+func (ex *CommandExchange7[A, B, C, D, E, F, G]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange7.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange7[A, B, C, D, E, F, G]) Exchange(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e, f, g)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange7[A, B, C, D, E, F, G]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+	ex.buffer.recordValue(ex.storeC, ex.storeC.add(c))
+	ex.buffer.recordValue(ex.storeD, ex.storeD.add(d))
+	ex.buffer.recordValue(ex.storeE, ex.storeE.add(e))
+	ex.buffer.recordValue(ex.storeF, ex.storeF.add(f))
+	ex.buffer.recordValue(ex.storeG, ex.storeG.add(g))
+}
+
+// CommandExchange8 is a typed helper for recording component exchanges with 8 component values in a [CommandBuffer].
+// It adds the given components. Use [CommandExchange8.Removes]
+// to set components to be removed.
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+//
+// See [CommandExchange2] for a usage example.
+type CommandExchange8[A any, B any, C any, D any, E any, F any, G any, H any] struct {
+	buffer *CommandBuffer
+	ids    []ID
+	remove []ID
+	mask   bitMask
+	storeA *commandValues[A]
+	storeB *commandValues[B]
+	storeC *commandValues[C]
+	storeD *commandValues[D]
+	storeE *commandValues[E]
+	storeF *commandValues[F]
+	storeG *commandValues[G]
+	storeH *commandValues[H]
+}
+
+// New creates a new [CommandExchange8]. It is safe to call on `nil` instance.
+// It is a helper method, intended to avoid repeated listing of type parameters.
+func (*CommandExchange8[A, B, C, D, E, F, G, H]) New(buffer *CommandBuffer) *CommandExchange8[A, B, C, D, E, F, G, H] {
+	return NewCommandExchange8[A, B, C, D, E, F, G, H](buffer)
+}
+
+// NewCommandExchange8 creates a new [CommandExchange8] for the given [CommandBuffer].
+//
+// See also [CommandExchange8.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+func NewCommandExchange8[A any, B any, C any, D any, E any, F any, G any, H any](buffer *CommandBuffer) *CommandExchange8[A, B, C, D, E, F, G, H] {
+	ids := []ID{
+		ComponentID[A](buffer.world),
+		ComponentID[B](buffer.world),
+		ComponentID[C](buffer.world),
+		ComponentID[D](buffer.world),
+		ComponentID[E](buffer.world),
+		ComponentID[F](buffer.world),
+		ComponentID[G](buffer.world),
+		ComponentID[H](buffer.world),
+	}
+	for _, id := range ids {
+		buffer.world.storage.checkNotSparse(id, "CommandExchange8")
+	}
+	ex := &CommandExchange8[A, B, C, D, E, F, G, H]{
+		buffer: buffer,
+		ids:    ids,
+		mask:   newMask(ids...),
+		storeA: &commandValues[A]{id: ids[0]},
+		storeB: &commandValues[B]{id: ids[1]},
+		storeC: &commandValues[C]{id: ids[2]},
+		storeD: &commandValues[D]{id: ids[3]},
+		storeE: &commandValues[E]{id: ids[4]},
+		storeF: &commandValues[F]{id: ids[5]},
+		storeG: &commandValues[G]{id: ids[6]},
+		storeH: &commandValues[H]{id: ids[7]},
+	}
+	buffer.registerStore(ex.storeA)
+	buffer.registerStore(ex.storeB)
+	buffer.registerStore(ex.storeC)
+	buffer.registerStore(ex.storeD)
+	buffer.registerStore(ex.storeE)
+	buffer.registerStore(ex.storeF)
+	buffer.registerStore(ex.storeG)
+	buffer.registerStore(ex.storeH)
+	return ex
+}
+
+// Removes sets the components that this [CommandExchange8] removes.
+// Can be called multiple times in chains, or once with multiple arguments.
+func (ex *CommandExchange8[A, B, C, D, E, F, G, H]) Removes(components ...Comp) *CommandExchange8[A, B, C, D, E, F, G, H] {
+	for _, c := range components {
+		ex.remove = append(ex.remove, ex.buffer.world.componentID(c.tp))
+	}
+	return ex
+}
+
+// Add records adding the mapped components to the given entity.
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange8[A, B, C, D, E, F, G, H]) Add(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, nil, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e, f, g, h)
+}
+
+// Remove records removing the components previously specified with [CommandExchange8.Removes] from the given entity.
+func (ex *CommandExchange8[A, B, C, D, E, F, G, H]) Remove(entity Entity) {
+	ex.buffer.recordIDs(cmdExchange, entity, nil, ex.remove, uint32(len(ex.buffer.relations)))
+}
+
+// Exchange records the exchange on the given entity, adding the provided components
+// and removing those previously specified with [CommandExchange8.Removes].
+//
+// For each mapped component that is a relationships (see [RelationMarker]),
+// a relation target entity must be provided via the variadic arguments.
+func (ex *CommandExchange8[A, B, C, D, E, F, G, H]) Exchange(entity Entity, a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H, rel ...Relation) {
+	ex.buffer.recordTyped(cmdExchange, entity, ex.ids, ex.remove, &ex.mask, rel)
+	ex.recordValues(a, b, c, d, e, f, g, h)
+}
+
+// recordValues adds the component values to the last recorded command.
+func (ex *CommandExchange8[A, B, C, D, E, F, G, H]) recordValues(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H) {
+	ex.buffer.recordValue(ex.storeA, ex.storeA.add(a))
+	ex.buffer.recordValue(ex.storeB, ex.storeB.add(b))
+	ex.buffer.recordValue(ex.storeC, ex.storeC.add(c))
+	ex.buffer.recordValue(ex.storeD, ex.storeD.add(d))
+	ex.buffer.recordValue(ex.storeE, ex.storeE.add(e))
+	ex.buffer.recordValue(ex.storeF, ex.storeF.add(f))
+	ex.buffer.recordValue(ex.storeG, ex.storeG.add(g))
+	ex.buffer.recordValue(ex.storeH, ex.storeH.add(h))
+}
@@ -0,0 +1,961 @@
+package ecs
+
+// Code generated by go generate; DO NOT EDIT.
+
+import "testing"
+
+func TestCommandMap1(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap1[CompA](w)
+
+	var m *CommandMap1[CompA]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{})
+
+	m.NewEntity(&CompA{})
+	m.Add(e1, &CompA{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap1Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap1[ChildOf](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap2(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap2[CompA, CompB](w)
+
+	var m *CommandMap2[CompA, CompB]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{})
+
+	m.NewEntity(&CompA{}, &CompB{})
+	m.Add(e1, &CompA{}, &CompB{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap2Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap2[ChildOf, CompB](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap3(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap3[CompA, CompB, CompC](w)
+
+	var m *CommandMap3[CompA, CompB, CompC]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap3Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap3[ChildOf, CompB, CompC](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap4(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap4[CompA, CompB, CompC, CompD](w)
+
+	var m *CommandMap4[CompA, CompB, CompC, CompD]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap4Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap4[ChildOf, CompB, CompC, CompD](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap5(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap5[CompA, CompB, CompC, CompD, CompE](w)
+
+	var m *CommandMap5[CompA, CompB, CompC, CompD, CompE]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap5Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap5[ChildOf, CompB, CompC, CompD, CompE](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap6(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap6[CompA, CompB, CompC, CompD, CompE, CompF](w)
+
+	var m *CommandMap6[CompA, CompB, CompC, CompD, CompE, CompF]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap6Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap6[ChildOf, CompB, CompC, CompD, CompE, CompF](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap7(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w)
+
+	var m *CommandMap7[CompA, CompB, CompC, CompD, CompE, CompF, CompG]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap7Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap7[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap8(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w)
+
+	var m *CommandMap8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap8Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap8[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap9(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap9[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI](w)
+
+	var m *CommandMap9[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap9Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap9[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap10(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap10[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ](w)
+
+	var m *CommandMap10[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap10Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap10[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap11(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap11[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ, CompK](w)
+
+	var m *CommandMap11[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ, CompK]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap11Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap11[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ, CompK](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap12(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap1[Position](w)
+	mapper := NewMap12[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ, CompK, CompL](w)
+
+	var m *CommandMap12[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ, CompK, CompL]
+	m = m.New(cmd)
+
+	e1 := posMap.NewEntity(&Position{})
+	e2 := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, &CompL{})
+
+	m.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, &CompL{})
+	m.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, &CompL{})
+	m.Remove(e2)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, mapper.HasAll(e1))
+	expectTrue(t, posMap.HasAll(e1))
+	expectFalse(t, mapper.HasAll(e2))
+	query := NewFilter1[CompA](w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandMap12Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	m := NewCommandMap12[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH, CompI, CompJ, CompK, CompL](cmd)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	m.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, &CompL{}, RelIdx(0, parent))
+	m.Add(e, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, &CompL{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e, 0))
+	query := NewFilter1[ChildOf](w).Relations(RelIdx(0, parent)).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandExchange1(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap1[CompA](w)
+
+	var ex *CommandExchange1[CompA]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{})
+	ex.Exchange(e2, &CompA{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange1Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange1[ChildOf](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange2(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap2[CompA, CompB](w)
+
+	var ex *CommandExchange2[CompA, CompB]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{})
+	ex.Exchange(e2, &CompA{}, &CompB{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange2Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange2[ChildOf, CompB](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange3(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap3[CompA, CompB, CompC](w)
+
+	var ex *CommandExchange3[CompA, CompB, CompC]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{}, &CompC{})
+	ex.Exchange(e2, &CompA{}, &CompB{}, &CompC{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange3Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange3[ChildOf, CompB, CompC](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange4(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap4[CompA, CompB, CompC, CompD](w)
+
+	var ex *CommandExchange4[CompA, CompB, CompC, CompD]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{})
+	ex.Exchange(e2, &CompA{}, &CompB{}, &CompC{}, &CompD{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange4Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange4[ChildOf, CompB, CompC, CompD](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange5(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap5[CompA, CompB, CompC, CompD, CompE](w)
+
+	var ex *CommandExchange5[CompA, CompB, CompC, CompD, CompE]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	ex.Exchange(e2, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange5Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange5[ChildOf, CompB, CompC, CompD, CompE](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange6(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap6[CompA, CompB, CompC, CompD, CompE, CompF](w)
+
+	var ex *CommandExchange6[CompA, CompB, CompC, CompD, CompE, CompF]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	ex.Exchange(e2, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange6Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange6[ChildOf, CompB, CompC, CompD, CompE, CompF](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange7(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w)
+
+	var ex *CommandExchange7[CompA, CompB, CompC, CompD, CompE, CompF, CompG]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	ex.Exchange(e2, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange7Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange7[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
+
+func TestCommandExchange8(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	posMap := NewMap2[Position, Velocity](w)
+	mapper := NewMap8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w)
+
+	var ex *CommandExchange8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH]
+	ex = ex.New(cmd).Removes(C[Velocity](), C[Position]())
+
+	e1 := posMap.NewEntity(&Position{}, &Velocity{})
+	e2 := posMap.NewEntity(&Position{}, &Velocity{})
+	e3 := posMap.NewEntity(&Position{}, &Velocity{})
+
+	ex.Add(e1, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	ex.Exchange(e2, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	ex.Remove(e3)
+	expectEqual(t, 3, cmd.Len())
+	cmd.Apply()
+
+	expectTrue(t, posMap.HasAll(e1))
+	expectTrue(t, mapper.HasAll(e1))
+	expectFalse(t, posMap.HasAll(e2))
+	expectTrue(t, mapper.HasAll(e2))
+	expectFalse(t, posMap.HasAll(e3))
+	expectTrue(t, w.Alive(e3))
+}
+
+func TestCommandExchange8Relations(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+
+	mapper := NewMap1[ChildOf](w)
+	posMap := NewMap1[Position](w)
+	ex := NewCommandExchange8[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH](cmd).Removes(C[Position]())
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity()
+	e2 := posMap.NewEntity(&Position{})
+
+	ex.Add(e1, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, parent))
+	ex.Exchange(e2, &ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, parent))
+	cmd.Apply()
+
+	expectEqual(t, parent, mapper.GetRelation(e1, 0))
+	expectEqual(t, parent, mapper.GetRelation(e2, 0))
+	expectFalse(t, posMap.HasAll(e2))
+}
@@ -0,0 +1,398 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCommandBuffer(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+	velID := ComponentID[Velocity](w)
+
+	cmd := NewCommandBuffer(w)
+	posMap := NewCommandMap[Position](cmd)
+	mapper := NewMap2[Position, Velocity](w)
+
+	e1 := mapper.NewEntity(&Position{1, 2}, &Velocity{3, 4})
+	e2 := mapper.NewEntity(&Position{5, 6}, &Velocity{7, 8})
+
+	filter := NewFilter2[Position, Velocity](w)
+	query := filter.Query()
+	for query.Next() {
+		cmd.NewEntity(posID, velID)
+		posMap.NewEntity(&Position{10, 20})
+		cmd.Remove(query.Entity(), velID)
+	}
+	cmd.RemoveEntity(e2)
+	expectEqual(t, 7, cmd.Len())
+
+	cmd.Apply()
+	expectEqual(t, 0, cmd.Len())
+
+	expectFalse(t, w.Alive(e2))
+	expectTrue(t, w.Alive(e1))
+	expectFalse(t, w.Unsafe().Has(e1, velID))
+
+	posFilter := NewFilter1[Position](w).Without(C[Velocity]())
+	query2 := posFilter.Query()
+	cnt := 0
+	for query2.Next() {
+		pos := query2.Get()
+		if query2.Entity() == e1 {
+			expectEqual(t, Position{1, 2}, *pos)
+		} else {
+			expectEqual(t, Position{10, 20}, *pos)
+		}
+		cnt++
+	}
+	expectEqual(t, 3, cnt)
+	query = filter.Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandBufferMap(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+	posMap := NewCommandMap[Position](cmd)
+	headMap := NewCommandMap[Heading](cmd)
+	velMap := NewMap[Velocity](w)
+
+	e := velMap.NewEntity(&Velocity{1, 2})
+	posMap.Add(e, &Position{3, 4})
+	headMap.Add(e, &Heading{5})
+	cmd.Apply()
+
+	expectEqual(t, Position{3, 4}, *NewMap[Position](w).Get(e))
+	expectEqual(t, Heading{5}, *NewMap[Heading](w).Get(e))
+
+	posMap.Remove(e)
+	cmd.Exchange(e, nil, []ID{ComponentID[Heading](w)})
+	cmd.Apply()
+	expectFalse(t, NewMap[Position](w).Has(e))
+	expectFalse(t, NewMap[Heading](w).Has(e))
+
+	posMap.Add(e, &Position{1, 1})
+	cmd.Reset()
+	cmd.Apply()
+	expectFalse(t, NewMap[Position](w).Has(e))
+
+	expectPanicsWithValue(t, "at least one component required to add or remove", func() {
+		cmd.Exchange(e, nil, nil)
+	})
+	expectPanicsWithValue(t, "no relations specified", func() {
+		cmd.SetRelations(e)
+	})
+}
+
+func TestCommandBufferRelations(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+	childID := ComponentID[ChildOf](w)
+
+	cmd := NewCommandBuffer(w)
+	childMap := NewCommandMap[ChildOf](cmd)
+	mapper := NewMap[ChildOf](w)
+
+	relAdded := 0
+	Observe(OnAddRelations).Do(func(e Entity) { relAdded++ }).Register(w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	childMap.NewEntity(&ChildOf{}, parent1)
+	cmd.NewEntityRel([]ID{posID, childID}, RelID(childID, parent2))
+	cmd.Apply()
+	expectEqual(t, 2, relAdded)
+
+	filter := NewFilter1[ChildOf](w)
+	expectEqual(t, 1, countChildren(filter, parent1))
+	expectEqual(t, 1, countChildren(filter, parent2))
+
+	query := filter.Query()
+	for query.Next() {
+		childMap.SetRelation(query.Entity(), parent2)
+	}
+	cmd.Apply()
+	expectEqual(t, 0, countChildren(filter, parent1))
+	expectEqual(t, 2, countChildren(filter, parent2))
+
+	e := w.NewEntity()
+	cmd.AddRel(e, []ID{childID}, RelID(childID, parent1))
+	cmd.Apply()
+	expectEqual(t, parent1, mapper.GetRelation(e))
+
+	cmd.SetRelations(e, Rel[ChildOf](parent2))
+	cmd.Apply()
+	expectEqual(t, parent2, mapper.GetRelation(e))
+}
+
+func TestCommandBufferObservers(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+	velID := ComponentID[Velocity](w)
+
+	created := 0
+	added := 0
+	removed := 0
+	Observe(OnCreateEntity).Do(func(e Entity) { created++ }).Register(w)
+	Observe(OnAddComponents).Do(func(e Entity) { added++ }).Register(w)
+	Observe(OnRemoveEntity).Do(func(e Entity) { removed++ }).Register(w)
+
+	cmd := NewCommandBuffer(w)
+	velMap := NewCommandMap[Velocity](cmd)
+
+	var values []Position
+	Observe1[Position](OnCreateEntity).Do(func(e Entity, p *Position) {
+		values = append(values, *p)
+	}).Register(w)
+	posMap := NewCommandMap[Position](cmd)
+
+	for i := range 10 {
+		posMap.NewEntity(&Position{float64(i), 0})
+	}
+	cmd.NewEntity(posID, velID)
+	cmd.Apply()
+
+	expectEqual(t, 11, created)
+	expectEqual(t, 11, len(values))
+	for i := range 10 {
+		expectEqual(t, Position{float64(i), 0}, values[i])
+	}
+
+	e := w.NewEntity()
+	velMap.Add(e, &Velocity{})
+	cmd.RemoveEntity(e)
+	cmd.Add(e, posID)
+	cmd.Apply()
+	expectEqual(t, 1, added)
+	expectEqual(t, 1, removed)
+	expectFalse(t, w.Alive(e))
+}
+
+func TestCommandBufferGroupExchange(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+	velID := ComponentID[Velocity](w)
+	headID := ComponentID[Heading](w)
+	childID := ComponentID[ChildOf](w)
+
+	cmd := NewCommandBuffer(w)
+	velMap := NewCommandMap[Velocity](cmd)
+
+	addCalls, added := 0, 0
+	Observe1[Velocity](OnAddComponents).DoBatch(func(entities []Entity, vel []Velocity) {
+		addCalls++
+		added += len(entities)
+		expectEqual(t, len(entities), len(vel))
+	}).Register(w)
+	relCalls := 0
+	Observe(OnAddRelations).Do(func(e Entity) { relCalls++ }).Register(w)
+	removed := 0
+	Observe(OnRemoveComponents).For(C[Velocity]()).Do(func(e Entity) { removed++ }).Register(w)
+
+	posMap := NewMap[Position](w)
+	entities := []Entity{}
+	for i := range 10 {
+		entities = append(entities, posMap.NewEntity(&Position{X: float64(i)}))
+	}
+
+	// Runs for entities moving between the same tables are grouped.
+	for i, e := range entities {
+		velMap.Add(e, &Velocity{X: float64(i)})
+	}
+	cmd.Apply()
+	expectEqual(t, 1, addCalls)
+	expectEqual(t, 10, added)
+	velGet := NewMap[Velocity](w)
+	for i, e := range entities {
+		expectEqual(t, Velocity{X: float64(i)}, *velGet.Get(e))
+	}
+
+	for _, e := range entities {
+		cmd.Remove(e, velID)
+	}
+	cmd.Apply()
+	expectEqual(t, 10, removed)
+
+	// Runs are split by source table, components, and dead entities.
+	u := w.Unsafe()
+	other := u.NewEntity(posID, headID)
+	dead := u.NewEntity(posID)
+	velMap.Add(entities[0], &Velocity{X: 0})
+	velMap.Add(entities[1], &Velocity{X: 1})
+	velMap.Add(other, &Velocity{X: 0})
+	velMap.Add(dead, &Velocity{X: 0})
+	cmd.Exchange(entities[2], []ID{velID}, []ID{posID})
+	cmd.Add(entities[3], velID)
+	cmd.RemoveEntity(dead)
+	cmd.Apply()
+	expectEqual(t, 6, addCalls)
+	expectEqual(t, 16, added)
+	expectFalse(t, w.Alive(dead))
+
+	// Runs with relations are grouped by target.
+	parent := w.NewEntity()
+	for _, e := range entities[4:] {
+		cmd.AddRel(e, []ID{velID, childID}, RelID(childID, parent))
+	}
+	cmd.AddRel(entities[0], []ID{headID, childID}, RelID(childID, parent))
+	cmd.Apply()
+	expectEqual(t, 7, addCalls)
+	expectEqual(t, 22, added)
+	expectEqual(t, 7, relCalls)
+}
+
+func TestCommandBufferApplyPanic(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+
+	cmd := NewCommandBuffer(w)
+	e := w.Unsafe().NewEntity(posID)
+	cmd.NewEntity(posID)
+	cmd.Add(e, posID)
+	cmd.NewEntity(posID)
+
+	expectPanics(t, func() { cmd.Apply() })
+	expectEqual(t, 0, cmd.Len())
+
+	// Commands are not applied again.
+	cmd.Apply()
+	query := NewFilter0(w).Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+}
+
+func TestCommandBufferOrder(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+	childMap := NewCommandMap[ChildOf](cmd)
+	posMap := NewCommandMap[Position](cmd)
+	filter := NewFilter1[ChildOf](w)
+
+	parent := w.NewEntity()
+	e := w.NewEntity()
+
+	// Creations recorded after removal of their target are applied after the removal.
+	childMap.NewEntity(&ChildOf{}, parent)
+	cmd.RemoveEntity(parent)
+	expectPanicsWithValue(t, "can't use a dead entity as relation target, except for the zero entity", func() {
+		childMap.NewEntity(&ChildOf{}, parent)
+		cmd.Apply()
+	})
+	expectEqual(t, 0, cmd.Len())
+
+	// Commands are applied in the recorded order.
+	parent = w.NewEntity()
+	childMap.NewEntity(&ChildOf{}, parent)
+	posMap.Add(e, &Position{1, 2})
+	childMap.NewEntity(&ChildOf{}, parent)
+	cmd.RemoveEntity(e)
+	childMap.NewEntity(&ChildOf{}, parent)
+	posMap.NewEntity(&Position{3, 4})
+	childMap.NewEntity(&ChildOf{}, parent)
+	cmd.RemoveEntity(parent)
+	cmd.Apply()
+
+	expectFalse(t, w.Alive(e))
+	expectFalse(t, w.Alive(parent))
+	expectEqual(t, 5, countChildren(filter, Entity{}))
+}
+
+func TestCommandMapN(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+	mapper := NewCommandMap3[Position, Velocity, ChildOf](cmd)
+	mapper = mapper.New(cmd)
+	posVelMap := NewMap2[Position, Velocity](w)
+	childMap := NewMap[ChildOf](w)
+
+	parent := w.NewEntity()
+	mapper.NewEntity(&Position{1, 2}, &Velocity{3, 4}, &ChildOf{}, RelIdx(2, parent))
+	mapper.NewEntity(&Position{5, 6}, &Velocity{7, 8}, &ChildOf{}, Rel[ChildOf](parent))
+	cmd.Apply()
+
+	query := NewFilter3[Position, Velocity, ChildOf](w).Query(RelIdx(2, parent))
+	expectEqual(t, 2, query.Count())
+	found := []Entity{}
+	for query.Next() {
+		pos, vel, _ := query.Get()
+		expectEqual(t, pos.X+2, vel.X)
+		found = append(found, query.Entity())
+	}
+
+	mapper.Remove(found[0])
+	cmd.Apply()
+	expectFalse(t, posVelMap.HasAll(found[0]))
+	expectFalse(t, childMap.Has(found[0]))
+
+	mapper.Add(found[0], &Position{9, 10}, &Velocity{11, 12}, &ChildOf{}, RelIdx(2, parent))
+	cmd.Apply()
+	pos, vel := posVelMap.Get(found[0])
+	expectEqual(t, Position{9, 10}, *pos)
+	expectEqual(t, Velocity{11, 12}, *vel)
+	expectEqual(t, parent, childMap.GetRelation(found[0]))
+
+	expectPanicsWithValue(t, fmt.Sprintf("requested relation component with ID %d was not specified in the filter or map", ComponentID[ChildOf2](w).id), func() {
+		mapper.NewEntity(&Position{}, &Velocity{}, &ChildOf{}, Rel[ChildOf2](parent))
+	})
+}
+
+func TestCommandExchangeN(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+	exchange := NewCommandExchange2[Position, Velocity](cmd).Removes(C[Heading]())
+	exchange = exchange.New(cmd).Removes(C[Heading]())
+	headMap := NewMap[Heading](w)
+	posVelMap := NewMap2[Position, Velocity](w)
+
+	e1 := headMap.NewEntity(&Heading{1})
+	e2 := headMap.NewEntity(&Heading{2})
+
+	query := NewFilter1[Heading](w).Query()
+	for query.Next() {
+		if query.Entity() == e1 {
+			exchange.Exchange(e1, &Position{1, 2}, &Velocity{3, 4})
+		} else {
+			exchange.Add(e2, &Position{5, 6}, &Velocity{7, 8})
+		}
+	}
+	cmd.Apply()
+
+	expectFalse(t, headMap.Has(e1))
+	expectTrue(t, headMap.Has(e2))
+	pos, vel := posVelMap.Get(e1)
+	expectEqual(t, Position{1, 2}, *pos)
+	expectEqual(t, Velocity{3, 4}, *vel)
+	pos, vel = posVelMap.Get(e2)
+	expectEqual(t, Position{5, 6}, *pos)
+	expectEqual(t, Velocity{7, 8}, *vel)
+
+	exchange.Remove(e2)
+	cmd.Apply()
+	expectFalse(t, headMap.Has(e2))
+	expectTrue(t, posVelMap.HasAll(e2))
+}
+
+func TestCommandBufferLocked(t *testing.T) {
+	w := NewWorld(16)
+	cmd := NewCommandBuffer(w)
+	cmd.NewEntity()
+
+	query := NewFilter0(w).Query()
+	expectPanics(t, func() {
+		cmd.Apply()
+	})
+	query.Close()
+	cmd.Apply()
+	query = NewFilter0(w).Query()
+	expectEqual(t, 1, query.Count())
+	query.Close()
+}
+
+func countChildren(filter *Filter1[ChildOf], parent Entity) int {
+	query := filter.Query(RelIdx(0, parent))
+	defer query.Close()
+	return query.Count()
+}
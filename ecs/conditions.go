@@ -0,0 +1,135 @@
+package ecs
+
+// entityConditions holds the conditions of a filter that are checked per entity rather than per table.
+// These are conditions on sparse components, targets of multi-target relations,
+// and iteration in a given order.
+type entityConditions struct {
+	sparseWith    []*sparseSet  // Sparse components that entities must have.
+	sparseWithout []*sparseSet  // Sparse components that entities must not have.
+	targets       []multiTarget // Targets of multi-target relations that entities must have.
+	ordered       bool          // Whether entities are iterated in a given order, see [storage.cascadeEntities].
+}
+
+// multiTarget is a filter condition on the targets of a multi-target relation component.
+type multiTarget struct {
+	relation *multiRelation // May be nil if no targets were set yet
+	target   Entity         // The required target. Zero for no targets, wildcard for any target.
+}
+
+// matches returns whether the given entity fulfills the condition.
+func (t *multiTarget) matches(entity Entity) bool {
+	if t.relation == nil {
+		// No targets were ever set for the component.
+		return t.target.IsZero()
+	}
+	if t.target.IsZero() {
+		return !t.relation.HasAny(entity)
+	}
+	if t.target.isWildcard() {
+		return t.relation.HasAny(entity)
+	}
+	return t.relation.Has(entity, t.target)
+}
+
+// matches returns whether the given entity fulfills the conditions.
+// Always returns true for nil conditions.
+func (c *entityConditions) matches(entity Entity) bool {
+	if c == nil {
+		return true
+	}
+	for _, set := range c.sparseWith {
+		if !set.Has(entity) {
+			return false
+		}
+	}
+	for _, set := range c.sparseWithout {
+		if set.Has(entity) {
+			return false
+		}
+	}
+	for i := range c.targets {
+		if !c.targets[i].matches(entity) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesRow returns whether the entity in the given table row fulfills the conditions.
+// Always returns true for nil conditions.
+func (c *entityConditions) matchesRow(table *table, row uintptr) bool {
+	return !c.hasChecks() || c.matches(table.GetEntity(row))
+}
+
+// hasSparse returns whether there are conditions on sparse components.
+func (c *entityConditions) hasSparse() bool {
+	return c != nil && (len(c.sparseWith) > 0 || len(c.sparseWithout) > 0)
+}
+
+// hasChecks returns whether there are conditions that need to be checked per entity,
+// on sparse components or multi-target relation targets.
+func (c *entityConditions) hasChecks() bool {
+	return c.hasSparse() || (c != nil && len(c.targets) > 0)
+}
+
+// driver returns the entities to drive iteration, or nil if there are none.
+// This is the smallest of the sets of required sparse components and the sources of required multi-target relation targets.
+func (c *entityConditions) driver() []Entity {
+	if c == nil {
+		return nil
+	}
+	var smallest []Entity
+	found := false
+	for _, set := range c.sparseWith {
+		if !found || int(set.len) < len(smallest) {
+			smallest = set.entities[:set.len]
+			found = true
+		}
+	}
+	for i := range c.targets {
+		target := &c.targets[i]
+		if target.target.IsZero() || target.target.isWildcard() {
+			continue
+		}
+		var sources []Entity
+		if target.relation != nil {
+			sources = target.relation.Sources(target.target)
+		}
+		if !found || len(sources) < len(smallest) {
+			smallest = sources
+			found = true
+		}
+	}
+	if found && smallest == nil {
+		return []Entity{}
+	}
+	return smallest
+}
+
+// checkTableIteration panics if there are conditions that can't be applied to entire tables,
+// or if entities are iterated in a given order.
+func (c *entityConditions) checkTableIteration() {
+	if c.hasSparse() {
+		panic("table-based iteration is not supported for filters with sparse components")
+	}
+	if c == nil {
+		return
+	}
+	if len(c.targets) > 0 {
+		panic("table-based iteration is not supported for filters with multi-target relation targets")
+	}
+	if c.ordered {
+		panic("table-based iteration is not supported for cascade queries on multi-target relations")
+	}
+}
+
+// removeSparseWith removes a sparse component from the required components.
+func (c *entityConditions) removeSparseWith(set *sparseSet) {
+	sets := c.sparseWith[:0]
+	for _, other := range c.sparseWith {
+		if other != set {
+			sets = append(sets, other)
+		}
+	}
+	c.sparseWith = sets
+}
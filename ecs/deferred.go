@@ -0,0 +1,111 @@
+package ecs
+
+import "unsafe"
+
+// FlushEvents delivers all queued events to deferred observers (see [Observer.Deferred]).
+//
+// Queued events are delivered automatically after the outermost operation that produced them.
+// Calling FlushEvents is only required for events that were queued while the world was locked,
+// like events emitted or components set during query iteration.
+//
+// Panics if the world is locked.
+func (w *World) FlushEvents() {
+	w.checkLocked()
+	w.storage.observers.flush()
+}
+
+// flushEvents delivers queued events to deferred observers, unless the world is locked.
+func (w *World) flushEvents() {
+	if len(w.storage.observers.queue) == 0 || w.IsLocked() {
+		return
+	}
+	w.storage.observers.flush()
+}
+
+// deferredEvent is an event queued for a deferred observer.
+type deferredEvent struct {
+	observer *observerData
+	entity   Entity
+	payload  unsafe.Pointer // Payload of custom events, see [TypedEvent]
+}
+
+// fire calls the observer for an entity, or queues the event if the observer is deferred.
+func (m *observerManager) fire(o *observerData, e Entity) {
+	if o.deferred {
+		m.queue = append(m.queue, deferredEvent{observer: o, entity: e, payload: m.payload})
+		return
+	}
+	o.callback(e)
+}
+
+// fireBatch calls the observer for a range of entities in a table, or queues the events if the observer is deferred.
+// Uses the batch callback if present, and calls the callback per entity otherwise.
+func (m *observerManager) fireBatch(o *observerData, table *table, start, end int) {
+	if o.deferred {
+		for i := start; i < end; i++ {
+			m.queue = append(m.queue, deferredEvent{observer: o, entity: table.GetEntity(uintptr(i))})
+		}
+		return
+	}
+	if o.batch != nil {
+		o.batch(table, start, end)
+		return
+	}
+	for i := start; i < end; i++ {
+		o.callback(table.GetEntity(uintptr(i)))
+	}
+}
+
+// flush delivers all queued events, including those queued by deferred observers during the flush.
+// Does nothing if called during a flush.
+func (m *observerManager) flush() {
+	if m.flushing {
+		return
+	}
+	m.flushing = true
+	oldPayload := m.payload
+	defer func() {
+		clear(m.queue)
+		m.queue = m.queue[:0]
+		m.flushing = false
+		m.payload = oldPayload
+	}()
+
+	for i := 0; i < len(m.queue); i++ {
+		evt := m.queue[i]
+		// Skip observers that were unregistered in the meantime.
+		if evt.observer.id != maxObserverID {
+			m.payload = evt.payload
+			evt.observer.callback(evt.entity)
+		}
+	}
+}
+
+// deferredColumn returns the column and row of a component of an entity,
+// for delivering deferred events to typed observers.
+// Returns a nil column if the entity is not alive anymore or does not have the component.
+func deferredColumn(w *World, storage *componentStorage, e Entity) (*column, uint32) {
+	if !w.Alive(e) {
+		return nil, 0
+	}
+	index := &w.storage.entities[e.id]
+	return storage.columns[index.table], index.row
+}
+
+// deferredComponent returns a pointer to a component of an entity, for delivering deferred events to typed observers.
+// Returns nil if the entity is not alive anymore or does not have the component.
+func deferredComponent[T any](w *World, storage *componentStorage, e Entity) *T {
+	column, row := deferredColumn(w, storage, e)
+	if column == nil {
+		return nil
+	}
+	return (*T)(column.Get(uintptr(row)))
+}
+
+// deferredSlice returns a slice of length one with a component of an entity,
+// for delivering deferred events to typed batch observers.
+// Returns nil if the entity is not alive anymore or does not have the component.
+func deferredSlice[T any](w *World, storage *componentStorage, e Entity) []T {
+	column, row := deferredColumn(w, storage, e)
+	return columnSlice[T](column, row, row+1)
+}
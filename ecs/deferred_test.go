@@ -0,0 +1,205 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestObserverDeferred(t *testing.T) {
+	w := NewWorld(16)
+
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+
+	added := []Entity{}
+	Observe(OnAddComponents).
+		For(C[Position]()).
+		Deferred().
+		Do(func(e Entity) {
+			expectFalse(t, w.IsLocked())
+			added = append(added, e)
+			// Deferred observers can modify the world.
+			velMap.Add(e, &Velocity{X: 1})
+		}).
+		Register(w)
+
+	withVel := []Entity{}
+	Observe(OnAddComponents).
+		For(C[Velocity]()).
+		Deferred().
+		Do(func(e Entity) {
+			withVel = append(withVel, e)
+		}).
+		Register(w)
+
+	e1 := w.NewEntity()
+	posMap.Add(e1, &Position{})
+	expectSlicesEqual(t, []Entity{e1}, added)
+	expectSlicesEqual(t, []Entity{e1}, withVel)
+	expectTrue(t, velMap.Has(e1))
+	expectEqual(t, 0, len(w.storage.observers.queue))
+
+	e2 := w.NewEntity()
+	posMap.Add(e2, &Position{})
+	expectSlicesEqual(t, []Entity{e1, e2}, added)
+	expectSlicesEqual(t, []Entity{e1, e2}, withVel)
+
+	expectPanicsWithValue(t, "can't modify a registered observer",
+		func() {
+			obs := Observe(OnCreateEntity).Do(func(e Entity) {}).Register(w)
+			obs.Deferred()
+		})
+}
+
+func TestObserverDeferredBatch(t *testing.T) {
+	w := NewWorld(16)
+
+	posMap := NewMap[Position](w)
+	filter := NewFilter1[Position](w)
+
+	created := 0
+	Observe(OnCreateEntity).
+		For(C[Position]()).
+		Deferred().
+		Do(func(e Entity) {
+			created++
+			posMap.Get(e).X = 10
+		}).
+		Register(w)
+
+	removed := []Entity{}
+	Observe(OnRemoveEntity).
+		Deferred().
+		Do(func(e Entity) {
+			expectFalse(t, w.Alive(e))
+			removed = append(removed, e)
+		}).
+		Register(w)
+
+	posMap.NewBatch(10, &Position{})
+	expectEqual(t, 10, created)
+
+	query := filter.Query()
+	for query.Next() {
+		expectEqual(t, 10.0, query.Get().X)
+	}
+
+	w.RemoveEntities(filter.Batch(), nil)
+	expectEqual(t, 10, len(removed))
+}
+
+func TestObserverDeferredLocked(t *testing.T) {
+	w := NewWorld(16)
+
+	posMap := NewMap[Position](w)
+	filter := NewFilter1[Position](w)
+
+	set := []Entity{}
+	Observe(OnSetComponents).
+		Deferred().
+		Do(func(e Entity) {
+			set = append(set, e)
+		}).
+		Register(w)
+
+	e1 := posMap.NewEntity(&Position{})
+	posMap.Set(e1, &Position{X: 1})
+	expectSlicesEqual(t, []Entity{e1}, set)
+
+	query := filter.Query()
+	for query.Next() {
+		posMap.Set(query.Entity(), &Position{X: 2})
+		expectPanicsWithValue(t, "cannot modify a locked world: collect entities into a slice and apply changes after query iteration has completed",
+			func() {
+				w.FlushEvents()
+			})
+	}
+	// Events queued while the world is locked are kept until the next flush.
+	expectSlicesEqual(t, []Entity{e1}, set)
+
+	w.FlushEvents()
+	expectSlicesEqual(t, []Entity{e1, e1}, set)
+
+	w.FlushEvents()
+	expectSlicesEqual(t, []Entity{e1, e1}, set)
+}
+
+func TestObserverDeferredUnregister(t *testing.T) {
+	w := NewWorld(16)
+
+	posMap := NewMap[Position](w)
+	filter := NewFilter1[Position](w)
+
+	calls := 0
+	obs := Observe(OnSetComponents).
+		Deferred().
+		Do(func(e Entity) {
+			calls++
+		}).
+		Register(w)
+
+	posMap.NewEntity(&Position{})
+
+	query := filter.Query()
+	for query.Next() {
+		posMap.Set(query.Entity(), &Position{X: 2})
+	}
+	obs.Unregister(w)
+	w.FlushEvents()
+	expectEqual(t, 0, calls)
+
+	Observe(OnSetComponents).
+		Deferred().
+		Do(func(e Entity) {
+			calls++
+		}).
+		Register(w)
+
+	query = filter.Query()
+	for query.Next() {
+		posMap.Set(query.Entity(), &Position{X: 2})
+	}
+	w.Reset()
+	expectEqual(t, 0, len(w.storage.observers.queue))
+	w.FlushEvents()
+	expectEqual(t, 0, calls)
+}
+
+func TestObserverDeferredNonDeferred(t *testing.T) {
+	w := NewWorld(16)
+
+	posMap := NewMap[Position](w)
+
+	events := []string{}
+	Observe(OnCreateEntity).
+		Deferred().
+		Do(func(e Entity) {
+			events = append(events, "deferred")
+		}).
+		Register(w)
+	Observe(OnCreateEntity).
+		Do(func(e Entity) {
+			events = append(events, "immediate")
+		}).
+		Register(w)
+
+	posMap.NewEntity(&Position{})
+	expectSlicesEqual(t, []string{"immediate", "deferred"}, events)
+
+	events = events[:0]
+	buffer := NewCommandBuffer(w)
+	buffer.NewEntity(ComponentID[Position](w))
+	buffer.NewEntity(ComponentID[Position](w))
+	buffer.Apply()
+	expectSlicesEqual(t, []string{"immediate", "immediate", "deferred", "deferred"}, events)
+
+	e := w.NewEntity()
+	events = events[:0]
+	Observe(CustomEvent).
+		Deferred().
+		Do(func(e Entity) {
+			events = append(events, "custom")
+		}).
+		Register(w)
+	w.Event(CustomEvent).Emit(e)
+	expectSlicesEqual(t, []string{"custom"}, events)
+}
@@ -50,13 +50,15 @@
 //
 // # Build tags
 //
-// Ark provides two build tags:
+// Ark provides three build tags:
 //   - ark_tiny: Reduces the maximum number of components to 64, for faster mask-related operations and smaller archetype memory footprint.
+//   - ark_large: Raises the maximum number of components to 1024, at the cost of slower mask-related operations and larger archetype memory footprint.
 //   - ark_debug: Improves error messages on incorrect use, at the cost of performance. Use this if you get panics from queries or maps.
 //
 // When building your application, use them like this:
 //
 //	go build -tags ark_tiny .
+//	go build -tags ark_large .
 //	go build -tags ark_debug .
 //	go build -tags ark_tiny,ark_debug .
 //
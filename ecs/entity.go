@@ -19,7 +19,8 @@ var entitySize = entityType.Size()
 // memory size of an entityIndex
 var entityIndexSize = reflect.TypeFor[entityIndex]().Size()
 
-//var wildcard = Entity{1, 0}
+// wildcard is the reserved wildcard entity, used to match any non-zero relation target in filters.
+var wildcard = Entity{1, 0}
 
 // Entity is an identifier for entities.
 //
@@ -3,6 +3,7 @@ package ecs
 import (
 	"fmt"
 	"math"
+	"unsafe"
 )
 
 // observerID is the observer ID type.
@@ -18,12 +19,15 @@ const maxObserverID = math.MaxUint32
 // See [Event] and [World.Event] for using custom events.
 //
 // See [Observer] for details on events and observers.
-type EventType uint8
+type EventType uint16
+
+// numPredefinedEvents is the number of predefined event types.
+const numPredefinedEvents = 7
 
 // Predefined event types.
 const (
 	// customEvent is the highest possible EventType for custom events.
-	customEvent EventType = iota + 248
+	customEvent EventType = iota + math.MaxUint16 - numPredefinedEvents
 
 	// OnCreateEntity event.
 	// Emitted after an entity is created.
@@ -68,7 +72,7 @@ type EventRegistry struct {
 // NewEventType creates a new EventType for custom events.
 // Custom event types should be stored in global variables.
 //
-// The maximum number of event types is limited to 256, with 7 predefined and 249 potential custom types.
+// The maximum number of event types is limited to 65536, with 7 predefined and 65529 potential custom types.
 //
 // See [Event] and [World.Event] for using custom events.
 func (r *EventRegistry) NewEventType() EventType {
@@ -104,7 +108,7 @@ func (e Event) For(comps ...Comp) Event {
 
 // Emit the event for the given entity.
 func (e Event) Emit(entity Entity) {
-	e.world.emitEvent(&e, entity)
+	e.world.emitEvent(&e, entity, nil)
 }
 
 // observerManager manages observers and distributes events.
@@ -118,19 +122,22 @@ type observerManager struct {
 	pool         intPool[observerID]   // Pool for observer IDs
 	indices      map[observerID]uint32 // Mapping for observer locations for fast removal
 	totalCount   uint32                // Total number of observers
-	maxEventType EventType             // Highest event type ID present in registered observers
+	hooks        []componentHooks      // Lifecycle hooks per component ID, created on first use
+	hookIDs      [numHookKinds][]ID    // Components with hooks, per hook kind
+	queue        []deferredEvent       // Queued events for deferred observers
+	flushing     bool                  // Whether queued events are currently being delivered
+	payload      unsafe.Pointer        // Payload of the custom event currently being delivered
 }
 
 // newObserverManager creates anew empty observerManager.
 func newObserverManager() *observerManager {
-	maxEvents := math.MaxUint8 + 1
 	return &observerManager{
-		observers:    make([][]*observerData, maxEvents),
-		hasObservers: make([]bool, maxEvents),
-		anyNoComps:   make([]bool, maxEvents),
-		anyNoWith:    make([]bool, maxEvents),
-		allComps:     make([]bitMask, maxEvents),
-		allWith:      make([]bitMask, maxEvents),
+		observers:    make([][]*observerData, numPredefinedEvents),
+		hasObservers: make([]bool, numPredefinedEvents),
+		anyNoComps:   make([]bool, numPredefinedEvents),
+		anyNoWith:    make([]bool, numPredefinedEvents),
+		allComps:     make([]bitMask, numPredefinedEvents),
+		allWith:      make([]bitMask, numPredefinedEvents),
 		pool:         newIntPool[observerID](32),
 		indices:      map[observerID]uint32{},
 	}
@@ -144,6 +151,11 @@ func (m *observerManager) AddObserver(o *Observer, w *World) {
 	if o.callback == nil {
 		panic("observer callback must be set via Do before registering")
 	}
+	for _, comps := range [][]Comp{o.comps, o.with, o.without} {
+		for _, c := range comps {
+			w.storage.checkNotSparse(TypeID(w, c.tp), "observing")
+		}
+	}
 
 	o.id = m.pool.Get()
 
@@ -189,18 +201,16 @@ func (m *observerManager) AddObserver(o *Observer, w *World) {
 		}
 	}
 
-	m.indices[o.id] = uint32(len(m.observers[o.event]))
-	m.observers[o.event] = append(m.observers[o.event], &o.observerData)
-	m.hasObservers[o.event] = true
-	if o.event > m.maxEventType {
-		m.maxEventType = o.event
-	}
+	m.grow(o.event)
+	m.indices[o.id] = uint32(len(m.observers[o.event.index()]))
+	m.observers[o.event.index()] = append(m.observers[o.event.index()], &o.observerData)
+	m.hasObservers[o.event.index()] = true
 	m.totalCount++
 
 	if o.hasWith {
-		m.allWith[o.event].OrI(&o.withMask)
+		m.allWith[o.event.index()].OrI(&o.withMask)
 	} else {
-		m.anyNoWith[o.event] = true
+		m.anyNoWith[o.event.index()] = true
 	}
 
 	if o.event == OnCreateEntity || o.event == OnRemoveEntity {
@@ -208,10 +218,24 @@ func (m *observerManager) AddObserver(o *Observer, w *World) {
 	}
 
 	if o.hasComps {
-		m.allComps[o.event].OrI(&o.compsMask)
+		m.allComps[o.event.index()].OrI(&o.compsMask)
 	} else {
-		m.anyNoComps[o.event] = true
+		m.anyNoComps[o.event.index()] = true
+	}
+}
+
+// references returns whether any registered observer refers to the given component.
+// Components only excluded by exclusive observers are not considered.
+func (m *observerManager) references(id ID) bool {
+	for _, observers := range m.observers {
+		for _, o := range observers {
+			if (o.hasComps && o.compsMask.Get(id.id)) || (o.hasWith && o.withMask.Get(id.id)) ||
+				(o.hasWithout && !o.exclusive && o.withoutMask.Get(id.id)) {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 // RemoveObserver removes an observer.
@@ -226,7 +250,7 @@ func (m *observerManager) RemoveObserver(o *Observer) {
 	}
 	delete(m.indices, o.id)
 
-	observers := m.observers[o.event]
+	observers := m.observers[o.event.index()]
 	observers[idx].id = maxObserverID
 
 	last := uint32(len(observers) - 1)
@@ -235,87 +259,109 @@ func (m *observerManager) RemoveObserver(o *Observer) {
 		m.indices[observers[idx].id] = idx
 	}
 	observers[last] = nil
-	m.observers[o.event] = observers[:last]
-	m.hasObservers[o.event] = last > 0
+	m.observers[o.event.index()] = observers[:last]
+	m.hasObservers[o.event.index()] = last > 0 || m.hasHooks(o.event)
 	m.totalCount--
 
 	var allWith bitMask
-	m.anyNoWith[o.event] = false
-	for _, obs := range m.observers[o.event] {
+	m.anyNoWith[o.event.index()] = false
+	for _, obs := range m.observers[o.event.index()] {
 		if !obs.hasWith {
-			m.anyNoWith[o.event] = true
+			m.anyNoWith[o.event.index()] = true
 			break
 		}
 		allWith.OrI(&obs.withMask)
 	}
-	m.allWith[o.event] = allWith
+	m.allWith[o.event.index()] = allWith
 
 	if o.event == OnCreateEntity || o.event == OnRemoveEntity {
 		return
 	}
 
 	var allComps bitMask
-	m.anyNoComps[o.event] = false
-	for _, obs := range m.observers[o.event] {
+	m.anyNoComps[o.event.index()] = false
+	for _, obs := range m.observers[o.event.index()] {
 		if !obs.hasComps {
-			m.anyNoComps[o.event] = true
+			m.anyNoComps[o.event.index()] = true
 			break
 		}
 		allComps.OrI(&obs.compsMask)
 	}
-	m.allComps[o.event] = allComps
+	m.allComps[o.event.index()] = allComps
 }
 
 // HasObservers returns whether there is any registered observer for the given event type.
 func (m *observerManager) HasObservers(evt EventType) bool {
-	return m.hasObservers[evt]
+	idx := evt.index()
+	return idx < len(m.hasObservers) && m.hasObservers[idx]
+}
+
+// index returns the index of the event type in the observerManager's per-event slices.
+// Predefined event types are mapped to the first indices, followed by custom event types.
+func (evt EventType) index() int {
+	// Overflow is intended: predefined event types wrap around to zero.
+	return int(evt + numPredefinedEvents)
+}
+
+// grow extends the per-event slices to cover the given event type.
+func (m *observerManager) grow(evt EventType) {
+	idx := evt.index()
+	if idx < len(m.observers) {
+		return
+	}
+	n := idx + 1 - len(m.observers)
+	m.observers = append(m.observers, make([][]*observerData, n)...)
+	m.hasObservers = append(m.hasObservers, make([]bool, n)...)
+	m.anyNoComps = append(m.anyNoComps, make([]bool, n)...)
+	m.anyNoWith = append(m.anyNoWith, make([]bool, n)...)
+	m.allComps = append(m.allComps, make([]bitMask, n)...)
+	m.allWith = append(m.allWith, make([]bitMask, n)...)
 }
 
 func (m *observerManager) anyWith(evt EventType, mask *bitMask) bool {
-	return m.anyNoWith[evt] || m.allWith[evt].ContainsAny(mask)
+	return m.anyNoWith[evt.index()] || m.allWith[evt.index()].ContainsAny(mask)
 }
 
 func (m *observerManager) any(evt EventType, compMask, mask *bitMask) bool {
-	return (m.anyNoComps[evt] || m.allComps[evt].ContainsAny(compMask)) &&
-		(m.anyNoWith[evt] || m.allWith[evt].ContainsAny(mask))
+	return (m.anyNoComps[evt.index()] || m.allComps[evt.index()].ContainsAny(compMask)) &&
+		(m.anyNoWith[evt.index()] || m.allWith[evt.index()].ContainsAny(mask))
 }
 
 func (m *observerManager) FireCreateEntityIfHas(e Entity, mask *bitMask) {
-	if !m.hasObservers[OnCreateEntity] {
+	if !m.hasObservers[OnCreateEntity.index()] {
 		return
 	}
 	m.fireCreateEntity(e, mask)
 }
 
 func (m *observerManager) fireCreateEntity(e Entity, mask *bitMask) {
+	m.fireHooks(hookAdd, e, mask, nil)
 	if !m.anyWith(OnCreateEntity, mask) {
 		return
 	}
-	observers := m.observers[OnCreateEntity]
+	observers := m.observers[OnCreateEntity.index()]
 	for _, o := range observers {
 		if o.matchesWithWithout(mask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
 
 func (m *observerManager) FireCreateEntityBatch(table *table, start int, mask *bitMask) {
+	m.fireHooksBatch(hookAdd, table, start, table.Len(), mask, nil)
 	if !m.anyWith(OnCreateEntity, mask) {
 		return
 	}
-	observers := m.observers[OnCreateEntity]
+	observers := m.observers[OnCreateEntity.index()]
 	for _, o := range observers {
 		if o.matchesWithWithout(mask) {
-			ln := table.Len()
-			for i := start; i < ln; i++ {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, start, table.Len())
 		}
 	}
 }
 
 func (m *observerManager) FireCreateEntityRelIfHas(e Entity, mask *bitMask) {
-	if !m.hasObservers[OnAddRelations] {
+	if !m.hasObservers[OnAddRelations.index()] {
 		return
 	}
 	m.fireCreateEntityRel(e, mask)
@@ -325,10 +371,10 @@ func (m *observerManager) fireCreateEntityRel(e Entity, mask *bitMask) {
 	if !m.any(OnAddRelations, mask, mask) {
 		return
 	}
-	observers := m.observers[OnAddRelations]
+	observers := m.observers[OnAddRelations.index()]
 	for _, o := range observers {
 		if o.matches(mask, mask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
@@ -337,40 +383,36 @@ func (m *observerManager) FireCreateEntityRelBatch(table *table, start int, mask
 	if !m.any(OnAddRelations, mask, mask) {
 		return
 	}
-	observers := m.observers[OnAddRelations]
+	observers := m.observers[OnAddRelations.index()]
 	for _, o := range observers {
 		if o.matches(mask, mask) {
-			ln := table.Len()
-			for i := start; i < ln; i++ {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, start, table.Len())
 		}
 	}
 }
 
 func (m *observerManager) FireRemoveEntity(e Entity, mask *bitMask) {
+	m.fireHooks(hookRemove, e, mask, nil)
 	if !m.anyWith(OnRemoveEntity, mask) {
 		return
 	}
-	observers := m.observers[OnRemoveEntity]
+	observers := m.observers[OnRemoveEntity.index()]
 	for _, o := range observers {
 		if o.matchesWithWithout(mask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
 
 func (m *observerManager) FireRemoveEntityBatch(table *table, mask *bitMask) {
+	m.fireHooksBatch(hookRemove, table, 0, table.Len(), mask, nil)
 	if !m.anyWith(OnRemoveEntity, mask) {
 		return
 	}
-	observers := m.observers[OnRemoveEntity]
+	observers := m.observers[OnRemoveEntity.index()]
 	for _, o := range observers {
 		if o.matchesWithWithout(mask) {
-			ln := table.Len()
-			for i := range ln {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, 0, table.Len())
 		}
 	}
 }
@@ -379,10 +421,10 @@ func (m *observerManager) FireRemoveEntityRel(e Entity, mask *bitMask) {
 	if !m.any(OnRemoveRelations, mask, mask) {
 		return
 	}
-	observers := m.observers[OnRemoveRelations]
+	observers := m.observers[OnRemoveRelations.index()]
 	for _, o := range observers {
 		if o.matches(mask, mask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
@@ -391,112 +433,118 @@ func (m *observerManager) FireRemoveEntityRelBatch(table *table, mask *bitMask)
 	if !m.any(OnRemoveRelations, mask, mask) {
 		return
 	}
-	observers := m.observers[OnRemoveRelations]
+	observers := m.observers[OnRemoveRelations.index()]
 	for _, o := range observers {
 		if o.matches(mask, mask) {
-			ln := table.Len()
-			for i := range ln {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, 0, table.Len())
 		}
 	}
 }
 
 func (m *observerManager) FireAddIfHas(evt EventType, e Entity, oldMask *bitMask, newMask *bitMask) {
-	if !m.hasObservers[evt] {
+	if !m.hasObservers[evt.index()] {
 		return
 	}
 	m.fireAdd(evt, e, oldMask, newMask)
 }
 
 func (m *observerManager) fireAdd(evt EventType, e Entity, oldMask *bitMask, newMask *bitMask) {
-	if !m.anyNoComps[evt] &&
-		(!m.allComps[evt].ContainsAny(newMask) || oldMask.Contains(&m.allComps[evt])) {
+	if evt == OnAddComponents {
+		m.fireHooks(hookAdd, e, newMask, oldMask)
+	}
+	if !m.anyNoComps[evt.index()] &&
+		(!m.allComps[evt.index()].ContainsAny(newMask) || oldMask.Contains(&m.allComps[evt.index()])) {
 		return
 	}
 	if !m.anyWith(evt, oldMask) {
 		return
 	}
-	observers := m.observers[evt]
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.hasComps && (!newMask.Contains(&o.compsMask) || oldMask.ContainsAny(&o.compsMask)) {
 			continue
 		}
 		if o.matchesWithWithout(oldMask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
 
 func (m *observerManager) FireAddBatch(evt EventType, table *table, start, end uint32, oldMask *bitMask, newMask *bitMask) {
-	if !m.anyNoComps[evt] &&
-		(!m.allComps[evt].ContainsAny(newMask) || oldMask.Contains(&m.allComps[evt])) {
+	if evt == OnAddComponents {
+		m.fireHooksBatch(hookAdd, table, int(start), int(end), newMask, oldMask)
+	}
+	if !m.anyNoComps[evt.index()] &&
+		(!m.allComps[evt.index()].ContainsAny(newMask) || oldMask.Contains(&m.allComps[evt.index()])) {
 		return
 	}
 	if !m.anyWith(evt, oldMask) {
 		return
 	}
-	observers := m.observers[evt]
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.hasComps && (!newMask.Contains(&o.compsMask) || oldMask.ContainsAny(&o.compsMask)) {
 			continue
 		}
 		if o.matchesWithWithout(oldMask) {
-			for i := start; i < end; i++ {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, int(start), int(end))
 		}
 	}
 }
 
 func (m *observerManager) FireRemove(evt EventType, e Entity, oldMask *bitMask, newMask *bitMask) {
-	if !m.anyNoComps[evt] &&
-		(!m.allComps[evt].ContainsAny(oldMask) || newMask.Contains(&m.allComps[evt])) {
+	if evt == OnRemoveComponents {
+		m.fireHooks(hookRemove, e, oldMask, newMask)
+	}
+	if !m.anyNoComps[evt.index()] &&
+		(!m.allComps[evt.index()].ContainsAny(oldMask) || newMask.Contains(&m.allComps[evt.index()])) {
 		return
 	}
 	if !m.anyWith(evt, oldMask) {
 		return
 	}
-	observers := m.observers[evt]
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.hasComps && (newMask.Contains(&o.compsMask) || !oldMask.ContainsAny(&o.compsMask)) {
 			continue
 		}
 		if o.matchesWithWithout(oldMask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
 
 func (m *observerManager) FireRemoveBatch(evt EventType, table *table, len int, oldMask *bitMask, newMask *bitMask) {
-	if !m.anyNoComps[evt] &&
-		(!m.allComps[evt].ContainsAny(oldMask) || newMask.Contains(&m.allComps[evt])) {
+	if evt == OnRemoveComponents {
+		m.fireHooksBatch(hookRemove, table, 0, len, oldMask, newMask)
+	}
+	if !m.anyNoComps[evt.index()] &&
+		(!m.allComps[evt.index()].ContainsAny(oldMask) || newMask.Contains(&m.allComps[evt.index()])) {
 		return
 	}
 	if !m.anyWith(evt, oldMask) {
 		return
 	}
-	observers := m.observers[evt]
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.hasComps && (newMask.Contains(&o.compsMask) || !oldMask.ContainsAny(&o.compsMask)) {
 			continue
 		}
 		if o.matchesWithWithout(oldMask) {
-			for i := range len {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, 0, len)
 		}
 	}
 }
 
 func (m *observerManager) FireSet(e Entity, mask *bitMask, newMask *bitMask) {
+	m.fireHooks(hookSet, e, mask, nil)
 	if !m.any(OnSetComponents, mask, newMask) {
 		return
 	}
-	observers := m.observers[OnSetComponents]
+	observers := m.observers[OnSetComponents.index()]
 	for _, o := range observers {
 		if o.matches(mask, newMask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
@@ -505,10 +553,10 @@ func (m *observerManager) FireSetRelations(evt EventType, e Entity, mask *bitMas
 	if !m.any(evt, mask, newMask) {
 		return
 	}
-	observers := m.observers[evt]
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.matches(mask, newMask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
 }
@@ -517,36 +565,39 @@ func (m *observerManager) FireSetRelationsBatch(evt EventType, table *table, sta
 	if !m.any(evt, mask, newMask) {
 		return
 	}
-	observers := m.observers[evt]
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.matches(mask, newMask) {
-			for i := start; i < end; i++ {
-				o.callback(table.GetEntity(uintptr(i)))
-			}
+			m.fireBatch(o, table, int(start), int(end))
 		}
 	}
 }
 
-func (m *observerManager) FireCustom(evt EventType, e Entity, mask, entityMask *bitMask) {
+func (m *observerManager) FireCustom(evt EventType, e Entity, mask, entityMask *bitMask, payload unsafe.Pointer) {
 	if !m.any(evt, mask, entityMask) {
 		return
 	}
-	observers := m.observers[evt]
+	oldPayload := m.payload
+	m.payload = payload
+	observers := m.observers[evt.index()]
 	for _, o := range observers {
 		if o.matches(mask, entityMask) {
-			o.callback(e)
+			m.fire(o, e)
 		}
 	}
+	m.payload = oldPayload
 }
 
 // Reset the observer manager.
 func (m *observerManager) Reset() {
+	clear(m.queue)
+	m.queue = m.queue[:0]
+
 	if len(m.indices) == 0 {
-		m.maxEventType = 0
 		return
 	}
 
-	for i := range m.maxEventType + 1 {
+	for i := range m.observers {
 		if !m.hasObservers[i] {
 			continue
 		}
@@ -556,7 +607,7 @@ func (m *observerManager) Reset() {
 			o.id = maxObserverID
 		}
 		m.observers[i] = m.observers[i][:0]
-		m.hasObservers[i] = false
+		m.hasObservers[i] = m.hasHooks(EventType(i) - numPredefinedEvents)
 		m.allComps[i].Reset()
 		m.allWith[i].Reset()
 		m.anyNoComps[i] = false
@@ -565,5 +616,4 @@ func (m *observerManager) Reset() {
 
 	m.pool.Reset()
 	m.totalCount = 0
-	m.maxEventType = 0
 }
@@ -22,7 +22,7 @@ func TestNewEventType(t *testing.T) {
 		}
 	}
 
-	expectPanicsWithValue(t, "reached maximum number of 249 custom event types",
+	expectPanicsWithValue(t, "reached maximum number of 65529 custom event types",
 		func() { reg.NewEventType() })
 }
 
@@ -201,3 +201,36 @@ func BenchmarkObserversResetCustom(b *testing.B) {
 		w.storage.observers.Reset()
 	}
 }
+
+func TestCustomEventLarge(t *testing.T) {
+	world := NewWorld()
+
+	evt := EventType(1000)
+	expectFalse(t, world.storage.observers.HasObservers(evt))
+	expectFalse(t, world.storage.observers.HasObservers(customEvent))
+
+	callCount := 0
+	obs := Observe(evt).
+		Do(func(e Entity) { callCount++ }).
+		Register(world)
+	expectTrue(t, world.storage.observers.HasObservers(evt))
+	expectFalse(t, world.storage.observers.HasObservers(evt-1))
+	expectFalse(t, world.storage.observers.HasObservers(customEvent))
+	expectEqual(t, int(evt)+numPredefinedEvents+1, len(world.storage.observers.observers))
+
+	world.Event(evt).Emit(world.NewEntity())
+	world.Event(evt - 1).Emit(world.NewEntity())
+	expectEqual(t, 1, callCount)
+
+	Observe(customEvent).
+		Do(func(e Entity) { callCount++ }).
+		Register(world)
+	world.Event(customEvent).Emit(world.NewEntity())
+	expectEqual(t, 2, callCount)
+
+	obs.Unregister(world)
+	expectFalse(t, world.storage.observers.HasObservers(evt))
+
+	world.Reset()
+	expectFalse(t, world.storage.observers.HasObservers(customEvent))
+}
@@ -0,0 +1,153 @@
+package ecs
+
+import "unsafe"
+
+// TypedEvent is a custom event type that carries a payload of type T.
+//
+// Create typed event types using [NewTypedEvent], and store them in global variables.
+// Create events for emitting using [TypedEvent.Event],
+// and observe them using [ObserveEvent].
+type TypedEvent[T any] struct {
+	eventType EventType
+}
+
+// NewTypedEvent creates a new custom event type with a payload of type T,
+// using the given [EventRegistry].
+//
+// Typed event types count towards the maximum number of custom event types, see [EventRegistry.NewEventType].
+func NewTypedEvent[T any](r *EventRegistry) TypedEvent[T] {
+	return TypedEvent[T]{eventType: r.NewEventType()}
+}
+
+// Type returns the [EventType] of the typed event.
+// It can be used to observe the event with an untyped [Observer], which does not receive the payload.
+func (e TypedEvent[T]) Type() EventType {
+	return e.eventType
+}
+
+// Event creates a new event of this type for the given world.
+//
+// The event can be further configured using [PayloadEvent.For].
+// It must be emitted using [PayloadEvent.Emit] to have an effect.
+func (e TypedEvent[T]) Event(w *World) PayloadEvent[T] {
+	return PayloadEvent[T]{event: w.Event(e.eventType)}
+}
+
+// PayloadEvent is a custom event with a payload of type T.
+//
+// Create events using [TypedEvent.Event].
+type PayloadEvent[T any] struct {
+	event Event
+}
+
+// For sets the event's component types. Optional.
+//
+// For best performance, store the event after setting component types,
+// and re-use afterwards.
+func (e PayloadEvent[T]) For(comps ...Comp) PayloadEvent[T] {
+	e.event = e.event.For(comps...)
+	return e
+}
+
+// Emit the event for the given entity, with the given payload.
+//
+// The payload is passed to observers by pointer, without copying.
+// It must not be modified while it may still be delivered to deferred observers (see [Observer.Deferred]).
+func (e PayloadEvent[T]) Emit(entity Entity, payload *T) {
+	e.event.world.emitEvent(&e.event, entity, unsafe.Pointer(payload))
+}
+
+// EventObserver is an observer for custom events with a payload of type T (see [TypedEvent]).
+//
+// See [Observer] for details on events and observers.
+type EventObserver[T any] struct {
+	observer Observer
+	callback func(Entity, *T)
+}
+
+// ObserveEvent creates a new [EventObserver] for the given typed event.
+func ObserveEvent[T any](evt TypedEvent[T]) *EventObserver[T] {
+	return &EventObserver[T]{
+		observer: Observer{
+			event: evt.eventType,
+			observerData: observerData{
+				id: maxObserverID,
+			},
+		},
+	}
+}
+
+// For adds components that the observer observes.
+// The observer triggers only for events that were emitted for these components (see [PayloadEvent.For]).
+//
+// Method calls can be chained, which has the same effect as calling with multiple arguments.
+func (o *EventObserver[T]) For(comps ...Comp) *EventObserver[T] {
+	o.observer.For(comps...)
+	return o
+}
+
+// With adds components that entities must have to trigger the observer.
+// If multiple components are provided, the entity must have all of them.
+//
+// Method calls can be chained, which has the same effect as calling with multiple arguments.
+func (o *EventObserver[T]) With(comps ...Comp) *EventObserver[T] {
+	o.observer.With(comps...)
+	return o
+}
+
+// Without adds components that entities must not have to trigger the observer.
+// If multiple components are provided, the entity must not have any of them.
+//
+// Method calls can be chained, which has the same effect as calling with multiple arguments.
+func (o *EventObserver[T]) Without(comps ...Comp) *EventObserver[T] {
+	o.observer.Without(comps...)
+	return o
+}
+
+// Exclusive makes the observer exclusive in the sense that the components given by [EventObserver.With]
+// are matched exactly, and no other components are allowed.
+//
+// Overwrites components set via [EventObserver.Without].
+func (o *EventObserver[T]) Exclusive() *EventObserver[T] {
+	o.observer.Exclusive()
+	return o
+}
+
+// Deferred makes the observer deferred. See [Observer.Deferred] for details.
+func (o *EventObserver[T]) Deferred() *EventObserver[T] {
+	o.observer.Deferred()
+	return o
+}
+
+// DoWith sets the observer's callback, which receives the event's payload.
+// Must be called exactly once before registration.
+//
+// The payload is nil if the event was emitted without payload, via [World.Event] and [Event.Emit].
+//
+// ⚠️ Do not store the obtained pointer outside of the current context!
+func (o *EventObserver[T]) DoWith(fn func(Entity, *T)) *EventObserver[T] {
+	if o.callback != nil {
+		panic("observer already has a callback")
+	}
+	o.callback = fn
+	return o
+}
+
+// Register this observer. This is mandatory for the observer to take effect.
+func (o *EventObserver[T]) Register(w *World) *EventObserver[T] {
+	if o.callback == nil {
+		panic("observer callback must be set via DoWith before registering")
+	}
+	observers := w.storage.observers
+	o.observer.callback = func(e Entity) {
+		o.callback(e, (*T)(observers.payload))
+	}
+	w.registerObserver(&o.observer)
+	return o
+}
+
+// Unregister this observer.
+func (o *EventObserver[T]) Unregister(w *World) *EventObserver[T] {
+	w.unregisterObserver(&o.observer)
+	return o
+}
@@ -0,0 +1,131 @@
+package ecs
+
+import (
+	"testing"
+)
+
+type damage struct {
+	Amount float64
+	Source Entity
+}
+
+var damageEvent = NewTypedEvent[damage](&reg)
+
+func TestTypedEvent(t *testing.T) {
+	world := NewWorld()
+	builder := NewMap2[Position, Velocity](world)
+
+	source := world.NewEntity()
+	e := builder.NewEntity(&Position{1, 2}, &Velocity{3, 4})
+
+	total := 0.0
+	ObserveEvent(damageEvent).
+		For(C[Position]()).
+		DoWith(func(entity Entity, d *damage) {
+			expectEqual(t, e, entity)
+			expectEqual(t, source, d.Source)
+			total += d.Amount
+		}).
+		Register(world)
+
+	untyped := 0
+	Observe(damageEvent.Type()).
+		Do(func(e Entity) { untyped++ }).
+		Register(world)
+
+	evt := damageEvent.Event(world).For(C[Position]())
+	evt.Emit(e, &damage{Amount: 5, Source: source})
+	expectEqual(t, 5.0, total)
+	expectEqual(t, 1, untyped)
+
+	damageEvent.Event(world).For(C[Velocity]()).Emit(e, &damage{Amount: 10, Source: source})
+	expectEqual(t, 5.0, total)
+	expectEqual(t, 2, untyped)
+
+	evt.Emit(e, &damage{Amount: 2, Source: source})
+	expectEqual(t, 7.0, total)
+	expectEqual(t, 3, untyped)
+}
+
+func TestTypedEventNoPayload(t *testing.T) {
+	world := NewWorld()
+
+	calls := 0
+	obs := ObserveEvent(damageEvent).
+		DoWith(func(entity Entity, d *damage) {
+			expectTrue(t, d == nil)
+			calls++
+		}).
+		Register(world)
+
+	world.Event(damageEvent.Type()).Emit(Entity{})
+	expectEqual(t, 1, calls)
+
+	obs.Unregister(world)
+	world.Event(damageEvent.Type()).Emit(Entity{})
+	expectEqual(t, 1, calls)
+
+	expectPanicsWithValue(t, "observer callback must be set via DoWith before registering",
+		func() {
+			ObserveEvent(damageEvent).Register(world)
+		})
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			ObserveEvent(damageEvent).
+				DoWith(func(entity Entity, d *damage) {}).
+				DoWith(func(entity Entity, d *damage) {})
+		})
+}
+
+func TestTypedEventNested(t *testing.T) {
+	world := NewWorld()
+	posMap := NewMap[Position](world)
+
+	e := posMap.NewEntity(&Position{})
+
+	amounts := []float64{}
+	ObserveEvent(damageEvent).
+		With(C[Position]()).
+		Without(C[Velocity]()).
+		DoWith(func(entity Entity, d *damage) {
+			if d.Amount > 1 {
+				damageEvent.Event(world).Emit(entity, &damage{Amount: d.Amount / 2})
+			}
+			amounts = append(amounts, d.Amount)
+		}).
+		Register(world)
+
+	damageEvent.Event(world).Emit(e, &damage{Amount: 4})
+	expectSlicesEqual(t, []float64{1, 2, 4}, amounts)
+}
+
+func TestTypedEventDeferred(t *testing.T) {
+	world := NewWorld()
+	posMap := NewMap[Position](world)
+	filter := NewFilter1[Position](world)
+
+	posMap.NewEntity(&Position{})
+	posMap.NewEntity(&Position{})
+
+	amounts := []float64{}
+	ObserveEvent(damageEvent).
+		With(C[Position]()).
+		Exclusive().
+		Deferred().
+		DoWith(func(entity Entity, d *damage) {
+			amounts = append(amounts, d.Amount)
+		}).
+		Register(world)
+
+	evt := damageEvent.Event(world)
+	query := filter.Query()
+	i := 1.0
+	for query.Next() {
+		evt.Emit(query.Entity(), &damage{Amount: i})
+		i++
+	}
+	expectEqual(t, 0, len(amounts))
+
+	world.FlushEvents()
+	expectSlicesEqual(t, []float64{1, 2}, amounts)
+}
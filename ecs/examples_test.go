@@ -104,6 +104,29 @@ func ExampleQuery2_tableBased() {
 	// Output:
 }
 
+func ExampleQuery2_ParallelTables() {
+	world := ecs.NewWorld()
+
+	// Create entities.
+	builder := ecs.NewMap2[Position, Velocity](world)
+	builder.NewBatch(1000, &Position{}, &Velocity{X: 1, Y: 0})
+
+	// A simple filter.
+	filter := ecs.NewFilter2[Position, Velocity](world)
+
+	// Create a fresh query and process it with 4 workers.
+	query := filter.Query()
+	query.ParallelTables(4, func(chunk *ecs.Chunk, entities []ecs.Entity, positions []Position, velocities []Velocity) {
+		// Only access the given slices, and concurrency-safe state.
+		for i := range positions {
+			pos, vel := &positions[i], &velocities[i]
+			pos.X += vel.X
+			pos.Y += vel.Y
+		}
+	})
+	// Output:
+}
+
 func ExampleQuery2_Count() {
 	world := ecs.NewWorld()
 
@@ -245,3 +268,41 @@ func ExampleExchange2_New() {
 	exchange = exchange.New(world).Removes(ecs.C[Altitude]())
 	// Output:
 }
+
+func ExampleCommandMap2() {
+	world := ecs.NewWorld()
+
+	// Create a command buffer and a typed command helper.
+	cmd := ecs.NewCommandBuffer(world)
+	commands := ecs.NewCommandMap2[Position, Velocity](cmd)
+
+	// Record entity creation, e.g. during query iteration.
+	commands.NewEntity(&Position{X: 100, Y: 100}, &Velocity{X: 1, Y: -1})
+
+	// Apply the recorded commands once the world is unlocked.
+	cmd.Apply()
+	// Output:
+}
+
+func ExampleCommandExchange2() {
+	world := ecs.NewWorld()
+
+	// Create a component mapper.
+	mapper := ecs.NewMap[Altitude](world)
+
+	// Create a command buffer and a typed exchange helper.
+	// Adds Position and Velocity, removes Altitude.
+	cmd := ecs.NewCommandBuffer(world)
+	exchange := ecs.NewCommandExchange2[Position, Velocity](cmd).
+		Removes(ecs.C[Altitude]())
+
+	// Create an entity with an Altitude component.
+	entity := mapper.NewEntity(&Altitude{Z: 10_000})
+
+	// Record the exchange, e.g. during query iteration.
+	exchange.Exchange(entity, &Position{X: 100, Y: 100}, &Velocity{X: 1, Y: -1})
+
+	// Apply the recorded commands once the world is unlocked.
+	cmd.Apply()
+	// Output:
+}
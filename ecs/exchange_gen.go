@@ -28,10 +28,15 @@ func (*Exchange1[A]) New(world *World) *Exchange1[A] {
 // NewExchange1 creates an [Exchange1].
 //
 // See also [Exchange1.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange1[A any](world *World) *Exchange1[A] {
 	ids := []ID{
 		ComponentID[A](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange1")
+	}
 	return &Exchange1[A]{
 		world: world,
 		ids:   ids,
@@ -75,11 +80,13 @@ func (ex *Exchange1[A]) AddFn(entity Entity, fn func(*A), rel ...Relation) {
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange1.Removes] from the given entity.
 func (ex *Exchange1[A]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -113,6 +120,7 @@ func (ex *Exchange1[A]) ExchangeFn(entity Entity, fn func(*A), rel ...Relation)
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -187,6 +195,7 @@ func (ex *Exchange1[A]) exchangeBatchFn(batch *Batch, fn func(Entity, *A), remov
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange1[A]) runCallback(entity Entity, fn func(a *A)) {
@@ -220,11 +229,16 @@ func (*Exchange2[A, B]) New(world *World) *Exchange2[A, B] {
 // NewExchange2 creates an [Exchange2].
 //
 // See also [Exchange2.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange2[A any, B any](world *World) *Exchange2[A, B] {
 	ids := []ID{
 		ComponentID[A](world),
 		ComponentID[B](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange2")
+	}
 	return &Exchange2[A, B]{
 		world: world,
 		ids:   ids,
@@ -269,11 +283,13 @@ func (ex *Exchange2[A, B]) AddFn(entity Entity, fn func(*A, *B), rel ...Relation
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange2.Removes] from the given entity.
 func (ex *Exchange2[A, B]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -308,6 +324,7 @@ func (ex *Exchange2[A, B]) ExchangeFn(entity Entity, fn func(*A, *B), rel ...Rel
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -386,6 +403,7 @@ func (ex *Exchange2[A, B]) exchangeBatchFn(batch *Batch, fn func(Entity, *A, *B)
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange2[A, B]) runCallback(entity Entity, fn func(a *A, b *B)) {
@@ -424,12 +442,17 @@ func (*Exchange3[A, B, C]) New(world *World) *Exchange3[A, B, C] {
 // NewExchange3 creates an [Exchange3].
 //
 // See also [Exchange3.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange3[A any, B any, C any](world *World) *Exchange3[A, B, C] {
 	ids := []ID{
 		ComponentID[A](world),
 		ComponentID[B](world),
 		ComponentID[C](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange3")
+	}
 	return &Exchange3[A, B, C]{
 		world: world,
 		ids:   ids,
@@ -475,11 +498,13 @@ func (ex *Exchange3[A, B, C]) AddFn(entity Entity, fn func(*A, *B, *C), rel ...R
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange3.Removes] from the given entity.
 func (ex *Exchange3[A, B, C]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -515,6 +540,7 @@ func (ex *Exchange3[A, B, C]) ExchangeFn(entity Entity, fn func(*A, *B, *C), rel
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -597,6 +623,7 @@ func (ex *Exchange3[A, B, C]) exchangeBatchFn(batch *Batch, fn func(Entity, *A,
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange3[A, B, C]) runCallback(entity Entity, fn func(a *A, b *B, c *C)) {
@@ -636,6 +663,8 @@ func (*Exchange4[A, B, C, D]) New(world *World) *Exchange4[A, B, C, D] {
 // NewExchange4 creates an [Exchange4].
 //
 // See also [Exchange4.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange4[A any, B any, C any, D any](world *World) *Exchange4[A, B, C, D] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -643,6 +672,9 @@ func NewExchange4[A any, B any, C any, D any](world *World) *Exchange4[A, B, C,
 		ComponentID[C](world),
 		ComponentID[D](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange4")
+	}
 	return &Exchange4[A, B, C, D]{
 		world: world,
 		ids:   ids,
@@ -689,11 +721,13 @@ func (ex *Exchange4[A, B, C, D]) AddFn(entity Entity, fn func(*A, *B, *C, *D), r
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange4.Removes] from the given entity.
 func (ex *Exchange4[A, B, C, D]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -730,6 +764,7 @@ func (ex *Exchange4[A, B, C, D]) ExchangeFn(entity Entity, fn func(*A, *B, *C, *
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -816,6 +851,7 @@ func (ex *Exchange4[A, B, C, D]) exchangeBatchFn(batch *Batch, fn func(Entity, *
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange4[A, B, C, D]) runCallback(entity Entity, fn func(a *A, b *B, c *C, d *D)) {
@@ -856,6 +892,8 @@ func (*Exchange5[A, B, C, D, E]) New(world *World) *Exchange5[A, B, C, D, E] {
 // NewExchange5 creates an [Exchange5].
 //
 // See also [Exchange5.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange5[A any, B any, C any, D any, E any](world *World) *Exchange5[A, B, C, D, E] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -864,6 +902,9 @@ func NewExchange5[A any, B any, C any, D any, E any](world *World) *Exchange5[A,
 		ComponentID[D](world),
 		ComponentID[E](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange5")
+	}
 	return &Exchange5[A, B, C, D, E]{
 		world: world,
 		ids:   ids,
@@ -911,11 +952,13 @@ func (ex *Exchange5[A, B, C, D, E]) AddFn(entity Entity, fn func(*A, *B, *C, *D,
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange5.Removes] from the given entity.
 func (ex *Exchange5[A, B, C, D, E]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -953,6 +996,7 @@ func (ex *Exchange5[A, B, C, D, E]) ExchangeFn(entity Entity, fn func(*A, *B, *C
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -1043,6 +1087,7 @@ func (ex *Exchange5[A, B, C, D, E]) exchangeBatchFn(batch *Batch, fn func(Entity
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange5[A, B, C, D, E]) runCallback(entity Entity, fn func(a *A, b *B, c *C, d *D, e *E)) {
@@ -1084,6 +1129,8 @@ func (*Exchange6[A, B, C, D, E, F]) New(world *World) *Exchange6[A, B, C, D, E,
 // NewExchange6 creates an [Exchange6].
 //
 // See also [Exchange6.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange6[A any, B any, C any, D any, E any, F any](world *World) *Exchange6[A, B, C, D, E, F] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -1093,6 +1140,9 @@ func NewExchange6[A any, B any, C any, D any, E any, F any](world *World) *Excha
 		ComponentID[E](world),
 		ComponentID[F](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange6")
+	}
 	return &Exchange6[A, B, C, D, E, F]{
 		world: world,
 		ids:   ids,
@@ -1141,11 +1191,13 @@ func (ex *Exchange6[A, B, C, D, E, F]) AddFn(entity Entity, fn func(*A, *B, *C,
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange6.Removes] from the given entity.
 func (ex *Exchange6[A, B, C, D, E, F]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -1184,6 +1236,7 @@ func (ex *Exchange6[A, B, C, D, E, F]) ExchangeFn(entity Entity, fn func(*A, *B,
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -1278,6 +1331,7 @@ func (ex *Exchange6[A, B, C, D, E, F]) exchangeBatchFn(batch *Batch, fn func(Ent
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange6[A, B, C, D, E, F]) runCallback(entity Entity, fn func(a *A, b *B, c *C, d *D, e *E, f *F)) {
@@ -1320,6 +1374,8 @@ func (*Exchange7[A, B, C, D, E, F, G]) New(world *World) *Exchange7[A, B, C, D,
 // NewExchange7 creates an [Exchange7].
 //
 // See also [Exchange7.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange7[A any, B any, C any, D any, E any, F any, G any](world *World) *Exchange7[A, B, C, D, E, F, G] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -1330,6 +1386,9 @@ func NewExchange7[A any, B any, C any, D any, E any, F any, G any](world *World)
 		ComponentID[F](world),
 		ComponentID[G](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange7")
+	}
 	return &Exchange7[A, B, C, D, E, F, G]{
 		world: world,
 		ids:   ids,
@@ -1379,11 +1438,13 @@ func (ex *Exchange7[A, B, C, D, E, F, G]) AddFn(entity Entity, fn func(*A, *B, *
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange7.Removes] from the given entity.
 func (ex *Exchange7[A, B, C, D, E, F, G]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -1423,6 +1484,7 @@ func (ex *Exchange7[A, B, C, D, E, F, G]) ExchangeFn(entity Entity, fn func(*A,
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -1521,6 +1583,7 @@ func (ex *Exchange7[A, B, C, D, E, F, G]) exchangeBatchFn(batch *Batch, fn func(
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange7[A, B, C, D, E, F, G]) runCallback(entity Entity, fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G)) {
@@ -1564,6 +1627,8 @@ func (*Exchange8[A, B, C, D, E, F, G, H]) New(world *World) *Exchange8[A, B, C,
 // NewExchange8 creates an [Exchange8].
 //
 // See also [Exchange8.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
 func NewExchange8[A any, B any, C any, D any, E any, F any, G any, H any](world *World) *Exchange8[A, B, C, D, E, F, G, H] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -1575,6 +1640,9 @@ func NewExchange8[A any, B any, C any, D any, E any, F any, G any, H any](world
 		ComponentID[G](world),
 		ComponentID[H](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Exchange8")
+	}
 	return &Exchange8[A, B, C, D, E, F, G, H]{
 		world: world,
 		ids:   ids,
@@ -1625,11 +1693,13 @@ func (ex *Exchange8[A, B, C, D, E, F, G, H]) AddFn(entity Entity, fn func(*A, *B
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // Remove the components previously specified with [Exchange8.Removes] from the given entity.
 func (ex *Exchange8[A, B, C, D, E, F, G, H]) Remove(entity Entity) {
 	ex.world.remove(entity, ex.remove)
+	ex.world.flushEvents()
 }
 
 // Exchange performs the exchange on the given entity, adding the provided components
@@ -1670,6 +1740,7 @@ func (ex *Exchange8[A, B, C, D, E, F, G, H]) ExchangeFn(entity Entity, fn func(*
 	if len(rel) > 0 {
 		ex.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	ex.world.flushEvents()
 }
 
 // AddBatch adds the mapped components to all entities matching the given batch filter.
@@ -1772,6 +1843,7 @@ func (ex *Exchange8[A, B, C, D, E, F, G, H]) exchangeBatchFn(batch *Batch, fn fu
 	} else {
 		ex.world.exchangeBatch(batch, ex.ids, nil, ex.relations, process)
 	}
+	ex.world.flushEvents()
 }
 
 func (ex *Exchange8[A, B, C, D, E, F, G, H]) runCallback(entity Entity, fn func(a *A, b *B, c *C, d *D, e *E, f *F, g *G, h *H)) {
@@ -0,0 +1,83 @@
+package ecs
+
+// exprOp is the operation of a [FilterExpr].
+type exprOp uint8
+
+const (
+	exprAll exprOp = iota
+	exprHas
+	exprAnd
+	exprOr
+	exprNot
+)
+
+// FilterExpr is a boolean expression on component presence, for use with [UnsafeFilter.Where].
+//
+// Expressions are created with [Has], [And], [Or] and [Not], and can be nested arbitrarily.
+// They are evaluated against the component mask of each archetype.
+// For registered filters, the result is evaluated only once per archetype.
+//
+// The zero value matches all archetypes.
+type FilterExpr struct {
+	operands []FilterExpr
+	id       ID
+	op       exprOp
+}
+
+// Has creates a [FilterExpr] that requires the given component.
+func Has(id ID) FilterExpr {
+	return FilterExpr{op: exprHas, id: id}
+}
+
+// And creates a [FilterExpr] that requires all the given expressions to be fulfilled.
+func And(exprs ...FilterExpr) FilterExpr {
+	return FilterExpr{op: exprAnd, operands: exprs}
+}
+
+// Or creates a [FilterExpr] that requires at least one of the given expressions to be fulfilled.
+func Or(exprs ...FilterExpr) FilterExpr {
+	return FilterExpr{op: exprOr, operands: exprs}
+}
+
+// Not creates a [FilterExpr] that negates the given expression.
+func Not(expr FilterExpr) FilterExpr {
+	return FilterExpr{op: exprNot, operands: []FilterExpr{expr}}
+}
+
+// matches the expression against a (archetype) mask.
+func (e *FilterExpr) matches(mask *bitMask) bool {
+	switch e.op {
+	case exprHas:
+		return mask.Get(e.id.id)
+	case exprAnd:
+		for i := range e.operands {
+			if !e.operands[i].matches(mask) {
+				return false
+			}
+		}
+		return true
+	case exprOr:
+		for i := range e.operands {
+			if e.operands[i].matches(mask) {
+				return true
+			}
+		}
+		return false
+	case exprNot:
+		return !e.operands[0].matches(mask)
+	}
+	return true
+}
+
+// references reports whether the expression refers to the given component.
+func (e *FilterExpr) references(id ID) bool {
+	if e.op == exprHas {
+		return e.id == id
+	}
+	for i := range e.operands {
+		if e.operands[i].references(id) {
+			return true
+		}
+	}
+	return false
+}
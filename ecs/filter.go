@@ -6,7 +6,8 @@ package ecs
 // and should only be used when component types are not known at compile time.
 type UnsafeFilter struct {
 	filter
-	world *World
+	world     *World
+	exclusive bool // Whether the filter is exclusive, applied in Query
 }
 
 // NewUnsafeFilter creates a new [UnsafeFilter] matching the given components.
@@ -17,6 +18,15 @@ func NewUnsafeFilter(world *World, ids ...ID) UnsafeFilter {
 	}
 }
 
+// references reports whether the filter refers to the given component,
+// by requiring or excluding it.
+func (f *filter) references(id ID) bool {
+	if f.mask.Get(id.id) || (f.hasWithout && f.without.Get(id.id)) {
+		return true
+	}
+	return f.clauses != nil && f.clauses.references(id)
+}
+
 // Without specifies components to exclude.
 // Resets previous excludes.
 func (f UnsafeFilter) Without(ids ...ID) UnsafeFilter {
@@ -28,22 +38,46 @@ func (f UnsafeFilter) Without(ids ...ID) UnsafeFilter {
 }
 
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed.
+// and no other components are allowed. This includes components set via [UnsafeFilter.AnyOf].
 //
-// Overwrites components set via [Filter.Without].
+// It is applied when creating a query, so it does not matter
+// whether other components are specified before or after calling it.
+// Overwrites components set via [UnsafeFilter.Without].
 func (f UnsafeFilter) Exclusive() UnsafeFilter {
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// AnyOf specifies components of which at least one is required.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given.
+func (f UnsafeFilter) AnyOf(ids ...ID) UnsafeFilter {
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Where specifies a boolean expression on component presence that must be fulfilled.
+// See [FilterExpr] for details.
+// Resets previous expressions.
+func (f UnsafeFilter) Where(expr FilterExpr) UnsafeFilter {
+	f.filter = f.filter.Where(expr)
 	return f
 }
 
 // Query returns a new query matching this filter and the given entity relation targets.
 func (f UnsafeFilter) Query(relations ...Relation) UnsafeQuery {
 	rel := relationSlice(relations).ToRelationIDsForUnsafe(f.world, nil)
+	var conditions *entityConditions
+	if f.world.storage.registry.hasMultiRelations {
+		rel, _, conditions = f.world.storage.multiTargetParams(rel, 0, nil)
+	}
 	return UnsafeQuery{
-		world:     f.world,
-		filter:    f.filter,
-		relations: rel,
-		lock:      f.world.lockSafe(),
+		world:      f.world,
+		filter:     f.build(),
+		relations:  rel,
+		conditions: conditions,
+		lock:       f.world.lockSafe(),
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
@@ -53,10 +87,19 @@ func (f UnsafeFilter) Query(relations ...Relation) UnsafeQuery {
 	}
 }
 
+// build returns the filter with exclusivity applied.
+func (f *UnsafeFilter) build() filter {
+	if f.exclusive {
+		return f.filter.Exclusive()
+	}
+	return f.filter
+}
+
 // filter is an mask filter for component presence and optional absence.
 type filter struct {
 	mask       bitMask
 	without    bitMask
+	clauses    *filterClauses // Optional, additional conditions.
 	cache      cacheID
 	hasWithout bool
 }
@@ -71,7 +114,10 @@ func newFilter(ids ...ID) filter {
 
 // matches this filter against a (archetype) mask.
 func (f *filter) matches(mask *bitMask) bool {
-	return mask.Contains(&f.mask) && (!f.hasWithout || !mask.ContainsAny(&f.without))
+	if !mask.Contains(&f.mask) || (f.hasWithout && mask.ContainsAny(&f.without)) {
+		return false
+	}
+	return f.clauses == nil || f.clauses.matches(mask)
 }
 
 // Without specifies components to exclude.
@@ -84,8 +130,73 @@ func (f filter) Without(ids ...ID) filter {
 
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
 // and no other components are allowed.
+// Components from AnyOf clauses are allowed.
 func (f filter) Exclusive() filter {
-	f.without = f.mask.Not()
+	allowed := f.mask
+	if f.clauses != nil {
+		for i := range f.clauses.anyOf {
+			allowed.OrI(&f.clauses.anyOf[i])
+		}
+	}
+	f.without = allowed.Not()
 	f.hasWithout = true
 	return f
 }
+
+// AnyOf adds a clause of components of which at least one is required.
+func (f filter) AnyOf(ids ...ID) filter {
+	if len(ids) == 0 {
+		panic("at least one component required for AnyOf")
+	}
+	clauses := f.clauses.copy()
+	clauses.anyOf = append(clauses.anyOf, newMask(ids...))
+	f.clauses = clauses
+	return f
+}
+
+// Where sets a boolean expression that must be fulfilled.
+func (f filter) Where(expr FilterExpr) filter {
+	clauses := f.clauses.copy()
+	clauses.expr = &expr
+	f.clauses = clauses
+	return f
+}
+
+// filterClauses holds additional, less commonly used conditions of a filter.
+// They are stored behind a pointer to keep filters small and comparable.
+type filterClauses struct {
+	anyOf []bitMask   // Clauses of components of which at least one is required.
+	expr  *FilterExpr // Boolean expression.
+}
+
+// copy creates a copy of the clauses, or new clauses if called on nil.
+// Used to avoid modifying filters that share the clauses.
+func (c *filterClauses) copy() *filterClauses {
+	if c == nil {
+		return &filterClauses{}
+	}
+	return &filterClauses{
+		anyOf: append([]bitMask(nil), c.anyOf...),
+		expr:  c.expr,
+	}
+}
+
+// matches the clauses against a (archetype) mask.
+func (c *filterClauses) matches(mask *bitMask) bool {
+	for i := range c.anyOf {
+		if !mask.ContainsAny(&c.anyOf[i]) {
+			return false
+		}
+	}
+	return c.expr == nil || c.expr.matches(mask)
+}
+
+// references reports whether any of the clauses refers to the given component.
+func (c *filterClauses) references(id ID) bool {
+	for i := range c.anyOf {
+		if c.anyOf[i].Get(id.id) {
+			return true
+		}
+	}
+	return c.expr != nil && c.expr.references(id)
+}
@@ -3,6 +3,7 @@ package ecs
 // Code generated by go generate; DO NOT EDIT.
 
 import (
+	"fmt"
 	"sync"
 	"unsafe"
 )
@@ -18,11 +19,20 @@ type Filter0 struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
 	filter       filter
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter0]. It is safe to call on `nil` instance.
@@ -41,20 +51,28 @@ func (*Filter0) New(world *World) *Filter0 {
 func NewFilter0(world *World) *Filter0 {
 	ids := []ID{}
 	components := make([]*componentStorage, 0)
-	return &Filter0{
+	f := &Filter0{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter0) With(comps ...Comp) *Filter0 {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -63,6 +81,8 @@ func (f *Filter0) With(comps ...Comp) *Filter0 {
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter0.With] apply.
 func (f *Filter0) Without(comps ...Comp) *Filter0 {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -70,19 +90,106 @@ func (f *Filter0) Without(comps ...Comp) *Filter0 {
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter0) AnyOf(comps ...Comp) *Filter0 {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter0.With].
+// and no other components are allowed. This includes components set via [Filter0.With] and [Filter0.AnyOf].
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter0.Without].
 func (f *Filter0) Exclusive() *Filter0 {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter0.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query0.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query0.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query0.Count] and [Query0.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter0) Changed(comps ...Comp) *Filter0 {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter0.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter0.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter0) Added(comps ...Comp) *Filter0 {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter0.Changed] and [Filter0.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter0) Since(tick uint32) *Filter0 {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -101,6 +208,34 @@ func (f *Filter0) Relations(rel ...Relation) *Filter0 {
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter0) Cascade(comp Comp) *Filter0 {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -109,6 +244,7 @@ func (f *Filter0) Register() *Filter0 {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -127,6 +263,7 @@ func (f *Filter0) Unregister() {
 // Relation targets provided here are added to those specified with [Filter0.Relations].
 // Relation components must be in the filter's parameters or added via [Filter0.With] beforehand.
 func (f *Filter0) Query(rel ...Relation) Query0 {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -139,11 +276,29 @@ func (f *Filter0) Query(rel ...Relation) Query0 {
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query0{
 		world:      f.world,
@@ -152,6 +307,9 @@ func (f *Filter0) Query(rel ...Relation) Query0 {
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
@@ -159,7 +317,7 @@ func (f *Filter0) Query(rel ...Relation) Query0 {
 			maxIndex:  -1,
 		},
 		rareComp:    f.rareComp,
-		hasRareComp: len(f.ids) > 0,
+		hasRareComp: f.hasRareComp,
 	}
 }
 
@@ -172,8 +330,18 @@ func (f *Filter0) Query(rel ...Relation) Query0 {
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter0.Batch] or [Filter0.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter0) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -184,15 +352,46 @@ func (f *Filter0) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter0) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter0) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
 func (f *Filter0) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter0) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter1 is a filter for 1 components.
 // Used to create [Query1] iterators.
 //
@@ -204,11 +403,22 @@ type Filter1[A any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter1]. It is safe to call on `nil` instance.
@@ -230,20 +440,31 @@ func NewFilter1[A any](world *World) *Filter1[A] {
 	}
 	components := make([]*componentStorage, 1)
 	components[0] = &world.storage.components[ids[0].id]
-	return &Filter1[A]{
+	f := &Filter1[A]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter1[A]) With(comps ...Comp) *Filter1[A] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -252,6 +473,8 @@ func (f *Filter1[A]) With(comps ...Comp) *Filter1[A] {
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter1.With] apply.
 func (f *Filter1[A]) Without(comps ...Comp) *Filter1[A] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -259,19 +482,144 @@ func (f *Filter1[A]) Without(comps ...Comp) *Filter1[A] {
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter1[A]) AnyOf(comps ...Comp) *Filter1[A] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query1.GetOptional] returns a nil pointer,
+// and [Query1.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter1.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter1.Changed] and [Filter1.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter1[A]) Optional(comps ...Comp) *Filter1[A] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:1] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter1.With].
+// and no other components are allowed. This includes components set via [Filter1.With] and [Filter1.AnyOf].
+// Components marked via [Filter1.Optional] are allowed, but not required.
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter1.Without].
 func (f *Filter1[A]) Exclusive() *Filter1[A] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter1.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query1.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query1.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query1.Count] and [Query1.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter1.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter1[A]) Changed(comps ...Comp) *Filter1[A] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter1.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter1.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter1[A]) Added(comps ...Comp) *Filter1[A] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter1.Changed] and [Filter1.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter1[A]) Since(tick uint32) *Filter1[A] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -290,6 +638,34 @@ func (f *Filter1[A]) Relations(rel ...Relation) *Filter1[A] {
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter1[A]) Cascade(comp Comp) *Filter1[A] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -298,6 +674,7 @@ func (f *Filter1[A]) Register() *Filter1[A] {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -316,6 +693,7 @@ func (f *Filter1[A]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter1.Relations].
 // Relation components must be in the filter's parameters or added via [Filter1.With] beforehand.
 func (f *Filter1[A]) Query(rel ...Relation) Query1[A] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -328,11 +706,29 @@ func (f *Filter1[A]) Query(rel ...Relation) Query1[A] {
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query1[A]{
 		world:      f.world,
@@ -341,14 +737,20 @@ func (f *Filter1[A]) Query(rel ...Relation) Query1[A] {
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -361,8 +763,18 @@ func (f *Filter1[A]) Query(rel ...Relation) Query1[A] {
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter1.Batch] or [Filter1.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter1[A]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -373,15 +785,64 @@ func (f *Filter1[A]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter1[A]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter1[A]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter1[A]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter1[A]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter1[A]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:1] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter2 is a filter for 2 components.
 // Used to create [Query2] iterators.
 //
@@ -391,11 +852,22 @@ type Filter2[A any, B any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter2]. It is safe to call on `nil` instance.
@@ -417,20 +889,31 @@ func NewFilter2[A any, B any](world *World) *Filter2[A, B] {
 	components := make([]*componentStorage, 2)
 	components[0] = &world.storage.components[ids[0].id]
 	components[1] = &world.storage.components[ids[1].id]
-	return &Filter2[A, B]{
+	f := &Filter2[A, B]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter2[A, B]) With(comps ...Comp) *Filter2[A, B] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -439,6 +922,8 @@ func (f *Filter2[A, B]) With(comps ...Comp) *Filter2[A, B] {
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter2.With] apply.
 func (f *Filter2[A, B]) Without(comps ...Comp) *Filter2[A, B] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -446,19 +931,144 @@ func (f *Filter2[A, B]) Without(comps ...Comp) *Filter2[A, B] {
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter2[A, B]) AnyOf(comps ...Comp) *Filter2[A, B] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query2.GetOptional] returns a nil pointer,
+// and [Query2.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter2.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter2.Changed] and [Filter2.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter2[A, B]) Optional(comps ...Comp) *Filter2[A, B] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:2] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter2.With].
+// and no other components are allowed. This includes components set via [Filter2.With] and [Filter2.AnyOf].
+// Components marked via [Filter2.Optional] are allowed, but not required.
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter2.Without].
 func (f *Filter2[A, B]) Exclusive() *Filter2[A, B] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter2.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query2.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query2.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query2.Count] and [Query2.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter2.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter2[A, B]) Changed(comps ...Comp) *Filter2[A, B] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter2.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter2.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter2[A, B]) Added(comps ...Comp) *Filter2[A, B] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter2.Changed] and [Filter2.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter2[A, B]) Since(tick uint32) *Filter2[A, B] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -477,6 +1087,34 @@ func (f *Filter2[A, B]) Relations(rel ...Relation) *Filter2[A, B] {
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter2[A, B]) Cascade(comp Comp) *Filter2[A, B] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -485,6 +1123,7 @@ func (f *Filter2[A, B]) Register() *Filter2[A, B] {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -503,6 +1142,7 @@ func (f *Filter2[A, B]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter2.Relations].
 // Relation components must be in the filter's parameters or added via [Filter2.With] beforehand.
 func (f *Filter2[A, B]) Query(rel ...Relation) Query2[A, B] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -515,11 +1155,29 @@ func (f *Filter2[A, B]) Query(rel ...Relation) Query2[A, B] {
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query2[A, B]{
 		world:      f.world,
@@ -528,15 +1186,21 @@ func (f *Filter2[A, B]) Query(rel ...Relation) Query2[A, B] {
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -549,8 +1213,18 @@ func (f *Filter2[A, B]) Query(rel ...Relation) Query2[A, B] {
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter2.Batch] or [Filter2.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter2[A, B]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -561,15 +1235,64 @@ func (f *Filter2[A, B]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter2[A, B]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter2[A, B]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter2[A, B]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter2[A, B]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter2[A, B]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:2] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter3 is a filter for 3 components.
 // Used to create [Query3] iterators.
 //
@@ -581,11 +1304,22 @@ type Filter3[A any, B any, C any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter3]. It is safe to call on `nil` instance.
@@ -611,20 +1345,31 @@ func NewFilter3[A any, B any, C any](world *World) *Filter3[A, B, C] {
 	components[0] = &world.storage.components[ids[0].id]
 	components[1] = &world.storage.components[ids[1].id]
 	components[2] = &world.storage.components[ids[2].id]
-	return &Filter3[A, B, C]{
+	f := &Filter3[A, B, C]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter3[A, B, C]) With(comps ...Comp) *Filter3[A, B, C] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -633,6 +1378,8 @@ func (f *Filter3[A, B, C]) With(comps ...Comp) *Filter3[A, B, C] {
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter3.With] apply.
 func (f *Filter3[A, B, C]) Without(comps ...Comp) *Filter3[A, B, C] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -640,24 +1387,149 @@ func (f *Filter3[A, B, C]) Without(comps ...Comp) *Filter3[A, B, C] {
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
-// Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter3.With].
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
 //
-// Overwrites components set via [Filter3.Without].
-func (f *Filter3[A, B, C]) Exclusive() *Filter3[A, B, C] {
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter3[A, B, C]) AnyOf(comps ...Comp) *Filter3[A, B, C] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
 	return f
 }
 
-// Relations sets permanent entity relation targets for this filter.
-// Relation targets set here are included in filter caching.
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query3.GetOptional] returns a nil pointer,
+// and [Query3.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter3.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter3.Changed] and [Filter3.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter3[A, B, C]) Optional(comps ...Comp) *Filter3[A, B, C] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:3] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
+// Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
+// and no other components are allowed. This includes components set via [Filter3.With] and [Filter3.AnyOf].
+// Components marked via [Filter3.Optional] are allowed, but not required.
+//
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
+// Overwrites components set via [Filter3.Without].
+func (f *Filter3[A, B, C]) Exclusive() *Filter3[A, B, C] {
+	f.checkModify()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter3.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query3.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query3.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query3.Count] and [Query3.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter3.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter3[A, B, C]) Changed(comps ...Comp) *Filter3[A, B, C] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter3.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter3.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter3[A, B, C]) Added(comps ...Comp) *Filter3[A, B, C] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter3.Changed] and [Filter3.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter3[A, B, C]) Since(tick uint32) *Filter3[A, B, C] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
+	return f
+}
+
+// Relations sets permanent entity relation targets for this filter.
+// Relation targets set here are included in filter caching.
 // Contrary, relation targets specified in [Filter3.Query] or [Filter3.Batch] are not cached.
 //
 // Relation components used here must be in the filter's parameters
@@ -671,6 +1543,34 @@ func (f *Filter3[A, B, C]) Relations(rel ...Relation) *Filter3[A, B, C] {
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter3[A, B, C]) Cascade(comp Comp) *Filter3[A, B, C] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -679,6 +1579,7 @@ func (f *Filter3[A, B, C]) Register() *Filter3[A, B, C] {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -697,6 +1598,7 @@ func (f *Filter3[A, B, C]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter3.Relations].
 // Relation components must be in the filter's parameters or added via [Filter3.With] beforehand.
 func (f *Filter3[A, B, C]) Query(rel ...Relation) Query3[A, B, C] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -709,11 +1611,29 @@ func (f *Filter3[A, B, C]) Query(rel ...Relation) Query3[A, B, C] {
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query3[A, B, C]{
 		world:      f.world,
@@ -722,16 +1642,22 @@ func (f *Filter3[A, B, C]) Query(rel ...Relation) Query3[A, B, C] {
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
-		columnPtrC: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		columnPtrC:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -744,8 +1670,18 @@ func (f *Filter3[A, B, C]) Query(rel ...Relation) Query3[A, B, C] {
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter3.Batch] or [Filter3.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter3[A, B, C]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -756,15 +1692,64 @@ func (f *Filter3[A, B, C]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter3[A, B, C]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter3[A, B, C]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter3[A, B, C]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter3[A, B, C]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter3[A, B, C]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:3] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter4 is a filter for 4 components.
 // Used to create [Query4] iterators.
 //
@@ -776,11 +1761,22 @@ type Filter4[A any, B any, C any, D any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter4]. It is safe to call on `nil` instance.
@@ -808,20 +1804,31 @@ func NewFilter4[A any, B any, C any, D any](world *World) *Filter4[A, B, C, D] {
 	components[1] = &world.storage.components[ids[1].id]
 	components[2] = &world.storage.components[ids[2].id]
 	components[3] = &world.storage.components[ids[3].id]
-	return &Filter4[A, B, C, D]{
+	f := &Filter4[A, B, C, D]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter4[A, B, C, D]) With(comps ...Comp) *Filter4[A, B, C, D] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -830,6 +1837,8 @@ func (f *Filter4[A, B, C, D]) With(comps ...Comp) *Filter4[A, B, C, D] {
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter4.With] apply.
 func (f *Filter4[A, B, C, D]) Without(comps ...Comp) *Filter4[A, B, C, D] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -837,19 +1846,144 @@ func (f *Filter4[A, B, C, D]) Without(comps ...Comp) *Filter4[A, B, C, D] {
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter4[A, B, C, D]) AnyOf(comps ...Comp) *Filter4[A, B, C, D] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query4.GetOptional] returns a nil pointer,
+// and [Query4.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter4.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter4.Changed] and [Filter4.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter4[A, B, C, D]) Optional(comps ...Comp) *Filter4[A, B, C, D] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:4] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter4.With].
+// and no other components are allowed. This includes components set via [Filter4.With] and [Filter4.AnyOf].
+// Components marked via [Filter4.Optional] are allowed, but not required.
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter4.Without].
 func (f *Filter4[A, B, C, D]) Exclusive() *Filter4[A, B, C, D] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter4.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query4.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query4.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query4.Count] and [Query4.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter4.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter4[A, B, C, D]) Changed(comps ...Comp) *Filter4[A, B, C, D] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter4.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter4.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter4[A, B, C, D]) Added(comps ...Comp) *Filter4[A, B, C, D] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter4.Changed] and [Filter4.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter4[A, B, C, D]) Since(tick uint32) *Filter4[A, B, C, D] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -868,6 +2002,34 @@ func (f *Filter4[A, B, C, D]) Relations(rel ...Relation) *Filter4[A, B, C, D] {
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter4[A, B, C, D]) Cascade(comp Comp) *Filter4[A, B, C, D] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -876,6 +2038,7 @@ func (f *Filter4[A, B, C, D]) Register() *Filter4[A, B, C, D] {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -894,6 +2057,7 @@ func (f *Filter4[A, B, C, D]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter4.Relations].
 // Relation components must be in the filter's parameters or added via [Filter4.With] beforehand.
 func (f *Filter4[A, B, C, D]) Query(rel ...Relation) Query4[A, B, C, D] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -906,11 +2070,29 @@ func (f *Filter4[A, B, C, D]) Query(rel ...Relation) Query4[A, B, C, D] {
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query4[A, B, C, D]{
 		world:      f.world,
@@ -919,17 +2101,23 @@ func (f *Filter4[A, B, C, D]) Query(rel ...Relation) Query4[A, B, C, D] {
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
-		columnPtrC: unsafe.Pointer(nilDummy),
-		columnPtrD: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		columnPtrC:  unsafe.Pointer(nilDummy),
+		columnPtrD:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -942,8 +2130,18 @@ func (f *Filter4[A, B, C, D]) Query(rel ...Relation) Query4[A, B, C, D] {
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter4.Batch] or [Filter4.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter4[A, B, C, D]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -954,15 +2152,64 @@ func (f *Filter4[A, B, C, D]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter4[A, B, C, D]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter4[A, B, C, D]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter4[A, B, C, D]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter4[A, B, C, D]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter4[A, B, C, D]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:4] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter5 is a filter for 5 components.
 // Used to create [Query5] iterators.
 //
@@ -974,11 +2221,22 @@ type Filter5[A any, B any, C any, D any, E any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter5]. It is safe to call on `nil` instance.
@@ -1008,20 +2266,31 @@ func NewFilter5[A any, B any, C any, D any, E any](world *World) *Filter5[A, B,
 	components[2] = &world.storage.components[ids[2].id]
 	components[3] = &world.storage.components[ids[3].id]
 	components[4] = &world.storage.components[ids[4].id]
-	return &Filter5[A, B, C, D, E]{
+	f := &Filter5[A, B, C, D, E]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter5[A, B, C, D, E]) With(comps ...Comp) *Filter5[A, B, C, D, E] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -1030,6 +2299,8 @@ func (f *Filter5[A, B, C, D, E]) With(comps ...Comp) *Filter5[A, B, C, D, E] {
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter5.With] apply.
 func (f *Filter5[A, B, C, D, E]) Without(comps ...Comp) *Filter5[A, B, C, D, E] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -1037,19 +2308,144 @@ func (f *Filter5[A, B, C, D, E]) Without(comps ...Comp) *Filter5[A, B, C, D, E]
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter5[A, B, C, D, E]) AnyOf(comps ...Comp) *Filter5[A, B, C, D, E] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query5.GetOptional] returns a nil pointer,
+// and [Query5.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter5.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter5.Changed] and [Filter5.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter5[A, B, C, D, E]) Optional(comps ...Comp) *Filter5[A, B, C, D, E] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:5] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter5.With].
+// and no other components are allowed. This includes components set via [Filter5.With] and [Filter5.AnyOf].
+// Components marked via [Filter5.Optional] are allowed, but not required.
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter5.Without].
 func (f *Filter5[A, B, C, D, E]) Exclusive() *Filter5[A, B, C, D, E] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter5.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query5.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query5.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query5.Count] and [Query5.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter5.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter5[A, B, C, D, E]) Changed(comps ...Comp) *Filter5[A, B, C, D, E] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter5.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter5.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter5[A, B, C, D, E]) Added(comps ...Comp) *Filter5[A, B, C, D, E] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter5.Changed] and [Filter5.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter5[A, B, C, D, E]) Since(tick uint32) *Filter5[A, B, C, D, E] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -1068,6 +2464,34 @@ func (f *Filter5[A, B, C, D, E]) Relations(rel ...Relation) *Filter5[A, B, C, D,
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter5[A, B, C, D, E]) Cascade(comp Comp) *Filter5[A, B, C, D, E] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -1076,6 +2500,7 @@ func (f *Filter5[A, B, C, D, E]) Register() *Filter5[A, B, C, D, E] {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -1094,6 +2519,7 @@ func (f *Filter5[A, B, C, D, E]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter5.Relations].
 // Relation components must be in the filter's parameters or added via [Filter5.With] beforehand.
 func (f *Filter5[A, B, C, D, E]) Query(rel ...Relation) Query5[A, B, C, D, E] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -1106,11 +2532,29 @@ func (f *Filter5[A, B, C, D, E]) Query(rel ...Relation) Query5[A, B, C, D, E] {
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query5[A, B, C, D, E]{
 		world:      f.world,
@@ -1119,18 +2563,24 @@ func (f *Filter5[A, B, C, D, E]) Query(rel ...Relation) Query5[A, B, C, D, E] {
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
-		columnPtrC: unsafe.Pointer(nilDummy),
-		columnPtrD: unsafe.Pointer(nilDummy),
-		columnPtrE: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		columnPtrC:  unsafe.Pointer(nilDummy),
+		columnPtrD:  unsafe.Pointer(nilDummy),
+		columnPtrE:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -1143,8 +2593,18 @@ func (f *Filter5[A, B, C, D, E]) Query(rel ...Relation) Query5[A, B, C, D, E] {
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter5.Batch] or [Filter5.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter5[A, B, C, D, E]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -1155,15 +2615,64 @@ func (f *Filter5[A, B, C, D, E]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter5[A, B, C, D, E]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter5[A, B, C, D, E]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter5[A, B, C, D, E]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter5[A, B, C, D, E]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter5[A, B, C, D, E]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:5] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter6 is a filter for 6 components.
 // Used to create [Query6] iterators.
 //
@@ -1175,11 +2684,22 @@ type Filter6[A any, B any, C any, D any, E any, F any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter6]. It is safe to call on `nil` instance.
@@ -1190,69 +2710,207 @@ func (*Filter6[A, B, C, D, E, F]) New(world *World) *Filter6[A, B, C, D, E, F] {
 	return NewFilter6[A, B, C, D, E, F](world)
 }
 
-// NewFilter6 creates a new [Filter6].
+// NewFilter6 creates a new [Filter6].
+//
+// Use [Filter6.Query] to obtain a [Query6].
+//
+// See also [Filter6.New] for a shortcut when constructing an already defined instance.
+func NewFilter6[A any, B any, C any, D any, E any, F any](world *World) *Filter6[A, B, C, D, E, F] {
+	ids := []ID{
+		ComponentID[A](world),
+		ComponentID[B](world),
+		ComponentID[C](world),
+		ComponentID[D](world),
+		ComponentID[E](world),
+		ComponentID[F](world),
+	}
+	components := make([]*componentStorage, 6)
+	components[0] = &world.storage.components[ids[0].id]
+	components[1] = &world.storage.components[ids[1].id]
+	components[2] = &world.storage.components[ids[2].id]
+	components[3] = &world.storage.components[ids[3].id]
+	components[4] = &world.storage.components[ids[4].id]
+	components[5] = &world.storage.components[ids[5].id]
+	f := &Filter6[A, B, C, D, E, F]{
+		world:      world,
+		ids:        ids,
+		filter:     newFilter(ids...),
+		components: components,
+	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
+}
+
+// With specifies additional components to filter for.
+// Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
+func (f *Filter6[A, B, C, D, E, F]) With(comps ...Comp) *Filter6[A, B, C, D, E, F] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
+		f.ids = append(f.ids, id)
+		f.filter.mask.Set(id.id)
+	}
+	return f
+}
+
+// Without specifies components to exclude.
+// Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter6.With] apply.
+func (f *Filter6[A, B, C, D, E, F]) Without(comps ...Comp) *Filter6[A, B, C, D, E, F] {
+	f.checkModify()
+	if len(comps) == 0 {
+		return f
+	}
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
+		f.filter.without.Set(id.id)
+		f.filter.hasWithout = true
+	}
+	return f
+}
+
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter6[A, B, C, D, E, F]) AnyOf(comps ...Comp) *Filter6[A, B, C, D, E, F] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query6.GetOptional] returns a nil pointer,
+// and [Query6.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter6.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter6.Changed] and [Filter6.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter6[A, B, C, D, E, F]) Optional(comps ...Comp) *Filter6[A, B, C, D, E, F] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:6] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
+// Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
+// and no other components are allowed. This includes components set via [Filter6.With] and [Filter6.AnyOf].
+// Components marked via [Filter6.Optional] are allowed, but not required.
+//
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
+// Overwrites components set via [Filter6.Without].
+func (f *Filter6[A, B, C, D, E, F]) Exclusive() *Filter6[A, B, C, D, E, F] {
+	f.checkModify()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter6.Since].
+// Components do not need to be in the filter's parameters.
 //
-// Use [Filter6.Query] to obtain a [Query6].
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query6.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query6.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query6.Count] and [Query6.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter6.Optional]).
 //
-// See also [Filter6.New] for a shortcut when constructing an already defined instance.
-func NewFilter6[A any, B any, C any, D any, E any, F any](world *World) *Filter6[A, B, C, D, E, F] {
-	ids := []ID{
-		ComponentID[A](world),
-		ComponentID[B](world),
-		ComponentID[C](world),
-		ComponentID[D](world),
-		ComponentID[E](world),
-		ComponentID[F](world),
-	}
-	components := make([]*componentStorage, 6)
-	components[0] = &world.storage.components[ids[0].id]
-	components[1] = &world.storage.components[ids[1].id]
-	components[2] = &world.storage.components[ids[2].id]
-	components[3] = &world.storage.components[ids[3].id]
-	components[4] = &world.storage.components[ids[4].id]
-	components[5] = &world.storage.components[ids[5].id]
-	return &Filter6[A, B, C, D, E, F]{
-		world:      world,
-		ids:        ids,
-		filter:     newFilter(ids...),
-		components: components,
-	}
-}
-
-// With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
-func (f *Filter6[A, B, C, D, E, F]) With(comps ...Comp) *Filter6[A, B, C, D, E, F] {
+func (f *Filter6[A, B, C, D, E, F]) Changed(comps ...Comp) *Filter6[A, B, C, D, E, F] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
-		f.ids = append(f.ids, id)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
 		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
 	}
 	return f
 }
 
-// Without specifies components to exclude.
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter6.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter6.Changed] for limitations.
+//
 // Can be called multiple times in chains, or once with multiple arguments.
-func (f *Filter6[A, B, C, D, E, F]) Without(comps ...Comp) *Filter6[A, B, C, D, E, F] {
+func (f *Filter6[A, B, C, D, E, F]) Added(comps ...Comp) *Filter6[A, B, C, D, E, F] {
 	f.checkModify()
-	if len(comps) == 0 {
-		return f
-	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
-		f.filter.without.Set(id.id)
-		f.filter.hasWithout = true
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
 	}
 	return f
 }
 
-// Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter6.With].
+// Since sets the tick after which changes are considered by [Filter6.Changed] and [Filter6.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
 //
-// Overwrites components set via [Filter6.Without].
-func (f *Filter6[A, B, C, D, E, F]) Exclusive() *Filter6[A, B, C, D, E, F] {
-	f.checkModify()
-	f.filter = f.filter.Exclusive()
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter6[A, B, C, D, E, F]) Since(tick uint32) *Filter6[A, B, C, D, E, F] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -1271,6 +2929,34 @@ func (f *Filter6[A, B, C, D, E, F]) Relations(rel ...Relation) *Filter6[A, B, C,
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter6[A, B, C, D, E, F]) Cascade(comp Comp) *Filter6[A, B, C, D, E, F] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -1279,6 +2965,7 @@ func (f *Filter6[A, B, C, D, E, F]) Register() *Filter6[A, B, C, D, E, F] {
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -1297,6 +2984,7 @@ func (f *Filter6[A, B, C, D, E, F]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter6.Relations].
 // Relation components must be in the filter's parameters or added via [Filter6.With] beforehand.
 func (f *Filter6[A, B, C, D, E, F]) Query(rel ...Relation) Query6[A, B, C, D, E, F] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -1309,11 +2997,29 @@ func (f *Filter6[A, B, C, D, E, F]) Query(rel ...Relation) Query6[A, B, C, D, E,
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query6[A, B, C, D, E, F]{
 		world:      f.world,
@@ -1322,19 +3028,25 @@ func (f *Filter6[A, B, C, D, E, F]) Query(rel ...Relation) Query6[A, B, C, D, E,
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
-		columnPtrC: unsafe.Pointer(nilDummy),
-		columnPtrD: unsafe.Pointer(nilDummy),
-		columnPtrE: unsafe.Pointer(nilDummy),
-		columnPtrF: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		columnPtrC:  unsafe.Pointer(nilDummy),
+		columnPtrD:  unsafe.Pointer(nilDummy),
+		columnPtrE:  unsafe.Pointer(nilDummy),
+		columnPtrF:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -1347,8 +3059,18 @@ func (f *Filter6[A, B, C, D, E, F]) Query(rel ...Relation) Query6[A, B, C, D, E,
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter6.Batch] or [Filter6.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter6[A, B, C, D, E, F]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -1359,15 +3081,64 @@ func (f *Filter6[A, B, C, D, E, F]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter6[A, B, C, D, E, F]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter6[A, B, C, D, E, F]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter6[A, B, C, D, E, F]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter6[A, B, C, D, E, F]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter6[A, B, C, D, E, F]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:6] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter7 is a filter for 7 components.
 // Used to create [Query7] iterators.
 //
@@ -1379,11 +3150,22 @@ type Filter7[A any, B any, C any, D any, E any, F any, G any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter7]. It is safe to call on `nil` instance.
@@ -1417,20 +3199,31 @@ func NewFilter7[A any, B any, C any, D any, E any, F any, G any](world *World) *
 	components[4] = &world.storage.components[ids[4].id]
 	components[5] = &world.storage.components[ids[5].id]
 	components[6] = &world.storage.components[ids[6].id]
-	return &Filter7[A, B, C, D, E, F, G]{
+	f := &Filter7[A, B, C, D, E, F, G]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter7[A, B, C, D, E, F, G]) With(comps ...Comp) *Filter7[A, B, C, D, E, F, G] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -1439,6 +3232,8 @@ func (f *Filter7[A, B, C, D, E, F, G]) With(comps ...Comp) *Filter7[A, B, C, D,
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter7.With] apply.
 func (f *Filter7[A, B, C, D, E, F, G]) Without(comps ...Comp) *Filter7[A, B, C, D, E, F, G] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -1446,19 +3241,144 @@ func (f *Filter7[A, B, C, D, E, F, G]) Without(comps ...Comp) *Filter7[A, B, C,
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter7[A, B, C, D, E, F, G]) AnyOf(comps ...Comp) *Filter7[A, B, C, D, E, F, G] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query7.GetOptional] returns a nil pointer,
+// and [Query7.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter7.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter7.Changed] and [Filter7.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter7[A, B, C, D, E, F, G]) Optional(comps ...Comp) *Filter7[A, B, C, D, E, F, G] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:7] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter7.With].
+// and no other components are allowed. This includes components set via [Filter7.With] and [Filter7.AnyOf].
+// Components marked via [Filter7.Optional] are allowed, but not required.
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter7.Without].
 func (f *Filter7[A, B, C, D, E, F, G]) Exclusive() *Filter7[A, B, C, D, E, F, G] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter7.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query7.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query7.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query7.Count] and [Query7.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter7.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter7[A, B, C, D, E, F, G]) Changed(comps ...Comp) *Filter7[A, B, C, D, E, F, G] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter7.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter7.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter7[A, B, C, D, E, F, G]) Added(comps ...Comp) *Filter7[A, B, C, D, E, F, G] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter7.Changed] and [Filter7.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter7[A, B, C, D, E, F, G]) Since(tick uint32) *Filter7[A, B, C, D, E, F, G] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -1477,6 +3397,34 @@ func (f *Filter7[A, B, C, D, E, F, G]) Relations(rel ...Relation) *Filter7[A, B,
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter7[A, B, C, D, E, F, G]) Cascade(comp Comp) *Filter7[A, B, C, D, E, F, G] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -1485,6 +3433,7 @@ func (f *Filter7[A, B, C, D, E, F, G]) Register() *Filter7[A, B, C, D, E, F, G]
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -1503,6 +3452,7 @@ func (f *Filter7[A, B, C, D, E, F, G]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter7.Relations].
 // Relation components must be in the filter's parameters or added via [Filter7.With] beforehand.
 func (f *Filter7[A, B, C, D, E, F, G]) Query(rel ...Relation) Query7[A, B, C, D, E, F, G] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -1515,11 +3465,29 @@ func (f *Filter7[A, B, C, D, E, F, G]) Query(rel ...Relation) Query7[A, B, C, D,
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query7[A, B, C, D, E, F, G]{
 		world:      f.world,
@@ -1528,20 +3496,26 @@ func (f *Filter7[A, B, C, D, E, F, G]) Query(rel ...Relation) Query7[A, B, C, D,
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
-		columnPtrC: unsafe.Pointer(nilDummy),
-		columnPtrD: unsafe.Pointer(nilDummy),
-		columnPtrE: unsafe.Pointer(nilDummy),
-		columnPtrF: unsafe.Pointer(nilDummy),
-		columnPtrG: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		columnPtrC:  unsafe.Pointer(nilDummy),
+		columnPtrD:  unsafe.Pointer(nilDummy),
+		columnPtrE:  unsafe.Pointer(nilDummy),
+		columnPtrF:  unsafe.Pointer(nilDummy),
+		columnPtrG:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -1554,8 +3528,18 @@ func (f *Filter7[A, B, C, D, E, F, G]) Query(rel ...Relation) Query7[A, B, C, D,
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter7.Batch] or [Filter7.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter7[A, B, C, D, E, F, G]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -1566,15 +3550,64 @@ func (f *Filter7[A, B, C, D, E, F, G]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter7[A, B, C, D, E, F, G]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter7[A, B, C, D, E, F, G]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter7[A, B, C, D, E, F, G]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter7[A, B, C, D, E, F, G]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
 
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter7[A, B, C, D, E, F, G]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:7] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
+
 // Filter8 is a filter for 8 components.
 // Used to create [Query8] iterators.
 //
@@ -1586,11 +3619,22 @@ type Filter8[A any, B any, C any, D any, E any, F any, G any, H any] struct {
 	ids          []ID
 	relations    []relationID
 	components   []*componentStorage
+	tracker      *changeTracker
+	conditions   *entityConditions
+	sparse       []*sparseSet
 	filter       filter
+	optional     bitMask // Optional components, applied on build
 	mutex        sync.Mutex
+	buildOnce    sync.Once
 	generation   uint32
-	rareComp     uint8
+	cascade      ID
+	rareComp     idIndex
 	numRelations uint8
+	hasRareComp  bool
+	hasOptional  bool
+	hasCascade   bool
+	exclusive    bool // Whether the filter is exclusive, applied on build
+	built        bool
 }
 
 // New creates a new [Filter8]. It is safe to call on `nil` instance.
@@ -1626,20 +3670,31 @@ func NewFilter8[A any, B any, C any, D any, E any, F any, G any, H any](world *W
 	components[5] = &world.storage.components[ids[5].id]
 	components[6] = &world.storage.components[ids[6].id]
 	components[7] = &world.storage.components[ids[7].id]
-	return &Filter8[A, B, C, D, E, F, G, H]{
+	f := &Filter8[A, B, C, D, E, F, G, H]{
 		world:      world,
 		ids:        ids,
 		filter:     newFilter(ids...),
 		components: components,
 	}
+	if world.storage.registry.hasSparse {
+		f.sparse = world.storage.sparseParams(ids, &f.filter, f.entityConditions)
+	}
+	return f
 }
 
 // With specifies additional components to filter for.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// Components with sparse storage (see [StorageSparse]) are checked per entity.
+// Filters using them can't be used for table-based iteration or batch operations.
 func (f *Filter8[A, B, C, D, E, F, G, H]) With(comps ...Comp) *Filter8[A, B, C, D, E, F, G, H] {
 	f.checkModify()
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWith = append(f.conditions.sparseWith, set)
+			continue
+		}
 		f.ids = append(f.ids, id)
 		f.filter.mask.Set(id.id)
 	}
@@ -1648,6 +3703,8 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) With(comps ...Comp) *Filter8[A, B, C,
 
 // Without specifies components to exclude.
 // Can be called multiple times in chains, or once with multiple arguments.
+//
+// For components with sparse storage, the limitations described in [Filter8.With] apply.
 func (f *Filter8[A, B, C, D, E, F, G, H]) Without(comps ...Comp) *Filter8[A, B, C, D, E, F, G, H] {
 	f.checkModify()
 	if len(comps) == 0 {
@@ -1655,19 +3712,144 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Without(comps ...Comp) *Filter8[A, B,
 	}
 	for _, c := range comps {
 		id := f.world.componentID(c.tp)
+		if set := f.world.storage.sparse[id.id]; set != nil {
+			f.entityConditions().sparseWithout = append(f.conditions.sparseWithout, set)
+			continue
+		}
 		f.filter.without.Set(id.id)
 		f.filter.hasWithout = true
 	}
 	return f
 }
 
+// AnyOf specifies components of which at least one is required.
+// The components are not accessible in queries.
+// Can be called multiple times, each call adding a clause that must be fulfilled.
+//
+// Panics if no components are given, or if any of them uses sparse storage (see [StorageSparse]).
+func (f *Filter8[A, B, C, D, E, F, G, H]) AnyOf(comps ...Comp) *Filter8[A, B, C, D, E, F, G, H] {
+	f.checkModify()
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(ids[i], "AnyOf")
+	}
+	f.filter = f.filter.AnyOf(ids...)
+	return f
+}
+
+// Optional marks components from the filter's parameters as optional.
+// Entities do not need to have optional components to match the filter.
+//
+// For entities that don't have an optional component, [Query8.GetOptional] returns a nil pointer,
+// and [Query8.GetColumns] returns a nil slice for tables without the component.
+// For exclusive filters (see [Filter8.Exclusive]), optional components are allowed.
+//
+// Panics if any of the given components is not in the filter's parameters,
+// or if it is used for change detection (see [Filter8.Changed] and [Filter8.Added]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter8[A, B, C, D, E, F, G, H]) Optional(comps ...Comp) *Filter8[A, B, C, D, E, F, G, H] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		isParam := false
+		for _, paramID := range f.ids[:8] {
+			if paramID == id {
+				isParam = true
+				break
+			}
+		}
+		if !isParam {
+			panic(fmt.Sprintf("optional component with ID %d is not in the filter's parameters", id.id))
+		}
+		if f.tracker.tracks(id) {
+			panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+		}
+		f.optional.Set(id.id)
+		f.hasOptional = true
+	}
+	return f
+}
+
 // Exclusive makes the filter exclusive in the sense that the component composition is matched exactly,
-// and no other components are allowed. This includes components set via [Filter8.With].
+// and no other components are allowed. This includes components set via [Filter8.With] and [Filter8.AnyOf].
+// Components marked via [Filter8.Optional] are allowed, but not required.
 //
+// It is applied when the filter is first used, so it does not matter
+// whether other components are specified before or after calling it.
 // Overwrites components set via [Filter8.Without].
 func (f *Filter8[A, B, C, D, E, F, G, H]) Exclusive() *Filter8[A, B, C, D, E, F, G, H] {
 	f.checkModify()
-	f.filter = f.filter.Exclusive()
+	f.exclusive = true
+	return f
+}
+
+// Changed restricts the filter to entities where all the given components
+// were changed after the tick given via [Filter8.Since].
+// Components do not need to be in the filter's parameters.
+//
+// Components count as changed when they are added, set via a [Map] or [CommandBuffer],
+// or obtained for writing via GetMut of a [Map] or query.
+// Further, [Query8.MarkChanged] marks entire tables as changed, and [Chunk.MarkChanged] marks chunks in [Query8.ParallelTables].
+// Plain read access via Get does not count as a change.
+//
+// Changes are only recorded for component types used in Changed or Added of any filter.
+// Recording starts when a filter first uses the component type,
+// with all existing components counting as added and changed at that tick.
+//
+// With entity-based query iteration, unchanged rows are skipped.
+// With table-based iteration, only tables without changes are skipped.
+// Batch operations as well as [Query8.Count] and [Query8.EntityAt] do not consider changes.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]),
+// or if it is optional (see [Filter8.Optional]).
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter8[A, B, C, D, E, F, G, H]) Changed(comps ...Comp) *Filter8[A, B, C, D, E, F, G, H] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().changed = append(f.tracker.changed, id)
+	}
+	return f
+}
+
+// Added restricts the filter to entities where all the given components
+// were added after the tick given via [Filter8.Since].
+// Components do not need to be in the filter's parameters.
+//
+// See [Filter8.Changed] for limitations.
+//
+// Can be called multiple times in chains, or once with multiple arguments.
+func (f *Filter8[A, B, C, D, E, F, G, H]) Added(comps ...Comp) *Filter8[A, B, C, D, E, F, G, H] {
+	f.checkModify()
+	for _, c := range comps {
+		id := f.world.componentID(c.tp)
+		f.world.storage.checkNotSparse(id, "change detection")
+		f.checkNotOptional(id)
+		f.world.storage.trackChanges(id)
+		f.filter.mask.Set(id.id)
+		f.changeTracker().added = append(f.tracker.added, id)
+	}
+	return f
+}
+
+// Since sets the tick after which changes are considered by [Filter8.Changed] and [Filter8.Added].
+// Usually, this is the tick returned by [World.AdvanceTick] at the end of the previous run of a system.
+//
+// In contrast to other filter settings, it can be changed any time.
+//
+// Panics if the filter does not use change detection.
+func (f *Filter8[A, B, C, D, E, F, G, H]) Since(tick uint32) *Filter8[A, B, C, D, E, F, G, H] {
+	if f.tracker == nil {
+		panic("filter does not use change detection, use Changed or Added first")
+	}
+	f.tracker.since = tick
 	return f
 }
 
@@ -1686,6 +3868,34 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Relations(rel ...Relation) *Filter8[A,
 	return f
 }
 
+// Cascade makes queries iterate entities ordered by their depth along the given relation component,
+// like parents before children in a hierarchy based on a `ChildOf` relation.
+// This is useful e.g. for propagating transforms from parents to children.
+//
+// Entities without the relation component, or with the zero entity as target, have depth zero.
+// Other entities have a depth one higher than their target.
+// The relation component does not need to be in the filter's parameters.
+//
+// As all entities in an archetype table share the same relation targets, ordering is done per table.
+// The order is determined each time a query is created,
+// with an overhead proportional to the number of matching tables.
+// Batch operations are not affected.
+//
+// For multi-target relations (see [MultiRelationMarker]), the deepest target is used.
+// As their targets are stored per entity, ordering is done per entity,
+// with an overhead proportional to the number of matching entities.
+// Such queries can't be used for table-based iteration.
+//
+// Panics if the component is not a relation component.
+func (f *Filter8[A, B, C, D, E, F, G, H]) Cascade(comp Comp) *Filter8[A, B, C, D, E, F, G, H] {
+	f.checkModify()
+	id := f.world.componentID(comp.tp)
+	f.world.storage.checkRelationComponent(id)
+	f.cascade = id
+	f.hasCascade = true
+	return f
+}
+
 // Register this filter to the world's filter cache.
 //
 // Registering filters is optional.
@@ -1694,6 +3904,7 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Register() *Filter8[A, B, C, D, E, F,
 	if f.filter.cache != maxCacheID {
 		panic("filter is already registered, can't register")
 	}
+	f.build()
 	f.world.storage.registerFilter(&f.filter, f.relations[:f.numRelations])
 	return f
 }
@@ -1712,6 +3923,7 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Unregister() {
 // Relation targets provided here are added to those specified with [Filter8.Relations].
 // Relation components must be in the filter's parameters or added via [Filter8.With] beforehand.
 func (f *Filter8[A, B, C, D, E, F, G, H]) Query(rel ...Relation) Query8[A, B, C, D, E, F, G, H] {
+	f.build()
 	relations := relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], true)
 
 	var start uint8
@@ -1724,11 +3936,29 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Query(rel ...Relation) Query8[A, B, C,
 		gen := reg.version
 		if f.generation != gen {
 			f.mutex.Lock()
-			f.rareComp = reg.rareComponent(f.ids).id
+			rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+			f.rareComp, f.hasRareComp = rare.id, ok
 			f.generation = gen
 			f.mutex.Unlock()
 		}
 	}
+	storage := &f.world.storage
+	conditions := f.conditions
+	if storage.registry.hasMultiRelations {
+		relations, start, conditions = storage.multiTargetParams(relations, start, conditions)
+	}
+	var driver []Entity
+	if f.hasCascade && storage.registry.IsMultiRelation[f.cascade.id] {
+		driver, conditions = storage.cascadeEntities(&f.filter, cache, relations, conditions, f.cascade)
+		cache = nil
+	} else {
+		if f.hasCascade {
+			cache = storage.newCascadeEntry(&f.filter, cache, relations, f.cascade)
+		}
+		if cache == nil {
+			driver = conditions.driver()
+		}
+	}
 
 	return Query8[A, B, C, D, E, F, G, H]{
 		world:      f.world,
@@ -1737,21 +3967,27 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Query(rel ...Relation) Query8[A, B, C,
 		cache:      cache,
 		lock:       f.world.lockSafe(),
 		components: f.components,
+		tracker:    f.tracker,
+		conditions: conditions,
+		driver:     driver,
 		cursor: cursor{
 			archetype: -1,
 			table:     -1,
 			index:     0,
 			maxIndex:  -1,
 		},
-		rareComp:   f.rareComp,
-		columnPtrA: unsafe.Pointer(nilDummy),
-		columnPtrB: unsafe.Pointer(nilDummy),
-		columnPtrC: unsafe.Pointer(nilDummy),
-		columnPtrD: unsafe.Pointer(nilDummy),
-		columnPtrE: unsafe.Pointer(nilDummy),
-		columnPtrF: unsafe.Pointer(nilDummy),
-		columnPtrG: unsafe.Pointer(nilDummy),
-		columnPtrH: unsafe.Pointer(nilDummy),
+		rareComp:    f.rareComp,
+		columnPtrA:  unsafe.Pointer(nilDummy),
+		columnPtrB:  unsafe.Pointer(nilDummy),
+		columnPtrC:  unsafe.Pointer(nilDummy),
+		columnPtrD:  unsafe.Pointer(nilDummy),
+		columnPtrE:  unsafe.Pointer(nilDummy),
+		columnPtrF:  unsafe.Pointer(nilDummy),
+		columnPtrG:  unsafe.Pointer(nilDummy),
+		columnPtrH:  unsafe.Pointer(nilDummy),
+		hasRareComp: f.hasRareComp,
+		sparse:      f.sparse,
+		hasOptional: f.hasOptional,
 	}
 }
 
@@ -1764,8 +4000,18 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Query(rel ...Relation) Query8[A, B, C,
 // each time a batch operation is called.
 // Otherwise, changes to the origin filter or calls to [Filter8.Batch] or [Filter8.Query]
 // with different relationship targets may modify stored instances.
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// or if any relation targets are given for multi-target relation components (see [MultiRelationMarker]).
 func (f *Filter8[A, B, C, D, E, F, G, H]) Batch(rel ...Relation) Batch {
+	f.build()
+	if f.conditions.hasSparse() {
+		panic("batch operations are not supported for filters with sparse components")
+	}
 	f.relations = relationSlice(rel).ToRelations(f.world, &f.filter.mask, f.ids, f.relations[:f.numRelations], false)
+	if f.world.storage.registry.hasMultiRelations && f.world.storage.hasMultiRelation(f.relations) {
+		panic("batch operations are not supported for multi-target relation targets")
+	}
 	var start uint8
 	if f.filter.cache != maxCacheID {
 		start = f.numRelations
@@ -1776,11 +4022,60 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Batch(rel ...Relation) Batch {
 	}
 }
 
+func (f *Filter8[A, B, C, D, E, F, G, H]) changeTracker() *changeTracker {
+	if f.tracker == nil {
+		f.tracker = &changeTracker{}
+	}
+	return f.tracker
+}
+
+func (f *Filter8[A, B, C, D, E, F, G, H]) entityConditions() *entityConditions {
+	if f.conditions == nil {
+		f.conditions = &entityConditions{}
+	}
+	return f.conditions
+}
+
+// checkNotOptional panics if the given component is optional, as optional components can't be used for change detection.
+func (f *Filter8[A, B, C, D, E, F, G, H]) checkNotOptional(id ID) {
+	if f.optional.Get(id.id) {
+		panic(fmt.Sprintf("component with ID %d can't be optional and used for change detection", id.id))
+	}
+}
+
 func (f *Filter8[A, B, C, D, E, F, G, H]) checkModify() {
 	if f.filter.cache != maxCacheID {
 		panic("can't modify a cached filter")
 	}
-	if f.generation != 0 {
+	if f.generation != 0 || f.built {
 		panic("can't modify a filter that was already queried")
 	}
 }
+
+// build applies the settings that depend on other settings, like exclusivity and optional components.
+// This is done once, on first use of the filter, so that the order of settings does not matter.
+func (f *Filter8[A, B, C, D, E, F, G, H]) build() {
+	f.buildOnce.Do(func() {
+		if f.exclusive {
+			// Before clearing optional components from the mask, so that they are allowed.
+			f.filter = f.filter.Exclusive()
+		}
+		if f.hasOptional {
+			for _, id := range f.ids[:8] {
+				if f.optional.Get(id.id) {
+					f.filter.mask.Clear(id.id)
+					f.filter.without.Clear(id.id)
+					if set := f.world.storage.sparse[id.id]; set != nil {
+						f.conditions.removeSparseWith(set)
+					}
+				}
+			}
+		}
+		// Initialize here, as the first queries may run concurrently.
+		reg := &f.world.storage.registry
+		rare, ok := reg.rareComponent(f.ids, &f.filter.mask)
+		f.rareComp, f.hasRareComp = rare.id, ok
+		f.generation = reg.version
+		f.built = true
+	})
+}
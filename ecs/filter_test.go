@@ -27,7 +27,117 @@ func TestFilter(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		expectEqual(t, test.matches, test.filter.matches(&test.mask))
+		filter := test.filter.build()
+		expectEqual(t, test.matches, filter.matches(&test.mask))
+	}
+}
+
+func TestFilterAnyOf(t *testing.T) {
+	id1 := ID{0}
+	id2 := ID{1}
+	id3 := ID{2}
+	id4 := ID{3}
+
+	tests := []struct {
+		filter  UnsafeFilter
+		mask    bitMask
+		matches bool
+	}{
+		{NewUnsafeFilter(nil).AnyOf(id1, id2), newMask(id1), true},
+		{NewUnsafeFilter(nil).AnyOf(id1, id2), newMask(id2, id3), true},
+		{NewUnsafeFilter(nil).AnyOf(id1, id2), newMask(id3), false},
+		{NewUnsafeFilter(nil, id3).AnyOf(id1, id2), newMask(id1), false},
+
+		{NewUnsafeFilter(nil).AnyOf(id1, id2).AnyOf(id3, id4), newMask(id1, id4), true},
+		{NewUnsafeFilter(nil).AnyOf(id1, id2).AnyOf(id3, id4), newMask(id1, id2), false},
+
+		{NewUnsafeFilter(nil, id3).AnyOf(id1, id2).Exclusive(), newMask(id1, id3), true},
+		{NewUnsafeFilter(nil, id3).AnyOf(id1, id2).Exclusive(), newMask(id1, id3, id4), false},
+		{NewUnsafeFilter(nil, id3).Exclusive().AnyOf(id1, id2), newMask(id1, id3), true},
+		{NewUnsafeFilter(nil, id3).Exclusive().AnyOf(id1, id2), newMask(id1, id3, id4), false},
+		{NewUnsafeFilter(nil).AnyOf(id1, id2).Without(id1), newMask(id1, id2), false},
+	}
+
+	for _, test := range tests {
+		filter := test.filter.build()
+		expectEqual(t, test.matches, filter.matches(&test.mask))
+	}
+
+	base := NewUnsafeFilter(nil).AnyOf(id1)
+	f1 := base.AnyOf(id2)
+	f2 := base.AnyOf(id3)
+	mask := newMask(id1, id2)
+	expectTrue(t, f1.matches(&mask))
+	expectFalse(t, f2.matches(&mask))
+
+	expectPanicsWithValue(t, "at least one component required for AnyOf", func() {
+		NewUnsafeFilter(nil).AnyOf()
+	})
+}
+
+func TestFilterWhere(t *testing.T) {
+	id1 := ID{0}
+	id2 := ID{1}
+	id3 := ID{2}
+
+	expr := Or(
+		And(Has(id1), Not(Has(id2))),
+		Has(id3),
+	)
+
+	tests := []struct {
+		filter  UnsafeFilter
+		mask    bitMask
+		matches bool
+	}{
+		{NewUnsafeFilter(nil).Where(expr), newMask(id1), true},
+		{NewUnsafeFilter(nil).Where(expr), newMask(id1, id2), false},
+		{NewUnsafeFilter(nil).Where(expr), newMask(id1, id2, id3), true},
+		{NewUnsafeFilter(nil).Where(expr), newMask(id2), false},
+		{NewUnsafeFilter(nil, id2).Where(expr), newMask(id1), false},
+		{NewUnsafeFilter(nil).Where(FilterExpr{}), newMask(), true},
+		{NewUnsafeFilter(nil).Where(And()), newMask(), true},
+		{NewUnsafeFilter(nil).Where(Or()), newMask(id1), false},
+	}
+
+	for _, test := range tests {
+		filter := test.filter.build()
+		expectEqual(t, test.matches, filter.matches(&test.mask))
+	}
+}
+
+func TestFilterReferences(t *testing.T) {
+	id1 := ID{0}
+	id2 := ID{1}
+	id3 := ID{2}
+	id4 := ID{3}
+	id5 := ID{4}
+
+	expr := Or(
+		And(Has(id1), Not(Has(id2))),
+		Has(id3),
+	)
+
+	tests := []struct {
+		filter     UnsafeFilter
+		id         ID
+		references bool
+	}{
+		{NewUnsafeFilter(nil, id1), id1, true},
+		{NewUnsafeFilter(nil, id1), id2, false},
+		{NewUnsafeFilter(nil).Without(id2), id2, true},
+		{NewUnsafeFilter(nil).AnyOf(id1, id2), id2, true},
+		{NewUnsafeFilter(nil).AnyOf(id1, id2), id3, false},
+		{NewUnsafeFilter(nil).Where(expr), id1, true},
+		{NewUnsafeFilter(nil).Where(expr), id2, true},
+		{NewUnsafeFilter(nil).Where(expr), id3, true},
+		{NewUnsafeFilter(nil).Where(expr), id4, false},
+		{NewUnsafeFilter(nil).AnyOf(id4).Where(expr), id5, false},
+	}
+
+	for _, test := range tests {
+		filter := test.filter.build()
+		expectEqual(t, test.references, filter.references(test.id))
 	}
 }
 
@@ -23,7 +23,7 @@ func ComponentIDs(w *World) []ID {
 	intIds := w.storage.registry.IDs
 	ids := make([]ID, len(intIds))
 	for i, iid := range intIds {
-		ids[i] = id8(iid)
+		ids[i] = ID{id: iid}
 	}
 	return ids
 }
@@ -36,9 +36,15 @@ func ComponentInfo(w *World, id ID) (CompInfo, bool) {
 	}
 
 	return CompInfo{
-		ID:         id,
-		Type:       tp,
-		IsRelation: w.storage.registry.IsRelation[id.id],
+		ID:              id,
+		Type:            tp,
+		IsRelation:      w.storage.registry.IsRelation[id.id],
+		IsMultiRelation: w.storage.registry.IsMultiRelation[id.id],
+		IsSymmetric:     w.storage.registry.IsSymmetric[id.id],
+		IsTrivial:       w.storage.registry.IsTrivial[id.id],
+		Name:            w.storage.registry.Names[id.id],
+		Storage:         w.storage.registry.Storage[id.id],
+		HasCloner:       w.storage.registry.Cloners[id.id] != nil,
 	}, true
 }
 
@@ -20,11 +20,11 @@ func TestCompResIDs(t *testing.T) {
 	expectEqual(t, posID, tPosID)
 	expectEqual(t, rotID, tRotID)
 
-	expectEqual(t, uint8(0), posID.id)
-	expectEqual(t, uint8(1), rotID.id)
+	expectEqual(t, idIndex(0), posID.id)
+	expectEqual(t, idIndex(1), rotID.id)
 
-	expectEqual(t, uint8(0), res1ID.id)
-	expectEqual(t, uint8(1), res2ID.id)
+	expectEqual(t, idIndex(0), res1ID.id)
+	expectEqual(t, idIndex(1), res2ID.id)
 
 	expectSlicesEqual(t, []ID{id(0), id(1)}, ComponentIDs(w))
 	expectSlicesEqual(t, []ResID{{id: 0}, {id: 1}}, ResourceIDs(w))
@@ -54,6 +54,12 @@ func TestComponentInfo(t *testing.T) {
 	info, ok := ComponentInfo(w, posID)
 	expectTrue(t, ok)
 	expectEqual(t, info.Type, reflect.TypeOf(Position{}))
+	expectTrue(t, info.IsTrivial)
+	expectFalse(t, info.IsMultiRelation)
+
+	info, _ = ComponentInfo(w, ComponentID[Likes](w))
+	expectTrue(t, info.IsRelation)
+	expectTrue(t, info.IsMultiRelation)
 
 	info, ok = ComponentInfo(w, ID{id: 3})
 	expectFalse(t, ok)
@@ -154,3 +154,34 @@ func (g *graph) findOrCreate(mask *bitMask) *node {
 	g.nodes = append(g.nodes, newNode(nodeID(len), maxArchetypeID, mask))
 	return &g.nodes[len]
 }
+
+// removeComponent removes all nodes that contain the given component, and all transitions via the component.
+// Nodes that contain the component must not have an archetype.
+//
+// Returns a mapping from old to new node IDs, for updating the nodes of archetypes.
+func (g *graph) removeComponent(id ID) []nodeID {
+	mapping := make([]nodeID, len(g.nodes))
+	nodes := g.nodes[:0]
+	for i := range g.nodes {
+		node := g.nodes[i]
+		if node.mask.Get(id.id) {
+			continue
+		}
+		mapping[i] = nodeID(len(nodes))
+		node.id = mapping[i]
+		nodes = append(nodes, node)
+	}
+	clear(g.nodes[len(nodes):])
+	g.nodes = nodes
+
+	for i := range g.nodes {
+		neighbors := &g.nodes[i].neighbors
+		neighbors.Remove(id.id)
+		for j := range neighbors.data {
+			if neighbors.used.Get(idIndex(j)) {
+				neighbors.data[j] = mapping[neighbors.data[j]]
+			}
+		}
+	}
+	return mapping
+}
@@ -0,0 +1,197 @@
+package ecs
+
+import "fmt"
+
+// Hierarchy provides helpers for entity hierarchies like scene trees,
+// based on a relation component type C that points from children to their parent, like a `ChildOf` relation.
+//
+// Create one with [NewHierarchy].
+//
+// Instances should be created during initialization and stored, e.g. in systems.
+type Hierarchy[C any] struct {
+	world  *World
+	mapper *Map[C]
+	filter *Filter1[C]
+	id     ID
+	stack  []Entity
+}
+
+// NewHierarchy creates a new [Hierarchy] for the given relation component type.
+//
+// Panics if C is not a relation component (see [RelationMarker]).
+func NewHierarchy[C any](world *World) *Hierarchy[C] {
+	id := ComponentID[C](world)
+	if !world.storage.registry.IsRelation[id.id] {
+		panic(fmt.Sprintf("component with ID %d is not a relation component", id.id))
+	}
+	return &Hierarchy[C]{
+		world:  world,
+		mapper: NewMap[C](world),
+		filter: NewFilter1[C](world),
+		id:     id,
+	}
+}
+
+// Parent returns the parent of the given entity.
+// Returns the zero entity if the entity has no parent, i.e. does not have the relation component.
+//
+// Panics if the entity is dead.
+func (h *Hierarchy[C]) Parent(entity Entity) Entity {
+	if !h.mapper.Has(entity) {
+		return Entity{}
+	}
+	return h.mapper.GetRelationUnchecked(entity)
+}
+
+// Children calls the given function for each direct child of the given parent entity.
+//
+// The world is locked during the function calls.
+func (h *Hierarchy[C]) Children(parent Entity, fn func(child Entity)) {
+	lock := h.world.lockSafe()
+	defer h.world.unlockSafe(lock)
+
+	h.children(parent, func(table *table) {
+		for i := range uintptr(table.len) {
+			fn(table.GetEntity(i))
+		}
+	})
+}
+
+// NumChildren returns the number of direct children of the given parent entity.
+func (h *Hierarchy[C]) NumChildren(parent Entity) int {
+	count := 0
+	h.children(parent, func(table *table) {
+		count += int(table.len)
+	})
+	return count
+}
+
+// DepthFirst traverses the hierarchy below the given root entity in depth-first pre-order,
+// calling the given function for the root and each of its descendants.
+// The function receives the entity and its depth relative to the root, which has depth 0.
+// If the function returns false, the descendants of the current entity are skipped.
+//
+// The order of siblings is not specified.
+// The world is locked during the traversal.
+func (h *Hierarchy[C]) DepthFirst(root Entity, fn func(entity Entity, depth int) bool) {
+	lock := h.world.lockSafe()
+	defer h.world.unlockSafe(lock)
+
+	h.depthFirst(root, 0, fn)
+}
+
+// BreadthFirst traverses the hierarchy below the given root entity in breadth-first order,
+// calling the given function for the root and each of its descendants.
+// The function receives the entity and its depth relative to the root, which has depth 0.
+// If the function returns false, the descendants of the current entity are skipped.
+//
+// The order of siblings is not specified.
+// The world is locked during the traversal.
+func (h *Hierarchy[C]) BreadthFirst(root Entity, fn func(entity Entity, depth int) bool) {
+	lock := h.world.lockSafe()
+	defer h.world.unlockSafe(lock)
+
+	current := []Entity{root}
+	next := []Entity{}
+	for depth := 0; len(current) > 0; depth++ {
+		for _, e := range current {
+			if !fn(e, depth) {
+				continue
+			}
+			next = h.appendChildren(e, next)
+		}
+		current, next = next, current[:0]
+	}
+}
+
+// Reparent sets the parent of the given entity.
+// Adds the relation component if the entity does not have it yet.
+// Use the zero entity as parent to detach the entity from its parent, but keep the relation component.
+//
+// Panics if the new parent is the entity itself or one of its descendants,
+// or if any of the entities is dead.
+func (h *Hierarchy[C]) Reparent(entity Entity, parent Entity) {
+	for p := parent; !p.IsZero(); p = h.Parent(p) {
+		if p == entity {
+			panic("can't reparent an entity to itself or one of its descendants")
+		}
+	}
+	if h.mapper.Has(entity) {
+		h.mapper.SetRelation(entity, parent)
+		return
+	}
+	h.mapper.AddFn(entity, nil, parent)
+}
+
+// RemoveRecursive removes the given entity and all its descendants from the world.
+// Descendants are removed bottom-up, using batch operations for the children of each entity.
+//
+// Panics if the world is locked, or if the entity is dead.
+func (h *Hierarchy[C]) RemoveRecursive(root Entity) {
+	h.world.checkLocked()
+	if !h.world.Alive(root) {
+		panic("can't remove a dead entity")
+	}
+
+	parents := h.stack[:0]
+	parents = append(parents, root)
+	for i := 0; i < len(parents); i++ {
+		h.children(parents[i], func(table *table) {
+			for j := range uintptr(table.len) {
+				child := table.GetEntity(j)
+				if h.world.storage.isTarget[child.id] {
+					parents = append(parents, child)
+				}
+			}
+		})
+	}
+
+	for i := len(parents) - 1; i >= 0; i-- {
+		h.world.RemoveEntities(h.filter.Batch(RelIdx(0, parents[i])), nil)
+	}
+	h.world.RemoveEntity(root)
+	h.stack = parents[:0]
+}
+
+// depthFirst recursively traverses the hierarchy below the given entity.
+func (h *Hierarchy[C]) depthFirst(entity Entity, depth int, fn func(entity Entity, depth int) bool) {
+	if !fn(entity, depth) {
+		return
+	}
+	start := len(h.stack)
+	h.stack = h.appendChildren(entity, h.stack)
+	end := len(h.stack)
+	for i := start; i < end; i++ {
+		h.depthFirst(h.stack[i], depth+1, fn)
+	}
+	h.stack = h.stack[:start]
+}
+
+// appendChildren appends all direct children of the given parent entity to the given slice.
+func (h *Hierarchy[C]) appendChildren(parent Entity, out []Entity) []Entity {
+	h.children(parent, func(table *table) {
+		for i := range uintptr(table.len) {
+			out = append(out, table.GetEntity(i))
+		}
+	})
+	return out
+}
+
+// children calls the given function for all non-empty tables that contain children of the given parent entity.
+// Uses the relation target index of archetypes for fast lookup.
+func (h *Hierarchy[C]) children(parent Entity, fn func(table *table)) {
+	s := &h.world.storage
+	if parent.IsZero() || int(parent.id) >= len(s.isTarget) || !s.isTarget[parent.id] {
+		return
+	}
+	relations := []relationID{{component: h.id, target: parent}}
+	for _, archID := range s.componentIndex[h.id.id] {
+		archetype := &s.archetypes[archID]
+		for _, tableID := range archetype.GetTables(relations) {
+			table := &s.tables[tableID]
+			if table.len > 0 {
+				fn(table)
+			}
+		}
+	}
+}
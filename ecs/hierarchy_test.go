@@ -0,0 +1,195 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+)
+
+// createHierarchy creates a tree with the given number of levels below the root,
+// where each entity has two children.
+func createHierarchy(w *World, levels int) (Entity, []Entity) {
+	childMap := NewMap2[Position, ChildOf](w)
+	root := w.NewEntity()
+	all := []Entity{root}
+	current := []Entity{root}
+	for range levels {
+		next := []Entity{}
+		for _, parent := range current {
+			for range 2 {
+				child := childMap.NewEntity(&Position{}, &ChildOf{}, Rel[ChildOf](parent))
+				next = append(next, child)
+			}
+		}
+		all = append(all, next...)
+		current = next
+	}
+	return root, all
+}
+
+func TestNewHierarchy(t *testing.T) {
+	w := NewWorld(16)
+	_ = NewHierarchy[ChildOf](w)
+
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a relation component", ComponentID[Position](w).id), func() {
+		NewHierarchy[Position](w)
+	})
+}
+
+func TestHierarchyParentChildren(t *testing.T) {
+	w := NewWorld(16)
+	h := NewHierarchy[ChildOf](w)
+	root, all := createHierarchy(w, 2)
+
+	expectEqual(t, Entity{}, h.Parent(root))
+	expectEqual(t, root, h.Parent(all[1]))
+	expectEqual(t, root, h.Parent(all[2]))
+	expectEqual(t, all[1], h.Parent(all[3]))
+
+	children := []Entity{}
+	h.Children(root, func(child Entity) {
+		expectTrue(t, w.IsLocked())
+		children = append(children, child)
+	})
+	expectFalse(t, w.IsLocked())
+	expectEqual(t, 2, len(children))
+	expectTrue(t, containsEntity(children, all[1]))
+	expectTrue(t, containsEntity(children, all[2]))
+
+	expectEqual(t, 2, h.NumChildren(root))
+	expectEqual(t, 2, h.NumChildren(all[1]))
+	expectEqual(t, 0, h.NumChildren(all[3]))
+	expectEqual(t, 0, h.NumChildren(Entity{}))
+
+	// Children in different archetypes.
+	velMap := NewMap[Velocity](w)
+	velMap.Add(all[1], &Velocity{})
+	expectEqual(t, 2, h.NumChildren(root))
+
+	w.RemoveEntity(all[1])
+	expectEqual(t, 1, h.NumChildren(root))
+	expectPanics(t, func() {
+		h.Parent(all[1])
+	})
+}
+
+func TestHierarchyTraversal(t *testing.T) {
+	w := NewWorld(16)
+	h := NewHierarchy[ChildOf](w)
+	root, all := createHierarchy(w, 3)
+
+	for _, traverse := range []func(Entity, func(Entity, int) bool){h.DepthFirst, h.BreadthFirst} {
+		visited := []Entity{}
+		depths := map[Entity]int{}
+		traverse(root, func(e Entity, depth int) bool {
+			expectTrue(t, w.IsLocked())
+			if depth > 0 {
+				parent := h.Parent(e)
+				expectTrue(t, containsEntity(visited, parent))
+				expectEqual(t, depths[parent]+1, depth)
+			}
+			visited = append(visited, e)
+			depths[e] = depth
+			return true
+		})
+		expectFalse(t, w.IsLocked())
+		expectEqual(t, len(all), len(visited))
+		expectEqual(t, root, visited[0])
+
+		// Skip descendants.
+		cnt := 0
+		traverse(root, func(e Entity, depth int) bool {
+			cnt++
+			return depth < 1
+		})
+		expectEqual(t, 3, cnt)
+	}
+
+	order := []int{}
+	h.BreadthFirst(root, func(e Entity, depth int) bool {
+		order = append(order, depth)
+		return true
+	})
+	for i := 1; i < len(order); i++ {
+		expectTrue(t, order[i-1] <= order[i])
+	}
+
+	order = order[:0]
+	h.DepthFirst(all[1], func(e Entity, depth int) bool {
+		order = append(order, depth)
+		return true
+	})
+	expectSlicesEqual(t, []int{0, 1, 2, 2, 1, 2, 2}, order)
+}
+
+func TestHierarchyReparent(t *testing.T) {
+	w := NewWorld(16)
+	h := NewHierarchy[ChildOf](w)
+	root, all := createHierarchy(w, 2)
+
+	h.Reparent(all[3], all[2])
+	expectEqual(t, all[2], h.Parent(all[3]))
+	expectEqual(t, 1, h.NumChildren(all[1]))
+	expectEqual(t, 3, h.NumChildren(all[2]))
+
+	e := w.NewEntity()
+	h.Reparent(e, root)
+	expectEqual(t, root, h.Parent(e))
+	expectEqual(t, 3, h.NumChildren(root))
+
+	h.Reparent(e, Entity{})
+	expectEqual(t, Entity{}, h.Parent(e))
+	expectEqual(t, 2, h.NumChildren(root))
+
+	expectPanicsWithValue(t, "can't reparent an entity to itself or one of its descendants", func() {
+		h.Reparent(root, root)
+	})
+	expectPanicsWithValue(t, "can't reparent an entity to itself or one of its descendants", func() {
+		h.Reparent(all[1], all[4])
+	})
+}
+
+func TestHierarchyRemoveRecursive(t *testing.T) {
+	w := NewWorld(16)
+	h := NewHierarchy[ChildOf](w)
+	root, all := createHierarchy(w, 3)
+	other, otherAll := createHierarchy(w, 2)
+
+	removed := []Entity{}
+	h.DepthFirst(all[1], func(e Entity, depth int) bool {
+		removed = append(removed, e)
+		return true
+	})
+
+	h.RemoveRecursive(all[1])
+	for _, e := range all {
+		expectEqual(t, !containsEntity(removed, e), w.Alive(e))
+	}
+	expectEqual(t, 1, h.NumChildren(root))
+
+	h.RemoveRecursive(root)
+	for _, e := range all {
+		expectFalse(t, w.Alive(e))
+	}
+	for _, e := range otherAll {
+		expectTrue(t, w.Alive(e))
+	}
+
+	childQuery := NewFilter1[ChildOf](w).Query()
+	expectEqual(t, len(otherAll)-1, childQuery.Count())
+	childQuery.Close()
+
+	h.RemoveRecursive(other)
+	query := NewFilter0(w).Query()
+	expectEqual(t, 0, query.Count())
+	query.Close()
+
+	expectPanicsWithValue(t, "can't remove a dead entity", func() {
+		h.RemoveRecursive(root)
+	})
+	e := w.NewEntity()
+	query = NewFilter0(w).Query()
+	expectPanics(t, func() {
+		h.RemoveRecursive(e)
+	})
+	query.Close()
+}
@@ -0,0 +1,144 @@
+package ecs
+
+import "unsafe"
+
+// Hooks are lifecycle callbacks for a component type.
+// Register them with [RegisterHooks].
+//
+// In contrast to [Observer], hooks are registered on the component type itself,
+// and receive a typed pointer to the component value.
+// Any field can be nil.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+type Hooks[T any] struct {
+	// OnAdd is called after the component was added to an entity, including entity creation.
+	// The component is already initialized.
+	OnAdd func(entity Entity, comp *T)
+	// OnRemove is called before the component is removed from an entity, including entity removal.
+	OnRemove func(entity Entity, comp *T)
+	// OnSet is called after the component was set for an entity, e.g. via [Map.Set].
+	OnSet func(entity Entity, comp *T)
+}
+
+// RegisterHooks registers lifecycle [Hooks] for the component type T.
+//
+// Hooks are called in the same situations as observers for [OnCreateEntity], [OnAddComponents],
+// [OnRemoveEntity], [OnRemoveComponents] and [OnSetComponents], right before the observers.
+// Thus, like for observers, the world may be locked during hook calls.
+//
+// Replaces any hooks previously registered for T.
+// Registering empty [Hooks] removes all hooks of T.
+// Hooks are kept on [World.Reset], but are not copied by [World.Clone].
+//
+// Panics if T uses sparse storage (see [StorageSparse]), as sparse components don't trigger hooks.
+func RegisterHooks[T any](w *World, hooks Hooks[T]) {
+	w.checkLocked()
+	id := ComponentID[T](w)
+	s := &w.storage
+	s.checkNotSparse(id, "RegisterHooks")
+
+	var h componentHooks
+	if fn := hooks.OnAdd; fn != nil {
+		h[hookAdd] = func(e Entity) { fn(e, (*T)(s.hookPointer(e, id))) }
+	}
+	if fn := hooks.OnRemove; fn != nil {
+		h[hookRemove] = func(e Entity) { fn(e, (*T)(s.hookPointer(e, id))) }
+	}
+	if fn := hooks.OnSet; fn != nil {
+		h[hookSet] = func(e Entity) { fn(e, (*T)(s.hookPointer(e, id))) }
+	}
+	s.observers.SetHooks(id, &h)
+}
+
+// hookKind identifies the kind of a component lifecycle hook.
+type hookKind uint8
+
+const (
+	hookAdd hookKind = iota
+	hookRemove
+	hookSet
+	numHookKinds
+)
+
+// componentHooks are the type-erased lifecycle hooks of a component.
+type componentHooks [numHookKinds]func(e Entity)
+
+// hookPointer returns a pointer to the component of the given entity,
+// without marking the component as changed.
+func (s *storage) hookPointer(entity Entity, component ID) unsafe.Pointer {
+	index := s.entities[entity.id]
+	return s.tables[index.table].Column(component).Get(uintptr(index.row))
+}
+
+// SetHooks sets the lifecycle hooks of a component.
+func (m *observerManager) SetHooks(id ID, hooks *componentHooks) {
+	if m.hooks == nil {
+		m.hooks = make([]componentHooks, maskTotalBits)
+	}
+	m.hooks[id.id] = *hooks
+	for kind := range numHookKinds {
+		ids := m.hookIDs[kind][:0]
+		for _, other := range m.hookIDs[kind] {
+			if other != id {
+				ids = append(ids, other)
+			}
+		}
+		if hooks[kind] != nil {
+			ids = append(ids, id)
+		}
+		m.hookIDs[kind] = ids
+	}
+	for _, evt := range []EventType{OnCreateEntity, OnRemoveEntity, OnAddComponents, OnRemoveComponents, OnSetComponents} {
+		m.hasObservers[evt.index()] = len(m.observers[evt.index()]) > 0 || m.hasHooks(evt)
+	}
+}
+
+// hasComponentHooks returns whether the given component has any hooks.
+func (m *observerManager) hasComponentHooks(id ID) bool {
+	if m.hooks == nil {
+		return false
+	}
+	for _, fn := range m.hooks[id.id] {
+		if fn != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHooks returns whether there are any hooks that are called for the given event type.
+func (m *observerManager) hasHooks(evt EventType) bool {
+	switch evt {
+	case OnCreateEntity, OnAddComponents:
+		return len(m.hookIDs[hookAdd]) > 0
+	case OnRemoveEntity, OnRemoveComponents:
+		return len(m.hookIDs[hookRemove]) > 0
+	case OnSetComponents:
+		return len(m.hookIDs[hookSet]) > 0
+	}
+	return false
+}
+
+// fireHooks calls the hooks of the given kind for all components in mask, except those in exclude.
+// Argument exclude can be nil.
+func (m *observerManager) fireHooks(kind hookKind, e Entity, mask, exclude *bitMask) {
+	for _, id := range m.hookIDs[kind] {
+		if mask.Get(id.id) && (exclude == nil || !exclude.Get(id.id)) {
+			m.hooks[id.id][kind](e)
+		}
+	}
+}
+
+// fireHooksBatch calls the hooks of the given kind for all components in mask, except those in exclude,
+// for the entities in the given range of a table.
+// Argument exclude can be nil.
+func (m *observerManager) fireHooksBatch(kind hookKind, table *table, start, end int, mask, exclude *bitMask) {
+	for _, id := range m.hookIDs[kind] {
+		if mask.Get(id.id) && (exclude == nil || !exclude.Get(id.id)) {
+			fn := m.hooks[id.id][kind]
+			for i := start; i < end; i++ {
+				fn(table.GetEntity(uintptr(i)))
+			}
+		}
+	}
+}
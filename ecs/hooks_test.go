@@ -0,0 +1,151 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestRegisterHooks(t *testing.T) {
+	w := NewWorld(16)
+
+	added := []Entity{}
+	removed := []Entity{}
+	set := []Entity{}
+	RegisterHooks(w, Hooks[Position]{
+		OnAdd: func(e Entity, pos *Position) {
+			expectEqual(t, 1.0, pos.X)
+			added = append(added, e)
+		},
+		OnRemove: func(e Entity, pos *Position) {
+			expectEqual(t, 2.0, pos.X)
+			removed = append(removed, e)
+		},
+		OnSet: func(e Entity, pos *Position) {
+			expectEqual(t, 2.0, pos.X)
+			set = append(set, e)
+		},
+	})
+
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+	mapper := NewMap2[Position, Velocity](w)
+
+	e1 := posMap.NewEntity(&Position{X: 1})
+	expectSlicesEqual(t, []Entity{e1}, added)
+
+	e2 := velMap.NewEntity(&Velocity{})
+	posMap.Add(e2, &Position{X: 1})
+	expectSlicesEqual(t, []Entity{e1, e2}, added)
+
+	// Adding other components does not trigger the hook.
+	velMap.Add(e1, &Velocity{})
+	expectEqual(t, 2, len(added))
+
+	posMap.Set(e1, &Position{X: 2})
+	posMap.Set(e2, &Position{X: 2})
+	expectSlicesEqual(t, []Entity{e1, e2}, set)
+
+	// Removing other components does not trigger the hook.
+	velMap.Remove(e1)
+	expectEqual(t, 0, len(removed))
+
+	posMap.Remove(e1)
+	w.RemoveEntity(e2)
+	expectSlicesEqual(t, []Entity{e1, e2}, removed)
+
+	// Batch operations
+	added = added[:0]
+	removed = removed[:0]
+	mapper.NewBatch(5, &Position{X: 1}, &Velocity{})
+	expectEqual(t, 5, len(added))
+
+	filter := NewFilter1[Position](w)
+	query := filter.Query()
+	for query.Next() {
+		query.Get().X = 2
+	}
+	w.RemoveEntities(filter.Batch(), nil)
+	expectEqual(t, 5, len(removed))
+
+	velMap.NewBatch(3, &Velocity{})
+	posMap.AddBatch(NewFilter1[Velocity](w).Batch(), &Position{X: 1})
+	expectEqual(t, 8, len(added))
+
+	query = filter.Query()
+	for query.Next() {
+		query.Get().X = 2
+	}
+	posMap.RemoveBatch(filter.Batch(), nil)
+	expectEqual(t, 8, len(removed))
+
+	// Remove hooks, while keeping those of other components
+	velAdded := 0
+	RegisterHooks(w, Hooks[Velocity]{
+		OnAdd: func(e Entity, vel *Velocity) { velAdded++ },
+	})
+	RegisterHooks(w, Hooks[Position]{})
+	mapper.NewEntity(&Position{X: 1}, &Velocity{})
+	expectEqual(t, 8, len(added))
+	expectEqual(t, 1, velAdded)
+
+	RegisterHooks(w, Hooks[Velocity]{})
+	velMap.NewEntity(&Velocity{})
+	expectEqual(t, 1, velAdded)
+	expectFalse(t, w.storage.observers.HasObservers(OnCreateEntity))
+}
+
+func TestHooksObservers(t *testing.T) {
+	w := NewWorld(16)
+
+	calls := []string{}
+	RegisterHooks(w, Hooks[Position]{
+		OnAdd: func(e Entity, pos *Position) {
+			calls = append(calls, "hook")
+		},
+	})
+	obs := Observe(OnCreateEntity).Do(func(e Entity) {
+		calls = append(calls, "observer")
+	}).Register(w)
+
+	posMap := NewMap[Position](w)
+	posMap.NewEntity(&Position{})
+	expectSlicesEqual(t, []string{"hook", "observer"}, calls)
+
+	obs.Unregister(w)
+	expectTrue(t, w.storage.observers.HasObservers(OnCreateEntity))
+
+	w.Reset()
+	expectTrue(t, w.storage.observers.HasObservers(OnCreateEntity))
+	posMap.NewEntity(&Position{})
+	expectSlicesEqual(t, []string{"hook", "observer", "hook"}, calls)
+}
+
+func TestHooksCommandBuffer(t *testing.T) {
+	w := NewWorld(16)
+
+	added := 0
+	removed := 0
+	RegisterHooks(w, Hooks[Position]{
+		OnAdd: func(e Entity, pos *Position) {
+			expectEqual(t, 1.0, pos.X)
+			added++
+		},
+		OnRemove: func(e Entity, pos *Position) {
+			removed++
+		},
+	})
+
+	cmd := NewCommandBuffer(w)
+	posMap := NewCommandMap[Position](cmd)
+	posMap.NewEntity(&Position{X: 1})
+	posMap.NewEntity(&Position{X: 1})
+	cmd.Apply()
+	expectEqual(t, 2, added)
+
+	filter := NewFilter1[Position](w)
+	query := filter.Query()
+	for query.Next() {
+		cmd.RemoveEntity(query.Entity())
+	}
+	cmd.Apply()
+	expectEqual(t, 2, removed)
+}
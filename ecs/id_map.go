@@ -18,7 +18,7 @@ func newIDMap() idMap {
 }
 
 // Get returns the value at the given key and whether the key is present.
-func (m *idMap) Get(index uint8) (nodeID, bool) {
+func (m *idMap) Get(index idIndex) (nodeID, bool) {
 	if !m.used.Get(index) {
 		return 0, false
 	}
@@ -26,7 +26,7 @@ func (m *idMap) Get(index uint8) (nodeID, bool) {
 }
 
 // Set sets the value at the given key.
-func (m *idMap) Set(index uint8, value nodeID) {
+func (m *idMap) Set(index idIndex, value nodeID) {
 	if len(m.data) <= int(index) {
 		len := ((uint32(index) + idMapChunkSize) / idMapChunkSize) * idMapChunkSize
 		data := make([]nodeID, len)
@@ -36,3 +36,8 @@ func (m *idMap) Set(index uint8, value nodeID) {
 	m.used.Set(index)
 	m.data[index] = value
 }
+
+// Remove removes the given key.
+func (m *idMap) Remove(index idIndex) {
+	m.used.Clear(index)
+}
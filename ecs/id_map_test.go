@@ -5,8 +5,8 @@ import (
 )
 
 func TestIDMap(t *testing.T) {
-	big1 := uint8(maskTotalBits - 20)
-	big2 := uint8(maskTotalBits - 3)
+	big1 := idIndex(maskTotalBits - 20)
+	big2 := idIndex(maskTotalBits - 3)
 
 	m := newIDMap()
 
@@ -48,12 +48,12 @@ func BenchmarkIdMapping_IDMap(b *testing.B) {
 
 	for i := range maskTotalBits {
 		entities[i] = nodeID(i)
-		m.Set(uint8(i), entities[i])
+		m.Set(idIndex(i), entities[i])
 	}
 
 	var v nodeID
 	for i := 0; b.Loop(); i++ {
-		v, _ = m.Get(uint8(i % maskTotalBits))
+		v, _ = m.Get(idIndex(i % maskTotalBits))
 	}
 	_ = v
 }
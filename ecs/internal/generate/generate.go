@@ -14,3 +14,5 @@ package main
 //go:generate go fmt ../../exchange_gen_test.go
 //go:generate go fmt ../../observers_gen.go
 //go:generate go fmt ../../observers_gen_test.go
+//go:generate go fmt ../../command_gen.go
+//go:generate go fmt ../../command_gen_test.go
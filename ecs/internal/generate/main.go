@@ -25,6 +25,8 @@ var files = []genFile{
 	{"./exchange_test.go.template", "../../exchange_gen_test.go"},
 	{"./observers.go.template", "../../observers_gen.go"},
 	{"./observers_test.go.template", "../../observers_gen_test.go"},
+	{"./command.go.template", "../../command_gen.go"},
+	{"./command_test.go.template", "../../command_gen_test.go"},
 }
 
 func main() {
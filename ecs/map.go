@@ -1,13 +1,18 @@
 package ecs
 
+import "fmt"
+
 // Map is a mapper to access and manipulate components of an entity.
 // It is equivalent to [Map1], with slightly more convenient methods tailored for a single component.
 //
 // Instances should be created during initialization and stored, e.g. in systems.
+//
+// For components with sparse storage (see [StorageSparse]), batch and relation operations are not supported.
 type Map[T any] struct {
 	mask      bitMask
 	world     *World
 	storage   *componentStorage
+	sparse    *sparseSet
 	relations []relationID
 	id        ID
 	ids       [1]ID
@@ -24,6 +29,7 @@ func NewMap[T any](w *World) *Map[T] {
 		id:      id,
 		ids:     [1]ID{id},
 		storage: &w.storage.components[id.id],
+		sparse:  w.storage.sparse[id.id],
 	}
 }
 
@@ -51,6 +57,11 @@ func (m *Map[T]) NewEntity(comp *T, target ...Entity) Entity {
 //
 // ⚠️ Do not store the obtained pointer outside of the current context!
 func (m *Map[T]) NewEntityFn(fn func(*T), target ...Entity) Entity {
+	if m.sparse != nil {
+		entity := m.world.NewEntity()
+		m.AddFn(entity, fn)
+		return entity
+	}
 	m.relations = relationEntities(target).ToRelation(m.world, m.id, m.relations)
 	entity, mask := m.world.newEntity(m.ids[:], m.relations)
 	if fn != nil {
@@ -60,6 +71,7 @@ func (m *Map[T]) NewEntityFn(fn func(*T), target ...Entity) Entity {
 	if len(target) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -82,6 +94,7 @@ func (m *Map[T]) NewBatch(count int, comp *T, target ...Entity) {
 // ⚠️ Do not store the obtained pointers outside of the current context!
 func (m *Map[T]) NewBatchFn(count int, fn func(Entity, *T), target ...Entity) {
 	m.world.checkLocked()
+	m.checkNotSparse("batch creation")
 	m.relations = relationEntities(target).ToRelation(m.world, m.id, m.relations)
 	tableID, start := m.world.newEntities(count, m.ids[:], m.relations)
 
@@ -118,6 +131,7 @@ func (m *Map[T]) NewBatchFn(count int, fn func(Entity, *T), target ...Entity) {
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped component for the given entity.
@@ -129,8 +143,7 @@ func (m *Map[T]) Get(entity Entity) *T {
 	if !m.world.storage.entityPool.Alive(entity) {
 		panic("can't get a component of a dead entity")
 	}
-	index := &m.world.storage.entities[entity.id]
-	return get[T](m.storage, index)
+	return m.GetUnchecked(entity)
 }
 
 // GetUnchecked returns the mapped component for the given entity.
@@ -141,10 +154,31 @@ func (m *Map[T]) Get(entity Entity) *T {
 //
 // ⚠️ Do not store the obtained pointer outside of the current context!
 func (m *Map[T]) GetUnchecked(entity Entity) *T {
+	if m.sparse != nil {
+		return (*T)(m.sparse.Get(entity))
+	}
 	index := &m.world.storage.entities[entity.id]
 	return get[T](m.storage, index)
 }
 
+// GetMut returns the mapped component for the given entity, like [Map.Get],
+// and marks it as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map.Get] when modifying the component in place.
+//
+// Returns nil if the entity does not have the mapped component.
+//
+// ⚠️ Do not store the obtained pointer outside of the current context!
+func (m *Map[T]) GetMut(entity Entity) *T {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get a component of a dead entity")
+	}
+	if m.sparse != nil {
+		return (*T)(m.sparse.Get(entity))
+	}
+	index := &m.world.storage.entities[entity.id]
+	return getMut[T](m.storage, index, m.world.storage.tick)
+}
+
 // Has return whether the given entity has the mapped component.
 //
 // Using [Map.Get] and checking for nil pointer may be faster
@@ -160,6 +194,9 @@ func (m *Map[T]) Has(entity Entity) bool {
 // In contrast to [Map.Has], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
 func (m *Map[T]) HasUnchecked(entity Entity) bool {
+	if m.sparse != nil {
+		return m.sparse.Has(entity)
+	}
 	return m.storage.columns[m.world.storage.entities[entity.id].table] != nil
 }
 
@@ -184,6 +221,10 @@ func (m *Map[T]) AddFn(entity Entity, fn func(*T), target ...Entity) {
 	if !m.world.storage.entityPool.Alive(entity) {
 		panic("can't add a component to a dead entity")
 	}
+	if m.sparse != nil {
+		m.addSparse(entity, fn)
+		return
+	}
 	m.relations = relationEntities(target).ToRelation(m.world, m.id, m.relations)
 	oldMask, newMask := m.world.add(entity, m.ids[:], m.relations)
 	if fn != nil {
@@ -194,6 +235,7 @@ func (m *Map[T]) AddFn(entity Entity, fn func(*T), target ...Entity) {
 	if len(target) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped component of the given entity to the given values.
@@ -208,14 +250,26 @@ func (m *Map[T]) Set(entity Entity, comp *T) {
 	if !m.world.storage.entityPool.Alive(entity) {
 		panic("can't set component of a dead entity")
 	}
+	if m.sparse != nil {
+		ptr := m.sparse.Get(entity)
+		if ptr == nil {
+			panic(fmt.Sprintf("entity does not have component with ID %d", m.id.id))
+		}
+		*(*T)(ptr) = *comp
+		return
+	}
 	m.world.storage.checkHasComponent(entity, m.ids[0])
 
 	index := &m.world.storage.entities[entity.id]
-	*(*T)(m.storage.columns[index.table].Get(uintptr(index.row))) = *comp
+	column := m.storage.columns[index.table]
+	row := uintptr(index.row)
+	*(*T)(column.Get(row)) = *comp
+	column.setChanged(row, m.world.storage.tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -237,6 +291,7 @@ func (m *Map[T]) AddBatch(batch Batch, comp *T, target ...Entity) {
 //
 // ⚠️ Do not store the obtained pointers outside of the current context!
 func (m *Map[T]) AddBatchFn(batch Batch, fn func(Entity, *T), target ...Entity) {
+	m.checkNotSparse("batch addition")
 	m.relations = relationEntities(target).ToRelation(m.world, m.id, m.relations)
 
 	var process func(tableID tableID, start, len uint32)
@@ -255,6 +310,7 @@ func (m *Map[T]) AddBatchFn(batch Batch, fn func(Entity, *T), target ...Entity)
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids[:], nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped component from the given entity.
@@ -262,20 +318,42 @@ func (m *Map[T]) Remove(entity Entity) {
 	if !m.world.storage.entityPool.Alive(entity) {
 		panic("can't remove a component from a dead entity")
 	}
+	if m.sparse != nil {
+		m.world.checkLocked()
+		if !m.sparse.Remove(entity) {
+			panic(fmt.Sprintf("entity does not have component with ID %d", m.id.id))
+		}
+		return
+	}
 	m.world.remove(entity, m.ids[:])
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped component from all entities matching the given batch filter,
 // running the given function on each. The function can be nil.
 func (m *Map[T]) RemoveBatch(batch Batch, fn func(entity Entity)) {
+	m.checkNotSparse("batch removal")
 	removeBatch(m.world, &batch, m.ids[:], fn)
 }
 
 // GetRelation returns the relation target for the entity and the mapped component.
+//
+// For multi-target relations (see [MultiRelationMarker]), returns the target with the lowest entity ID.
+// Use [Map.GetRelations] to get all targets.
 func (m *Map[T]) GetRelation(entity Entity) Entity {
 	return m.world.storage.getRelation(entity, m.id)
 }
 
+// GetRelations returns all relation targets for the entity and the mapped component,
+// in no particular order.
+// The zero entity is not included.
+//
+// Intended for multi-target relations (see [MultiRelationMarker]).
+// For single-target relations, returns the relation target if it is not the zero entity.
+func (m *Map[T]) GetRelations(entity Entity) []Entity {
+	return m.world.storage.getRelations(entity, m.id, nil)
+}
+
 // GetRelationUnchecked returns the relation target for the entity and the mapped component.
 // In contrast to [Map.GetRelation], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -287,6 +365,51 @@ func (m *Map[T]) GetRelationUnchecked(entity Entity) Entity {
 func (m *Map[T]) SetRelation(entity Entity, target Entity) {
 	m.relations = toRelation(m.world, target, m.id, m.relations)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
+}
+
+// AddTargets adds relation targets for the entity and the mapped multi-target relation component
+// (see [MultiRelationMarker]).
+// Targets that the entity already has are ignored.
+//
+// Panics if the mapped component is not a multi-target relation component.
+func (m *Map[T]) AddTargets(entity Entity, targets ...Entity) {
+	m.world.storage.checkMultiRelationComponent(m.id)
+	m.relations = m.relations[:0]
+	for _, target := range m.world.storage.getRelations(entity, m.id, nil) {
+		m.relations = append(m.relations, relationID{target: target, component: m.id})
+	}
+	for _, target := range targets {
+		m.world.storage.checkRelationTarget(target)
+		m.relations = append(m.relations, relationID{target: target, component: m.id})
+	}
+	m.setTargets(entity)
+}
+
+// RemoveTargets removes relation targets for the entity and the mapped multi-target relation component
+// (see [MultiRelationMarker]).
+// Targets that the entity does not have are ignored.
+// The entity keeps the component, even if all targets are removed.
+//
+// Panics if the mapped component is not a multi-target relation component.
+func (m *Map[T]) RemoveTargets(entity Entity, targets ...Entity) {
+	m.world.storage.checkMultiRelationComponent(m.id)
+	m.relations = m.relations[:0]
+	for _, target := range m.world.storage.getRelations(entity, m.id, nil) {
+		if !containsEntity(targets, target) {
+			m.relations = append(m.relations, relationID{target: target, component: m.id})
+		}
+	}
+	m.setTargets(entity)
+}
+
+// setTargets replaces all targets of the entity's multi-target relation by the map's current relations.
+func (m *Map[T]) setTargets(entity Entity) {
+	if len(m.relations) == 0 {
+		m.relations = append(m.relations, relationID{component: m.id})
+	}
+	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationBatch sets the relation target for all entities matching the given batch filter.
@@ -294,3 +417,22 @@ func (m *Map[T]) SetRelationBatch(batch Batch, target Entity, fn func(entity Ent
 	m.relations = toRelation(m.world, target, m.id, m.relations)
 	setRelationsBatch(m.world, &batch, fn, m.relations)
 }
+
+// addSparse adds the mapped sparse component to the given entity.
+func (m *Map[T]) addSparse(entity Entity, fn func(*T)) {
+	m.world.checkLocked()
+	if m.sparse.Has(entity) {
+		panic(fmt.Sprintf("entity already has component with ID %d", m.id.id))
+	}
+	comp := (*T)(m.sparse.Add(entity))
+	if fn != nil {
+		fn(comp)
+	}
+}
+
+// checkNotSparse panics if the mapped component uses sparse storage.
+func (m *Map[T]) checkNotSparse(op string) {
+	if m.sparse != nil {
+		panic(fmt.Sprintf("%s is not supported for sparse component with ID %d", op, m.id.id))
+	}
+}
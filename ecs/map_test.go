@@ -56,6 +56,9 @@ func TestMap(t *testing.T) {
 	expectPanics(t, func() {
 		posMap.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		posMap.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		posMap.Has(Entity{})
 	})
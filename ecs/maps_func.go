@@ -14,6 +14,7 @@ func removeBatch(world *World, batch *Batch, ids []ID, fn func(entity Entity)) {
 		}
 	}
 	world.exchangeBatch(batch, nil, ids, nil, process)
+	world.flushEvents()
 }
 
 // setRelationsBatch performs batch relation changes.
@@ -30,4 +31,5 @@ func setRelationsBatch(world *World, batch *Batch, fn func(entity Entity), relat
 		}
 	}
 	world.setRelationsBatch(batch, relations, process)
+	world.flushEvents()
 }
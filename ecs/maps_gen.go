@@ -26,10 +26,15 @@ func (*Map1[A]) New(world *World) *Map1[A] {
 // NewMap1 creates a new [Map1].
 //
 // See also [Map1.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap1[A any](world *World) *Map1[A] {
 	ids := []ID{
 		ComponentID[A](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map1")
+	}
 	return &Map1[A]{
 		world:    world,
 		ids:      ids,
@@ -69,6 +74,7 @@ func (m *Map1[A]) NewEntityFn(fn func(*A), rel ...Relation) Entity {
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -126,6 +132,7 @@ func (m *Map1[A]) NewBatchFn(count int, fn func(Entity, *A), rel ...Relation) {
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -141,6 +148,22 @@ func (m *Map1[A]) Get(entity Entity) *A {
 	return get[A](m.storageA, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map1.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map1.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map1[A]) GetMut(entity Entity) *A {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map1.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -196,6 +219,7 @@ func (m *Map1[A]) AddFn(entity Entity, fn func(*A), rel ...Relation) {
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -214,11 +238,15 @@ func (m *Map1[A]) Set(entity Entity, a *A) {
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -258,11 +286,13 @@ func (m *Map1[A]) AddBatchFn(batch Batch, fn func(Entity, *A), rel ...Relation)
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map1[A]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -291,6 +321,7 @@ func (m *Map1[A]) SetRelations(entity Entity, rel ...Relation) {
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -320,11 +351,16 @@ func (*Map2[A, B]) New(world *World) *Map2[A, B] {
 // NewMap2 creates a new [Map2].
 //
 // See also [Map2.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap2[A any, B any](world *World) *Map2[A, B] {
 	ids := []ID{
 		ComponentID[A](world),
 		ComponentID[B](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map2")
+	}
 	return &Map2[A, B]{
 		world:    world,
 		ids:      ids,
@@ -367,6 +403,7 @@ func (m *Map2[A, B]) NewEntityFn(fn func(*A, *B), rel ...Relation) Entity {
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -427,6 +464,7 @@ func (m *Map2[A, B]) NewBatchFn(count int, fn func(Entity, *A, *B), rel ...Relat
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -442,6 +480,22 @@ func (m *Map2[A, B]) Get(entity Entity) (*A, *B) {
 	return get[A](m.storageA, index), get[B](m.storageB, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map2.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map2.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map2[A, B]) GetMut(entity Entity) (*A, *B) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map2.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -500,6 +554,7 @@ func (m *Map2[A, B]) AddFn(entity Entity, fn func(*A, *B), rel ...Relation) {
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -519,12 +574,18 @@ func (m *Map2[A, B]) Set(entity Entity, a *A, b *B) {
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -567,11 +628,13 @@ func (m *Map2[A, B]) AddBatchFn(batch Batch, fn func(Entity, *A, *B), rel ...Rel
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map2[A, B]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -600,6 +663,7 @@ func (m *Map2[A, B]) SetRelations(entity Entity, rel ...Relation) {
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -634,12 +698,17 @@ func (*Map3[A, B, C]) New(world *World) *Map3[A, B, C] {
 // NewMap3 creates a new [Map3].
 //
 // See also [Map3.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap3[A any, B any, C any](world *World) *Map3[A, B, C] {
 	ids := []ID{
 		ComponentID[A](world),
 		ComponentID[B](world),
 		ComponentID[C](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map3")
+	}
 	return &Map3[A, B, C]{
 		world:    world,
 		ids:      ids,
@@ -685,6 +754,7 @@ func (m *Map3[A, B, C]) NewEntityFn(fn func(*A, *B, *C), rel ...Relation) Entity
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -748,6 +818,7 @@ func (m *Map3[A, B, C]) NewBatchFn(count int, fn func(Entity, *A, *B, *C), rel .
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -763,6 +834,22 @@ func (m *Map3[A, B, C]) Get(entity Entity) (*A, *B, *C) {
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map3.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map3.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map3[A, B, C]) GetMut(entity Entity) (*A, *B, *C) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map3.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -824,6 +911,7 @@ func (m *Map3[A, B, C]) AddFn(entity Entity, fn func(*A, *B, *C), rel ...Relatio
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -844,13 +932,21 @@ func (m *Map3[A, B, C]) Set(entity Entity, a *A, b *B, c *C) {
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -896,11 +992,13 @@ func (m *Map3[A, B, C]) AddBatchFn(batch Batch, fn func(Entity, *A, *B, *C), rel
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map3[A, B, C]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -929,6 +1027,7 @@ func (m *Map3[A, B, C]) SetRelations(entity Entity, rel ...Relation) {
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -964,6 +1063,8 @@ func (*Map4[A, B, C, D]) New(world *World) *Map4[A, B, C, D] {
 // NewMap4 creates a new [Map4].
 //
 // See also [Map4.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap4[A any, B any, C any, D any](world *World) *Map4[A, B, C, D] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -971,6 +1072,9 @@ func NewMap4[A any, B any, C any, D any](world *World) *Map4[A, B, C, D] {
 		ComponentID[C](world),
 		ComponentID[D](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map4")
+	}
 	return &Map4[A, B, C, D]{
 		world:    world,
 		ids:      ids,
@@ -1019,6 +1123,7 @@ func (m *Map4[A, B, C, D]) NewEntityFn(fn func(*A, *B, *C, *D), rel ...Relation)
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -1085,6 +1190,7 @@ func (m *Map4[A, B, C, D]) NewBatchFn(count int, fn func(Entity, *A, *B, *C, *D)
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -1100,6 +1206,22 @@ func (m *Map4[A, B, C, D]) Get(entity Entity) (*A, *B, *C, *D) {
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map4.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map4.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map4[A, B, C, D]) GetMut(entity Entity) (*A, *B, *C, *D) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map4.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -1164,6 +1286,7 @@ func (m *Map4[A, B, C, D]) AddFn(entity Entity, fn func(*A, *B, *C, *D), rel ...
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -1185,14 +1308,24 @@ func (m *Map4[A, B, C, D]) Set(entity Entity, a *A, b *B, c *C, d *D) {
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -1241,11 +1374,13 @@ func (m *Map4[A, B, C, D]) AddBatchFn(batch Batch, fn func(Entity, *A, *B, *C, *
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map4[A, B, C, D]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -1274,6 +1409,7 @@ func (m *Map4[A, B, C, D]) SetRelations(entity Entity, rel ...Relation) {
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -1310,6 +1446,8 @@ func (*Map5[A, B, C, D, E]) New(world *World) *Map5[A, B, C, D, E] {
 // NewMap5 creates a new [Map5].
 //
 // See also [Map5.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap5[A any, B any, C any, D any, E any](world *World) *Map5[A, B, C, D, E] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -1318,6 +1456,9 @@ func NewMap5[A any, B any, C any, D any, E any](world *World) *Map5[A, B, C, D,
 		ComponentID[D](world),
 		ComponentID[E](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map5")
+	}
 	return &Map5[A, B, C, D, E]{
 		world:    world,
 		ids:      ids,
@@ -1369,6 +1510,7 @@ func (m *Map5[A, B, C, D, E]) NewEntityFn(fn func(*A, *B, *C, *D, *E), rel ...Re
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -1438,6 +1580,7 @@ func (m *Map5[A, B, C, D, E]) NewBatchFn(count int, fn func(Entity, *A, *B, *C,
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -1453,6 +1596,22 @@ func (m *Map5[A, B, C, D, E]) Get(entity Entity) (*A, *B, *C, *D, *E) {
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map5.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map5.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map5[A, B, C, D, E]) GetMut(entity Entity) (*A, *B, *C, *D, *E) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map5.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -1520,6 +1679,7 @@ func (m *Map5[A, B, C, D, E]) AddFn(entity Entity, fn func(*A, *B, *C, *D, *E),
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -1542,15 +1702,27 @@ func (m *Map5[A, B, C, D, E]) Set(entity Entity, a *A, b *B, c *C, d *D, e *E) {
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -1602,11 +1774,13 @@ func (m *Map5[A, B, C, D, E]) AddBatchFn(batch Batch, fn func(Entity, *A, *B, *C
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map5[A, B, C, D, E]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -1635,6 +1809,7 @@ func (m *Map5[A, B, C, D, E]) SetRelations(entity Entity, rel ...Relation) {
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -1672,6 +1847,8 @@ func (*Map6[A, B, C, D, E, F]) New(world *World) *Map6[A, B, C, D, E, F] {
 // NewMap6 creates a new [Map6].
 //
 // See also [Map6.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap6[A any, B any, C any, D any, E any, F any](world *World) *Map6[A, B, C, D, E, F] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -1681,6 +1858,9 @@ func NewMap6[A any, B any, C any, D any, E any, F any](world *World) *Map6[A, B,
 		ComponentID[E](world),
 		ComponentID[F](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map6")
+	}
 	return &Map6[A, B, C, D, E, F]{
 		world:    world,
 		ids:      ids,
@@ -1735,6 +1915,7 @@ func (m *Map6[A, B, C, D, E, F]) NewEntityFn(fn func(*A, *B, *C, *D, *E, *F), re
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -1807,6 +1988,7 @@ func (m *Map6[A, B, C, D, E, F]) NewBatchFn(count int, fn func(Entity, *A, *B, *
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -1822,6 +2004,22 @@ func (m *Map6[A, B, C, D, E, F]) Get(entity Entity) (*A, *B, *C, *D, *E, *F) {
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map6.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map6.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map6[A, B, C, D, E, F]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map6.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -1892,6 +2090,7 @@ func (m *Map6[A, B, C, D, E, F]) AddFn(entity Entity, fn func(*A, *B, *C, *D, *E
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -1915,16 +2114,30 @@ func (m *Map6[A, B, C, D, E, F]) Set(entity Entity, a *A, b *B, c *C, d *D, e *E
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -1979,11 +2192,13 @@ func (m *Map6[A, B, C, D, E, F]) AddBatchFn(batch Batch, fn func(Entity, *A, *B,
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map6[A, B, C, D, E, F]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -2012,6 +2227,7 @@ func (m *Map6[A, B, C, D, E, F]) SetRelations(entity Entity, rel ...Relation) {
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -2050,6 +2266,8 @@ func (*Map7[A, B, C, D, E, F, G]) New(world *World) *Map7[A, B, C, D, E, F, G] {
 // NewMap7 creates a new [Map7].
 //
 // See also [Map7.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap7[A any, B any, C any, D any, E any, F any, G any](world *World) *Map7[A, B, C, D, E, F, G] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -2060,6 +2278,9 @@ func NewMap7[A any, B any, C any, D any, E any, F any, G any](world *World) *Map
 		ComponentID[F](world),
 		ComponentID[G](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map7")
+	}
 	return &Map7[A, B, C, D, E, F, G]{
 		world:    world,
 		ids:      ids,
@@ -2117,6 +2338,7 @@ func (m *Map7[A, B, C, D, E, F, G]) NewEntityFn(fn func(*A, *B, *C, *D, *E, *F,
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -2192,6 +2414,7 @@ func (m *Map7[A, B, C, D, E, F, G]) NewBatchFn(count int, fn func(Entity, *A, *B
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -2207,6 +2430,22 @@ func (m *Map7[A, B, C, D, E, F, G]) Get(entity Entity) (*A, *B, *C, *D, *E, *F,
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index), get[G](m.storageG, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map7.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map7.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map7[A, B, C, D, E, F, G]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F, *G) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick), getMut[G](m.storageG, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map7.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -2280,6 +2519,7 @@ func (m *Map7[A, B, C, D, E, F, G]) AddFn(entity Entity, fn func(*A, *B, *C, *D,
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -2304,17 +2544,33 @@ func (m *Map7[A, B, C, D, E, F, G]) Set(entity Entity, a *A, b *B, c *C, d *D, e
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
-	*(*G)(m.storageG.columns[index.table].Get(row)) = *g
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
+	columnG := m.storageG.columns[index.table]
+	*(*G)(columnG.Get(row)) = *g
+	columnG.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -2372,11 +2628,13 @@ func (m *Map7[A, B, C, D, E, F, G]) AddBatchFn(batch Batch, fn func(Entity, *A,
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map7[A, B, C, D, E, F, G]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -2405,6 +2663,7 @@ func (m *Map7[A, B, C, D, E, F, G]) SetRelations(entity Entity, rel ...Relation)
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -2444,6 +2703,8 @@ func (*Map8[A, B, C, D, E, F, G, H]) New(world *World) *Map8[A, B, C, D, E, F, G
 // NewMap8 creates a new [Map8].
 //
 // See also [Map8.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap8[A any, B any, C any, D any, E any, F any, G any, H any](world *World) *Map8[A, B, C, D, E, F, G, H] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -2455,6 +2716,9 @@ func NewMap8[A any, B any, C any, D any, E any, F any, G any, H any](world *Worl
 		ComponentID[G](world),
 		ComponentID[H](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map8")
+	}
 	return &Map8[A, B, C, D, E, F, G, H]{
 		world:    world,
 		ids:      ids,
@@ -2515,6 +2779,7 @@ func (m *Map8[A, B, C, D, E, F, G, H]) NewEntityFn(fn func(*A, *B, *C, *D, *E, *
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -2593,6 +2858,7 @@ func (m *Map8[A, B, C, D, E, F, G, H]) NewBatchFn(count int, fn func(Entity, *A,
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -2608,6 +2874,22 @@ func (m *Map8[A, B, C, D, E, F, G, H]) Get(entity Entity) (*A, *B, *C, *D, *E, *
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index), get[G](m.storageG, index), get[H](m.storageH, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map8.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map8.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map8[A, B, C, D, E, F, G, H]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F, *G, *H) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick), getMut[G](m.storageG, index, tick), getMut[H](m.storageH, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map8.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -2684,6 +2966,7 @@ func (m *Map8[A, B, C, D, E, F, G, H]) AddFn(entity Entity, fn func(*A, *B, *C,
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -2709,18 +2992,36 @@ func (m *Map8[A, B, C, D, E, F, G, H]) Set(entity Entity, a *A, b *B, c *C, d *D
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
-	*(*G)(m.storageG.columns[index.table].Get(row)) = *g
-	*(*H)(m.storageH.columns[index.table].Get(row)) = *h
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
+	columnG := m.storageG.columns[index.table]
+	*(*G)(columnG.Get(row)) = *g
+	columnG.setChanged(row, tick)
+	columnH := m.storageH.columns[index.table]
+	*(*H)(columnH.Get(row)) = *h
+	columnH.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -2781,11 +3082,13 @@ func (m *Map8[A, B, C, D, E, F, G, H]) AddBatchFn(batch Batch, fn func(Entity, *
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map8[A, B, C, D, E, F, G, H]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -2814,6 +3117,7 @@ func (m *Map8[A, B, C, D, E, F, G, H]) SetRelations(entity Entity, rel ...Relati
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -2854,6 +3158,8 @@ func (*Map9[A, B, C, D, E, F, G, H, I]) New(world *World) *Map9[A, B, C, D, E, F
 // NewMap9 creates a new [Map9].
 //
 // See also [Map9.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap9[A any, B any, C any, D any, E any, F any, G any, H any, I any](world *World) *Map9[A, B, C, D, E, F, G, H, I] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -2866,6 +3172,9 @@ func NewMap9[A any, B any, C any, D any, E any, F any, G any, H any, I any](worl
 		ComponentID[H](world),
 		ComponentID[I](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map9")
+	}
 	return &Map9[A, B, C, D, E, F, G, H, I]{
 		world:    world,
 		ids:      ids,
@@ -2929,6 +3238,7 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) NewEntityFn(fn func(*A, *B, *C, *D, *E
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -3010,6 +3320,7 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) NewBatchFn(count int, fn func(Entity,
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -3025,6 +3336,22 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) Get(entity Entity) (*A, *B, *C, *D, *E
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index), get[G](m.storageG, index), get[H](m.storageH, index), get[I](m.storageI, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map9.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map9.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map9[A, B, C, D, E, F, G, H, I]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F, *G, *H, *I) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick), getMut[G](m.storageG, index, tick), getMut[H](m.storageH, index, tick), getMut[I](m.storageI, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map9.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -3104,6 +3431,7 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) AddFn(entity Entity, fn func(*A, *B, *
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -3130,19 +3458,39 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) Set(entity Entity, a *A, b *B, c *C, d
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
-	*(*G)(m.storageG.columns[index.table].Get(row)) = *g
-	*(*H)(m.storageH.columns[index.table].Get(row)) = *h
-	*(*I)(m.storageI.columns[index.table].Get(row)) = *i
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
+	columnG := m.storageG.columns[index.table]
+	*(*G)(columnG.Get(row)) = *g
+	columnG.setChanged(row, tick)
+	columnH := m.storageH.columns[index.table]
+	*(*H)(columnH.Get(row)) = *h
+	columnH.setChanged(row, tick)
+	columnI := m.storageI.columns[index.table]
+	*(*I)(columnI.Get(row)) = *i
+	columnI.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -3206,11 +3554,13 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) AddBatchFn(batch Batch, fn func(Entity
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map9[A, B, C, D, E, F, G, H, I]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -3239,6 +3589,7 @@ func (m *Map9[A, B, C, D, E, F, G, H, I]) SetRelations(entity Entity, rel ...Rel
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -3280,6 +3631,8 @@ func (*Map10[A, B, C, D, E, F, G, H, I, J]) New(world *World) *Map10[A, B, C, D,
 // NewMap10 creates a new [Map10].
 //
 // See also [Map10.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap10[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any](world *World) *Map10[A, B, C, D, E, F, G, H, I, J] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -3293,6 +3646,9 @@ func NewMap10[A any, B any, C any, D any, E any, F any, G any, H any, I any, J a
 		ComponentID[I](world),
 		ComponentID[J](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map10")
+	}
 	return &Map10[A, B, C, D, E, F, G, H, I, J]{
 		world:    world,
 		ids:      ids,
@@ -3359,6 +3715,7 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) NewEntityFn(fn func(*A, *B, *C, *D
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -3443,6 +3800,7 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) NewBatchFn(count int, fn func(Enti
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -3458,6 +3816,22 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) Get(entity Entity) (*A, *B, *C, *D
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index), get[G](m.storageG, index), get[H](m.storageH, index), get[I](m.storageI, index), get[J](m.storageJ, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map10.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map10.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map10[A, B, C, D, E, F, G, H, I, J]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F, *G, *H, *I, *J) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick), getMut[G](m.storageG, index, tick), getMut[H](m.storageH, index, tick), getMut[I](m.storageI, index, tick), getMut[J](m.storageJ, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map10.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -3540,6 +3914,7 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) AddFn(entity Entity, fn func(*A, *
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -3567,20 +3942,42 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) Set(entity Entity, a *A, b *B, c *
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
-	*(*G)(m.storageG.columns[index.table].Get(row)) = *g
-	*(*H)(m.storageH.columns[index.table].Get(row)) = *h
-	*(*I)(m.storageI.columns[index.table].Get(row)) = *i
-	*(*J)(m.storageJ.columns[index.table].Get(row)) = *j
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
+	columnG := m.storageG.columns[index.table]
+	*(*G)(columnG.Get(row)) = *g
+	columnG.setChanged(row, tick)
+	columnH := m.storageH.columns[index.table]
+	*(*H)(columnH.Get(row)) = *h
+	columnH.setChanged(row, tick)
+	columnI := m.storageI.columns[index.table]
+	*(*I)(columnI.Get(row)) = *i
+	columnI.setChanged(row, tick)
+	columnJ := m.storageJ.columns[index.table]
+	*(*J)(columnJ.Get(row)) = *j
+	columnJ.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -3647,11 +4044,13 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) AddBatchFn(batch Batch, fn func(En
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map10[A, B, C, D, E, F, G, H, I, J]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -3680,6 +4079,7 @@ func (m *Map10[A, B, C, D, E, F, G, H, I, J]) SetRelations(entity Entity, rel ..
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -3722,6 +4122,8 @@ func (*Map11[A, B, C, D, E, F, G, H, I, J, K]) New(world *World) *Map11[A, B, C,
 // NewMap11 creates a new [Map11].
 //
 // See also [Map11.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap11[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any, K any](world *World) *Map11[A, B, C, D, E, F, G, H, I, J, K] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -3736,6 +4138,9 @@ func NewMap11[A any, B any, C any, D any, E any, F any, G any, H any, I any, J a
 		ComponentID[J](world),
 		ComponentID[K](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map11")
+	}
 	return &Map11[A, B, C, D, E, F, G, H, I, J, K]{
 		world:    world,
 		ids:      ids,
@@ -3805,6 +4210,7 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) NewEntityFn(fn func(*A, *B, *C,
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -3892,6 +4298,7 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) NewBatchFn(count int, fn func(E
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -3907,6 +4314,22 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) Get(entity Entity) (*A, *B, *C,
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index), get[G](m.storageG, index), get[H](m.storageH, index), get[I](m.storageI, index), get[J](m.storageJ, index), get[K](m.storageK, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map11.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map11.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F, *G, *H, *I, *J, *K) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick), getMut[G](m.storageG, index, tick), getMut[H](m.storageH, index, tick), getMut[I](m.storageI, index, tick), getMut[J](m.storageJ, index, tick), getMut[K](m.storageK, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map11.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -3992,6 +4415,7 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) AddFn(entity Entity, fn func(*A
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -4020,21 +4444,45 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) Set(entity Entity, a *A, b *B,
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
-	*(*G)(m.storageG.columns[index.table].Get(row)) = *g
-	*(*H)(m.storageH.columns[index.table].Get(row)) = *h
-	*(*I)(m.storageI.columns[index.table].Get(row)) = *i
-	*(*J)(m.storageJ.columns[index.table].Get(row)) = *j
-	*(*K)(m.storageK.columns[index.table].Get(row)) = *k
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
+	columnG := m.storageG.columns[index.table]
+	*(*G)(columnG.Get(row)) = *g
+	columnG.setChanged(row, tick)
+	columnH := m.storageH.columns[index.table]
+	*(*H)(columnH.Get(row)) = *h
+	columnH.setChanged(row, tick)
+	columnI := m.storageI.columns[index.table]
+	*(*I)(columnI.Get(row)) = *i
+	columnI.setChanged(row, tick)
+	columnJ := m.storageJ.columns[index.table]
+	*(*J)(columnJ.Get(row)) = *j
+	columnJ.setChanged(row, tick)
+	columnK := m.storageK.columns[index.table]
+	*(*K)(columnK.Get(row)) = *k
+	columnK.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -4104,11 +4552,13 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) AddBatchFn(batch Batch, fn func
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -4137,6 +4587,7 @@ func (m *Map11[A, B, C, D, E, F, G, H, I, J, K]) SetRelations(entity Entity, rel
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -4180,6 +4631,8 @@ func (*Map12[A, B, C, D, E, F, G, H, I, J, K, L]) New(world *World) *Map12[A, B,
 // NewMap12 creates a new [Map12].
 //
 // See also [Map12.New] for a shortcut when constructing an already defined instance.
+//
+// Panics if any of the components uses sparse storage (see [StorageSparse]). Use [Map] for these.
 func NewMap12[A any, B any, C any, D any, E any, F any, G any, H any, I any, J any, K any, L any](world *World) *Map12[A, B, C, D, E, F, G, H, I, J, K, L] {
 	ids := []ID{
 		ComponentID[A](world),
@@ -4195,6 +4648,9 @@ func NewMap12[A any, B any, C any, D any, E any, F any, G any, H any, I any, J a
 		ComponentID[K](world),
 		ComponentID[L](world),
 	}
+	for _, id := range ids {
+		world.storage.checkNotSparse(id, "Map12")
+	}
 	return &Map12[A, B, C, D, E, F, G, H, I, J, K, L]{
 		world:    world,
 		ids:      ids,
@@ -4267,6 +4723,7 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) NewEntityFn(fn func(*A, *B,
 	if len(rel) > 0 {
 		m.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	m.world.flushEvents()
 	return entity
 }
 
@@ -4357,6 +4814,7 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) NewBatchFn(count int, fn fun
 	if shouldLock {
 		m.world.unlock(lock)
 	}
+	m.world.flushEvents()
 }
 
 // Get returns the mapped components for the given entity.
@@ -4372,6 +4830,22 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) Get(entity Entity) (*A, *B,
 	return get[A](m.storageA, index), get[B](m.storageB, index), get[C](m.storageC, index), get[D](m.storageD, index), get[E](m.storageE, index), get[F](m.storageF, index), get[G](m.storageG, index), get[H](m.storageH, index), get[I](m.storageI, index), get[J](m.storageJ, index), get[K](m.storageK, index), get[L](m.storageL, index)
 }
 
+// GetMut returns the mapped components for the given entity, like [Map12.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// Use this instead of [Map12.Get] when modifying the components in place.
+//
+// Return nil for components the entity is missing.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context!
+func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) GetMut(entity Entity) (*A, *B, *C, *D, *E, *F, *G, *H, *I, *J, *K, *L) {
+	if !m.world.storage.entityPool.Alive(entity) {
+		panic("can't get components of a dead entity")
+	}
+	index := &m.world.storage.entities[entity.id]
+	tick := m.world.storage.tick
+	return getMut[A](m.storageA, index, tick), getMut[B](m.storageB, index, tick), getMut[C](m.storageC, index, tick), getMut[D](m.storageD, index, tick), getMut[E](m.storageE, index, tick), getMut[F](m.storageF, index, tick), getMut[G](m.storageG, index, tick), getMut[H](m.storageH, index, tick), getMut[I](m.storageI, index, tick), getMut[J](m.storageJ, index, tick), getMut[K](m.storageK, index, tick), getMut[L](m.storageL, index, tick)
+}
+
 // GetUnchecked returns the mapped components for the given entity.
 // In contrast to [Map12.Get], it does not check whether the entity is alive.
 // Can be used as an optimization when it is certain that the entity is alive.
@@ -4460,6 +4934,7 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) AddFn(entity Entity, fn func
 	if len(rel) > 0 {
 		m.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	m.world.flushEvents()
 }
 
 // Set the mapped components of the given entity to the given values.
@@ -4489,22 +4964,48 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) Set(entity Entity, a *A, b *
 
 	index := &m.world.storage.entities[entity.id]
 	row := uintptr(index.row)
-	*(*A)(m.storageA.columns[index.table].Get(row)) = *a
-	*(*B)(m.storageB.columns[index.table].Get(row)) = *b
-	*(*C)(m.storageC.columns[index.table].Get(row)) = *c
-	*(*D)(m.storageD.columns[index.table].Get(row)) = *d
-	*(*E)(m.storageE.columns[index.table].Get(row)) = *e
-	*(*F)(m.storageF.columns[index.table].Get(row)) = *f
-	*(*G)(m.storageG.columns[index.table].Get(row)) = *g
-	*(*H)(m.storageH.columns[index.table].Get(row)) = *h
-	*(*I)(m.storageI.columns[index.table].Get(row)) = *i
-	*(*J)(m.storageJ.columns[index.table].Get(row)) = *j
-	*(*K)(m.storageK.columns[index.table].Get(row)) = *k
-	*(*L)(m.storageL.columns[index.table].Get(row)) = *l
+	tick := m.world.storage.tick
+	columnA := m.storageA.columns[index.table]
+	*(*A)(columnA.Get(row)) = *a
+	columnA.setChanged(row, tick)
+	columnB := m.storageB.columns[index.table]
+	*(*B)(columnB.Get(row)) = *b
+	columnB.setChanged(row, tick)
+	columnC := m.storageC.columns[index.table]
+	*(*C)(columnC.Get(row)) = *c
+	columnC.setChanged(row, tick)
+	columnD := m.storageD.columns[index.table]
+	*(*D)(columnD.Get(row)) = *d
+	columnD.setChanged(row, tick)
+	columnE := m.storageE.columns[index.table]
+	*(*E)(columnE.Get(row)) = *e
+	columnE.setChanged(row, tick)
+	columnF := m.storageF.columns[index.table]
+	*(*F)(columnF.Get(row)) = *f
+	columnF.setChanged(row, tick)
+	columnG := m.storageG.columns[index.table]
+	*(*G)(columnG.Get(row)) = *g
+	columnG.setChanged(row, tick)
+	columnH := m.storageH.columns[index.table]
+	*(*H)(columnH.Get(row)) = *h
+	columnH.setChanged(row, tick)
+	columnI := m.storageI.columns[index.table]
+	*(*I)(columnI.Get(row)) = *i
+	columnI.setChanged(row, tick)
+	columnJ := m.storageJ.columns[index.table]
+	*(*J)(columnJ.Get(row)) = *j
+	columnJ.setChanged(row, tick)
+	columnK := m.storageK.columns[index.table]
+	*(*K)(columnK.Get(row)) = *k
+	columnK.setChanged(row, tick)
+	columnL := m.storageL.columns[index.table]
+	*(*L)(columnL.Get(row)) = *l
+	columnL.setChanged(row, tick)
 
 	if m.world.storage.observers.HasObservers(OnSetComponents) {
 		newMask := &m.world.storage.archetypes[m.world.storage.tables[index.table].archetype].mask
 		m.world.storage.observers.FireSet(entity, &m.mask, newMask)
+		m.world.flushEvents()
 	}
 }
 
@@ -4577,11 +5078,13 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) AddBatchFn(batch Batch, fn f
 		}
 	}
 	m.world.exchangeBatch(&batch, m.ids, nil, m.relations, process)
+	m.world.flushEvents()
 }
 
 // Remove the mapped components from the given entity.
 func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) Remove(entity Entity) {
 	m.world.remove(entity, m.ids)
+	m.world.flushEvents()
 }
 
 // RemoveBatch removes the mapped components from all entities matching the given batch filter,
@@ -4610,6 +5113,7 @@ func (m *Map12[A, B, C, D, E, F, G, H, I, J, K, L]) SetRelations(entity Entity,
 	// alive check is done in World.setRelations
 	m.relations = relationSlice(rel).ToRelations(m.world, &m.mask, m.ids, m.relations[:0], false)
 	m.world.setRelations(entity, m.relations)
+	m.world.flushEvents()
 }
 
 // SetRelationsBatch sets relation targets for all entities matching the given batch filter.
@@ -43,6 +43,8 @@ func TestMap1(t *testing.T) {
 		expectNotNil(t, a)
 		a = mapper.GetUnchecked(entity)
 		expectNotNil(t, a)
+		a = mapper.GetMut(entity)
+		expectNotNil(t, a)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{})
 	}
@@ -55,6 +57,9 @@ func TestMap1(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -83,6 +88,9 @@ func TestMap1Nil(t *testing.T) {
 
 	a = mapper.GetUnchecked(entity)
 	expectNil(t, a)
+
+	a = mapper.GetMut(entity)
+	expectNil(t, a)
 }
 
 func TestMap1NewBatch(t *testing.T) {
@@ -375,6 +383,9 @@ func TestMap2(t *testing.T) {
 		a, b = mapper.GetUnchecked(entity)
 		expectNotNil(t, a)
 		expectNotNil(t, b)
+		a, b = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{})
 	}
@@ -387,6 +398,9 @@ func TestMap2(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -417,6 +431,10 @@ func TestMap2Nil(t *testing.T) {
 	a, b = mapper.GetUnchecked(entity)
 	expectNil(t, a)
 	expectNil(t, b)
+
+	a, b = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
 }
 
 func TestMap2NewBatch(t *testing.T) {
@@ -711,6 +729,10 @@ func TestMap3(t *testing.T) {
 		expectNotNil(t, a)
 		expectNotNil(t, b)
 		expectNotNil(t, c)
+		a, b, c = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{})
 	}
@@ -723,6 +745,9 @@ func TestMap3(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -755,6 +780,11 @@ func TestMap3Nil(t *testing.T) {
 	expectNil(t, a)
 	expectNil(t, b)
 	expectNil(t, c)
+
+	a, b, c = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
 }
 
 func TestMap3NewBatch(t *testing.T) {
@@ -1051,6 +1081,11 @@ func TestMap4(t *testing.T) {
 		expectNotNil(t, b)
 		expectNotNil(t, c)
 		expectNotNil(t, d)
+		a, b, c, d = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{})
 	}
@@ -1063,6 +1098,9 @@ func TestMap4(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -1097,6 +1135,12 @@ func TestMap4Nil(t *testing.T) {
 	expectNil(t, b)
 	expectNil(t, c)
 	expectNil(t, d)
+
+	a, b, c, d = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
 }
 
 func TestMap4NewBatch(t *testing.T) {
@@ -1395,6 +1439,12 @@ func TestMap5(t *testing.T) {
 		expectNotNil(t, c)
 		expectNotNil(t, d)
 		expectNotNil(t, e)
+		a, b, c, d, e = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
 	}
@@ -1407,6 +1457,9 @@ func TestMap5(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -1443,6 +1496,13 @@ func TestMap5Nil(t *testing.T) {
 	expectNil(t, c)
 	expectNil(t, d)
 	expectNil(t, e)
+
+	a, b, c, d, e = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
 }
 
 func TestMap5NewBatch(t *testing.T) {
@@ -1743,6 +1803,13 @@ func TestMap6(t *testing.T) {
 		expectNotNil(t, d)
 		expectNotNil(t, e)
 		expectNotNil(t, f)
+		a, b, c, d, e, f = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
 	}
@@ -1755,6 +1822,9 @@ func TestMap6(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -1793,6 +1863,14 @@ func TestMap6Nil(t *testing.T) {
 	expectNil(t, d)
 	expectNil(t, e)
 	expectNil(t, f)
+
+	a, b, c, d, e, f = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
 }
 
 func TestMap6NewBatch(t *testing.T) {
@@ -2095,6 +2173,14 @@ func TestMap7(t *testing.T) {
 		expectNotNil(t, e)
 		expectNotNil(t, f)
 		expectNotNil(t, g)
+		a, b, c, d, e, f, g = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
+		expectNotNil(t, g)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
 	}
@@ -2107,6 +2193,9 @@ func TestMap7(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -2147,6 +2236,15 @@ func TestMap7Nil(t *testing.T) {
 	expectNil(t, e)
 	expectNil(t, f)
 	expectNil(t, g)
+
+	a, b, c, d, e, f, g = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
+	expectNil(t, g)
 }
 
 func TestMap7NewBatch(t *testing.T) {
@@ -2451,6 +2549,15 @@ func TestMap8(t *testing.T) {
 		expectNotNil(t, f)
 		expectNotNil(t, g)
 		expectNotNil(t, h)
+		a, b, c, d, e, f, g, h = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
+		expectNotNil(t, g)
+		expectNotNil(t, h)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
 	}
@@ -2463,6 +2570,9 @@ func TestMap8(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -2505,6 +2615,16 @@ func TestMap8Nil(t *testing.T) {
 	expectNil(t, f)
 	expectNil(t, g)
 	expectNil(t, h)
+
+	a, b, c, d, e, f, g, h = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
+	expectNil(t, g)
+	expectNil(t, h)
 }
 
 func TestMap8NewBatch(t *testing.T) {
@@ -2811,6 +2931,16 @@ func TestMap9(t *testing.T) {
 		expectNotNil(t, g)
 		expectNotNil(t, h)
 		expectNotNil(t, i)
+		a, b, c, d, e, f, g, h, i = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
+		expectNotNil(t, g)
+		expectNotNil(t, h)
+		expectNotNil(t, i)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{})
 	}
@@ -2823,6 +2953,9 @@ func TestMap9(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -2867,6 +3000,17 @@ func TestMap9Nil(t *testing.T) {
 	expectNil(t, g)
 	expectNil(t, h)
 	expectNil(t, i)
+
+	a, b, c, d, e, f, g, h, i = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
+	expectNil(t, g)
+	expectNil(t, h)
+	expectNil(t, i)
 }
 
 func TestMap9NewBatch(t *testing.T) {
@@ -3175,6 +3319,17 @@ func TestMap10(t *testing.T) {
 		expectNotNil(t, h)
 		expectNotNil(t, i)
 		expectNotNil(t, j)
+		a, b, c, d, e, f, g, h, i, j = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
+		expectNotNil(t, g)
+		expectNotNil(t, h)
+		expectNotNil(t, i)
+		expectNotNil(t, j)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{})
 	}
@@ -3187,6 +3342,9 @@ func TestMap10(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -3234,6 +3392,18 @@ func TestMap10Nil(t *testing.T) {
 	expectNil(t, h)
 	expectNil(t, i)
 	expectNil(t, j)
+
+	a, b, c, d, e, f, g, h, i, j = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
+	expectNil(t, g)
+	expectNil(t, h)
+	expectNil(t, i)
+	expectNil(t, j)
 }
 
 func TestMap10NewBatch(t *testing.T) {
@@ -3544,6 +3714,18 @@ func TestMap11(t *testing.T) {
 		expectNotNil(t, i)
 		expectNotNil(t, j)
 		expectNotNil(t, k)
+		a, b, c, d, e, f, g, h, i, j, k = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
+		expectNotNil(t, g)
+		expectNotNil(t, h)
+		expectNotNil(t, i)
+		expectNotNil(t, j)
+		expectNotNil(t, k)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{})
 	}
@@ -3556,6 +3738,9 @@ func TestMap11(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -3605,6 +3790,19 @@ func TestMap11Nil(t *testing.T) {
 	expectNil(t, i)
 	expectNil(t, j)
 	expectNil(t, k)
+
+	a, b, c, d, e, f, g, h, i, j, k = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
+	expectNil(t, g)
+	expectNil(t, h)
+	expectNil(t, i)
+	expectNil(t, j)
+	expectNil(t, k)
 }
 
 func TestMap11NewBatch(t *testing.T) {
@@ -3917,6 +4115,19 @@ func TestMap12(t *testing.T) {
 		expectNotNil(t, j)
 		expectNotNil(t, k)
 		expectNotNil(t, l)
+		a, b, c, d, e, f, g, h, i, j, k, l = mapper.GetMut(entity)
+		expectNotNil(t, a)
+		expectNotNil(t, b)
+		expectNotNil(t, c)
+		expectNotNil(t, d)
+		expectNotNil(t, e)
+		expectNotNil(t, f)
+		expectNotNil(t, g)
+		expectNotNil(t, h)
+		expectNotNil(t, i)
+		expectNotNil(t, j)
+		expectNotNil(t, k)
+		expectNotNil(t, l)
 		expectTrue(t, mapper.HasAll(entity))
 		mapper.Set(entity, &CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, &CompI{}, &CompJ{}, &CompK{}, &CompL{})
 	}
@@ -3929,6 +4140,9 @@ func TestMap12(t *testing.T) {
 	expectPanics(t, func() {
 		mapper.Get(Entity{})
 	})
+	expectPanics(t, func() {
+		mapper.GetMut(Entity{})
+	})
 	expectPanics(t, func() {
 		mapper.HasAll(Entity{})
 	})
@@ -3980,6 +4194,20 @@ func TestMap12Nil(t *testing.T) {
 	expectNil(t, j)
 	expectNil(t, k)
 	expectNil(t, l)
+
+	a, b, c, d, e, f, g, h, i, j, k, l = mapper.GetMut(entity)
+	expectNil(t, a)
+	expectNil(t, b)
+	expectNil(t, c)
+	expectNil(t, d)
+	expectNil(t, e)
+	expectNil(t, f)
+	expectNil(t, g)
+	expectNil(t, h)
+	expectNil(t, i)
+	expectNil(t, j)
+	expectNil(t, k)
+	expectNil(t, l)
 }
 
 func TestMap12NewBatch(t *testing.T) {
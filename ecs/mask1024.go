@@ -0,0 +1,130 @@
+package ecs
+
+import (
+	"math/bits"
+)
+
+// mask1024TotalBits is the size of a [bitMask1024] in bits.
+// It is the maximum number of component types that may exist in any [World] with build tag ark_large.
+const mask1024TotalBits = 1024
+const mask1024Words = mask1024TotalBits / wordSize
+
+// bitMask1024 is a 1024 bit bit-mask.
+type bitMask1024 struct {
+	bits [mask1024Words]uint64 // 16x 64 bits of the mask
+}
+
+// newMask1024 creates a new Mask from a list of IDs.
+// Matches all entities that have the respective components, and potentially further components.
+func newMask1024(ids ...ID) bitMask1024 {
+	var mask bitMask1024
+	for _, id := range ids {
+		mask.Set(uint16(id.id))
+	}
+	return mask
+}
+
+// Get reports whether the bit at the given index [ID] is set.
+func (b *bitMask1024) Get(bit uint16) bool {
+	idx := bit >> 6
+	mask := uint64(1) << (bit & 63)
+	return b.bits[idx]&mask == mask
+}
+
+// Set sets the state of the bit at the given index.
+func (b *bitMask1024) Set(bit uint16) {
+	idx := bit >> 6
+	mask := uint64(1) << (bit & 63)
+	b.bits[idx] |= mask
+}
+
+// Clear sets the state of the bit at the given index.
+func (b *bitMask1024) Clear(bit uint16) {
+	idx := bit >> 6
+	mask := uint64(1) << (bit & 63)
+	b.bits[idx] &^= mask
+}
+
+// Not returns the inversion of this mask.
+func (b *bitMask1024) Not() bitMask1024 {
+	var result bitMask1024
+	for i, w := range b.bits {
+		result.bits[i] = ^w
+	}
+	return result
+}
+
+// OrI calculates the OR of this mask and other in-place.
+func (b *bitMask1024) OrI(other *bitMask1024) {
+	for i, w := range other.bits {
+		b.bits[i] |= w
+	}
+}
+
+// IsZero returns whether no bits are set in the mask.
+func (b *bitMask1024) IsZero() bool {
+	return b.bits == [mask1024Words]uint64{}
+}
+
+// Reset the mask setting all bits to false.
+func (b *bitMask1024) Reset() {
+	b.bits = [mask1024Words]uint64{}
+}
+
+// Contains reports if the other mask is a subset of this mask.
+func (b *bitMask1024) Contains(other *bitMask1024) bool {
+	for i, o := range other.bits {
+		if b.bits[i]&o != o {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny reports if any bit of the other mask is in this mask.
+func (b *bitMask1024) ContainsAny(other *bitMask1024) bool {
+	for i, o := range other.bits {
+		if b.bits[i]&o != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalBitsSet returns how many bits are set in this mask.
+func (b *bitMask1024) TotalBitsSet() int {
+	count := 0
+	for _, w := range b.bits {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Equals returns whether two masks are equal.
+func (b *bitMask1024) Equals(other *bitMask1024) bool {
+	return b.bits == other.bits
+}
+
+// toTypes converts a mask to a list of component IDs.
+func (b *bitMask1024) toTypes(reg *registry) []ID {
+	count := b.TotalBitsSet()
+	types := make([]ID, count)
+
+	totalIDs := reg.Count()
+
+	idx := 0
+	for i := 0; i*wordSize < totalIDs; i++ {
+		if b.bits[i] == 0 {
+			continue
+		}
+		cnt := min(wordSize, totalIDs-i*wordSize)
+		for j := range cnt {
+			bit := uint16(i*wordSize + j)
+			if b.Get(bit) {
+				types[idx] = ID{id: idIndex(bit)}
+				idx++
+			}
+		}
+	}
+	return types
+}
@@ -0,0 +1,98 @@
+package ecs
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMask1024(t *testing.T) {
+	big := uint16(mask1024TotalBits - 2)
+	var mask bitMask1024
+	for _, bit := range []uint16{1, 2, 13, 27, 300, big} {
+		mask.Set(bit)
+	}
+
+	expectEqual(t, 6, mask.TotalBitsSet())
+
+	expectTrue(t, mask.Get(1))
+	expectTrue(t, mask.Get(300))
+	expectTrue(t, mask.Get(big))
+
+	expectFalse(t, mask.Get(0))
+	expectFalse(t, mask.Get(299))
+	expectFalse(t, mask.Get(big-1))
+	expectFalse(t, mask.Get(big+1))
+
+	mask.Clear(300)
+	expectFalse(t, mask.Get(300))
+
+	other1 := newMask1024(id(1), id(2))
+	other1.Set(700)
+	other2 := newMask1024(id(2), id(13))
+	other2.Set(big)
+
+	expectFalse(t, mask.Contains(&other1))
+	expectTrue(t, mask.Contains(&other2))
+	expectTrue(t, mask.ContainsAny(&other1))
+
+	other1 = bitMask1024{}
+	other1.Set(700)
+	expectFalse(t, mask.ContainsAny(&other1))
+
+	not := mask.Not()
+	expectFalse(t, not.Get(1))
+	expectTrue(t, not.Get(700))
+	expectEqual(t, mask1024TotalBits-5, not.TotalBitsSet())
+
+	expectTrue(t, mask.Equals(&mask))
+	expectFalse(t, mask.Equals(&other2))
+
+	mask.OrI(&other1)
+	expectTrue(t, mask.Get(700))
+	expectTrue(t, mask.Get(big))
+
+	expectFalse(t, mask.IsZero())
+	mask.Reset()
+	expectTrue(t, mask.IsZero())
+	expectEqual(t, 0, mask.TotalBitsSet())
+
+	for i := range mask1024TotalBits {
+		bit := uint16(i)
+		expectFalse(t, mask.Get(bit))
+		mask.Set(bit)
+		expectTrue(t, mask.Get(bit))
+		mask.Clear(bit)
+		expectFalse(t, mask.Get(bit))
+	}
+}
+
+func TestMask1024ToTypes(t *testing.T) {
+	w := NewWorld(1024)
+
+	id1 := ComponentID[Position](w)
+	id2 := ComponentID[Velocity](w)
+
+	mask := newMask1024()
+	comps := mask.toTypes(&w.storage.registry.registry)
+	expectSlicesEqual(t, []ID{}, comps)
+
+	mask = newMask1024(id1, id2)
+	comps = mask.toTypes(&w.storage.registry.registry)
+	expectSlicesEqual(t, []ID{id1, id2}, comps)
+}
+
+func BenchmarkMask1024Contains(b *testing.B) {
+	mask := newMask1024()
+	for i := range mask1024TotalBits {
+		if rand.Float64() < 0.5 {
+			mask.Set(uint16(i))
+		}
+	}
+	filter := newMask1024(id(rand.Intn(mask256TotalBits)))
+
+	var v bool
+	for b.Loop() {
+		v = mask.Contains(&filter)
+	}
+	_ = v
+}
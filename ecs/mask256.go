@@ -19,7 +19,7 @@ type bitMask256 struct {
 func newMask256(ids ...ID) bitMask256 {
 	var mask bitMask256
 	for _, id := range ids {
-		mask.Set(id.id)
+		mask.Set(uint8(id.id))
 	}
 	return mask
 }
@@ -119,9 +119,9 @@ func (b *bitMask256) toTypes(reg *registry) []ID {
 			cnt = bits
 		}
 		for j := range cnt {
-			id := ID{id: uint8(i*wordSize + j)}
-			if b.Get(id.id) {
-				types[idx] = id
+			bit := uint8(i*wordSize + j)
+			if b.Get(bit) {
+				types[idx] = ID{id: idIndex(bit)}
 				idx++
 			}
 		}
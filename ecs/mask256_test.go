@@ -122,12 +122,12 @@ func TestBitMask256(t *testing.T) {
 
 	mask = newMask256()
 	for i := range 256 {
-		id := ID{uint8(i)}
-		expectFalse(t, mask.Get(id.id))
-		mask.Set(id.id)
-		expectTrue(t, mask.Get(id.id))
-		mask.Clear(id.id)
-		expectFalse(t, mask.Get(id.id))
+		bit := uint8(i)
+		expectFalse(t, mask.Get(bit))
+		mask.Set(bit)
+		expectTrue(t, mask.Get(bit))
+		mask.Clear(bit)
+		expectFalse(t, mask.Get(bit))
 	}
 }
 
@@ -153,11 +153,11 @@ func BenchmarkMask256Get(b *testing.B) {
 			mask.Set(uint8(i))
 		}
 	}
-	idx := id(rand.Intn(mask256TotalBits))
+	idx := uint8(rand.Intn(mask256TotalBits))
 
 	var v bool
 	for b.Loop() {
-		v = mask.Get(idx.id)
+		v = mask.Get(idx)
 	}
 	_ = v
 }
@@ -18,7 +18,7 @@ type bitMask64 struct {
 func newMask64(ids ...ID) bitMask64 {
 	var mask bitMask64
 	for _, id := range ids {
-		mask.Set(id.id)
+		mask.Set(uint8(id.id))
 	}
 	return mask
 }
@@ -93,9 +93,9 @@ func (b *bitMask64) toTypes(reg *registry) []ID {
 
 	idx := 0
 	for j := range totalIDs {
-		id := ID{id: uint8(j)}
-		if b.Get(id.id) {
-			types[idx] = id
+		bit := uint8(j)
+		if b.Get(bit) {
+			types[idx] = ID{id: idIndex(bit)}
 			idx++
 		}
 	}
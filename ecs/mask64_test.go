@@ -119,12 +119,12 @@ func TestBitMask64(t *testing.T) {
 
 	mask = newMask64()
 	for i := range 64 {
-		id := ID{uint8(i)}
-		expectFalse(t, mask.Get(id.id))
-		mask.Set(id.id)
-		expectTrue(t, mask.Get(id.id))
-		mask.Clear(id.id)
-		expectFalse(t, mask.Get(id.id))
+		bit := uint8(i)
+		expectFalse(t, mask.Get(bit))
+		mask.Set(bit)
+		expectTrue(t, mask.Get(bit))
+		mask.Clear(bit)
+		expectFalse(t, mask.Get(bit))
 	}
 }
 
@@ -150,11 +150,11 @@ func BenchmarkMask64Get(b *testing.B) {
 			mask.Set(uint8(i))
 		}
 	}
-	idx := id(rand.Intn(mask64TotalBits))
+	idx := uint8(rand.Intn(mask64TotalBits))
 
 	var v bool
 	for b.Loop() {
-		v = mask.Get(idx.id)
+		v = mask.Get(idx)
 	}
 	_ = v
 }
@@ -0,0 +1,15 @@
+//go:build ark_large && !ark_tiny
+
+package ecs
+
+// maskTotalBits for 1024 bit large ECS.
+const maskTotalBits = mask1024TotalBits
+
+// bitMask for 1024 bit large ECS.
+type bitMask = bitMask1024
+
+// newMask constructor for 1024 bit large ECS.
+var newMask = newMask1024
+
+// idIndex is the type of component and resource indices for 1024 bit large ECS.
+type idIndex = uint16
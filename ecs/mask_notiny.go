@@ -1,4 +1,4 @@
-//go:build !ark_tiny
+//go:build !ark_tiny && !ark_large
 
 package ecs
 
@@ -10,3 +10,6 @@ type bitMask = bitMask256
 
 // newMask constructor for 256 bit ECS.
 var newMask = newMask256
+
+// idIndex is the type of component and resource indices for 256 bit ECS.
+type idIndex = uint8
@@ -10,3 +10,6 @@ type bitMask = bitMask64
 
 // newMask constructor for 64 bit tiny ECS.
 var newMask = newMask64
+
+// idIndex is the type of component and resource indices for 64 bit tiny ECS.
+type idIndex = uint8
@@ -0,0 +1,440 @@
+package ecs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// multiRelation stores the targets of a multi-target relation component (see [MultiRelationMarker]).
+//
+// In contrast to single-target relations, targets don't split archetypes into tables.
+// Instead, they are stored per entity, with a reverse index from targets to the entities pointing to them.
+type multiRelation struct {
+	targets map[entityID][]Entity  // Targets per entity, sorted by ID, without duplicates and zero entities
+	sources map[Entity]*entityList // Entities per target, for filters and cleanup
+}
+
+// newMultiRelation creates a new, empty multiRelation.
+func newMultiRelation() *multiRelation {
+	return &multiRelation{
+		targets: map[entityID][]Entity{},
+		sources: map[Entity]*entityList{},
+	}
+}
+
+// Targets returns the targets of the given entity.
+// The returned slice must not be modified.
+func (m *multiRelation) Targets(entity Entity) []Entity {
+	return m.targets[entity.id]
+}
+
+// HasAny returns whether the given entity has any targets.
+func (m *multiRelation) HasAny(entity Entity) bool {
+	_, ok := m.targets[entity.id]
+	return ok
+}
+
+// Has returns whether the given entity has the given target.
+func (m *multiRelation) Has(entity Entity, target Entity) bool {
+	list, ok := m.sources[target]
+	return ok && list.Has(entity)
+}
+
+// Sources returns all entities that have the given target.
+// The returned slice must not be modified.
+func (m *multiRelation) Sources(target Entity) []Entity {
+	if list, ok := m.sources[target]; ok {
+		return list.entities
+	}
+	return nil
+}
+
+// Set replaces the targets of the given entity.
+// Targets must be normalized, see [normalizeTargets].
+// The slice is used directly, so it should not be modified afterwards.
+// Returns whether the targets changed.
+func (m *multiRelation) Set(entity Entity, targets []Entity) bool {
+	old := m.targets[entity.id]
+	if sameSlices(old, targets) {
+		return false
+	}
+	for _, target := range old {
+		if !containsEntity(targets, target) {
+			m.removeSource(target, entity)
+		}
+	}
+	for _, target := range targets {
+		if !containsEntity(old, target) {
+			m.addSource(target, entity)
+		}
+	}
+	if len(targets) == 0 {
+		delete(m.targets, entity.id)
+	} else {
+		m.targets[entity.id] = targets
+	}
+	return true
+}
+
+// Remove removes all targets of the given entity.
+// Returns whether the entity had any targets.
+func (m *multiRelation) Remove(entity Entity) bool {
+	return m.Set(entity, nil)
+}
+
+// Reset removes all targets.
+func (m *multiRelation) Reset() {
+	clear(m.targets)
+	clear(m.sources)
+}
+
+// Clone creates an independent copy.
+func (m *multiRelation) Clone() *multiRelation {
+	clone := newMultiRelation()
+	for entity, targets := range m.targets {
+		clone.targets[entity] = append([]Entity(nil), targets...)
+	}
+	for target, list := range m.sources {
+		clone.sources[target] = list.Clone()
+	}
+	return clone
+}
+
+// addSource adds an entity to the sources of a target.
+func (m *multiRelation) addSource(target Entity, entity Entity) {
+	if list, ok := m.sources[target]; ok {
+		list.Append(entity)
+		return
+	}
+	list := newEntityList(entity)
+	m.sources[target] = &list
+}
+
+// removeSource removes an entity from the sources of a target.
+func (m *multiRelation) removeSource(target Entity, entity Entity) {
+	list := m.sources[target]
+	list.Remove(entity)
+	if len(list.entities) == 0 {
+		delete(m.sources, target)
+	}
+}
+
+// entityList helper for faster search and remove operations.
+type entityList struct {
+	entities []Entity            // List of entities
+	indices  map[entityID]uint32 // Mapping from entity ID to the index in the list
+}
+
+// newEntityList creates a new entityList.
+func newEntityList(entities ...Entity) entityList {
+	indices := make(map[entityID]uint32, len(entities))
+	for i, e := range entities {
+		indices[e.id] = uint32(i)
+	}
+	return entityList{
+		entities: entities,
+		indices:  indices,
+	}
+}
+
+// Has returns whether the list contains the given entity.
+func (l *entityList) Has(entity Entity) bool {
+	_, ok := l.indices[entity.id]
+	return ok
+}
+
+// Append an entity.
+func (l *entityList) Append(entity Entity) {
+	l.entities = append(l.entities, entity)
+	l.indices[entity.id] = uint32(len(l.entities) - 1)
+}
+
+// Remove an entity.
+func (l *entityList) Remove(entity Entity) {
+	index, ok := l.indices[entity.id]
+	if !ok {
+		return
+	}
+	last := uint32(len(l.entities) - 1)
+	if index != last {
+		l.entities[index] = l.entities[last]
+		l.indices[l.entities[index].id] = index
+	}
+	l.entities = l.entities[:last]
+	delete(l.indices, entity.id)
+}
+
+// Clone creates an independent copy of the list.
+func (l *entityList) Clone() *entityList {
+	list := newEntityList(append([]Entity(nil), l.entities...)...)
+	return &list
+}
+
+// normalizeTargets sorts targets by entity ID and removes duplicates and zero entities, in place.
+func normalizeTargets(targets []Entity) []Entity {
+	sort.Slice(targets, func(i, j int) bool { return targets[i].id < targets[j].id })
+	n := 0
+	for _, target := range targets {
+		if target.IsZero() || (n > 0 && targets[n-1] == target) {
+			continue
+		}
+		targets[n] = target
+		n++
+	}
+	return targets[:n]
+}
+
+// multiRelation returns the target storage of the given multi-target relation component.
+// Creates it if it doesn't exist yet.
+func (s *storage) multiRelation(id ID) *multiRelation {
+	m := s.multi[id.id]
+	if m == nil {
+		m = newMultiRelation()
+		s.multi[id.id] = m
+	}
+	return m
+}
+
+// splitRelations splits relations into relations of single-target relation components,
+// which determine an entity's table, and relations of multi-target relation components.
+// Returns the given relations and nil if there are no multi-target relation components.
+func (s *storage) splitRelations(relations []relationID) (tableRelations []relationID, multiRelations []relationID) {
+	if !s.registry.hasMultiRelations {
+		return relations, nil
+	}
+	if !s.hasMultiRelation(relations) {
+		return relations, nil
+	}
+	tableRelations = make([]relationID, 0, len(relations))
+	for _, rel := range relations {
+		if s.registry.IsMultiRelation[rel.component.id] {
+			multiRelations = append(multiRelations, rel)
+		} else {
+			tableRelations = append(tableRelations, rel)
+		}
+	}
+	return tableRelations, multiRelations
+}
+
+// hasMultiRelation returns whether any of the relations is for a multi-target relation component.
+func (s *storage) hasMultiRelation(relations []relationID) bool {
+	for _, rel := range relations {
+		if s.registry.IsMultiRelation[rel.component.id] {
+			return true
+		}
+	}
+	return false
+}
+
+// multiTargets returns the normalized targets for the given component from relations.
+// The wildcard is not allowed.
+func (s *storage) multiTargets(relations []relationID, comp ID) []Entity {
+	var targets []Entity
+	for _, rel := range relations {
+		if rel.component != comp {
+			continue
+		}
+		s.checkRelationTarget(rel.target)
+		if rel.target.isWildcard() {
+			panic("relation targets must be fully specified, no wildcard allowed")
+		}
+		targets = append(targets, rel.target)
+	}
+	return normalizeTargets(targets)
+}
+
+// setMultiTargets replaces the targets of all multi-target relation components in relations for the given entity.
+// Relations must only contain multi-target relation components.
+func (s *storage) setMultiTargets(entity Entity, relations []relationID) {
+	for i, rel := range relations {
+		if hasRelationComponent(relations[:i], rel.component) {
+			continue
+		}
+		s.multiRelation(rel.component).Set(entity, s.multiTargets(relations[i:], rel.component))
+	}
+}
+
+// checkMultiTargets checks that the given mask contains all multi-target relation components in relations.
+func (s *storage) checkMultiTargets(mask *bitMask, relations []relationID) {
+	for _, rel := range relations {
+		if !mask.Get(rel.component.id) {
+			tp, _ := s.registry.ComponentType(rel.component.id)
+			panic(fmt.Sprintf("entity has no component of type %s to set relation target for", tp.Name()))
+		}
+	}
+}
+
+// changesMultiTargets returns whether setting the multi-target relations in relations would change
+// the targets of the given entity, without applying the changes.
+// If the mask is not nil, the components with changed targets are set in it.
+func (s *storage) changesMultiTargets(entity Entity, relations []relationID, mask *bitMask) bool {
+	changed := false
+	for i, rel := range relations {
+		if hasRelationComponent(relations[:i], rel.component) {
+			continue
+		}
+		targets := s.multiTargets(relations[i:], rel.component)
+		if !sameSlices(s.multiRelation(rel.component).Targets(entity), targets) {
+			changed = true
+			if mask == nil {
+				return true
+			}
+			mask.Set(rel.component.id)
+		}
+	}
+	return changed
+}
+
+// removeMultiTargets removes the targets of the given multi-target relation components for the given entity.
+// Other components are ignored.
+func (s *storage) removeMultiTargets(entity Entity, comps []ID) {
+	for _, id := range comps {
+		if m := s.multi[id.id]; m != nil {
+			m.Remove(entity)
+		}
+	}
+}
+
+// removeAllMultiTargets removes all targets of multi-target relation components for the given entity.
+func (s *storage) removeAllMultiTargets(entity Entity) {
+	for _, m := range s.multi {
+		if m != nil {
+			m.Remove(entity)
+		}
+	}
+}
+
+// copyMultiTargets copies the targets of all multi-target relation components from one entity to another.
+func (s *storage) copyMultiTargets(from, to Entity) {
+	for _, m := range s.multi {
+		if m == nil {
+			continue
+		}
+		if targets := m.Targets(from); len(targets) > 0 {
+			m.Set(to, append([]Entity(nil), targets...))
+		}
+	}
+}
+
+// hasMultiTarget returns whether the given entity is a target of any multi-target relation.
+func (s *storage) hasMultiTarget(target Entity) bool {
+	for _, m := range s.multi {
+		if m != nil {
+			if _, ok := m.sources[target]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// firstMultiTarget returns the target with the lowest ID of the given entity and multi-target relation component,
+// or the zero entity if the entity has no targets.
+func (s *storage) firstMultiTarget(entity Entity, comp ID) Entity {
+	if m := s.multi[comp.id]; m != nil {
+		if targets := m.Targets(entity); len(targets) > 0 {
+			return targets[0]
+		}
+	}
+	return Entity{}
+}
+
+// multiTargetParams moves conditions on targets of multi-target relation components from relations
+// to a copy of the given entity conditions, as they are checked per entity rather than per table.
+// Returns the remaining relations, the start index of non-cached relations among them, and the conditions.
+// Returns the arguments unchanged if there are no multi-target relation components in relations.
+func (s *storage) multiTargetParams(relations []relationID, start uint8, conditions *entityConditions) ([]relationID, uint8, *entityConditions) {
+	if !s.hasMultiRelation(relations) {
+		return relations, start, conditions
+	}
+	withTargets := entityConditions{}
+	if conditions != nil {
+		withTargets = *conditions
+	}
+	withTargets.targets = append([]multiTarget(nil), withTargets.targets...)
+
+	tableRelations := make([]relationID, 0, len(relations))
+	tableStart := start
+	for i, rel := range relations {
+		if !s.registry.IsMultiRelation[rel.component.id] {
+			tableRelations = append(tableRelations, rel)
+			continue
+		}
+		withTargets.targets = append(withTargets.targets, multiTarget{relation: s.multi[rel.component.id], target: rel.target})
+		if i < int(start) {
+			tableStart--
+		}
+	}
+	return tableRelations, tableStart, &withTargets
+}
+
+// removesMultiRelation returns whether any of the removed components is a multi-target relation component of the table.
+func (s *storage) removesMultiRelation(table *table, remove []ID) bool {
+	for _, id := range remove {
+		if column := table.components[id.id]; column != nil && column.isMultiRelation {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupMultiTargets removes the given target entity from the targets of all multi-target relations,
+// together with other removed target entities.
+// Observers are notified about the changed relations.
+//
+// Entities that lose their last target are handled according to the component's [CleanupPolicy].
+func (s *storage) cleanupMultiTargets(target Entity) {
+	var remove []Entity
+	hasRemRelObs := s.observers.HasObservers(OnRemoveRelations)
+	hasAddRelObs := s.observers.HasObservers(OnAddRelations)
+	for i, m := range s.multi {
+		if m == nil {
+			continue
+		}
+		sources := m.Sources(target)
+		if len(sources) == 0 {
+			continue
+		}
+		id := ID{id: idIndex(i)}
+		policy := s.registry.Cleanup[i]
+		var changeMask bitMask
+		changeMask.Set(id.id)
+
+		for _, entity := range append([]Entity(nil), sources...) {
+			var targets []Entity
+			for _, t := range m.Targets(entity) {
+				if !s.isRemovedTarget(t, target) {
+					targets = append(targets, t)
+				}
+			}
+			if len(targets) == 0 && policy == CleanupDelete {
+				remove = append(remove, entity)
+				continue
+			}
+			if len(targets) == 0 && policy == CleanupRemove {
+				s.removeComponents(entity, []ID{id})
+				continue
+			}
+
+			mask := &s.archetypes[s.tables[s.entities[entity.id].table].archetype].mask
+			if hasRemRelObs {
+				l := s.lock()
+				s.observers.FireSetRelations(OnRemoveRelations, entity, &changeMask, mask)
+				s.unlock(l)
+			}
+			m.Set(entity, targets)
+			if hasAddRelObs {
+				l := s.lock()
+				s.observers.FireSetRelations(OnAddRelations, entity, &changeMask, mask)
+				s.unlock(l)
+			}
+		}
+	}
+
+	for _, entity := range remove {
+		// Entities may have been removed already through other relations.
+		if s.entityPool.Alive(entity) {
+			s.RemoveEntity(entity)
+		}
+	}
+}
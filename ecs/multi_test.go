@@ -0,0 +1,184 @@
+package ecs
+
+import "testing"
+
+func TestEntityList(t *testing.T) {
+	e1 := Entity{id: 1}
+	e2 := Entity{id: 2}
+	e3 := Entity{id: 3}
+
+	list := newEntityList(e1, e2)
+	list.Append(e3)
+	expectTrue(t, list.Has(e3))
+	expectSlicesEqual(t, []Entity{e1, e2, e3}, list.entities)
+
+	clone := list.Clone()
+
+	list.Remove(e1)
+	expectFalse(t, list.Has(e1))
+	expectSlicesEqual(t, []Entity{e3, e2}, list.entities)
+
+	list.Remove(e1)
+	expectSlicesEqual(t, []Entity{e3, e2}, list.entities)
+
+	list.Remove(e2)
+	expectSlicesEqual(t, []Entity{e3}, list.entities)
+
+	expectSlicesEqual(t, []Entity{e1, e2, e3}, clone.entities)
+	expectTrue(t, clone.Has(e1))
+}
+
+func TestMultiRelationComponents(t *testing.T) {
+	w := NewWorld(16)
+	likesID := ComponentID[Likes](w)
+	friendID := ComponentID[FriendOf](w)
+	posID := ComponentID[Position](w)
+
+	mapper := NewMap2[Likes, FriendOf](w)
+	likesMap := NewMap[Likes](w)
+	friendMap := NewMap[FriendOf](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	c := w.NewEntity()
+
+	e := mapper.NewEntity(&Likes{}, &FriendOf{}, Rel[Likes](a), Rel[FriendOf](b), Rel[Likes](b))
+	expectSlicesEqual(t, []Entity{a, b}, likesMap.GetRelations(e))
+	expectSlicesEqual(t, []Entity{b}, friendMap.GetRelations(e))
+
+	added := 0
+	removed := 0
+	Observe(OnAddRelations).For(C[Likes]()).Do(func(e Entity) { added++ }).Register(w)
+	Observe(OnRemoveRelations).For(C[Likes]()).Do(func(e Entity) { removed++ }).Register(w)
+
+	u := w.Unsafe()
+	u.SetRelations(e, RelID(likesID, b), RelID(likesID, a))
+	expectEqual(t, 0, added)
+
+	u.SetRelations(e, RelID(likesID, c), RelID(friendID, c), RelID(likesID, c))
+	expectEqual(t, 1, added)
+	expectEqual(t, 1, removed)
+	expectSlicesEqual(t, []Entity{c}, likesMap.GetRelations(e))
+	expectSlicesEqual(t, []Entity{c}, friendMap.GetRelations(e))
+
+	expectFalse(t, w.IsTarget(b))
+
+	copied := w.CopyEntity(e)
+	expectSlicesEqual(t, []Entity{c}, likesMap.GetRelations(copied))
+	expectSlicesEqual(t, []Entity{c}, friendMap.GetRelations(copied))
+
+	u.Exchange(copied, nil, []ID{likesID})
+	expectFalse(t, likesMap.Has(copied))
+	expectSlicesEqual(t, []Entity{c}, friendMap.GetRelations(copied))
+	expectEqual(t, 2, countTargeting(w.Targeting(c, C[Likes](), C[FriendOf]())))
+
+	u.Exchange(copied, []ID{likesID}, []ID{friendID}, RelID(likesID, a))
+	expectSlicesEqual(t, []Entity{a}, likesMap.GetRelations(copied))
+	expectFalse(t, friendMap.Has(copied))
+
+	w.RemoveEntity(copied)
+	expectEqual(t, 1, len(w.storage.multi[likesID.id].Sources(c)))
+	expectEqual(t, 0, len(w.storage.multi[likesID.id].Sources(a)))
+
+	expectPanicsWithValue(t, "relation targets must be fully specified, no wildcard allowed", func() {
+		u.SetRelations(e, RelID(likesID, wildcard))
+	})
+	expectPanicsWithValue(t, "entity has no component of type Likes to set relation target for", func() {
+		u.Exchange(a, []ID{posID}, nil, RelID(likesID, b))
+	})
+	expectPanicsWithValue(t, "can't get relations for a dead entity", func() {
+		likesMap.GetRelations(copied)
+	})
+
+	w.Reset()
+	expectEqual(t, 0, len(w.storage.multi[likesID.id].targets))
+	expectEqual(t, 0, len(w.storage.multi[likesID.id].sources))
+}
+
+func TestMultiRelationQueries(t *testing.T) {
+	w := NewWorld(16)
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+	likesID := ComponentID[Likes](w)
+
+	posLikesMap := NewMap2[Position, Likes](w)
+	labelMap := NewMap[Label](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+
+	// No targets were set yet.
+	e1 := posLikesMap.NewEntity(&Position{}, &Likes{})
+	e2 := posLikesMap.NewEntity(&Position{}, &Likes{})
+	expectEqual(t, 2, countLikes(NewFilter1[Likes](w).Query(Rel[Likes](Entity{}))))
+	expectEqual(t, 0, countLikes(NewFilter1[Likes](w).Query(Rel[Likes](a))))
+
+	cascade := NewFilter1[Likes](w).Cascade(C[Likes]())
+	expectEqual(t, 2, countLikes(cascade.Query()))
+
+	posLikesMap.SetRelations(e1, Rel[Likes](a))
+	posLikesMap.SetRelations(e2, Rel[Likes](a), Rel[Likes](e1))
+	labelMap.Add(e2, &Label{})
+
+	registered := NewFilter1[Likes](w).Relations(Rel[Likes](a)).Register()
+	query := registered.Query()
+	expectEqual(t, 2, query.Count())
+	expectEqual(t, e2, query.EntityAt(1))
+	query.Close()
+	registered.Unregister()
+
+	expectEqual(t, 1, countLikes(NewFilter1[Likes](w).With(C[Label]()).Query(Rel[Likes](a))))
+
+	query = NewFilter1[Likes](w).With(C[Label]()).Cascade(C[Likes]()).Query()
+	expectEqual(t, 1, countLikes(query))
+
+	cascade = NewFilter1[Likes](w).Cascade(C[Likes]()).Register()
+	query = cascade.Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e1, e2}, found)
+	cascade.Unregister()
+
+	// Cycles are broken.
+	posLikesMap.SetRelations(e1, Rel[Likes](e2))
+	expectEqual(t, 2, countLikes(NewFilter1[Likes](w).Cascade(C[Likes]()).Query()))
+
+	uQuery := NewUnsafeFilter(w, likesID).Query(RelID(likesID, e1))
+	cnt := 0
+	for uQuery.Next() {
+		expectEqual(t, e2, uQuery.Entity())
+		expectEqual(t, a, uQuery.GetRelation(likesID))
+		cnt++
+	}
+	expectEqual(t, 1, cnt)
+
+	NewMap[ChildOf](w).NewEntity(&ChildOf{}, a)
+	expectEqual(t, 0, countLikes(NewFilter1[Likes](w).Query(Rel[Likes](b))))
+	expectFalse(t, w.IsTarget(b))
+	expectTrue(t, w.IsTarget(e1))
+
+	table := &w.storage.tables[w.storage.entities[e1.id].table]
+	labeled := &entityConditions{sparseWith: []*sparseSet{w.storage.sparse[ComponentID[Label](w).id]}}
+	expectEqual(t, e2, tableEntityAt(table, labeled, 0))
+	expectPanicsWithValue(t, "entity index 0 out of bounds for table with 2 entities", func() {
+		tableEntityAt(table, labeled, 1)
+	})
+
+	var conditions *entityConditions
+	expectTrue(t, conditions.matches(e1))
+
+	child := NewMap[ChildOf](w).NewEntity(&ChildOf{}, e1)
+	w.RemoveEntity(child)
+	targeting := w.Targeting(e1)
+	cnt = 0
+	for targeting.Next() {
+		expectEqual(t, e2, targeting.Entity())
+		cnt++
+	}
+	expectEqual(t, 1, cnt)
+	targeting.Close() // already closed
+
+	w.Unsafe().Remove(e2, ComponentID[Position](w))
+	expectSlicesEqual(t, []Entity{a, e1}, NewMap[Likes](w).GetRelations(e2))
+}
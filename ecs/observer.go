@@ -9,11 +9,10 @@ package ecs
 // See also [Observer1], [Observer2], etc.
 type Observer struct {
 	observerData
-	comps     []Comp
-	with      []Comp
-	without   []Comp
-	event     EventType
-	exclusive bool
+	comps   []Comp
+	with    []Comp
+	without []Comp
+	event   EventType
 }
 
 // observerData contains the observer data that is required by the observerManager.
@@ -22,10 +21,13 @@ type observerData struct {
 	withMask    bitMask
 	withoutMask bitMask
 	callback    func(Entity)
+	batch       func(table *table, start, end int) // Optional callback for table ranges, used by batch events
 	id          observerID
+	deferred    bool
 	hasComps    bool
 	hasWithout  bool
 	hasWith     bool
+	exclusive   bool
 }
 
 // Observe creates a new ECS event observer for the specified event type.
@@ -120,7 +122,27 @@ func (o *Observer) Do(fn func(Entity)) *Observer {
 	return o
 }
 
+// Deferred makes the observer deferred.
+//
+// Deferred observers are not called immediately during the operation that triggered the event.
+// Instead, events are queued and delivered after the outermost operation has completed,
+// or explicitly via [World.FlushEvents].
+// Thus, in contrast to normal observers, deferred observers can safely modify the world.
+//
+// Note that entities may have changed or may have been removed until the event is delivered.
+// E.g., for [OnRemoveEntity], the entity is always dead already.
+// Use [World.Alive] to check whether an entity is still alive.
+func (o *Observer) Deferred() *Observer {
+	if o.id != maxObserverID {
+		panic("can't modify a registered observer")
+	}
+	o.deferred = true
+	return o
+}
+
 // Register this observer. This is mandatory for the observer to take effect.
+//
+// Panics if any of the observed components uses sparse storage (see [StorageSparse]).
 func (o *Observer) Register(w *World) *Observer {
 	w.registerObserver(o)
 	return o
@@ -104,11 +104,11 @@ func TestObserverRegister(t *testing.T) {
 	_ = Observe(OnCreateEntity).With(C[Position]()).Do(func(e Entity) {}).Register(w)
 	obs3 := Observe(OnCreateEntity).With(C[Velocity]()).Do(func(e Entity) {}).Register(w)
 	obs3.Unregister(w)
-	expectTrue(t, w.storage.observers.anyNoWith[OnCreateEntity])
+	expectTrue(t, w.storage.observers.anyNoWith[OnCreateEntity.index()])
 	obs1.Unregister(w)
-	expectFalse(t, w.storage.observers.anyNoWith[OnCreateEntity])
+	expectFalse(t, w.storage.observers.anyNoWith[OnCreateEntity.index()])
 
-	anyNoComps := &w.storage.observers.anyNoComps[OnAddComponents]
+	anyNoComps := &w.storage.observers.anyNoComps[OnAddComponents.index()]
 	obs1 = Observe(OnAddComponents).For(C[Velocity]()).Do(func(e Entity) {}).Register(w)
 	obs2 = Observe(OnAddComponents).For(C[Position]()).Do(func(e Entity) {}).Register(w)
 	expectFalse(t, *anyNoComps)
@@ -8,6 +8,7 @@ package ecs
 type Observer1[A any] struct {
 	observer Observer
 	callback func(Entity, *A)
+	batch    func([]Entity, []A)
 }
 
 // Observe1 creates a new Observer1.
@@ -69,22 +70,73 @@ func (o *Observer1[A]) Exclusive() *Observer1[A] {
 	return o
 }
 
+// Deferred makes the observer deferred. See [Observer.Deferred] for details.
+//
+// Components are obtained when the event is delivered, rather than when it is triggered.
+// Pointers and slices passed to the callback are nil for components that the entity does not have anymore,
+// or if the entity is not alive anymore.
+// With [Observer1.DoBatch], the callback is called once per event, with slices of length one or nil.
+func (o *Observer1[A]) Deferred() *Observer1[A] {
+	o.observer.Deferred()
+	return o
+}
+
 // Do sets the observer's callback. Must be called exactly once before registration.
+// Can't be combined with [Observer1.DoBatch].
 func (o *Observer1[A]) Do(fn func(Entity, *A)) *Observer1[A] {
-	if o.callback != nil {
+	if o.callback != nil || o.batch != nil {
 		panic("observer already has a callback")
 	}
 	o.callback = fn
 	return o
 }
 
+// DoBatch sets the observer's callback for ranges of entities in the same table,
+// as an alternative to [Observer1.Do].
+// Must be called exactly once before registration.
+//
+// The callback receives the entities and the corresponding component columns as slices.
+// For events of batch operations, it is called once per affected table range.
+// For events of individual operations, it is called with slices of length one.
+//
+// ⚠️ Do not store the obtained slices outside of the current context,
+// and do not set/replace any of the elements of the entities slice!
+func (o *Observer1[A]) DoBatch(fn func([]Entity, []A)) *Observer1[A] {
+	if o.callback != nil || o.batch != nil {
+		panic("observer already has a callback")
+	}
+	o.batch = fn
+	return o
+}
+
 // Register this observer. This is mandatory for the observer to take effect.
+//
+// Panics if any of the observed components uses sparse storage (see [StorageSparse]).
 func (o *Observer1[A]) Register(w *World) *Observer1[A] {
-	if o.callback == nil {
+	if o.callback == nil && o.batch == nil {
 		panic("observer callback must be set via Do before registering")
 	}
 
 	storageA := &w.storage.components[ComponentID[A](w).id]
+	if o.observer.deferred {
+		o.registerDeferred(w, storageA)
+		return o
+	}
+	if o.batch != nil {
+		o.observer.batch = func(table *table, start, end int) {
+			o.batch(
+				table.entities.data.Interface().([]Entity)[start:end:end],
+				columnSlice[A](storageA.columns[table.id], uint32(start), uint32(end)),
+			)
+		}
+		o.observer.callback = func(e Entity) {
+			index := &w.storage.entities[e.id]
+			row := int(index.row)
+			o.observer.batch(&w.storage.tables[index.table], row, row+1)
+		}
+		w.registerObserver(&o.observer)
+		return o
+	}
 	o.observer.callback = func(e Entity) {
 		index := &w.storage.entities[e.id]
 		row := uintptr(index.row)
@@ -98,6 +150,27 @@ func (o *Observer1[A]) Register(w *World) *Observer1[A] {
 	return o
 }
 
+// registerDeferred registers a deferred observer.
+// Components are obtained when events are delivered, as entities may have changed in the meantime.
+func (o *Observer1[A]) registerDeferred(w *World, storageA *componentStorage) {
+	if o.batch != nil {
+		o.observer.callback = func(e Entity) {
+			o.batch(
+				[]Entity{e},
+				deferredSlice[A](w, storageA, e),
+			)
+		}
+	} else {
+		o.observer.callback = func(e Entity) {
+			o.callback(
+				e,
+				deferredComponent[A](w, storageA, e),
+			)
+		}
+	}
+	w.registerObserver(&o.observer)
+}
+
 // Unregister this observer.
 func (o *Observer1[A]) Unregister(w *World) *Observer1[A] {
 	w.unregisterObserver(&o.observer)
@@ -112,6 +185,7 @@ func (o *Observer1[A]) Unregister(w *World) *Observer1[A] {
 type Observer2[A any, B any] struct {
 	observer Observer
 	callback func(Entity, *A, *B)
+	batch    func([]Entity, []A, []B)
 }
 
 // Observe2 creates a new Observer2.
@@ -184,23 +258,75 @@ func (o *Observer2[A, B]) Exclusive() *Observer2[A, B] {
 	return o
 }
 
+// Deferred makes the observer deferred. See [Observer.Deferred] for details.
+//
+// Components are obtained when the event is delivered, rather than when it is triggered.
+// Pointers and slices passed to the callback are nil for components that the entity does not have anymore,
+// or if the entity is not alive anymore.
+// With [Observer2.DoBatch], the callback is called once per event, with slices of length one or nil.
+func (o *Observer2[A, B]) Deferred() *Observer2[A, B] {
+	o.observer.Deferred()
+	return o
+}
+
 // Do sets the observer's callback. Must be called exactly once before registration.
+// Can't be combined with [Observer2.DoBatch].
 func (o *Observer2[A, B]) Do(fn func(Entity, *A, *B)) *Observer2[A, B] {
-	if o.callback != nil {
+	if o.callback != nil || o.batch != nil {
 		panic("observer already has a callback")
 	}
 	o.callback = fn
 	return o
 }
 
+// DoBatch sets the observer's callback for ranges of entities in the same table,
+// as an alternative to [Observer2.Do].
+// Must be called exactly once before registration.
+//
+// The callback receives the entities and the corresponding component columns as slices.
+// For events of batch operations, it is called once per affected table range.
+// For events of individual operations, it is called with slices of length one.
+//
+// ⚠️ Do not store the obtained slices outside of the current context,
+// and do not set/replace any of the elements of the entities slice!
+func (o *Observer2[A, B]) DoBatch(fn func([]Entity, []A, []B)) *Observer2[A, B] {
+	if o.callback != nil || o.batch != nil {
+		panic("observer already has a callback")
+	}
+	o.batch = fn
+	return o
+}
+
 // Register this observer. This is mandatory for the observer to take effect.
+//
+// Panics if any of the observed components uses sparse storage (see [StorageSparse]).
 func (o *Observer2[A, B]) Register(w *World) *Observer2[A, B] {
-	if o.callback == nil {
+	if o.callback == nil && o.batch == nil {
 		panic("observer callback must be set via Do before registering")
 	}
 
 	storageA := &w.storage.components[ComponentID[A](w).id]
 	storageB := &w.storage.components[ComponentID[B](w).id]
+	if o.observer.deferred {
+		o.registerDeferred(w, storageA, storageB)
+		return o
+	}
+	if o.batch != nil {
+		o.observer.batch = func(table *table, start, end int) {
+			o.batch(
+				table.entities.data.Interface().([]Entity)[start:end:end],
+				columnSlice[A](storageA.columns[table.id], uint32(start), uint32(end)),
+				columnSlice[B](storageB.columns[table.id], uint32(start), uint32(end)),
+			)
+		}
+		o.observer.callback = func(e Entity) {
+			index := &w.storage.entities[e.id]
+			row := int(index.row)
+			o.observer.batch(&w.storage.tables[index.table], row, row+1)
+		}
+		w.registerObserver(&o.observer)
+		return o
+	}
 	o.observer.callback = func(e Entity) {
 		index := &w.storage.entities[e.id]
 		row := uintptr(index.row)
@@ -215,6 +341,29 @@ func (o *Observer2[A, B]) Register(w *World) *Observer2[A, B] {
 	return o
 }
 
+// registerDeferred registers a deferred observer.
+// Components are obtained when events are delivered, as entities may have changed in the meantime.
+func (o *Observer2[A, B]) registerDeferred(w *World, storageA *componentStorage, storageB *componentStorage) {
+	if o.batch != nil {
+		o.observer.callback = func(e Entity) {
+			o.batch(
+				[]Entity{e},
+				deferredSlice[A](w, storageA, e),
+				deferredSlice[B](w, storageB, e),
+			)
+		}
+	} else {
+		o.observer.callback = func(e Entity) {
+			o.callback(
+				e,
+				deferredComponent[A](w, storageA, e),
+				deferredComponent[B](w, storageB, e),
+			)
+		}
+	}
+	w.registerObserver(&o.observer)
+}
+
 // Unregister this observer.
 func (o *Observer2[A, B]) Unregister(w *World) *Observer2[A, B] {
 	w.unregisterObserver(&o.observer)
@@ -229,6 +378,7 @@ func (o *Observer2[A, B]) Unregister(w *World) *Observer2[A, B] {
 type Observer3[A any, B any, C any] struct {
 	observer Observer
 	callback func(Entity, *A, *B, *C)
+	batch    func([]Entity, []A, []B, []C)
 }
 
 // Observe3 creates a new Observer3.
@@ -302,24 +452,77 @@ func (o *Observer3[A, B, C]) Exclusive() *Observer3[A, B, C] {
 	return o
 }
 
+// Deferred makes the observer deferred. See [Observer.Deferred] for details.
+//
+// Components are obtained when the event is delivered, rather than when it is triggered.
+// Pointers and slices passed to the callback are nil for components that the entity does not have anymore,
+// or if the entity is not alive anymore.
+// With [Observer3.DoBatch], the callback is called once per event, with slices of length one or nil.
+func (o *Observer3[A, B, C]) Deferred() *Observer3[A, B, C] {
+	o.observer.Deferred()
+	return o
+}
+
 // Do sets the observer's callback. Must be called exactly once before registration.
+// Can't be combined with [Observer3.DoBatch].
 func (o *Observer3[A, B, C]) Do(fn func(Entity, *A, *B, *C)) *Observer3[A, B, C] {
-	if o.callback != nil {
+	if o.callback != nil || o.batch != nil {
 		panic("observer already has a callback")
 	}
 	o.callback = fn
 	return o
 }
 
+// DoBatch sets the observer's callback for ranges of entities in the same table,
+// as an alternative to [Observer3.Do].
+// Must be called exactly once before registration.
+//
+// The callback receives the entities and the corresponding component columns as slices.
+// For events of batch operations, it is called once per affected table range.
+// For events of individual operations, it is called with slices of length one.
+//
+// ⚠️ Do not store the obtained slices outside of the current context,
+// and do not set/replace any of the elements of the entities slice!
+func (o *Observer3[A, B, C]) DoBatch(fn func([]Entity, []A, []B, []C)) *Observer3[A, B, C] {
+	if o.callback != nil || o.batch != nil {
+		panic("observer already has a callback")
+	}
+	o.batch = fn
+	return o
+}
+
 // Register this observer. This is mandatory for the observer to take effect.
+//
+// Panics if any of the observed components uses sparse storage (see [StorageSparse]).
 func (o *Observer3[A, B, C]) Register(w *World) *Observer3[A, B, C] {
-	if o.callback == nil {
+	if o.callback == nil && o.batch == nil {
 		panic("observer callback must be set via Do before registering")
 	}
 
 	storageA := &w.storage.components[ComponentID[A](w).id]
 	storageB := &w.storage.components[ComponentID[B](w).id]
 	storageC := &w.storage.components[ComponentID[C](w).id]
+	if o.observer.deferred {
+		o.registerDeferred(w, storageA, storageB, storageC)
+		return o
+	}
+	if o.batch != nil {
+		o.observer.batch = func(table *table, start, end int) {
+			o.batch(
+				table.entities.data.Interface().([]Entity)[start:end:end],
+				columnSlice[A](storageA.columns[table.id], uint32(start), uint32(end)),
+				columnSlice[B](storageB.columns[table.id], uint32(start), uint32(end)),
+				columnSlice[C](storageC.columns[table.id], uint32(start), uint32(end)),
+			)
+		}
+		o.observer.callback = func(e Entity) {
+			index := &w.storage.entities[e.id]
+			row := int(index.row)
+			o.observer.batch(&w.storage.tables[index.table], row, row+1)
+		}
+		w.registerObserver(&o.observer)
+		return o
+	}
 	o.observer.callback = func(e Entity) {
 		index := &w.storage.entities[e.id]
 		row := uintptr(index.row)
@@ -335,6 +538,31 @@ func (o *Observer3[A, B, C]) Register(w *World) *Observer3[A, B, C] {
 	return o
 }
 
+// registerDeferred registers a deferred observer.
+// Components are obtained when events are delivered, as entities may have changed in the meantime.
+func (o *Observer3[A, B, C]) registerDeferred(w *World, storageA *componentStorage, storageB *componentStorage, storageC *componentStorage) {
+	if o.batch != nil {
+		o.observer.callback = func(e Entity) {
+			o.batch(
+				[]Entity{e},
+				deferredSlice[A](w, storageA, e),
+				deferredSlice[B](w, storageB, e),
+				deferredSlice[C](w, storageC, e),
+			)
+		}
+	} else {
+		o.observer.callback = func(e Entity) {
+			o.callback(
+				e,
+				deferredComponent[A](w, storageA, e),
+				deferredComponent[B](w, storageB, e),
+				deferredComponent[C](w, storageC, e),
+			)
+		}
+	}
+	w.registerObserver(&o.observer)
+}
+
 // Unregister this observer.
 func (o *Observer3[A, B, C]) Unregister(w *World) *Observer3[A, B, C] {
 	w.unregisterObserver(&o.observer)
@@ -349,6 +577,7 @@ func (o *Observer3[A, B, C]) Unregister(w *World) *Observer3[A, B, C] {
 type Observer4[A any, B any, C any, D any] struct {
 	observer Observer
 	callback func(Entity, *A, *B, *C, *D)
+	batch    func([]Entity, []A, []B, []C, []D)
 }
 
 // Observe4 creates a new Observer4.
@@ -423,18 +652,50 @@ func (o *Observer4[A, B, C, D]) Exclusive() *Observer4[A, B, C, D] {
 	return o
 }
 
+// Deferred makes the observer deferred. See [Observer.Deferred] for details.
+//
+// Components are obtained when the event is delivered, rather than when it is triggered.
+// Pointers and slices passed to the callback are nil for components that the entity does not have anymore,
+// or if the entity is not alive anymore.
+// With [Observer4.DoBatch], the callback is called once per event, with slices of length one or nil.
+func (o *Observer4[A, B, C, D]) Deferred() *Observer4[A, B, C, D] {
+	o.observer.Deferred()
+	return o
+}
+
 // Do sets the observer's callback. Must be called exactly once before registration.
+// Can't be combined with [Observer4.DoBatch].
 func (o *Observer4[A, B, C, D]) Do(fn func(Entity, *A, *B, *C, *D)) *Observer4[A, B, C, D] {
-	if o.callback != nil {
+	if o.callback != nil || o.batch != nil {
 		panic("observer already has a callback")
 	}
 	o.callback = fn
 	return o
 }
 
+// DoBatch sets the observer's callback for ranges of entities in the same table,
+// as an alternative to [Observer4.Do].
+// Must be called exactly once before registration.
+//
+// The callback receives the entities and the corresponding component columns as slices.
+// For events of batch operations, it is called once per affected table range.
+// For events of individual operations, it is called with slices of length one.
+//
+// ⚠️ Do not store the obtained slices outside of the current context,
+// and do not set/replace any of the elements of the entities slice!
+func (o *Observer4[A, B, C, D]) DoBatch(fn func([]Entity, []A, []B, []C, []D)) *Observer4[A, B, C, D] {
+	if o.callback != nil || o.batch != nil {
+		panic("observer already has a callback")
+	}
+	o.batch = fn
+	return o
+}
+
 // Register this observer. This is mandatory for the observer to take effect.
+//
+// Panics if any of the observed components uses sparse storage (see [StorageSparse]).
 func (o *Observer4[A, B, C, D]) Register(w *World) *Observer4[A, B, C, D] {
-	if o.callback == nil {
+	if o.callback == nil && o.batch == nil {
 		panic("observer callback must be set via Do before registering")
 	}
 
@@ -442,6 +703,28 @@ func (o *Observer4[A, B, C, D]) Register(w *World) *Observer4[A, B, C, D] {
 	storageB := &w.storage.components[ComponentID[B](w).id]
 	storageC := &w.storage.components[ComponentID[C](w).id]
 	storageD := &w.storage.components[ComponentID[D](w).id]
+	if o.observer.deferred {
+		o.registerDeferred(w, storageA, storageB, storageC, storageD)
+		return o
+	}
+	if o.batch != nil {
+		o.observer.batch = func(table *table, start, end int) {
+			o.batch(
+				table.entities.data.Interface().([]Entity)[start:end:end],
+				columnSlice[A](storageA.columns[table.id], uint32(start), uint32(end)),
+				columnSlice[B](storageB.columns[table.id], uint32(start), uint32(end)),
+				columnSlice[C](storageC.columns[table.id], uint32(start), uint32(end)),
+				columnSlice[D](storageD.columns[table.id], uint32(start), uint32(end)),
+			)
+		}
+		o.observer.callback = func(e Entity) {
+			index := &w.storage.entities[e.id]
+			row := int(index.row)
+			o.observer.batch(&w.storage.tables[index.table], row, row+1)
+		}
+		w.registerObserver(&o.observer)
+		return o
+	}
 	o.observer.callback = func(e Entity) {
 		index := &w.storage.entities[e.id]
 		row := uintptr(index.row)
@@ -458,6 +741,33 @@ func (o *Observer4[A, B, C, D]) Register(w *World) *Observer4[A, B, C, D] {
 	return o
 }
 
+// registerDeferred registers a deferred observer.
+// Components are obtained when events are delivered, as entities may have changed in the meantime.
+func (o *Observer4[A, B, C, D]) registerDeferred(w *World, storageA *componentStorage, storageB *componentStorage, storageC *componentStorage, storageD *componentStorage) {
+	if o.batch != nil {
+		o.observer.callback = func(e Entity) {
+			o.batch(
+				[]Entity{e},
+				deferredSlice[A](w, storageA, e),
+				deferredSlice[B](w, storageB, e),
+				deferredSlice[C](w, storageC, e),
+				deferredSlice[D](w, storageD, e),
+			)
+		}
+	} else {
+		o.observer.callback = func(e Entity) {
+			o.callback(
+				e,
+				deferredComponent[A](w, storageA, e),
+				deferredComponent[B](w, storageB, e),
+				deferredComponent[C](w, storageC, e),
+				deferredComponent[D](w, storageD, e),
+			)
+		}
+	}
+	w.registerObserver(&o.observer)
+}
+
 // Unregister this observer.
 func (o *Observer4[A, B, C, D]) Unregister(w *World) *Observer4[A, B, C, D] {
 	w.unregisterObserver(&o.observer)
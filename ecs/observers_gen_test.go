@@ -112,6 +112,113 @@ func TestObserver1Callback(t *testing.T) {
 	)
 }
 
+func TestObserver1DoBatch(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap1[CompA](w)
+
+	calls := 0
+	count := 0
+	Observe1[CompA](OnCreateEntity).
+		DoBatch(func(entities []Entity, a []CompA) {
+			calls++
+			count += len(entities)
+			expectEqual(t, len(entities), len(a))
+			expectEqual(t, 1.0, a[0].X)
+		}).
+		Register(w)
+
+	builder.NewBatch(100,
+		&CompA{X: 1},
+	)
+	expectEqual(t, 1, calls)
+	expectEqual(t, 100, count)
+
+	builder.NewEntity(
+		&CompA{X: 1},
+	)
+	expectEqual(t, 2, calls)
+	expectEqual(t, 101, count)
+
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe1[CompA](OnCreateEntity).
+				Do(func(e Entity, a *CompA) {}).
+				DoBatch(func(e []Entity, a []CompA) {})
+		})
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe1[CompA](OnCreateEntity).
+				DoBatch(func(e []Entity, a []CompA) {}).
+				Do(func(e Entity, a *CompA) {})
+		})
+}
+
+func TestObserver1Deferred(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap1[CompA](w)
+	posMap := NewMap[Position](w)
+
+	created := []Entity{}
+	Observe1[CompA](OnCreateEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA) {
+			expectFalse(t, w.IsLocked())
+			expectEqual(t, CompA{X: 1}, *a)
+			created = append(created, e)
+			// Deferred observers can modify the world.
+			posMap.Add(e, &Position{X: a.X})
+		}).
+		Register(w)
+
+	batchCalls := 0
+	Observe1[CompA](OnCreateEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA) {
+			batchCalls++
+			expectEqual(t, 1, len(entities))
+			expectEqual(t, 1, len(a))
+			expectTrue(t, posMap.Has(entities[0]))
+		}).
+		Register(w)
+
+	removed := 0
+	Observe1[CompA](OnRemoveEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA) {
+			expectFalse(t, w.Alive(e))
+			expectTrue(t, a == nil)
+			removed++
+		}).
+		Register(w)
+
+	removedBatch := 0
+	Observe1[CompA](OnRemoveEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA) {
+			expectEqual(t, 1, len(entities))
+			expectTrue(t, a == nil)
+			removedBatch++
+		}).
+		Register(w)
+
+	e := builder.NewEntity(
+		&CompA{X: 1},
+	)
+	expectSlicesEqual(t, []Entity{e}, created)
+	expectEqual(t, 1, batchCalls)
+	expectEqual(t, Position{X: 1}, *posMap.Get(e))
+
+	builder.NewBatch(10,
+		&CompA{X: 1},
+	)
+	expectEqual(t, 11, len(created))
+	expectEqual(t, 11, batchCalls)
+
+	w.RemoveEntity(e)
+	expectEqual(t, 1, removed)
+	expectEqual(t, 1, removedBatch)
+}
+
 func TestObserve2(t *testing.T) {
 	w := NewWorld()
 	var obs *Observer2[CompA, CompB]
@@ -221,6 +328,122 @@ func TestObserver2Callback(t *testing.T) {
 	)
 }
 
+func TestObserver2DoBatch(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap2[CompA, CompB](w)
+
+	calls := 0
+	count := 0
+	Observe2[CompA, CompB](OnCreateEntity).
+		DoBatch(func(entities []Entity, a []CompA, b []CompB) {
+			calls++
+			count += len(entities)
+			expectEqual(t, len(entities), len(a))
+			expectEqual(t, len(entities), len(b))
+			expectEqual(t, 1.0, a[0].X)
+		}).
+		Register(w)
+
+	builder.NewBatch(100,
+		&CompA{X: 1},
+		&CompB{X: 1},
+	)
+	expectEqual(t, 1, calls)
+	expectEqual(t, 100, count)
+
+	builder.NewEntity(
+		&CompA{X: 1},
+		&CompB{X: 1},
+	)
+	expectEqual(t, 2, calls)
+	expectEqual(t, 101, count)
+
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe2[CompA, CompB](OnCreateEntity).
+				Do(func(e Entity, a *CompA, b *CompB) {}).
+				DoBatch(func(e []Entity, a []CompA, b []CompB) {})
+		})
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe2[CompA, CompB](OnCreateEntity).
+				DoBatch(func(e []Entity, a []CompA, b []CompB) {}).
+				Do(func(e Entity, a *CompA, b *CompB) {})
+		})
+}
+
+func TestObserver2Deferred(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap2[CompA, CompB](w)
+	posMap := NewMap[Position](w)
+
+	created := []Entity{}
+	Observe2[CompA, CompB](OnCreateEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA, b *CompB) {
+			expectFalse(t, w.IsLocked())
+			expectEqual(t, CompA{X: 1}, *a)
+			expectEqual(t, CompB{X: 1}, *b)
+			created = append(created, e)
+			// Deferred observers can modify the world.
+			posMap.Add(e, &Position{X: a.X})
+		}).
+		Register(w)
+
+	batchCalls := 0
+	Observe2[CompA, CompB](OnCreateEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA, b []CompB) {
+			batchCalls++
+			expectEqual(t, 1, len(entities))
+			expectEqual(t, 1, len(a))
+			expectEqual(t, 1, len(b))
+			expectTrue(t, posMap.Has(entities[0]))
+		}).
+		Register(w)
+
+	removed := 0
+	Observe2[CompA, CompB](OnRemoveEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA, b *CompB) {
+			expectFalse(t, w.Alive(e))
+			expectTrue(t, a == nil)
+			expectTrue(t, b == nil)
+			removed++
+		}).
+		Register(w)
+
+	removedBatch := 0
+	Observe2[CompA, CompB](OnRemoveEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA, b []CompB) {
+			expectEqual(t, 1, len(entities))
+			expectTrue(t, a == nil)
+			expectTrue(t, b == nil)
+			removedBatch++
+		}).
+		Register(w)
+
+	e := builder.NewEntity(
+		&CompA{X: 1},
+		&CompB{X: 1},
+	)
+	expectSlicesEqual(t, []Entity{e}, created)
+	expectEqual(t, 1, batchCalls)
+	expectEqual(t, Position{X: 1}, *posMap.Get(e))
+
+	builder.NewBatch(10,
+		&CompA{X: 1},
+		&CompB{X: 1},
+	)
+	expectEqual(t, 11, len(created))
+	expectEqual(t, 11, batchCalls)
+
+	w.RemoveEntity(e)
+	expectEqual(t, 1, removed)
+	expectEqual(t, 1, removedBatch)
+}
+
 func TestObserve3(t *testing.T) {
 	w := NewWorld()
 	var obs *Observer3[CompA, CompB, CompC]
@@ -331,6 +554,131 @@ func TestObserver3Callback(t *testing.T) {
 	)
 }
 
+func TestObserver3DoBatch(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap3[CompA, CompB, CompC](w)
+
+	calls := 0
+	count := 0
+	Observe3[CompA, CompB, CompC](OnCreateEntity).
+		DoBatch(func(entities []Entity, a []CompA, b []CompB, c []CompC) {
+			calls++
+			count += len(entities)
+			expectEqual(t, len(entities), len(a))
+			expectEqual(t, len(entities), len(b))
+			expectEqual(t, len(entities), len(c))
+			expectEqual(t, 1.0, a[0].X)
+		}).
+		Register(w)
+
+	builder.NewBatch(100,
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+	)
+	expectEqual(t, 1, calls)
+	expectEqual(t, 100, count)
+
+	builder.NewEntity(
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+	)
+	expectEqual(t, 2, calls)
+	expectEqual(t, 101, count)
+
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe3[CompA, CompB, CompC](OnCreateEntity).
+				Do(func(e Entity, a *CompA, b *CompB, c *CompC) {}).
+				DoBatch(func(e []Entity, a []CompA, b []CompB, c []CompC) {})
+		})
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe3[CompA, CompB, CompC](OnCreateEntity).
+				DoBatch(func(e []Entity, a []CompA, b []CompB, c []CompC) {}).
+				Do(func(e Entity, a *CompA, b *CompB, c *CompC) {})
+		})
+}
+
+func TestObserver3Deferred(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap3[CompA, CompB, CompC](w)
+	posMap := NewMap[Position](w)
+
+	created := []Entity{}
+	Observe3[CompA, CompB, CompC](OnCreateEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA, b *CompB, c *CompC) {
+			expectFalse(t, w.IsLocked())
+			expectEqual(t, CompA{X: 1}, *a)
+			expectEqual(t, CompB{X: 1}, *b)
+			expectEqual(t, CompC{X: 1}, *c)
+			created = append(created, e)
+			// Deferred observers can modify the world.
+			posMap.Add(e, &Position{X: a.X})
+		}).
+		Register(w)
+
+	batchCalls := 0
+	Observe3[CompA, CompB, CompC](OnCreateEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA, b []CompB, c []CompC) {
+			batchCalls++
+			expectEqual(t, 1, len(entities))
+			expectEqual(t, 1, len(a))
+			expectEqual(t, 1, len(b))
+			expectEqual(t, 1, len(c))
+			expectTrue(t, posMap.Has(entities[0]))
+		}).
+		Register(w)
+
+	removed := 0
+	Observe3[CompA, CompB, CompC](OnRemoveEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA, b *CompB, c *CompC) {
+			expectFalse(t, w.Alive(e))
+			expectTrue(t, a == nil)
+			expectTrue(t, b == nil)
+			expectTrue(t, c == nil)
+			removed++
+		}).
+		Register(w)
+
+	removedBatch := 0
+	Observe3[CompA, CompB, CompC](OnRemoveEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA, b []CompB, c []CompC) {
+			expectEqual(t, 1, len(entities))
+			expectTrue(t, a == nil)
+			expectTrue(t, b == nil)
+			expectTrue(t, c == nil)
+			removedBatch++
+		}).
+		Register(w)
+
+	e := builder.NewEntity(
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+	)
+	expectSlicesEqual(t, []Entity{e}, created)
+	expectEqual(t, 1, batchCalls)
+	expectEqual(t, Position{X: 1}, *posMap.Get(e))
+
+	builder.NewBatch(10,
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+	)
+	expectEqual(t, 11, len(created))
+	expectEqual(t, 11, batchCalls)
+
+	w.RemoveEntity(e)
+	expectEqual(t, 1, removed)
+	expectEqual(t, 1, removedBatch)
+}
+
 func TestObserve4(t *testing.T) {
 	w := NewWorld()
 	var obs *Observer4[CompA, CompB, CompC, CompD]
@@ -441,3 +789,137 @@ func TestObserver4Callback(t *testing.T) {
 		&CompD{},
 	)
 }
+
+func TestObserver4DoBatch(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap4[CompA, CompB, CompC, CompD](w)
+
+	calls := 0
+	count := 0
+	Observe4[CompA, CompB, CompC, CompD](OnCreateEntity).
+		DoBatch(func(entities []Entity, a []CompA, b []CompB, c []CompC, d []CompD) {
+			calls++
+			count += len(entities)
+			expectEqual(t, len(entities), len(a))
+			expectEqual(t, len(entities), len(b))
+			expectEqual(t, len(entities), len(c))
+			expectEqual(t, len(entities), len(d))
+			expectEqual(t, 1.0, a[0].X)
+		}).
+		Register(w)
+
+	builder.NewBatch(100,
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+		&CompD{X: 1},
+	)
+	expectEqual(t, 1, calls)
+	expectEqual(t, 100, count)
+
+	builder.NewEntity(
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+		&CompD{X: 1},
+	)
+	expectEqual(t, 2, calls)
+	expectEqual(t, 101, count)
+
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe4[CompA, CompB, CompC, CompD](OnCreateEntity).
+				Do(func(e Entity, a *CompA, b *CompB, c *CompC, d *CompD) {}).
+				DoBatch(func(e []Entity, a []CompA, b []CompB, c []CompC, d []CompD) {})
+		})
+	expectPanicsWithValue(t, "observer already has a callback",
+		func() {
+			Observe4[CompA, CompB, CompC, CompD](OnCreateEntity).
+				DoBatch(func(e []Entity, a []CompA, b []CompB, c []CompC, d []CompD) {}).
+				Do(func(e Entity, a *CompA, b *CompB, c *CompC, d *CompD) {})
+		})
+}
+
+func TestObserver4Deferred(t *testing.T) {
+	w := NewWorld()
+	builder := NewMap4[CompA, CompB, CompC, CompD](w)
+	posMap := NewMap[Position](w)
+
+	created := []Entity{}
+	Observe4[CompA, CompB, CompC, CompD](OnCreateEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA, b *CompB, c *CompC, d *CompD) {
+			expectFalse(t, w.IsLocked())
+			expectEqual(t, CompA{X: 1}, *a)
+			expectEqual(t, CompB{X: 1}, *b)
+			expectEqual(t, CompC{X: 1}, *c)
+			expectEqual(t, CompD{X: 1}, *d)
+			created = append(created, e)
+			// Deferred observers can modify the world.
+			posMap.Add(e, &Position{X: a.X})
+		}).
+		Register(w)
+
+	batchCalls := 0
+	Observe4[CompA, CompB, CompC, CompD](OnCreateEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA, b []CompB, c []CompC, d []CompD) {
+			batchCalls++
+			expectEqual(t, 1, len(entities))
+			expectEqual(t, 1, len(a))
+			expectEqual(t, 1, len(b))
+			expectEqual(t, 1, len(c))
+			expectEqual(t, 1, len(d))
+			expectTrue(t, posMap.Has(entities[0]))
+		}).
+		Register(w)
+
+	removed := 0
+	Observe4[CompA, CompB, CompC, CompD](OnRemoveEntity).
+		Deferred().
+		Do(func(e Entity, a *CompA, b *CompB, c *CompC, d *CompD) {
+			expectFalse(t, w.Alive(e))
+			expectTrue(t, a == nil)
+			expectTrue(t, b == nil)
+			expectTrue(t, c == nil)
+			expectTrue(t, d == nil)
+			removed++
+		}).
+		Register(w)
+
+	removedBatch := 0
+	Observe4[CompA, CompB, CompC, CompD](OnRemoveEntity).
+		Deferred().
+		DoBatch(func(entities []Entity, a []CompA, b []CompB, c []CompC, d []CompD) {
+			expectEqual(t, 1, len(entities))
+			expectTrue(t, a == nil)
+			expectTrue(t, b == nil)
+			expectTrue(t, c == nil)
+			expectTrue(t, d == nil)
+			removedBatch++
+		}).
+		Register(w)
+
+	e := builder.NewEntity(
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+		&CompD{X: 1},
+	)
+	expectSlicesEqual(t, []Entity{e}, created)
+	expectEqual(t, 1, batchCalls)
+	expectEqual(t, Position{X: 1}, *posMap.Get(e))
+
+	builder.NewBatch(10,
+		&CompA{X: 1},
+		&CompB{X: 1},
+		&CompC{X: 1},
+		&CompD{X: 1},
+	)
+	expectEqual(t, 11, len(created))
+	expectEqual(t, 11, batchCalls)
+
+	w.RemoveEntity(e)
+	expectEqual(t, 1, removed)
+	expectEqual(t, 1, removedBatch)
+}
@@ -0,0 +1,112 @@
+package ecs
+
+import (
+	"runtime"
+	"sync"
+)
+
+// chunksPerWorker is the number of table chunks created per worker in parallel iteration.
+// Creating more chunks than workers helps to balance the load.
+const chunksPerWorker = 4
+
+// tableChunk is a range of rows in a table, processed by a single worker in parallel iteration.
+type tableChunk struct {
+	table *table
+	start uint32
+	end   uint32
+}
+
+// Chunk is a range of rows in a table, passed to the callback of parallel table iteration.
+// See [Query2.ParallelTables].
+type Chunk struct {
+	columns []*column
+	start   uint32
+	end     uint32
+	tick    uint32
+}
+
+// MarkChanged marks the queried components of the chunk's entities as changed,
+// for change detection (see [Filter2.Changed]).
+// Use this when modifying the component slices passed along with the chunk.
+func (c *Chunk) MarkChanged() {
+	for _, column := range c.columns {
+		if column != nil {
+			column.markRange(c.start, c.end, c.tick)
+		}
+	}
+}
+
+// runParallel splits the given tables into chunks and processes them with the given number of workers.
+// Uses [runtime.GOMAXPROCS] workers if workers is not positive.
+//
+// Blocks until all chunks are processed.
+// Panics in fn are recovered and re-raised in the calling goroutine after all workers have finished.
+func runParallel(tables []*table, workers int, fn func(table *table, start, end uint32)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunks := splitTables(tables, workers)
+	if len(chunks) == 0 {
+		return
+	}
+	workers = min(workers, len(chunks))
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var panicValue any
+	panicked := false
+
+	work := make(chan *tableChunk, len(chunks))
+	for i := range chunks {
+		work <- &chunks[i]
+	}
+	close(work)
+
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					once.Do(func() {
+						panicValue = r
+						panicked = true
+					})
+				}
+			}()
+			for chunk := range work {
+				fn(chunk.table, chunk.start, chunk.end)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if panicked {
+		panic(panicValue)
+	}
+}
+
+// splitTables splits tables into chunks of roughly equal size for the given number of workers.
+func splitTables(tables []*table, workers int) []tableChunk {
+	total := 0
+	for _, t := range tables {
+		total += int(t.len)
+	}
+	if total == 0 {
+		return nil
+	}
+	numChunks := workers * chunksPerWorker
+	chunkSize := uint32(max((total+numChunks-1)/numChunks, 1))
+
+	chunks := make([]tableChunk, 0, numChunks+len(tables))
+	for _, t := range tables {
+		for start := uint32(0); start < t.len; start += chunkSize {
+			chunks = append(chunks, tableChunk{
+				table: t,
+				start: start,
+				end:   min(start+chunkSize, t.len),
+			})
+		}
+	}
+	return chunks
+}
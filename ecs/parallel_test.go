@@ -0,0 +1,96 @@
+package ecs
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestQueryParallelTables(t *testing.T) {
+	w := NewWorld(16)
+	mapper := NewMap2[Position, Velocity](w)
+	childMap := NewMap3[Position, Velocity, ChildOf](w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	mapper.NewBatch(1000, &Position{}, &Velocity{X: 1, Y: 2})
+	childMap.NewBatch(100, &Position{}, &Velocity{X: 1, Y: 2}, &ChildOf{}, Rel[ChildOf](parent1))
+	childMap.NewBatch(10, &Position{}, &Velocity{X: 1, Y: 2}, &ChildOf{}, Rel[ChildOf](parent2))
+
+	filter := NewFilter2[Position, Velocity](w)
+
+	var count atomic.Int64
+	query := filter.Query()
+	query.ParallelTables(4, func(chunk *Chunk, entities []Entity, pos []Position, vel []Velocity) {
+		expectTrue(t, w.IsLocked())
+		expectEqual(t, len(entities), len(pos))
+		expectEqual(t, len(entities), len(vel))
+		for i := range pos {
+			pos[i].X += vel[i].X
+			pos[i].Y += vel[i].Y
+		}
+		count.Add(int64(len(entities)))
+	})
+	expectFalse(t, w.IsLocked())
+	expectEqual(t, 1110, count.Load())
+
+	query = filter.Query()
+	for query.Next() {
+		pos, _ := query.Get()
+		expectEqual(t, Position{X: 1, Y: 2}, *pos)
+	}
+
+	count.Store(0)
+	childFilter := NewFilter1[ChildOf](w)
+	query1 := childFilter.Query(RelIdx(0, parent2))
+	query1.ParallelTables(0, func(chunk *Chunk, entities []Entity, child []ChildOf) {
+		count.Add(int64(len(entities)))
+	})
+	expectEqual(t, 10, count.Load())
+	expectFalse(t, w.IsLocked())
+
+	count.Store(0)
+	query0 := NewFilter0(w).Query()
+	query0.ParallelTables(3, func(entities []Entity) {
+		count.Add(int64(len(entities)))
+	})
+	expectEqual(t, 1112, count.Load())
+
+	query0 = NewFilter0(w).With(C[Heading]()).Query()
+	query0.ParallelTables(3, func(entities []Entity) {
+		t.Error("no entities expected")
+	})
+	expectFalse(t, w.IsLocked())
+}
+
+func TestQueryParallelTablesPanic(t *testing.T) {
+	w := NewWorld(16)
+	mapper := NewMap1[Position](w)
+	mapper.NewBatch(100, &Position{})
+
+	query := NewFilter1[Position](w).Query()
+	expectPanicsWithValue(t, "test panic", func() {
+		query.ParallelTables(4, func(chunk *Chunk, entities []Entity, pos []Position) {
+			panic("test panic")
+		})
+	})
+	expectFalse(t, w.IsLocked())
+}
+
+func TestSplitTables(t *testing.T) {
+	tables := []*table{{len: 100}, {len: 0}, {len: 7}}
+
+	chunks := splitTables(tables, 2)
+	expectEqual(t, 9, len(chunks))
+
+	total := uint32(0)
+	for _, c := range chunks {
+		expectTrue(t, c.end > c.start)
+		expectTrue(t, c.end <= c.table.len)
+		total += c.end - c.start
+	}
+	expectEqual(t, 107, total)
+
+	expectEqual(t, 0, len(splitTables([]*table{{len: 0}}, 4)))
+	expectEqual(t, 0, len(splitTables(nil, 4)))
+}
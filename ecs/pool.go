@@ -55,6 +55,32 @@ func (p *entityPool) getNew() Entity {
 	return e
 }
 
+// Clone returns an independent copy of the pool.
+func (p *entityPool) Clone() entityPool {
+	entities := append([]Entity(nil), p.entities...)
+	return entityPool{
+		entities:  entities,
+		next:      p.next,
+		available: p.available,
+		pointer:   unsafe.Pointer(&entities[0]),
+		reserved:  p.reserved,
+	}
+}
+
+// Restore resets the pool to the state of the given pool, as obtained by [entityPool.Clone].
+// Keeps the current memory, so that all entities issued so far can still be checked by [entityPool.Alive].
+// Entities beyond the restored state get an increased generation, so that they are not alive.
+func (p *entityPool) Restore(other *entityPool) {
+	for i := len(other.entities); i < len(p.entities); i++ {
+		p.entities[i].gen++
+	}
+	p.entities = append(p.entities[:0], other.entities...)
+	p.pointer = unsafe.Pointer(&p.entities[0])
+	p.next = other.next
+	p.available = other.available
+	p.reserved = other.reserved
+}
+
 // Recycle hands an entity back for recycling.
 func (p *entityPool) Recycle(e Entity) {
 	if e.id < p.reserved {
@@ -1,16 +1,19 @@
 package ecs
 
+import "unsafe"
+
 // UnsafeQuery is an unsafe query.
 // It is significantly slower than type-safe generic queries like [Query2],
 // and should only be used when component types are not known at compile time.
 type UnsafeQuery struct {
-	world     *World
-	table     *table
-	relations []relationID
-	tables    []tableID
-	filter    filter
-	cursor    cursor
-	lock      uint8
+	world      *World
+	table      *table
+	relations  []relationID
+	tables     []tableID
+	filter     filter
+	conditions *entityConditions
+	cursor     cursor
+	lock       uint8
 }
 
 // Has returns whether the current entity has the given component.
@@ -19,13 +22,17 @@ func (q *UnsafeQuery) Has(comp ID) bool {
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
 func (q *UnsafeQuery) GetRelation(comp ID) Entity {
+	if q.table.components[comp.id].isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), comp)
+	}
 	return q.table.GetRelation(comp)
 }
 
 // Count returns the number of entities matching this query.
 func (q *UnsafeQuery) Count() int {
-	return countQuery(&q.world.storage, &q.filter, q.relations, q.world.storage.allArchetypes)
+	return countQuery(&q.world.storage, &q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 }
 
 // EntityAt returns the entity at a given index.
@@ -38,7 +45,7 @@ func (q *UnsafeQuery) Count() int {
 //
 // Panics if the index is out of range, as indicated by [Query.Count].
 func (q *UnsafeQuery) EntityAt(index int) Entity {
-	return entityAt(&q.world.storage, &q.filter, q.relations, q.world.storage.allArchetypes, uint32(index))
+	return entityAt(&q.world.storage, &q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 }
 
 // IDs returns the IDs of all component of the current [Entity]n.
@@ -61,6 +68,20 @@ func (q *UnsafeQuery) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// nextTracked advances the cursor to the next entity that matches the filter's entity conditions.
+func (q *UnsafeQuery) nextTracked() bool {
+	for {
+		if int64(q.cursor.index) < q.cursor.maxIndex {
+			q.cursor.index++
+		} else if !q.nextTableOrArchetype() {
+			return false
+		}
+		if q.conditions.matchesRow(q.table, q.cursor.index) {
+			return true
+		}
+	}
+}
+
 func (q *UnsafeQuery) nextTableOrArchetype() bool {
 	if q.cursor.archetype >= 0 && q.nextTable() {
 		return true
@@ -117,3 +138,21 @@ func (q *UnsafeQuery) setTable(index int32, table *table) {
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
+
+// columnSlice returns the given range of a column's data as a slice.
+// Returns nil for nil columns, i.e. for absent optional components.
+func columnSlice[T any](c *column, start, end uint32) []T {
+	if c == nil {
+		return nil
+	}
+	return c.data.Interface().([]T)[start:end:end]
+}
+
+// optionalPtr returns a pointer to the item at the given index of a column's data,
+// or nil for nil data pointers, i.e. for absent optional components.
+func optionalPtr[T any](ptr unsafe.Pointer, index, itemSize uintptr) *T {
+	if ptr == nil {
+		return nil
+	}
+	return (*T)(unsafe.Add(ptr, index*itemSize))
+}
@@ -49,6 +49,47 @@ func BenchmarkPosVelQueryInline_100k(b *testing.B) {
 	}
 }
 
+func BenchmarkPosVelQueryChanged_1000(b *testing.B) {
+	n := 1000
+	world := NewWorld(1024)
+
+	mapper := NewMap2[Position, Velocity](world)
+	mapper.NewBatch(n, &Position{}, &Velocity{X: 1, Y: 0})
+
+	filter := NewFilter2[Position, Velocity](world).Changed(C[Velocity]())
+	loop := func(filter *Filter2[Position, Velocity]) {
+		query := filter.Query()
+		for query.Next() {
+			pos, vel := query.Get()
+			pos.X += vel.X
+			pos.Y += vel.Y
+		}
+	}
+
+	for b.Loop() {
+		loop(filter)
+	}
+}
+
+func BenchmarkQuery0Next_1000(b *testing.B) {
+	n := 1000
+	world := NewWorld(1024)
+
+	mapper := NewMap2[Position, Velocity](world)
+	mapper.NewBatch(n, &Position{}, &Velocity{X: 1, Y: 0})
+
+	filter := NewFilter0(world)
+	loop := func(filter *Filter0) {
+		query := filter.Query()
+		for query.Next() {
+		}
+	}
+
+	for b.Loop() {
+		loop(filter)
+	}
+}
+
 func BenchmarkPosVelQueryTables_1000(b *testing.B) {
 	n := 1000
 	world := NewWorld(1024)
@@ -3,7 +3,7 @@ package ecs
 import "fmt"
 
 // entityAtCache return the entity at a specific index in a query, for cached queries.
-func entityAtCache(storage *storage, cache *cacheEntry, relations []relationID, index uint32) Entity {
+func entityAtCache(storage *storage, cache *cacheEntry, relations []relationID, conditions *entityConditions, index uint32) Entity {
 	count := uint32(0)
 	for _, tableID := range cache.tables.tables {
 		table := &storage.tables[tableID]
@@ -13,9 +13,9 @@ func entityAtCache(storage *storage, cache *cacheEntry, relations []relationID,
 		if !table.Matches(relations) {
 			continue
 		}
-		len := uint32(table.Len())
+		len := countTable(table, conditions)
 		if count+len > index {
-			return table.GetEntity(uintptr(index - count))
+			return tableEntityAt(table, conditions, index-count)
 		}
 		count += len
 	}
@@ -23,7 +23,7 @@ func entityAtCache(storage *storage, cache *cacheEntry, relations []relationID,
 }
 
 // entityAtCache return the entity at a specific index in a query, for uncached queries.
-func entityAt(storage *storage, filter *filter, relations []relationID, archetypes []archetypeID, index uint32) Entity {
+func entityAt(storage *storage, filter *filter, relations []relationID, conditions *entityConditions, archetypes []archetypeID, index uint32) Entity {
 	count := uint32(0)
 	for _, arch := range archetypes {
 		archetype := &storage.archetypes[arch]
@@ -33,9 +33,9 @@ func entityAt(storage *storage, filter *filter, relations []relationID, archetyp
 
 		if !archetype.HasRelations() {
 			table := &storage.tables[archetype.tables.tables[0]]
-			len := uint32(table.Len())
+			len := countTable(table, conditions)
 			if count+len > index {
-				return table.GetEntity(uintptr(index - count))
+				return tableEntityAt(table, conditions, index-count)
 			}
 			count += len
 			continue
@@ -47,9 +47,9 @@ func entityAt(storage *storage, filter *filter, relations []relationID, archetyp
 			if !table.Matches(relations) {
 				continue
 			}
-			len := uint32(table.Len())
+			len := countTable(table, conditions)
 			if count+len > index {
-				return table.GetEntity(uintptr(index - count))
+				return tableEntityAt(table, conditions, index-count)
 			}
 			count += len
 		}
@@ -58,7 +58,7 @@ func entityAt(storage *storage, filter *filter, relations []relationID, archetyp
 }
 
 // countQueryCache returns the number of entities in a query, for cached queries.
-func countQueryCache(storage *storage, cache *cacheEntry, relations []relationID) int {
+func countQueryCache(storage *storage, cache *cacheEntry, relations []relationID, conditions *entityConditions) int {
 	count := 0
 	for _, tableID := range cache.tables.tables {
 		table := &storage.tables[tableID]
@@ -68,13 +68,13 @@ func countQueryCache(storage *storage, cache *cacheEntry, relations []relationID
 		if !table.Matches(relations) {
 			continue
 		}
-		count += table.Len()
+		count += int(countTable(table, conditions))
 	}
 	return count
 }
 
 // countQueryCache returns the number of entities in a query, for uncached queries.
-func countQuery(storage *storage, filter *filter, relations []relationID, archetypes []archetypeID) int {
+func countQuery(storage *storage, filter *filter, relations []relationID, conditions *entityConditions, archetypes []archetypeID) int {
 	count := 0
 	for _, arch := range archetypes {
 		archetype := &storage.archetypes[arch]
@@ -84,7 +84,7 @@ func countQuery(storage *storage, filter *filter, relations []relationID, archet
 
 		if !archetype.HasRelations() {
 			table := &storage.tables[archetype.tables.tables[0]]
-			count += table.Len()
+			count += int(countTable(table, conditions))
 			continue
 		}
 
@@ -94,8 +94,42 @@ func countQuery(storage *storage, filter *filter, relations []relationID, archet
 			if !table.Matches(relations) {
 				continue
 			}
-			count += table.Len()
+			count += int(countTable(table, conditions))
 		}
 	}
 	return count
 }
+
+// countTable returns the number of entities in a table
+// that match the given entity conditions.
+func countTable(table *table, conditions *entityConditions) uint32 {
+	if !conditions.hasChecks() {
+		return uint32(table.Len())
+	}
+	count := uint32(0)
+	for i := range uintptr(table.Len()) {
+		if conditions.matches(table.GetEntity(i)) {
+			count++
+		}
+	}
+	return count
+}
+
+// tableEntityAt returns the entity at a specific index among the entities of a table
+// that match the given entity conditions.
+func tableEntityAt(table *table, conditions *entityConditions, index uint32) Entity {
+	if !conditions.hasChecks() {
+		return table.GetEntity(uintptr(index))
+	}
+	for i := range uintptr(table.Len()) {
+		entity := table.GetEntity(i)
+		if !conditions.matches(entity) {
+			continue
+		}
+		if index == 0 {
+			return entity
+		}
+		index--
+	}
+	panic(fmt.Sprintf("entity index %d out of bounds for table with %d entities", index, table.Len()))
+}
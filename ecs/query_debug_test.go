@@ -0,0 +1,53 @@
+//go:build ark_debug
+
+package ecs
+
+import "testing"
+
+func TestQueryDebugOptional(t *testing.T) {
+	world := NewWorld()
+	NewMap2[Position, Velocity](world).NewBatch(2, &Position{}, &Velocity{})
+
+	query := NewFilter2[Position, Velocity](world).Optional(C[Velocity]()).Query()
+	query.Next()
+	expectPanicsWithValue(t, "query has optional components, use GetOptional", func() {
+		query.Get()
+	})
+	query.Close()
+}
+
+func TestQueryDebugOptionalAll(t *testing.T) {
+	world := NewWorld()
+	NewMap8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](world).NewEntity(
+		&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+
+	msg := "query has optional components, use GetOptional"
+	q1 := NewFilter1[CompA](world).Optional(C[CompA]()).Query()
+	q1.Next()
+	expectPanicsWithValue(t, msg, func() { q1.Get() })
+	q1.Close()
+	q3 := NewFilter3[CompA, CompB, CompC](world).Optional(C[CompA]()).Query()
+	q3.Next()
+	expectPanicsWithValue(t, msg, func() { q3.Get() })
+	q3.Close()
+	q4 := NewFilter4[CompA, CompB, CompC, CompD](world).Optional(C[CompA]()).Query()
+	q4.Next()
+	expectPanicsWithValue(t, msg, func() { q4.Get() })
+	q4.Close()
+	q5 := NewFilter5[CompA, CompB, CompC, CompD, CompE](world).Optional(C[CompA]()).Query()
+	q5.Next()
+	expectPanicsWithValue(t, msg, func() { q5.Get() })
+	q5.Close()
+	q6 := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](world).Optional(C[CompA]()).Query()
+	q6.Next()
+	expectPanicsWithValue(t, msg, func() { q6.Get() })
+	q6.Close()
+	q7 := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](world).Optional(C[CompA]()).Query()
+	q7.Next()
+	expectPanicsWithValue(t, msg, func() { q7.Get() })
+	q7.Close()
+	q8 := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](world).Optional(C[CompA]()).Query()
+	q8.Next()
+	expectPanicsWithValue(t, msg, func() { q8.Get() })
+	q8.Close()
+}
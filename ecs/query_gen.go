@@ -5,7 +5,7 @@ package ecs
 import "unsafe"
 
 type cursor struct {
-	archetype int32
+	archetype int32 // Index of the archetype, or of the entity in the driving entities
 	table     int32
 	index     uintptr
 	maxIndex  int64
@@ -22,12 +22,15 @@ type Query0 struct {
 	filter      *filter
 	table       *table
 	cache       *cacheEntry
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
 	relations   []relationID
 	tables      []tableID
 	components  []*componentStorage
 	cursor      cursor
 	lock        uint8
-	rareComp    uint8
+	rareComp    idIndex
 	hasRareComp bool
 }
 
@@ -42,11 +45,11 @@ type Query0 struct {
 func (q *Query0) Count() int {
 	if q.cache == nil {
 		if q.hasRareComp {
-			return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
 		}
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.allArchetypes)
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -63,11 +66,11 @@ func (q *Query0) Count() int {
 func (q *Query0) EntityAt(index int) Entity {
 	if q.cache == nil {
 		if q.hasRareComp {
-			return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
 		}
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.allArchetypes, uint32(index))
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -86,6 +89,97 @@ func (q *Query0) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk, its entities and component columns.
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query0.Next] or [Query0.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query0) ParallelTables(workers int, fn func(entities []Entity)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		fn(table.entities.data.Interface().([]Entity)[start:end:end])
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query0.Next], so that it can be inlined.
+func (q *Query0) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query0.Next] always calls this for the next row.
+func (q *Query0) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query0) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query0) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -115,7 +209,7 @@ func (q *Query0) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -137,7 +231,7 @@ func (q *Query0) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -163,27 +257,40 @@ func (q *Query0) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query1[A any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query1[A]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -196,9 +303,12 @@ func (q *Query1[A]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query1[A]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -214,9 +324,12 @@ func (q *Query1[A]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query1[A]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -238,6 +351,115 @@ func (q *Query1[A]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter1.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query1.Next] or [Query1.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter1.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query1[A]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query1.Next], so that it can be inlined.
+func (q *Query1[A]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query1.Next] always calls this for the next row.
+func (q *Query1[A]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query1[A]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query1[A]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -250,7 +472,12 @@ func (q *Query1[A]) nextTableOrArchetype() bool {
 
 func (q *Query1[A]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -262,7 +489,7 @@ func (q *Query1[A]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -284,7 +511,7 @@ func (q *Query1[A]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -300,41 +527,108 @@ func (q *Query1[A]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query1.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter1.Optional]).
+// Use this with entity iteration using [Query1.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query1[A]) GetOptional() *A {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query1.Get] and [Query1.GetOptional] need no special handling for them.
+func (q *Query1[A]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query1.Get],
+// and marks them as changed for change detection (see [Filter1.Changed]).
+// For filters with optional components, it returns nil pointers like [Query1.GetOptional].
+// Use this instead of [Query1.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query1[A]) GetMut() *A {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter1.Changed]).
+// Use this with table-based iteration using [Query1.NextTable],
+// when modifying the columns obtained via [Query1.GetColumns].
+func (q *Query1[A]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+}
+
 // Query2 is a query for 2 components.
 // Use a [Filter2] to create one.
 //
 // Queries are one-time use iterators and must be re-created each time before iterating.
 type Query2[A any, B any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query2[A, B]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -345,9 +639,12 @@ func (q *Query2[A, B]) GetRelation(index int) Entity {
 // Does not iterate or close the query.
 func (q *Query2[A, B]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -361,9 +658,12 @@ func (q *Query2[A, B]) Count() int {
 // Panics if the index is out of range, as indicated by [Query2.Count].
 func (q *Query2[A, B]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -388,6 +688,114 @@ func (q *Query2[A, B]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter2.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query2.Next] or [Query2.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter2.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+func (q *Query2[A, B]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query2.Next], so that it can be inlined.
+func (q *Query2[A, B]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query2.Next] always calls this for the next row.
+func (q *Query2[A, B]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query2[A, B]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query2[A, B]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -400,7 +808,12 @@ func (q *Query2[A, B]) nextTableOrArchetype() bool {
 
 func (q *Query2[A, B]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -412,7 +825,7 @@ func (q *Query2[A, B]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -434,7 +847,7 @@ func (q *Query2[A, B]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -450,15 +863,84 @@ func (q *Query2[A, B]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query2.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter2.Optional]).
+// Use this with entity iteration using [Query2.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query2[A, B]) GetOptional() (*A, *B) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query2.Get] and [Query2.GetOptional] need no special handling for them.
+func (q *Query2[A, B]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query2.Get],
+// and marks them as changed for change detection (see [Filter2.Changed]).
+// For filters with optional components, it returns nil pointers like [Query2.GetOptional].
+// Use this instead of [Query2.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query2[A, B]) GetMut() (*A, *B) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter2.Changed]).
+// Use this with table-based iteration using [Query2.NextTable],
+// when modifying the columns obtained via [Query2.GetColumns].
+func (q *Query2[A, B]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+}
+
 // Query3 is a query for 3 components.
 // Use a [Filter3] to create one.
 //
@@ -466,33 +948,46 @@ func (q *Query2[A, B]) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query3[A any, B any, C any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	columnC    *column
-	columnPtrC unsafe.Pointer
-	itemSizeC  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	columnC     *column
+	columnPtrC  unsafe.Pointer
+	itemSizeC   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query3[A, B, C]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -505,9 +1000,12 @@ func (q *Query3[A, B, C]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query3[A, B, C]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -523,9 +1021,12 @@ func (q *Query3[A, B, C]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query3[A, B, C]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -553,6 +1054,117 @@ func (q *Query3[A, B, C]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter3.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query3.Next] or [Query3.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter3.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query3[A, B, C]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B, c []C)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id], q.components[2].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end),
+			columnSlice[C](q.components[2].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query3.Next], so that it can be inlined.
+func (q *Query3[A, B, C]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query3.Next] always calls this for the next row.
+func (q *Query3[A, B, C]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query3[A, B, C]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query3[A, B, C]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -565,7 +1177,12 @@ func (q *Query3[A, B, C]) nextTableOrArchetype() bool {
 
 func (q *Query3[A, B, C]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -577,7 +1194,7 @@ func (q *Query3[A, B, C]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -599,7 +1216,7 @@ func (q *Query3[A, B, C]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -615,18 +1232,102 @@ func (q *Query3[A, B, C]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.columnC = q.components[2].columns[q.table.id]
-	q.columnPtrC = q.columnC.pointer
-	q.itemSizeC = q.columnC.itemSize
+	if q.columnC != nil {
+		q.columnPtrC = q.columnC.pointer
+		q.itemSizeC = q.columnC.itemSize
+	} else {
+		q.columnPtrC = nil
+		q.itemSizeC = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query3.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter3.Optional]).
+// Use this with entity iteration using [Query3.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query3[A, B, C]) GetOptional() (*A, *B, *C) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB),
+		optionalPtr[C](q.columnPtrC, index, q.itemSizeC)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query3.Get] and [Query3.GetOptional] need no special handling for them.
+func (q *Query3[A, B, C]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+	if set := q.sparse[2]; set != nil {
+		q.columnPtrC = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query3.Get],
+// and marks them as changed for change detection (see [Filter3.Changed]).
+// For filters with optional components, it returns nil pointers like [Query3.GetOptional].
+// Use this instead of [Query3.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query3[A, B, C]) GetMut() (*A, *B, *C) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.columnC != nil {
+		q.columnC.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter3.Changed]).
+// Use this with table-based iteration using [Query3.NextTable],
+// when modifying the columns obtained via [Query3.GetColumns].
+func (q *Query3[A, B, C]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+	if q.columnC != nil {
+		q.columnC.markAll(tick)
+	}
+}
+
 // Query4 is a query for 4 components.
 // Use a [Filter4] to create one.
 //
@@ -634,36 +1335,49 @@ func (q *Query3[A, B, C]) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query4[A any, B any, C any, D any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	columnC    *column
-	columnPtrC unsafe.Pointer
-	itemSizeC  uintptr
-	columnD    *column
-	columnPtrD unsafe.Pointer
-	itemSizeD  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	columnC     *column
+	columnPtrC  unsafe.Pointer
+	itemSizeC   uintptr
+	columnD     *column
+	columnPtrD  unsafe.Pointer
+	itemSizeD   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query4[A, B, C, D]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -676,9 +1390,12 @@ func (q *Query4[A, B, C, D]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query4[A, B, C, D]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -694,9 +1411,12 @@ func (q *Query4[A, B, C, D]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query4[A, B, C, D]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -727,19 +1447,136 @@ func (q *Query4[A, B, C, D]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
-func (q *Query4[A, B, C, D]) nextTableOrArchetype() bool {
-	if q.cache != nil {
-		return q.nextTable(q.cache.tables.tables)
-	}
-	if q.cursor.archetype >= 0 && q.nextTable(q.tables) {
-		return true
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter4.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query4.Next] or [Query4.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter4.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query4[A, B, C, D]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B, c []C, d []D)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id], q.components[2].columns[table.id], q.components[3].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end),
+			columnSlice[C](q.components[2].columns[table.id], start, end),
+			columnSlice[D](q.components[3].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query4.Next], so that it can be inlined.
+func (q *Query4[A, B, C, D]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query4.Next] always calls this for the next row.
+func (q *Query4[A, B, C, D]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query4[A, B, C, D]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
+func (q *Query4[A, B, C, D]) nextTableOrArchetype() bool {
+	if q.cache != nil {
+		return q.nextTable(q.cache.tables.tables)
+	}
+	if q.cursor.archetype >= 0 && q.nextTable(q.tables) {
+		return true
 	}
 	return q.nextArchetype()
 }
 
 func (q *Query4[A, B, C, D]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -751,7 +1588,7 @@ func (q *Query4[A, B, C, D]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -773,7 +1610,7 @@ func (q *Query4[A, B, C, D]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -789,21 +1626,120 @@ func (q *Query4[A, B, C, D]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.columnC = q.components[2].columns[q.table.id]
-	q.columnPtrC = q.columnC.pointer
-	q.itemSizeC = q.columnC.itemSize
+	if q.columnC != nil {
+		q.columnPtrC = q.columnC.pointer
+		q.itemSizeC = q.columnC.itemSize
+	} else {
+		q.columnPtrC = nil
+		q.itemSizeC = 0
+	}
 	q.columnD = q.components[3].columns[q.table.id]
-	q.columnPtrD = q.columnD.pointer
-	q.itemSizeD = q.columnD.itemSize
+	if q.columnD != nil {
+		q.columnPtrD = q.columnD.pointer
+		q.itemSizeD = q.columnD.itemSize
+	} else {
+		q.columnPtrD = nil
+		q.itemSizeD = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query4.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter4.Optional]).
+// Use this with entity iteration using [Query4.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query4[A, B, C, D]) GetOptional() (*A, *B, *C, *D) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB),
+		optionalPtr[C](q.columnPtrC, index, q.itemSizeC),
+		optionalPtr[D](q.columnPtrD, index, q.itemSizeD)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query4.Get] and [Query4.GetOptional] need no special handling for them.
+func (q *Query4[A, B, C, D]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+	if set := q.sparse[2]; set != nil {
+		q.columnPtrC = set.Get(entity)
+	}
+	if set := q.sparse[3]; set != nil {
+		q.columnPtrD = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query4.Get],
+// and marks them as changed for change detection (see [Filter4.Changed]).
+// For filters with optional components, it returns nil pointers like [Query4.GetOptional].
+// Use this instead of [Query4.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query4[A, B, C, D]) GetMut() (*A, *B, *C, *D) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.columnC != nil {
+		q.columnC.setChanged(row, tick)
+	}
+	if q.columnD != nil {
+		q.columnD.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter4.Changed]).
+// Use this with table-based iteration using [Query4.NextTable],
+// when modifying the columns obtained via [Query4.GetColumns].
+func (q *Query4[A, B, C, D]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+	if q.columnC != nil {
+		q.columnC.markAll(tick)
+	}
+	if q.columnD != nil {
+		q.columnD.markAll(tick)
+	}
+}
+
 // Query5 is a query for 5 components.
 // Use a [Filter5] to create one.
 //
@@ -811,39 +1747,52 @@ func (q *Query4[A, B, C, D]) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query5[A any, B any, C any, D any, E any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	columnC    *column
-	columnPtrC unsafe.Pointer
-	itemSizeC  uintptr
-	columnD    *column
-	columnPtrD unsafe.Pointer
-	itemSizeD  uintptr
-	columnE    *column
-	columnPtrE unsafe.Pointer
-	itemSizeE  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	columnC     *column
+	columnPtrC  unsafe.Pointer
+	itemSizeC   uintptr
+	columnD     *column
+	columnPtrD  unsafe.Pointer
+	itemSizeD   uintptr
+	columnE     *column
+	columnPtrE  unsafe.Pointer
+	itemSizeE   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query5[A, B, C, D, E]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -856,9 +1805,12 @@ func (q *Query5[A, B, C, D, E]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query5[A, B, C, D, E]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -874,9 +1826,12 @@ func (q *Query5[A, B, C, D, E]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query5[A, B, C, D, E]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -910,6 +1865,119 @@ func (q *Query5[A, B, C, D, E]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter5.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query5.Next] or [Query5.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter5.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query5[A, B, C, D, E]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B, c []C, d []D, e []E)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id], q.components[2].columns[table.id], q.components[3].columns[table.id], q.components[4].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end),
+			columnSlice[C](q.components[2].columns[table.id], start, end),
+			columnSlice[D](q.components[3].columns[table.id], start, end),
+			columnSlice[E](q.components[4].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query5.Next], so that it can be inlined.
+func (q *Query5[A, B, C, D, E]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query5.Next] always calls this for the next row.
+func (q *Query5[A, B, C, D, E]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query5[A, B, C, D, E]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query5[A, B, C, D, E]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -922,7 +1990,12 @@ func (q *Query5[A, B, C, D, E]) nextTableOrArchetype() bool {
 
 func (q *Query5[A, B, C, D, E]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -934,7 +2007,7 @@ func (q *Query5[A, B, C, D, E]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -956,7 +2029,7 @@ func (q *Query5[A, B, C, D, E]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -972,24 +2045,138 @@ func (q *Query5[A, B, C, D, E]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.columnC = q.components[2].columns[q.table.id]
-	q.columnPtrC = q.columnC.pointer
-	q.itemSizeC = q.columnC.itemSize
+	if q.columnC != nil {
+		q.columnPtrC = q.columnC.pointer
+		q.itemSizeC = q.columnC.itemSize
+	} else {
+		q.columnPtrC = nil
+		q.itemSizeC = 0
+	}
 	q.columnD = q.components[3].columns[q.table.id]
-	q.columnPtrD = q.columnD.pointer
-	q.itemSizeD = q.columnD.itemSize
+	if q.columnD != nil {
+		q.columnPtrD = q.columnD.pointer
+		q.itemSizeD = q.columnD.itemSize
+	} else {
+		q.columnPtrD = nil
+		q.itemSizeD = 0
+	}
 	q.columnE = q.components[4].columns[q.table.id]
-	q.columnPtrE = q.columnE.pointer
-	q.itemSizeE = q.columnE.itemSize
+	if q.columnE != nil {
+		q.columnPtrE = q.columnE.pointer
+		q.itemSizeE = q.columnE.itemSize
+	} else {
+		q.columnPtrE = nil
+		q.itemSizeE = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query5.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter5.Optional]).
+// Use this with entity iteration using [Query5.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query5[A, B, C, D, E]) GetOptional() (*A, *B, *C, *D, *E) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB),
+		optionalPtr[C](q.columnPtrC, index, q.itemSizeC),
+		optionalPtr[D](q.columnPtrD, index, q.itemSizeD),
+		optionalPtr[E](q.columnPtrE, index, q.itemSizeE)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query5.Get] and [Query5.GetOptional] need no special handling for them.
+func (q *Query5[A, B, C, D, E]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+	if set := q.sparse[2]; set != nil {
+		q.columnPtrC = set.Get(entity)
+	}
+	if set := q.sparse[3]; set != nil {
+		q.columnPtrD = set.Get(entity)
+	}
+	if set := q.sparse[4]; set != nil {
+		q.columnPtrE = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query5.Get],
+// and marks them as changed for change detection (see [Filter5.Changed]).
+// For filters with optional components, it returns nil pointers like [Query5.GetOptional].
+// Use this instead of [Query5.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query5[A, B, C, D, E]) GetMut() (*A, *B, *C, *D, *E) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.columnC != nil {
+		q.columnC.setChanged(row, tick)
+	}
+	if q.columnD != nil {
+		q.columnD.setChanged(row, tick)
+	}
+	if q.columnE != nil {
+		q.columnE.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter5.Changed]).
+// Use this with table-based iteration using [Query5.NextTable],
+// when modifying the columns obtained via [Query5.GetColumns].
+func (q *Query5[A, B, C, D, E]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+	if q.columnC != nil {
+		q.columnC.markAll(tick)
+	}
+	if q.columnD != nil {
+		q.columnD.markAll(tick)
+	}
+	if q.columnE != nil {
+		q.columnE.markAll(tick)
+	}
+}
+
 // Query6 is a query for 6 components.
 // Use a [Filter6] to create one.
 //
@@ -997,42 +2184,55 @@ func (q *Query5[A, B, C, D, E]) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query6[A any, B any, C any, D any, E any, F any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	columnC    *column
-	columnPtrC unsafe.Pointer
-	itemSizeC  uintptr
-	columnD    *column
-	columnPtrD unsafe.Pointer
-	itemSizeD  uintptr
-	columnE    *column
-	columnPtrE unsafe.Pointer
-	itemSizeE  uintptr
-	columnF    *column
-	columnPtrF unsafe.Pointer
-	itemSizeF  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	columnC     *column
+	columnPtrC  unsafe.Pointer
+	itemSizeC   uintptr
+	columnD     *column
+	columnPtrD  unsafe.Pointer
+	itemSizeD   uintptr
+	columnE     *column
+	columnPtrE  unsafe.Pointer
+	itemSizeE   uintptr
+	columnF     *column
+	columnPtrF  unsafe.Pointer
+	itemSizeF   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query6[A, B, C, D, E, F]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -1045,9 +2245,12 @@ func (q *Query6[A, B, C, D, E, F]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query6[A, B, C, D, E, F]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -1063,9 +2266,12 @@ func (q *Query6[A, B, C, D, E, F]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query6[A, B, C, D, E, F]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -1102,6 +2308,120 @@ func (q *Query6[A, B, C, D, E, F]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter6.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query6.Next] or [Query6.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter6.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query6[A, B, C, D, E, F]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B, c []C, d []D, e []E, f []F)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id], q.components[2].columns[table.id], q.components[3].columns[table.id], q.components[4].columns[table.id], q.components[5].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end),
+			columnSlice[C](q.components[2].columns[table.id], start, end),
+			columnSlice[D](q.components[3].columns[table.id], start, end),
+			columnSlice[E](q.components[4].columns[table.id], start, end),
+			columnSlice[F](q.components[5].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query6.Next], so that it can be inlined.
+func (q *Query6[A, B, C, D, E, F]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query6.Next] always calls this for the next row.
+func (q *Query6[A, B, C, D, E, F]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query6[A, B, C, D, E, F]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query6[A, B, C, D, E, F]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -1114,7 +2434,12 @@ func (q *Query6[A, B, C, D, E, F]) nextTableOrArchetype() bool {
 
 func (q *Query6[A, B, C, D, E, F]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -1126,7 +2451,7 @@ func (q *Query6[A, B, C, D, E, F]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -1148,7 +2473,7 @@ func (q *Query6[A, B, C, D, E, F]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -1164,27 +2489,156 @@ func (q *Query6[A, B, C, D, E, F]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.columnC = q.components[2].columns[q.table.id]
-	q.columnPtrC = q.columnC.pointer
-	q.itemSizeC = q.columnC.itemSize
+	if q.columnC != nil {
+		q.columnPtrC = q.columnC.pointer
+		q.itemSizeC = q.columnC.itemSize
+	} else {
+		q.columnPtrC = nil
+		q.itemSizeC = 0
+	}
 	q.columnD = q.components[3].columns[q.table.id]
-	q.columnPtrD = q.columnD.pointer
-	q.itemSizeD = q.columnD.itemSize
+	if q.columnD != nil {
+		q.columnPtrD = q.columnD.pointer
+		q.itemSizeD = q.columnD.itemSize
+	} else {
+		q.columnPtrD = nil
+		q.itemSizeD = 0
+	}
 	q.columnE = q.components[4].columns[q.table.id]
-	q.columnPtrE = q.columnE.pointer
-	q.itemSizeE = q.columnE.itemSize
+	if q.columnE != nil {
+		q.columnPtrE = q.columnE.pointer
+		q.itemSizeE = q.columnE.itemSize
+	} else {
+		q.columnPtrE = nil
+		q.itemSizeE = 0
+	}
 	q.columnF = q.components[5].columns[q.table.id]
-	q.columnPtrF = q.columnF.pointer
-	q.itemSizeF = q.columnF.itemSize
+	if q.columnF != nil {
+		q.columnPtrF = q.columnF.pointer
+		q.itemSizeF = q.columnF.itemSize
+	} else {
+		q.columnPtrF = nil
+		q.itemSizeF = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query6.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter6.Optional]).
+// Use this with entity iteration using [Query6.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query6[A, B, C, D, E, F]) GetOptional() (*A, *B, *C, *D, *E, *F) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB),
+		optionalPtr[C](q.columnPtrC, index, q.itemSizeC),
+		optionalPtr[D](q.columnPtrD, index, q.itemSizeD),
+		optionalPtr[E](q.columnPtrE, index, q.itemSizeE),
+		optionalPtr[F](q.columnPtrF, index, q.itemSizeF)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query6.Get] and [Query6.GetOptional] need no special handling for them.
+func (q *Query6[A, B, C, D, E, F]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+	if set := q.sparse[2]; set != nil {
+		q.columnPtrC = set.Get(entity)
+	}
+	if set := q.sparse[3]; set != nil {
+		q.columnPtrD = set.Get(entity)
+	}
+	if set := q.sparse[4]; set != nil {
+		q.columnPtrE = set.Get(entity)
+	}
+	if set := q.sparse[5]; set != nil {
+		q.columnPtrF = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query6.Get],
+// and marks them as changed for change detection (see [Filter6.Changed]).
+// For filters with optional components, it returns nil pointers like [Query6.GetOptional].
+// Use this instead of [Query6.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query6[A, B, C, D, E, F]) GetMut() (*A, *B, *C, *D, *E, *F) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.columnC != nil {
+		q.columnC.setChanged(row, tick)
+	}
+	if q.columnD != nil {
+		q.columnD.setChanged(row, tick)
+	}
+	if q.columnE != nil {
+		q.columnE.setChanged(row, tick)
+	}
+	if q.columnF != nil {
+		q.columnF.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter6.Changed]).
+// Use this with table-based iteration using [Query6.NextTable],
+// when modifying the columns obtained via [Query6.GetColumns].
+func (q *Query6[A, B, C, D, E, F]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+	if q.columnC != nil {
+		q.columnC.markAll(tick)
+	}
+	if q.columnD != nil {
+		q.columnD.markAll(tick)
+	}
+	if q.columnE != nil {
+		q.columnE.markAll(tick)
+	}
+	if q.columnF != nil {
+		q.columnF.markAll(tick)
+	}
+}
+
 // Query7 is a query for 7 components.
 // Use a [Filter7] to create one.
 //
@@ -1192,45 +2646,58 @@ func (q *Query6[A, B, C, D, E, F]) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query7[A any, B any, C any, D any, E any, F any, G any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	columnC    *column
-	columnPtrC unsafe.Pointer
-	itemSizeC  uintptr
-	columnD    *column
-	columnPtrD unsafe.Pointer
-	itemSizeD  uintptr
-	columnE    *column
-	columnPtrE unsafe.Pointer
-	itemSizeE  uintptr
-	columnF    *column
-	columnPtrF unsafe.Pointer
-	itemSizeF  uintptr
-	columnG    *column
-	columnPtrG unsafe.Pointer
-	itemSizeG  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	columnC     *column
+	columnPtrC  unsafe.Pointer
+	itemSizeC   uintptr
+	columnD     *column
+	columnPtrD  unsafe.Pointer
+	itemSizeD   uintptr
+	columnE     *column
+	columnPtrE  unsafe.Pointer
+	itemSizeE   uintptr
+	columnF     *column
+	columnPtrF  unsafe.Pointer
+	itemSizeF   uintptr
+	columnG     *column
+	columnPtrG  unsafe.Pointer
+	itemSizeG   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query7[A, B, C, D, E, F, G]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -1243,9 +2710,12 @@ func (q *Query7[A, B, C, D, E, F, G]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query7[A, B, C, D, E, F, G]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -1261,9 +2731,12 @@ func (q *Query7[A, B, C, D, E, F, G]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query7[A, B, C, D, E, F, G]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -1303,6 +2776,121 @@ func (q *Query7[A, B, C, D, E, F, G]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter7.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query7.Next] or [Query7.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter7.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query7[A, B, C, D, E, F, G]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B, c []C, d []D, e []E, f []F, g []G)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id], q.components[2].columns[table.id], q.components[3].columns[table.id], q.components[4].columns[table.id], q.components[5].columns[table.id], q.components[6].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end),
+			columnSlice[C](q.components[2].columns[table.id], start, end),
+			columnSlice[D](q.components[3].columns[table.id], start, end),
+			columnSlice[E](q.components[4].columns[table.id], start, end),
+			columnSlice[F](q.components[5].columns[table.id], start, end),
+			columnSlice[G](q.components[6].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query7.Next], so that it can be inlined.
+func (q *Query7[A, B, C, D, E, F, G]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query7.Next] always calls this for the next row.
+func (q *Query7[A, B, C, D, E, F, G]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query7[A, B, C, D, E, F, G]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query7[A, B, C, D, E, F, G]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -1315,7 +2903,12 @@ func (q *Query7[A, B, C, D, E, F, G]) nextTableOrArchetype() bool {
 
 func (q *Query7[A, B, C, D, E, F, G]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -1327,7 +2920,7 @@ func (q *Query7[A, B, C, D, E, F, G]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -1349,7 +2942,7 @@ func (q *Query7[A, B, C, D, E, F, G]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -1365,30 +2958,174 @@ func (q *Query7[A, B, C, D, E, F, G]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.columnC = q.components[2].columns[q.table.id]
-	q.columnPtrC = q.columnC.pointer
-	q.itemSizeC = q.columnC.itemSize
+	if q.columnC != nil {
+		q.columnPtrC = q.columnC.pointer
+		q.itemSizeC = q.columnC.itemSize
+	} else {
+		q.columnPtrC = nil
+		q.itemSizeC = 0
+	}
 	q.columnD = q.components[3].columns[q.table.id]
-	q.columnPtrD = q.columnD.pointer
-	q.itemSizeD = q.columnD.itemSize
+	if q.columnD != nil {
+		q.columnPtrD = q.columnD.pointer
+		q.itemSizeD = q.columnD.itemSize
+	} else {
+		q.columnPtrD = nil
+		q.itemSizeD = 0
+	}
 	q.columnE = q.components[4].columns[q.table.id]
-	q.columnPtrE = q.columnE.pointer
-	q.itemSizeE = q.columnE.itemSize
+	if q.columnE != nil {
+		q.columnPtrE = q.columnE.pointer
+		q.itemSizeE = q.columnE.itemSize
+	} else {
+		q.columnPtrE = nil
+		q.itemSizeE = 0
+	}
 	q.columnF = q.components[5].columns[q.table.id]
-	q.columnPtrF = q.columnF.pointer
-	q.itemSizeF = q.columnF.itemSize
+	if q.columnF != nil {
+		q.columnPtrF = q.columnF.pointer
+		q.itemSizeF = q.columnF.itemSize
+	} else {
+		q.columnPtrF = nil
+		q.itemSizeF = 0
+	}
 	q.columnG = q.components[6].columns[q.table.id]
-	q.columnPtrG = q.columnG.pointer
-	q.itemSizeG = q.columnG.itemSize
+	if q.columnG != nil {
+		q.columnPtrG = q.columnG.pointer
+		q.itemSizeG = q.columnG.itemSize
+	} else {
+		q.columnPtrG = nil
+		q.itemSizeG = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
 
+// GetOptional returns the queried components of the current entity, like [Query7.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter7.Optional]).
+// Use this with entity iteration using [Query7.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query7[A, B, C, D, E, F, G]) GetOptional() (*A, *B, *C, *D, *E, *F, *G) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB),
+		optionalPtr[C](q.columnPtrC, index, q.itemSizeC),
+		optionalPtr[D](q.columnPtrD, index, q.itemSizeD),
+		optionalPtr[E](q.columnPtrE, index, q.itemSizeE),
+		optionalPtr[F](q.columnPtrF, index, q.itemSizeF),
+		optionalPtr[G](q.columnPtrG, index, q.itemSizeG)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query7.Get] and [Query7.GetOptional] need no special handling for them.
+func (q *Query7[A, B, C, D, E, F, G]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+	if set := q.sparse[2]; set != nil {
+		q.columnPtrC = set.Get(entity)
+	}
+	if set := q.sparse[3]; set != nil {
+		q.columnPtrD = set.Get(entity)
+	}
+	if set := q.sparse[4]; set != nil {
+		q.columnPtrE = set.Get(entity)
+	}
+	if set := q.sparse[5]; set != nil {
+		q.columnPtrF = set.Get(entity)
+	}
+	if set := q.sparse[6]; set != nil {
+		q.columnPtrG = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query7.Get],
+// and marks them as changed for change detection (see [Filter7.Changed]).
+// For filters with optional components, it returns nil pointers like [Query7.GetOptional].
+// Use this instead of [Query7.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query7[A, B, C, D, E, F, G]) GetMut() (*A, *B, *C, *D, *E, *F, *G) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.columnC != nil {
+		q.columnC.setChanged(row, tick)
+	}
+	if q.columnD != nil {
+		q.columnD.setChanged(row, tick)
+	}
+	if q.columnE != nil {
+		q.columnE.setChanged(row, tick)
+	}
+	if q.columnF != nil {
+		q.columnF.setChanged(row, tick)
+	}
+	if q.columnG != nil {
+		q.columnG.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter7.Changed]).
+// Use this with table-based iteration using [Query7.NextTable],
+// when modifying the columns obtained via [Query7.GetColumns].
+func (q *Query7[A, B, C, D, E, F, G]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+	if q.columnC != nil {
+		q.columnC.markAll(tick)
+	}
+	if q.columnD != nil {
+		q.columnD.markAll(tick)
+	}
+	if q.columnE != nil {
+		q.columnE.markAll(tick)
+	}
+	if q.columnF != nil {
+		q.columnF.markAll(tick)
+	}
+	if q.columnG != nil {
+		q.columnG.markAll(tick)
+	}
+}
+
 // Query8 is a query for 8 components.
 // Use a [Filter8] to create one.
 //
@@ -1396,48 +3133,61 @@ func (q *Query7[A, B, C, D, E, F, G]) setTable(index int32, table *table) {
 //
 // See [Query2] for a usage example.
 type Query8[A any, B any, C any, D any, E any, F any, G any, H any] struct {
-	world      *World
-	filter     *filter
-	table      *table
-	cache      *cacheEntry
-	columnA    *column
-	columnPtrA unsafe.Pointer
-	itemSizeA  uintptr
-	columnB    *column
-	columnPtrB unsafe.Pointer
-	itemSizeB  uintptr
-	columnC    *column
-	columnPtrC unsafe.Pointer
-	itemSizeC  uintptr
-	columnD    *column
-	columnPtrD unsafe.Pointer
-	itemSizeD  uintptr
-	columnE    *column
-	columnPtrE unsafe.Pointer
-	itemSizeE  uintptr
-	columnF    *column
-	columnPtrF unsafe.Pointer
-	itemSizeF  uintptr
-	columnG    *column
-	columnPtrG unsafe.Pointer
-	itemSizeG  uintptr
-	columnH    *column
-	columnPtrH unsafe.Pointer
-	itemSizeH  uintptr
-	relations  []relationID
-	tables     []tableID
-	components []*componentStorage
-	cursor     cursor
-	lock       uint8
-	rareComp   uint8
+	world       *World
+	filter      *filter
+	table       *table
+	cache       *cacheEntry
+	columnA     *column
+	columnPtrA  unsafe.Pointer
+	itemSizeA   uintptr
+	columnB     *column
+	columnPtrB  unsafe.Pointer
+	itemSizeB   uintptr
+	columnC     *column
+	columnPtrC  unsafe.Pointer
+	itemSizeC   uintptr
+	columnD     *column
+	columnPtrD  unsafe.Pointer
+	itemSizeD   uintptr
+	columnE     *column
+	columnPtrE  unsafe.Pointer
+	itemSizeE   uintptr
+	columnF     *column
+	columnPtrF  unsafe.Pointer
+	itemSizeF   uintptr
+	columnG     *column
+	columnPtrG  unsafe.Pointer
+	itemSizeG   uintptr
+	columnH     *column
+	columnPtrH  unsafe.Pointer
+	itemSizeH   uintptr
+	tracker     *changeTracker
+	conditions  *entityConditions
+	driver      []Entity
+	sparse      []*sparseSet
+	relations   []relationID
+	tables      []tableID
+	components  []*componentStorage
+	cursor      cursor
+	lock        uint8
+	rareComp    idIndex
+	hasRareComp bool
+	hasOptional bool
 }
 
 // GetRelation returns the entity relation target of the component at the given index.
 //
 // Can be used for the current entity in entity-based iteration,
 // as well as for the entire current table in table-based iteration.
+// For filters with wildcard relations (see [RelWildcard]), this gives the matched target of the current table.
+// For multi-target relations (see [MultiRelationMarker]), it is the current entity's target with the lowest ID.
+// Use [Map.GetRelations] to get all targets.
 func (q *Query8[A, B, C, D, E, F, G, H]) GetRelation(index int) Entity {
-	return q.components[index].columns[q.table.id].target
+	column := q.components[index].columns[q.table.id]
+	if column.isMultiRelation {
+		return q.world.storage.firstMultiTarget(q.table.GetEntity(q.cursor.index), q.table.ids[column.index])
+	}
+	return column.target
 }
 
 // Count counts the entities matching this query.
@@ -1450,9 +3200,12 @@ func (q *Query8[A, B, C, D, E, F, G, H]) GetRelation(index int) Entity {
 // See [Query2.Count] for an example.
 func (q *Query8[A, B, C, D, E, F, G, H]) Count() int {
 	if q.cache == nil {
-		return countQuery(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp])
+		if q.hasRareComp {
+			return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp])
+		}
+		return countQuery(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes)
 	}
-	return countQueryCache(&q.world.storage, q.cache, q.relations)
+	return countQueryCache(&q.world.storage, q.cache, q.relations, q.conditions)
 }
 
 // EntityAt returns the entity at a given index.
@@ -1468,9 +3221,12 @@ func (q *Query8[A, B, C, D, E, F, G, H]) Count() int {
 // See [Query2.EntityAt] for an example.
 func (q *Query8[A, B, C, D, E, F, G, H]) EntityAt(index int) Entity {
 	if q.cache == nil {
-		return entityAt(&q.world.storage, q.filter, q.relations, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		if q.hasRareComp {
+			return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.componentIndex[q.rareComp], uint32(index))
+		}
+		return entityAt(&q.world.storage, q.filter, q.relations, q.conditions, q.world.storage.allArchetypes, uint32(index))
 	}
-	return entityAtCache(&q.world.storage, q.cache, q.relations, uint32(index))
+	return entityAtCache(&q.world.storage, q.cache, q.relations, q.conditions, uint32(index))
 }
 
 // Close closes the Query and unlocks the world.
@@ -1513,6 +3269,122 @@ func (q *Query8[A, B, C, D, E, F, G, H]) Close() {
 	q.world.unlockSafe(q.lock)
 }
 
+// ParallelTables processes the query's tables in parallel, using the given number of workers.
+// If workers is not positive, [runtime.GOMAXPROCS] workers are used.
+//
+// Matching tables are split into chunks of roughly equal size,
+// and fn is called once per chunk with the chunk handle, its entities and component columns.
+// Columns of optional components absent from a table are passed as nil slices (see [Filter8.Optional]).
+// The world remains locked until all chunks are processed, so no structural changes can occur.
+// Blocks until all chunks are processed.
+//
+// Use this instead of [Query8.Next] or [Query8.NextTable], on a fresh query.
+// The query is closed afterwards.
+//
+// fn is called concurrently from multiple goroutines, and must only access
+// the slices passed to it and other concurrency-safe state.
+// ⚠️ Do not set/replace any of the elements of the entities slice!
+//
+// Components are not marked as changed automatically.
+// Call [Chunk.MarkChanged] when modifying the component slices of a chunk (see [Filter8.Changed]).
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
+//
+// See [Query2.ParallelTables] for an example.
+func (q *Query8[A, B, C, D, E, F, G, H]) ParallelTables(workers int, fn func(chunk *Chunk, entities []Entity, a []A, b []B, c []C, d []D, e []E, f []F, g []G, h []H)) {
+	q.conditions.checkTableIteration()
+	lock := q.world.lockSafe()
+	defer q.world.unlockSafe(lock)
+
+	var tables []*table
+	for q.NextTable() {
+		tables = append(tables, q.table)
+	}
+	tick := q.world.storage.tick
+	runParallel(tables, workers, func(table *table, start, end uint32) {
+		chunk := Chunk{
+			columns: []*column{q.components[0].columns[table.id], q.components[1].columns[table.id], q.components[2].columns[table.id], q.components[3].columns[table.id], q.components[4].columns[table.id], q.components[5].columns[table.id], q.components[6].columns[table.id], q.components[7].columns[table.id]},
+			start:   start,
+			end:     end,
+			tick:    tick,
+		}
+		fn(&chunk, table.entities.data.Interface().([]Entity)[start:end:end],
+			columnSlice[A](q.components[0].columns[table.id], start, end),
+			columnSlice[B](q.components[1].columns[table.id], start, end),
+			columnSlice[C](q.components[2].columns[table.id], start, end),
+			columnSlice[D](q.components[3].columns[table.id], start, end),
+			columnSlice[E](q.components[4].columns[table.id], start, end),
+			columnSlice[F](q.components[5].columns[table.id], start, end),
+			columnSlice[G](q.components[6].columns[table.id], start, end),
+			columnSlice[H](q.components[7].columns[table.id], start, end))
+	})
+}
+
+// nextTableOrTracked advances the cursor to the next table in entity iteration,
+// or to the next matching entity for filters with per-entity conditions.
+// Kept out of [Query8.Next], so that it can be inlined.
+func (q *Query8[A, B, C, D, E, F, G, H]) nextTableOrTracked() bool {
+	if q.tracker != nil || q.conditions != nil {
+		return q.nextTracked()
+	}
+	return q.nextTableOrArchetype()
+}
+
+// nextTracked advances the cursor to the next entity that matches the filter's change detection and entity conditions.
+// The cursor's maximum index is kept at -1, so that [Query8.Next] always calls this for the next row.
+func (q *Query8[A, B, C, D, E, F, G, H]) nextTracked() bool {
+	if q.driver != nil {
+		return q.nextSparse()
+	}
+	for {
+		if q.table != nil && q.cursor.index+1 < uintptr(q.table.len) {
+			q.cursor.index++
+		} else if q.nextTableOrArchetype() {
+			q.cursor.maxIndex = -1
+		} else {
+			return false
+		}
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+}
+
+// nextSparse advances the cursor to the next matching entity of the driving entities.
+// These are the smallest set of entities required by the filter (see [entityConditions.driver]),
+// or the entities ordered by a cascade (see [storage.cascadeEntities]).
+func (q *Query8[A, B, C, D, E, F, G, H]) nextSparse() bool {
+	storage := &q.world.storage
+	maxIndex := int32(len(q.driver)) - 1
+	for q.cursor.archetype < maxIndex {
+		q.cursor.archetype++
+		entity := q.driver[q.cursor.archetype]
+		index := &storage.entities[entity.id]
+		table := &storage.tables[index.table]
+		if table != q.table {
+			if !q.filter.matches(&storage.archetypes[table.archetype].mask) ||
+				!table.Matches(q.relations) || !q.tracker.matchesTable(table) {
+				continue
+			}
+			q.setTable(0, table)
+			q.cursor.maxIndex = -1
+		}
+		q.cursor.index = uintptr(index.row)
+		if q.tracker.matchesRow(q.table, q.cursor.index) && q.conditions.matchesRow(q.table, q.cursor.index) {
+			if q.sparse != nil {
+				q.setSparse()
+			}
+			return true
+		}
+	}
+	q.Close()
+	return false
+}
+
 func (q *Query8[A, B, C, D, E, F, G, H]) nextTableOrArchetype() bool {
 	if q.cache != nil {
 		return q.nextTable(q.cache.tables.tables)
@@ -1525,7 +3397,12 @@ func (q *Query8[A, B, C, D, E, F, G, H]) nextTableOrArchetype() bool {
 
 func (q *Query8[A, B, C, D, E, F, G, H]) nextArchetype() bool {
 	q.tables = nil
-	archetypes := q.world.storage.componentIndex[q.rareComp]
+	var archetypes []archetypeID
+	if q.hasRareComp {
+		archetypes = q.world.storage.componentIndex[q.rareComp]
+	} else {
+		archetypes = q.world.storage.allArchetypes
+	}
 	maxArchIndex := int32(len(archetypes) - 1)
 	for q.cursor.archetype < maxArchIndex {
 		q.cursor.archetype++
@@ -1537,7 +3414,7 @@ func (q *Query8[A, B, C, D, E, F, G, H]) nextArchetype() bool {
 
 		if !archetype.HasRelations() {
 			table := &q.world.storage.tables[archetype.tables.tables[0]]
-			if table.len > 0 {
+			if table.len > 0 && q.tracker.matchesTable(table) {
 				q.setTable(0, table)
 				return true
 			}
@@ -1559,7 +3436,7 @@ func (q *Query8[A, B, C, D, E, F, G, H]) nextTable(tables []tableID) bool {
 	for q.cursor.table < maxTableIndex {
 		q.cursor.table++
 		table := &q.world.storage.tables[tables[q.cursor.table]]
-		if table.len == 0 || !table.Matches(q.relations) {
+		if table.len == 0 || !table.Matches(q.relations) || !q.tracker.matchesTable(table) {
 			continue
 		}
 		q.setTable(q.cursor.table, table)
@@ -1575,29 +3452,188 @@ func (q *Query8[A, B, C, D, E, F, G, H]) setTable(index int32, table *table) {
 	q.cursor.table = index
 	q.table = table
 	q.columnA = q.components[0].columns[q.table.id]
-	q.columnPtrA = q.columnA.pointer
-	q.itemSizeA = q.columnA.itemSize
+	if q.columnA != nil {
+		q.columnPtrA = q.columnA.pointer
+		q.itemSizeA = q.columnA.itemSize
+	} else {
+		q.columnPtrA = nil
+		q.itemSizeA = 0
+	}
 	q.columnB = q.components[1].columns[q.table.id]
-	q.columnPtrB = q.columnB.pointer
-	q.itemSizeB = q.columnB.itemSize
+	if q.columnB != nil {
+		q.columnPtrB = q.columnB.pointer
+		q.itemSizeB = q.columnB.itemSize
+	} else {
+		q.columnPtrB = nil
+		q.itemSizeB = 0
+	}
 	q.columnC = q.components[2].columns[q.table.id]
-	q.columnPtrC = q.columnC.pointer
-	q.itemSizeC = q.columnC.itemSize
+	if q.columnC != nil {
+		q.columnPtrC = q.columnC.pointer
+		q.itemSizeC = q.columnC.itemSize
+	} else {
+		q.columnPtrC = nil
+		q.itemSizeC = 0
+	}
 	q.columnD = q.components[3].columns[q.table.id]
-	q.columnPtrD = q.columnD.pointer
-	q.itemSizeD = q.columnD.itemSize
+	if q.columnD != nil {
+		q.columnPtrD = q.columnD.pointer
+		q.itemSizeD = q.columnD.itemSize
+	} else {
+		q.columnPtrD = nil
+		q.itemSizeD = 0
+	}
 	q.columnE = q.components[4].columns[q.table.id]
-	q.columnPtrE = q.columnE.pointer
-	q.itemSizeE = q.columnE.itemSize
+	if q.columnE != nil {
+		q.columnPtrE = q.columnE.pointer
+		q.itemSizeE = q.columnE.itemSize
+	} else {
+		q.columnPtrE = nil
+		q.itemSizeE = 0
+	}
 	q.columnF = q.components[5].columns[q.table.id]
-	q.columnPtrF = q.columnF.pointer
-	q.itemSizeF = q.columnF.itemSize
+	if q.columnF != nil {
+		q.columnPtrF = q.columnF.pointer
+		q.itemSizeF = q.columnF.itemSize
+	} else {
+		q.columnPtrF = nil
+		q.itemSizeF = 0
+	}
 	q.columnG = q.components[6].columns[q.table.id]
-	q.columnPtrG = q.columnG.pointer
-	q.itemSizeG = q.columnG.itemSize
+	if q.columnG != nil {
+		q.columnPtrG = q.columnG.pointer
+		q.itemSizeG = q.columnG.itemSize
+	} else {
+		q.columnPtrG = nil
+		q.itemSizeG = 0
+	}
 	q.columnH = q.components[7].columns[q.table.id]
-	q.columnPtrH = q.columnH.pointer
-	q.itemSizeH = q.columnH.itemSize
+	if q.columnH != nil {
+		q.columnPtrH = q.columnH.pointer
+		q.itemSizeH = q.columnH.itemSize
+	} else {
+		q.columnPtrH = nil
+		q.itemSizeH = 0
+	}
 	q.cursor.index = 0
 	q.cursor.maxIndex = int64(q.table.len - 1)
 }
+
+// GetOptional returns the queried components of the current entity, like [Query8.Get],
+// but with nil pointers for optional components the entity does not have (see [Filter8.Optional]).
+// Use this with entity iteration using [Query8.Next], for filters with optional components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query8[A, B, C, D, E, F, G, H]) GetOptional() (*A, *B, *C, *D, *E, *F, *G, *H) {
+	index := q.cursor.index
+	return optionalPtr[A](q.columnPtrA, index, q.itemSizeA),
+		optionalPtr[B](q.columnPtrB, index, q.itemSizeB),
+		optionalPtr[C](q.columnPtrC, index, q.itemSizeC),
+		optionalPtr[D](q.columnPtrD, index, q.itemSizeD),
+		optionalPtr[E](q.columnPtrE, index, q.itemSizeE),
+		optionalPtr[F](q.columnPtrF, index, q.itemSizeF),
+		optionalPtr[G](q.columnPtrG, index, q.itemSizeG),
+		optionalPtr[H](q.columnPtrH, index, q.itemSizeH)
+}
+
+// setSparse points the column pointers of components with sparse storage
+// to the current entity's components in their sparse sets, or to nil for absent components.
+// With an item size of zero, [Query8.Get] and [Query8.GetOptional] need no special handling for them.
+func (q *Query8[A, B, C, D, E, F, G, H]) setSparse() {
+	entity := q.table.GetEntity(q.cursor.index)
+	if set := q.sparse[0]; set != nil {
+		q.columnPtrA = set.Get(entity)
+	}
+	if set := q.sparse[1]; set != nil {
+		q.columnPtrB = set.Get(entity)
+	}
+	if set := q.sparse[2]; set != nil {
+		q.columnPtrC = set.Get(entity)
+	}
+	if set := q.sparse[3]; set != nil {
+		q.columnPtrD = set.Get(entity)
+	}
+	if set := q.sparse[4]; set != nil {
+		q.columnPtrE = set.Get(entity)
+	}
+	if set := q.sparse[5]; set != nil {
+		q.columnPtrF = set.Get(entity)
+	}
+	if set := q.sparse[6]; set != nil {
+		q.columnPtrG = set.Get(entity)
+	}
+	if set := q.sparse[7]; set != nil {
+		q.columnPtrH = set.Get(entity)
+	}
+}
+
+// GetMut returns the queried components of the current entity, like [Query8.Get],
+// and marks them as changed for change detection (see [Filter8.Changed]).
+// For filters with optional components, it returns nil pointers like [Query8.GetOptional].
+// Use this instead of [Query8.Get] when modifying the components.
+//
+// ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
+func (q *Query8[A, B, C, D, E, F, G, H]) GetMut() (*A, *B, *C, *D, *E, *F, *G, *H) {
+	tick := q.world.storage.tick
+	row := q.cursor.index
+	if q.columnA != nil {
+		q.columnA.setChanged(row, tick)
+	}
+	if q.columnB != nil {
+		q.columnB.setChanged(row, tick)
+	}
+	if q.columnC != nil {
+		q.columnC.setChanged(row, tick)
+	}
+	if q.columnD != nil {
+		q.columnD.setChanged(row, tick)
+	}
+	if q.columnE != nil {
+		q.columnE.setChanged(row, tick)
+	}
+	if q.columnF != nil {
+		q.columnF.setChanged(row, tick)
+	}
+	if q.columnG != nil {
+		q.columnG.setChanged(row, tick)
+	}
+	if q.columnH != nil {
+		q.columnH.setChanged(row, tick)
+	}
+	if q.hasOptional {
+		return q.GetOptional()
+	}
+	return q.Get()
+}
+
+// MarkChanged marks the queried components of the entire current table as changed,
+// for change detection (see [Filter8.Changed]).
+// Use this with table-based iteration using [Query8.NextTable],
+// when modifying the columns obtained via [Query8.GetColumns].
+func (q *Query8[A, B, C, D, E, F, G, H]) MarkChanged() {
+	tick := q.world.storage.tick
+	if q.columnA != nil {
+		q.columnA.markAll(tick)
+	}
+	if q.columnB != nil {
+		q.columnB.markAll(tick)
+	}
+	if q.columnC != nil {
+		q.columnC.markAll(tick)
+	}
+	if q.columnD != nil {
+		q.columnD.markAll(tick)
+	}
+	if q.columnE != nil {
+		q.columnE.markAll(tick)
+	}
+	if q.columnF != nil {
+		q.columnF.markAll(tick)
+	}
+	if q.columnG != nil {
+		q.columnG.markAll(tick)
+	}
+	if q.columnH != nil {
+		q.columnH.markAll(tick)
+	}
+}
@@ -2,7 +2,10 @@ package ecs
 
 // Code generated by go generate; DO NOT EDIT.
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestQuery1(t *testing.T) {
 	n := 10
@@ -83,7 +86,24 @@ func TestQuery1(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter1[CompA](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery1Tables(t *testing.T) {
@@ -387,6 +407,224 @@ func TestQuery1Registered(t *testing.T) {
 	})
 }
 
+func TestQuery1Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap1[CompA](w)
+
+	e := mapper.NewEntity(&CompA{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{})
+	}
+
+	count := func(filter *Filter1[CompA]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter1[CompA](w).Changed(C[CompA]())
+	added := NewFilter1[CompA](w).Added(C[CompA]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter1[CompA](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_ = query.GetMut()
+		} else {
+			_ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter1[CompA](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter1[CompA](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter1[CompA](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter1[CompA](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery1Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap1[CompA](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{})
+	}
+
+	filter := NewFilter1[CompA](w).Optional(C[CompA]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a := query.GetMut()
+		nils := []bool{a == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter1[CompA](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery1Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter1[CompA]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter1[CompA](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a := query.Get()
+		xs := []float64{a.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter1[CompA](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter1[CompA](w).Without(C[Label]())))
+
+	filter := NewFilter1[CompA](w).Optional(C[CompA]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a := query.GetOptional()
+		nils := []bool{a == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter1[CompA](w).Batch()
+	})
+}
+
+func TestQuery1Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap1[ChildOf](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter1[ChildOf](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap1[Likes](w)
+	l1 := likesMap.NewEntity(&Likes{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter1[Likes](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter1[Likes](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter1[Likes](w).Batch(RelIdx(0, l2))
+	})
+}
+
 func TestQuery2(t *testing.T) {
 	n := 10
 	w := NewWorld(4)
@@ -466,7 +704,24 @@ func TestQuery2(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter2[CompA, CompB](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery2Tables(t *testing.T) {
@@ -773,6 +1028,227 @@ func TestQuery2Registered(t *testing.T) {
 	})
 }
 
+func TestQuery2Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap2[CompA, CompB](w)
+
+	e := mapper.NewEntity(&CompA{}, &CompB{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{})
+	}
+
+	count := func(filter *Filter2[CompA, CompB]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter2[CompA, CompB](w).Changed(C[CompA](), C[CompB]())
+	added := NewFilter2[CompA, CompB](w).Added(C[CompA](), C[CompB]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter2[CompA, CompB](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _ = query.GetMut()
+		} else {
+			_, _ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter2[CompA, CompB](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{}, &CompB{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter2[CompA, CompB](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter2[CompA, CompB](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter2[CompA, CompB](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery2Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap2[CompA, CompB](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{})
+	}
+
+	filter := NewFilter2[CompA, CompB](w).Optional(C[CompA](), C[CompB]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b := query.GetMut()
+		nils := []bool{a == nil, b == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter2[CompA, CompB](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery2Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter2[CompA, CompB]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter2[CompA, CompB](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a, b := query.Get()
+		xs := []float64{a.X, b.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter2[CompA, CompB](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter2[CompA, CompB](w).Without(C[Label]())))
+
+	filter := NewFilter2[CompA, CompB](w).Optional(C[CompA](), C[CompB]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b := query.GetOptional()
+		nils := []bool{a == nil, b == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter2[CompA, CompB](w).Batch()
+	})
+}
+
+func TestQuery2Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap2[ChildOf, CompB](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter2[ChildOf, CompB](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap2[Likes, CompB](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter2[Likes, CompB](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter2[Likes, CompB](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter2[Likes, CompB](w).Batch(RelIdx(0, l2))
+	})
+}
+
 func TestQuery3(t *testing.T) {
 	n := 10
 	w := NewWorld(4)
@@ -852,7 +1328,24 @@ func TestQuery3(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter3[CompA, CompB, CompC](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery3Tables(t *testing.T) {
@@ -1162,74 +1655,298 @@ func TestQuery3Registered(t *testing.T) {
 	})
 }
 
-func TestQuery4(t *testing.T) {
-	n := 10
+func TestQuery3Changed(t *testing.T) {
 	w := NewWorld(4)
+	mapper := NewMap3[CompA, CompB, CompC](w)
 
-	posMapper := NewMap[Position](w)
-	mapper := NewMap4[CompA, CompB, CompC, CompD](w)
-	compMapper := NewMap[CompA](w)
-	posVelMapper := NewMap2[Position, Velocity](w)
+	e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{})
+	}
 
-	for range n {
-		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+	count := func(filter *Filter3[CompA, CompB, CompC]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
 
-		e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
-		compMapper.Remove(e)
+	changed := NewFilter3[CompA, CompB, CompC](w).Changed(C[CompA](), C[CompB](), C[CompC]())
+	added := NewFilter3[CompA, CompB, CompC](w).Added(C[CompA](), C[CompB](), C[CompC]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
 
-		e = posMapper.NewEntity(&Position{})
-		mapper.Add(e, &CompA{}, &CompB{}, &CompC{}, &CompD{})
+	query := NewFilter3[CompA, CompB, CompC](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _, _ = query.GetMut()
+		} else {
+			_, _, _ = query.Get()
+		}
 	}
+	expectEqual(t, 1, count(changed))
 
-	w.RemoveEntity(posVelMapper.NewEntityFn(nil))
+	query = NewFilter3[CompA, CompB, CompC](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
 
-	// normal filter
-	var filter *Filter4[CompA, CompB, CompC, CompD]
-	filter = filter.New(w).Without()
-	query := filter.Query()
-	expectEqual(t, 2*n, query.Count())
+	_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
 
-	expectPanicsWithValue(t, "can't modify a filter that was already queried", func() {
-		filter.With(C[Position]())
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter3[CompA, CompB, CompC](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter3[CompA, CompB, CompC](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter3[CompA, CompB, CompC](w).Optional(C[CompA]()).Added(C[CompA]())
 	})
+}
 
-	cnt := 0
-	for query.Next() {
-		_ = query.Entity()
-		_, _, _, _ = query.Get()
-		cnt++
-	}
-	expectEqual(t, 2*n, cnt)
-	query.Close() // should not panic anymore
+func TestQuery3Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap3[CompA, CompB, CompC](w)
 
-	// filter without
-	filter = NewFilter4[CompA, CompB, CompC, CompD](w).Without(C[Position]())
-	query = filter.Query()
-	expectEqual(t, n, query.Count())
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{})
+	}
 
-	cnt = 0
+	filter := NewFilter3[CompA, CompB, CompC](w).Optional(C[CompA](), C[CompB](), C[CompC]())
+	query := filter.Query()
+	cnt, present := 0, 0
 	for query.Next() {
-		_ = query.Entity()
-		_, _, _, _ = query.Get()
+		a, b, c := query.GetMut()
+		nils := []bool{a == nil, b == nil, c == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
 		cnt++
 	}
-	expectEqual(t, n, cnt)
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
 
-	// filter exclusive
-	filter = NewFilter4[CompA, CompB, CompC, CompD](w).Exclusive()
 	query = filter.Query()
-	expectEqual(t, n, query.Count())
-
-	cnt = 0
-	for query.Next() {
-		_ = query.Entity()
-		_, _, _, _ = query.Get()
-		cnt++
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _, _ = query.GetColumns()
 	}
-	expectEqual(t, n, cnt)
 
-	// filter with
-	filter = NewFilter4[CompA, CompB, CompC, CompD](w).With(C[Position]())
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB, _ []CompC) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter3[CompA, CompB, CompC](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery3Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompC]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+	mapC := NewMap[CompC](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+			mapC.Add(e, &CompC{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter3[CompA, CompB, CompC]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter3[CompA, CompB, CompC](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a, b, c := query.Get()
+		xs := []float64{a.X, b.X, c.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter3[CompA, CompB, CompC](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter3[CompA, CompB, CompC](w).Without(C[Label]())))
+
+	filter := NewFilter3[CompA, CompB, CompC](w).Optional(C[CompA](), C[CompB](), C[CompC]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c := query.GetOptional()
+		nils := []bool{a == nil, b == nil, c == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter3[CompA, CompB, CompC](w).Batch()
+	})
+}
+
+func TestQuery3Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap3[ChildOf, CompB, CompC](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter3[ChildOf, CompB, CompC](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap3[Likes, CompB, CompC](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter3[Likes, CompB, CompC](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter3[Likes, CompB, CompC](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter3[Likes, CompB, CompC](w).Batch(RelIdx(0, l2))
+	})
+}
+
+func TestQuery4(t *testing.T) {
+	n := 10
+	w := NewWorld(4)
+
+	posMapper := NewMap[Position](w)
+	mapper := NewMap4[CompA, CompB, CompC, CompD](w)
+	compMapper := NewMap[CompA](w)
+	posVelMapper := NewMap2[Position, Velocity](w)
+
+	for range n {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+
+		e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+		compMapper.Remove(e)
+
+		e = posMapper.NewEntity(&Position{})
+		mapper.Add(e, &CompA{}, &CompB{}, &CompC{}, &CompD{})
+	}
+
+	w.RemoveEntity(posVelMapper.NewEntityFn(nil))
+
+	// normal filter
+	var filter *Filter4[CompA, CompB, CompC, CompD]
+	filter = filter.New(w).Without()
+	query := filter.Query()
+	expectEqual(t, 2*n, query.Count())
+
+	expectPanicsWithValue(t, "can't modify a filter that was already queried", func() {
+		filter.With(C[Position]())
+	})
+
+	cnt := 0
+	for query.Next() {
+		_ = query.Entity()
+		_, _, _, _ = query.Get()
+		cnt++
+	}
+	expectEqual(t, 2*n, cnt)
+	query.Close() // should not panic anymore
+
+	// filter without
+	filter = NewFilter4[CompA, CompB, CompC, CompD](w).Without(C[Position]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		_ = query.Entity()
+		_, _, _, _ = query.Get()
+		cnt++
+	}
+	expectEqual(t, n, cnt)
+
+	// filter exclusive
+	filter = NewFilter4[CompA, CompB, CompC, CompD](w).Exclusive()
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		_ = query.Entity()
+		_, _, _, _ = query.Get()
+		cnt++
+	}
+	expectEqual(t, n, cnt)
+
+	// filter with
+	filter = NewFilter4[CompA, CompB, CompC, CompD](w).With(C[Position]())
 	query = filter.Query()
 	expectEqual(t, n, query.Count())
 
@@ -1241,7 +1958,24 @@ func TestQuery4(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter4[CompA, CompB, CompC, CompD](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery4Tables(t *testing.T) {
@@ -1554,6 +2288,233 @@ func TestQuery4Registered(t *testing.T) {
 	})
 }
 
+func TestQuery4Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap4[CompA, CompB, CompC, CompD](w)
+
+	e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+	}
+
+	count := func(filter *Filter4[CompA, CompB, CompC, CompD]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter4[CompA, CompB, CompC, CompD](w).Changed(C[CompA](), C[CompB](), C[CompC](), C[CompD]())
+	added := NewFilter4[CompA, CompB, CompC, CompD](w).Added(C[CompA](), C[CompB](), C[CompC](), C[CompD]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter4[CompA, CompB, CompC, CompD](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _, _, _ = query.GetMut()
+		} else {
+			_, _, _, _ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter4[CompA, CompB, CompC, CompD](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter4[CompA, CompB, CompC, CompD](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter4[CompA, CompB, CompC, CompD](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter4[CompA, CompB, CompC, CompD](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery4Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap4[CompA, CompB, CompC, CompD](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{})
+	}
+
+	filter := NewFilter4[CompA, CompB, CompC, CompD](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d := query.GetMut()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _, _, _ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB, _ []CompC, _ []CompD) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter4[CompA, CompB, CompC, CompD](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery4Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompC]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompD]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+	mapC := NewMap[CompC](w)
+	mapD := NewMap[CompD](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+			mapC.Add(e, &CompC{X: float64(i)})
+			mapD.Add(e, &CompD{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter4[CompA, CompB, CompC, CompD]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter4[CompA, CompB, CompC, CompD](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a, b, c, d := query.Get()
+		xs := []float64{a.X, b.X, c.X, d.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter4[CompA, CompB, CompC, CompD](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter4[CompA, CompB, CompC, CompD](w).Without(C[Label]())))
+
+	filter := NewFilter4[CompA, CompB, CompC, CompD](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d := query.GetOptional()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter4[CompA, CompB, CompC, CompD](w).Batch()
+	})
+}
+
+func TestQuery4Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap4[ChildOf, CompB, CompC, CompD](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter4[ChildOf, CompB, CompC, CompD](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap4[Likes, CompB, CompC, CompD](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter4[Likes, CompB, CompC, CompD](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter4[Likes, CompB, CompC, CompD](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter4[Likes, CompB, CompC, CompD](w).Batch(RelIdx(0, l2))
+	})
+}
+
 func TestQuery5(t *testing.T) {
 	n := 10
 	w := NewWorld(4)
@@ -1633,7 +2594,24 @@ func TestQuery5(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter5[CompA, CompB, CompC, CompD, CompE](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery5Tables(t *testing.T) {
@@ -1949,6 +2927,236 @@ func TestQuery5Registered(t *testing.T) {
 	})
 }
 
+func TestQuery5Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap5[CompA, CompB, CompC, CompD, CompE](w)
+
+	e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	}
+
+	count := func(filter *Filter5[CompA, CompB, CompC, CompD, CompE]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Changed(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE]())
+	added := NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Added(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _, _, _, _ = query.GetMut()
+		} else {
+			_, _, _, _, _ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery5Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap5[CompA, CompB, CompC, CompD, CompE](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{})
+	}
+
+	filter := NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e := query.GetMut()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _, _, _, _ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB, _ []CompC, _ []CompD, _ []CompE) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery5Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompC]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompD]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompE]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+	mapC := NewMap[CompC](w)
+	mapD := NewMap[CompD](w)
+	mapE := NewMap[CompE](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+			mapC.Add(e, &CompC{X: float64(i)})
+			mapD.Add(e, &CompD{X: float64(i)})
+			mapE.Add(e, &CompE{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter5[CompA, CompB, CompC, CompD, CompE]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter5[CompA, CompB, CompC, CompD, CompE](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a, b, c, d, e := query.Get()
+		xs := []float64{a.X, b.X, c.X, d.X, e.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter5[CompA, CompB, CompC, CompD, CompE](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Without(C[Label]())))
+
+	filter := NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e := query.GetOptional()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter5[CompA, CompB, CompC, CompD, CompE](w).Batch()
+	})
+}
+
+func TestQuery5Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap5[ChildOf, CompB, CompC, CompD, CompE](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter5[ChildOf, CompB, CompC, CompD, CompE](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap5[Likes, CompB, CompC, CompD, CompE](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter5[Likes, CompB, CompC, CompD, CompE](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter5[Likes, CompB, CompC, CompD, CompE](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter5[Likes, CompB, CompC, CompD, CompE](w).Batch(RelIdx(0, l2))
+	})
+}
+
 func TestQuery6(t *testing.T) {
 	n := 10
 	w := NewWorld(4)
@@ -2028,7 +3236,24 @@ func TestQuery6(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery6Tables(t *testing.T) {
@@ -2291,59 +3516,292 @@ func TestQuery6Registered(t *testing.T) {
 	e = query.EntityAt(count - 1)
 	expectEqual(t, 24, int(e.ID()))
 
-	cnt = 0
+	cnt = 0
+	for query.Next() {
+		_ = query.Entity()
+		_, _, _, _, _, _ = query.Get()
+		expectEqual(t, parent2, query.GetRelation(0))
+		cnt++
+	}
+	expectEqual(t, n, cnt)
+
+	batch = filter.Batch()
+	expectEqual(t, filter.filter, *batch.filter)
+	expectEqual(t, 0, len(batch.relations))
+
+	filter.Unregister()
+
+	// relation filter 2
+	filter = NewFilter6[ChildOf, CompB, CompC, CompD, CompE, CompF](w)
+
+	// dummy query
+	query = filter.Query(RelIdx(0, parent1))
+	query.Close()
+
+	filter = filter.Register()
+	query = filter.Query(RelIdx(0, parent2))
+	count = query.Count()
+	expectEqual(t, n, count)
+
+	e = query.EntityAt(count - 1)
+	expectEqual(t, 24, int(e.ID()))
+
+	cnt = 0
+	for query.Next() {
+		_ = query.Entity()
+		_, _, _, _, _, _ = query.Get()
+		expectEqual(t, parent2, query.GetRelation(0))
+		cnt++
+	}
+	expectEqual(t, n, cnt)
+
+	batch = filter.Batch(RelIdx(0, parent2))
+	expectEqual(t, filter.filter, *batch.filter)
+	expectEqual(t, 1, len(batch.relations))
+
+	expectPanics(t, func() {
+		filter.Exclusive()
+	})
+	expectPanics(t, func() {
+		filter.Register()
+	})
+
+	filter.Unregister()
+	expectPanics(t, func() {
+		filter.Unregister()
+	})
+}
+
+func TestQuery6Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap6[CompA, CompB, CompC, CompD, CompE, CompF](w)
+
+	e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	}
+
+	count := func(filter *Filter6[CompA, CompB, CompC, CompD, CompE, CompF]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Changed(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF]())
+	added := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Added(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _, _, _, _, _ = query.GetMut()
+		} else {
+			_, _, _, _, _, _ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery6Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap6[CompA, CompB, CompC, CompD, CompE, CompF](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{})
+	}
+
+	filter := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e, f := query.GetMut()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil, f == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _, _, _, _, _ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB, _ []CompC, _ []CompD, _ []CompE, _ []CompF) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery6Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompC]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompD]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompE]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompF]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+	mapC := NewMap[CompC](w)
+	mapD := NewMap[CompD](w)
+	mapE := NewMap[CompE](w)
+	mapF := NewMap[CompF](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+			mapC.Add(e, &CompC{X: float64(i)})
+			mapD.Add(e, &CompD{X: float64(i)})
+			mapE.Add(e, &CompE{X: float64(i)})
+			mapF.Add(e, &CompF{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter6[CompA, CompB, CompC, CompD, CompE, CompF]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
 	for query.Next() {
-		_ = query.Entity()
-		_, _, _, _, _, _ = query.Get()
-		expectEqual(t, parent2, query.GetRelation(0))
+		a, b, c, d, e, f := query.Get()
+		xs := []float64{a.X, b.X, c.X, d.X, e.X, f.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
 		cnt++
 	}
-	expectEqual(t, n, cnt)
-
-	batch = filter.Batch()
-	expectEqual(t, filter.filter, *batch.filter)
-	expectEqual(t, 0, len(batch.relations))
+	expectEqual(t, 2, cnt)
 
-	filter.Unregister()
+	expectEqual(t, 2, count(NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Without(C[Label]())))
 
-	// relation filter 2
-	filter = NewFilter6[ChildOf, CompB, CompC, CompD, CompE, CompF](w)
+	filter := NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e, f := query.GetOptional()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil, f == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
 
-	// dummy query
-	query = filter.Query(RelIdx(0, parent1))
-	query.Close()
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter6[CompA, CompB, CompC, CompD, CompE, CompF](w).Batch()
+	})
+}
 
-	filter = filter.Register()
-	query = filter.Query(RelIdx(0, parent2))
-	count = query.Count()
-	expectEqual(t, n, count)
+func TestQuery6Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap6[ChildOf, CompB, CompC, CompD, CompE, CompF](w)
+	childMap := NewMap[ChildOf](w)
 
-	e = query.EntityAt(count - 1)
-	expectEqual(t, 24, int(e.ID()))
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
 
-	cnt = 0
+	query := NewFilter6[ChildOf, CompB, CompC, CompD, CompE, CompF](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
 	for query.Next() {
-		_ = query.Entity()
-		_, _, _, _, _, _ = query.Get()
-		expectEqual(t, parent2, query.GetRelation(0))
-		cnt++
+		found = append(found, query.Entity())
 	}
-	expectEqual(t, n, cnt)
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
 
-	batch = filter.Batch(RelIdx(0, parent2))
-	expectEqual(t, filter.filter, *batch.filter)
-	expectEqual(t, 1, len(batch.relations))
+	likesMap := NewMap6[Likes, CompB, CompC, CompD, CompE, CompF](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
 
-	expectPanics(t, func() {
-		filter.Exclusive()
-	})
-	expectPanics(t, func() {
-		filter.Register()
-	})
+	filter := NewFilter6[Likes, CompB, CompC, CompD, CompE, CompF](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
 
-	filter.Unregister()
-	expectPanics(t, func() {
-		filter.Unregister()
+	query2 = NewFilter6[Likes, CompB, CompC, CompD, CompE, CompF](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter6[Likes, CompB, CompC, CompD, CompE, CompF](w).Batch(RelIdx(0, l2))
 	})
 }
 
@@ -2426,7 +3884,24 @@ func TestQuery7(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery7Tables(t *testing.T) {
@@ -2748,6 +4223,242 @@ func TestQuery7Registered(t *testing.T) {
 	})
 }
 
+func TestQuery7Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w)
+
+	e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	}
+
+	count := func(filter *Filter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Changed(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG]())
+	added := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Added(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _, _, _, _, _, _ = query.GetMut()
+		} else {
+			_, _, _, _, _, _, _ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery7Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{})
+	}
+
+	filter := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e, f, g := query.GetMut()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil, f == nil, g == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _, _, _, _, _, _ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB, _ []CompC, _ []CompD, _ []CompE, _ []CompF, _ []CompG) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery7Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompC]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompD]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompE]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompF]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompG]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+	mapC := NewMap[CompC](w)
+	mapD := NewMap[CompD](w)
+	mapE := NewMap[CompE](w)
+	mapF := NewMap[CompF](w)
+	mapG := NewMap[CompG](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+			mapC.Add(e, &CompC{X: float64(i)})
+			mapD.Add(e, &CompD{X: float64(i)})
+			mapE.Add(e, &CompE{X: float64(i)})
+			mapF.Add(e, &CompF{X: float64(i)})
+			mapG.Add(e, &CompG{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a, b, c, d, e, f, g := query.Get()
+		xs := []float64{a.X, b.X, c.X, d.X, e.X, f.X, g.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Without(C[Label]())))
+
+	filter := NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e, f, g := query.GetOptional()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil, f == nil, g == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter7[CompA, CompB, CompC, CompD, CompE, CompF, CompG](w).Batch()
+	})
+}
+
+func TestQuery7Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap7[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter7[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap7[Likes, CompB, CompC, CompD, CompE, CompF, CompG](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter7[Likes, CompB, CompC, CompD, CompE, CompF, CompG](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter7[Likes, CompB, CompC, CompD, CompE, CompF, CompG](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter7[Likes, CompB, CompC, CompD, CompE, CompF, CompG](w).Batch(RelIdx(0, l2))
+	})
+}
+
 func TestQuery8(t *testing.T) {
 	n := 10
 	w := NewWorld(4)
@@ -2827,7 +4538,24 @@ func TestQuery8(t *testing.T) {
 	}
 	expectEqual(t, n, cnt)
 
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[CompA, Heading](w).NewEntity(&CompA{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+	query.Close()
+
 	_ = filter.Batch()
+
+	// filter any of
+	filter = NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).AnyOf(C[Position](), C[Velocity]())
+	query = filter.Query()
+	expectEqual(t, n, query.Count())
+
+	cnt = 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, n, cnt)
 }
 
 func TestQuery8Tables(t *testing.T) {
@@ -3152,6 +4880,245 @@ func TestQuery8Registered(t *testing.T) {
 	})
 }
 
+func TestQuery8Changed(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w)
+
+	e := mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	for range 4 {
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	}
+
+	count := func(filter *Filter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	changed := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Changed(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG](), C[CompH]())
+	added := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Added(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG](), C[CompH]())
+	since := w.AdvanceTick()
+	changed.Since(since)
+	added.Since(since)
+	expectEqual(t, 0, count(changed))
+	expectEqual(t, 0, count(added))
+
+	query := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Query()
+	for query.Next() {
+		if query.Entity() == e {
+			_, _, _, _, _, _, _, _ = query.GetMut()
+		} else {
+			_, _, _, _, _, _, _, _ = query.Get()
+		}
+	}
+	expectEqual(t, 1, count(changed))
+
+	query = NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Query()
+	for query.NextTable() {
+		query.MarkChanged()
+	}
+	expectEqual(t, 5, count(changed))
+	expectEqual(t, 0, count(added))
+
+	_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	expectEqual(t, 6, count(changed))
+	expectEqual(t, 1, count(added))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Since(0)
+	})
+	msg := fmt.Sprintf("component with ID %d can't be optional and used for change detection", ComponentID[CompA](w).id)
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Changed(C[CompA]()).Optional(C[CompA]())
+	})
+	expectPanicsWithValue(t, msg, func() {
+		NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Optional(C[CompA]()).Added(C[CompA]())
+	})
+}
+
+func TestQuery8Optional(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w)
+
+	for range 3 {
+		_ = posMap.NewEntity(&Position{})
+		_ = mapper.NewEntity(&CompA{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{})
+	}
+
+	filter := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG](), C[CompH]())
+	query := filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e, f, g, h := query.GetMut()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil, f == nil, g == nil, h == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 6, cnt)
+	expectEqual(t, 3, present)
+
+	query = filter.Query()
+	expectEqual(t, 6, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(5)))
+	for query.NextTable() {
+		query.MarkChanged()
+		_, _, _, _, _, _, _, _ = query.GetColumns()
+	}
+
+	query = filter.Query()
+	query.ParallelTables(2, func(chunk *Chunk, _ []Entity, _ []CompA, _ []CompB, _ []CompC, _ []CompD, _ []CompE, _ []CompF, _ []CompG, _ []CompH) {
+		chunk.MarkChanged()
+	})
+
+	expectPanicsWithValue(t, fmt.Sprintf("optional component with ID %d is not in the filter's parameters", ComponentID[Position](w).id), func() {
+		NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Optional(C[Position]())
+	})
+}
+
+func TestQuery8Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[CompA]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompB]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompC]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompD]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompE]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompF]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompG]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[CompH]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+	mapA := NewMap[CompA](w)
+	mapB := NewMap[CompB](w)
+	mapC := NewMap[CompC](w)
+	mapD := NewMap[CompD](w)
+	mapE := NewMap[CompE](w)
+	mapF := NewMap[CompF](w)
+	mapG := NewMap[CompG](w)
+	mapH := NewMap[CompH](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			mapA.Add(e, &CompA{X: float64(i)})
+			mapB.Add(e, &CompB{X: float64(i)})
+			mapC.Add(e, &CompC{X: float64(i)})
+			mapD.Add(e, &CompD{X: float64(i)})
+			mapE.Add(e, &CompE{X: float64(i)})
+			mapF.Add(e, &CompF{X: float64(i)})
+			mapG.Add(e, &CompG{X: float64(i)})
+			mapH.Add(e, &CompH{X: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			headMap.Add(e, &Heading{})
+		}
+	}
+
+	count := func(filter *Filter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH]) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	query := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).With(C[Position]()).Without(C[Heading]()).Query()
+	cnt := 0
+	for query.Next() {
+		a, b, c, d, e, f, g, h := query.Get()
+		xs := []float64{a.X, b.X, c.X, d.X, e.X, f.X, g.X, h.X}
+		for _, x := range xs {
+			expectEqual(t, xs[0], x)
+		}
+		expectTrue(t, int(xs[0])%4 == 2)
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	expectEqual(t, 2, count(NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).With(C[Label]())))
+	expectEqual(t, 3, count(NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Without(C[Label]())))
+
+	filter := NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Optional(C[CompA](), C[CompB](), C[CompC](), C[CompD](), C[CompE](), C[CompF](), C[CompG](), C[CompH]()).With(C[Position]())
+	query = filter.Query()
+	cnt, present := 0, 0
+	for query.Next() {
+		a, b, c, d, e, f, g, h := query.GetOptional()
+		nils := []bool{a == nil, b == nil, c == nil, d == nil, e == nil, f == nil, g == nil, h == nil}
+		for _, isNil := range nils {
+			expectEqual(t, nils[0], isNil)
+		}
+		if !nils[0] {
+			present++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, present)
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter8[CompA, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Batch()
+	})
+}
+
+func TestQuery8Cascade(t *testing.T) {
+	w := NewWorld(4)
+	mapper := NewMap8[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, Entity{}))
+	e2 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, Entity{}))
+	e3 := mapper.NewEntity(&ChildOf{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, Entity{}))
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter8[ChildOf, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap8[Likes, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w)
+	l1 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, Entity{}))
+	l2 := likesMap.NewEntity(&Likes{}, &CompB{}, &CompC{}, &CompD{}, &CompE{}, &CompF{}, &CompG{}, &CompH{}, RelIdx(0, Entity{}))
+	NewMap[Likes](w).SetRelation(l1, l2)
+
+	filter := NewFilter8[Likes, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Cascade(C[Likes]())
+	query2 := filter.Query()
+	found = []Entity{}
+	targets := []Entity{}
+	for query2.Next() {
+		found = append(found, query2.Entity())
+		targets = append(targets, query2.GetRelation(0))
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+	var noTarget Entity
+	expectSlicesEqual(t, []Entity{noTarget, l2}, targets)
+
+	query2 = NewFilter8[Likes, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Query(RelIdx(0, l2))
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter8[Likes, CompB, CompC, CompD, CompE, CompF, CompG, CompH](w).Batch(RelIdx(0, l2))
+	})
+}
+
 func TestQuery0(t *testing.T) {
 	n := 10
 	w := NewWorld(4)
@@ -3171,6 +5138,7 @@ func TestQuery0(t *testing.T) {
 	filter = filter.New(w).Without()
 	query := filter.Query()
 	expectEqual(t, 2*n, query.Count())
+	expectTrue(t, w.Alive(query.EntityAt(2*n-1)))
 
 	expectPanicsWithValue(t, "can't modify a filter that was already queried", func() {
 		filter.With(C[Position]())
@@ -3219,6 +5187,94 @@ func TestQuery0(t *testing.T) {
 		cnt++
 	}
 	expectEqual(t, n, cnt)
+
+	// a new archetype refreshes the filter's rare component
+	_ = NewMap2[Position, Heading](w).NewEntity(&Position{}, &Heading{})
+	query = filter.Query()
+	expectEqual(t, n+1, query.Count())
+	query.Close()
+}
+
+func TestQuery0Sparse(t *testing.T) {
+	w := NewWorld(4)
+	RegisterComponent(w, ComponentOptions[Heading]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		if i%2 == 0 {
+			headMap.Add(e, &Heading{H: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+		if i%4 == 0 {
+			velMap.Add(e, &Velocity{})
+		}
+	}
+
+	count := func(filter *Filter0) int {
+		query := filter.Query()
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+
+	expectEqual(t, 2, count(NewFilter0(w).With(C[Heading]()).Without(C[Velocity]())))
+	expectEqual(t, 2, count(NewFilter0(w).With(C[Heading](), C[Label]())))
+	expectEqual(t, 5, count(NewFilter0(w).With(C[Position]()).Without(C[Heading]())))
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components", func() {
+		NewFilter0(w).With(C[Heading]()).Batch()
+	})
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter0(w).Since(0)
+	})
+}
+
+func TestQuery0Cascade(t *testing.T) {
+	w := NewWorld(4)
+	childMap := NewMap[ChildOf](w)
+
+	e1 := childMap.NewEntity(&ChildOf{}, Entity{})
+	e2 := childMap.NewEntity(&ChildOf{}, Entity{})
+	e3 := childMap.NewEntity(&ChildOf{}, Entity{})
+	childMap.SetRelation(e1, e2)
+	childMap.SetRelation(e2, e3)
+
+	query := NewFilter0(w).Cascade(C[ChildOf]()).Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{e3, e2, e1}, found)
+
+	likesMap := NewMap[Likes](w)
+	l1 := likesMap.NewEntity(&Likes{}, Entity{})
+	l2 := likesMap.NewEntity(&Likes{}, Entity{})
+	likesMap.SetRelation(l1, l2)
+
+	query = NewFilter0(w).With(C[Likes]()).Cascade(C[Likes]()).Query()
+	found = []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{l2, l1}, found)
+
+	query = NewFilter0(w).With(C[Likes]()).Query(Rel[Likes](l2))
+	expectEqual(t, 1, query.Count())
+	query.Close()
+
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets", func() {
+		NewFilter0(w).With(C[Likes]()).Batch(Rel[Likes](l2))
+	})
 }
 
 func TestQuery0Tables(t *testing.T) {
@@ -6,6 +6,9 @@ import "unsafe"
 
 // Next advances the query's cursor to the next entity.
 func (q *UnsafeQuery) Next() bool {
+	if q.conditions != nil {
+		return q.nextTracked()
+	}
 	if int64(q.cursor.index) < q.cursor.maxIndex {
 		q.cursor.index++
 		return true
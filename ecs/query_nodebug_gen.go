@@ -14,13 +14,17 @@ func (q *Query0) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query0.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query0) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -46,13 +50,17 @@ func (q *Query1[A]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query1.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query1[A]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -73,6 +81,8 @@ func (q *Query1[A]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query1.Next].
 //
+// For filters with optional components (see [Filter1.Optional]), use [Query1.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query1[A]) Get() *A {
 	index := q.cursor.index
@@ -81,8 +91,10 @@ func (q *Query1[A]) Get() *A {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query1.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter1.Optional]).
 func (q *Query1[A]) GetColumns() []A {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -93,13 +105,17 @@ func (q *Query2[A, B]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query2.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query2[A, B]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -120,6 +136,8 @@ func (q *Query2[A, B]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query2.Next].
 //
+// For filters with optional components (see [Filter2.Optional]), use [Query2.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query2[A, B]) Get() (*A, *B) {
 	index := q.cursor.index
@@ -129,9 +147,11 @@ func (q *Query2[A, B]) Get() (*A, *B) {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query2.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter2.Optional]).
 func (q *Query2[A, B]) GetColumns() ([]A, []B) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -142,13 +162,17 @@ func (q *Query3[A, B, C]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query3.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query3[A, B, C]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -169,6 +193,8 @@ func (q *Query3[A, B, C]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query3.Next].
 //
+// For filters with optional components (see [Filter3.Optional]), use [Query3.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query3[A, B, C]) Get() (*A, *B, *C) {
 	index := q.cursor.index
@@ -179,10 +205,12 @@ func (q *Query3[A, B, C]) Get() (*A, *B, *C) {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query3.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter3.Optional]).
 func (q *Query3[A, B, C]) GetColumns() ([]A, []B, []C) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len],
-		q.columnC.data.Interface().([]C)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len),
+		columnSlice[C](q.columnC, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -193,13 +221,17 @@ func (q *Query4[A, B, C, D]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query4.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query4[A, B, C, D]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -220,6 +252,8 @@ func (q *Query4[A, B, C, D]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query4.Next].
 //
+// For filters with optional components (see [Filter4.Optional]), use [Query4.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query4[A, B, C, D]) Get() (*A, *B, *C, *D) {
 	index := q.cursor.index
@@ -231,11 +265,13 @@ func (q *Query4[A, B, C, D]) Get() (*A, *B, *C, *D) {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query4.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter4.Optional]).
 func (q *Query4[A, B, C, D]) GetColumns() ([]A, []B, []C, []D) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len],
-		q.columnC.data.Interface().([]C)[:q.table.len:q.table.len],
-		q.columnD.data.Interface().([]D)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len),
+		columnSlice[C](q.columnC, 0, q.table.len),
+		columnSlice[D](q.columnD, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -246,13 +282,17 @@ func (q *Query5[A, B, C, D, E]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query5.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query5[A, B, C, D, E]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -273,6 +313,8 @@ func (q *Query5[A, B, C, D, E]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query5.Next].
 //
+// For filters with optional components (see [Filter5.Optional]), use [Query5.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query5[A, B, C, D, E]) Get() (*A, *B, *C, *D, *E) {
 	index := q.cursor.index
@@ -285,12 +327,14 @@ func (q *Query5[A, B, C, D, E]) Get() (*A, *B, *C, *D, *E) {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query5.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter5.Optional]).
 func (q *Query5[A, B, C, D, E]) GetColumns() ([]A, []B, []C, []D, []E) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len],
-		q.columnC.data.Interface().([]C)[:q.table.len:q.table.len],
-		q.columnD.data.Interface().([]D)[:q.table.len:q.table.len],
-		q.columnE.data.Interface().([]E)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len),
+		columnSlice[C](q.columnC, 0, q.table.len),
+		columnSlice[D](q.columnD, 0, q.table.len),
+		columnSlice[E](q.columnE, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -301,13 +345,17 @@ func (q *Query6[A, B, C, D, E, F]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query6.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query6[A, B, C, D, E, F]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -328,6 +376,8 @@ func (q *Query6[A, B, C, D, E, F]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query6.Next].
 //
+// For filters with optional components (see [Filter6.Optional]), use [Query6.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query6[A, B, C, D, E, F]) Get() (*A, *B, *C, *D, *E, *F) {
 	index := q.cursor.index
@@ -341,13 +391,15 @@ func (q *Query6[A, B, C, D, E, F]) Get() (*A, *B, *C, *D, *E, *F) {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query6.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter6.Optional]).
 func (q *Query6[A, B, C, D, E, F]) GetColumns() ([]A, []B, []C, []D, []E, []F) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len],
-		q.columnC.data.Interface().([]C)[:q.table.len:q.table.len],
-		q.columnD.data.Interface().([]D)[:q.table.len:q.table.len],
-		q.columnE.data.Interface().([]E)[:q.table.len:q.table.len],
-		q.columnF.data.Interface().([]F)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len),
+		columnSlice[C](q.columnC, 0, q.table.len),
+		columnSlice[D](q.columnD, 0, q.table.len),
+		columnSlice[E](q.columnE, 0, q.table.len),
+		columnSlice[F](q.columnF, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -358,13 +410,17 @@ func (q *Query7[A, B, C, D, E, F, G]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query7.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query7[A, B, C, D, E, F, G]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -385,6 +441,8 @@ func (q *Query7[A, B, C, D, E, F, G]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query7.Next].
 //
+// For filters with optional components (see [Filter7.Optional]), use [Query7.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query7[A, B, C, D, E, F, G]) Get() (*A, *B, *C, *D, *E, *F, *G) {
 	index := q.cursor.index
@@ -399,14 +457,16 @@ func (q *Query7[A, B, C, D, E, F, G]) Get() (*A, *B, *C, *D, *E, *F, *G) {
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query7.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter7.Optional]).
 func (q *Query7[A, B, C, D, E, F, G]) GetColumns() ([]A, []B, []C, []D, []E, []F, []G) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len],
-		q.columnC.data.Interface().([]C)[:q.table.len:q.table.len],
-		q.columnD.data.Interface().([]D)[:q.table.len:q.table.len],
-		q.columnE.data.Interface().([]E)[:q.table.len:q.table.len],
-		q.columnF.data.Interface().([]F)[:q.table.len:q.table.len],
-		q.columnG.data.Interface().([]G)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len),
+		columnSlice[C](q.columnC, 0, q.table.len),
+		columnSlice[D](q.columnD, 0, q.table.len),
+		columnSlice[E](q.columnE, 0, q.table.len),
+		columnSlice[F](q.columnF, 0, q.table.len),
+		columnSlice[G](q.columnG, 0, q.table.len)
 }
 
 // Next advances the query's cursor to the next entity.
@@ -417,13 +477,17 @@ func (q *Query8[A, B, C, D, E, F, G, H]) Next() bool {
 		q.cursor.index++
 		return true
 	}
-	return q.nextTableOrArchetype()
+	return q.nextTableOrTracked()
 }
 
 // NextTable advances the query's cursor to the next table.
 //
 // For alternative iteration over entities, use [Query8.Next].
+//
+// Panics if the filter uses components with sparse storage (see [StorageSparse]),
+// targets of multi-target relations, or a cascade on a multi-target relation (see [MultiRelationMarker]).
 func (q *Query8[A, B, C, D, E, F, G, H]) NextTable() bool {
+	q.conditions.checkTableIteration()
 	return q.nextTableOrArchetype()
 }
 
@@ -444,6 +508,8 @@ func (q *Query8[A, B, C, D, E, F, G, H]) Entities() []Entity {
 // Get returns the queried components of the current entity.
 // Use this with entity iteration using [Query8.Next].
 //
+// For filters with optional components (see [Filter8.Optional]), use [Query8.GetOptional] instead.
+//
 // ⚠️ Do not store the obtained pointers outside of the current context (i.e. the query loop)!
 func (q *Query8[A, B, C, D, E, F, G, H]) Get() (*A, *B, *C, *D, *E, *F, *G, *H) {
 	index := q.cursor.index
@@ -459,13 +525,15 @@ func (q *Query8[A, B, C, D, E, F, G, H]) Get() (*A, *B, *C, *D, *E, *F, *G, *H)
 
 // GetColumns returns the queried component columns of the current table.
 // Use this with table-based iteration using [Query8.NextTable].
+//
+// Returns nil slices for optional components absent from the current table (see [Filter8.Optional]).
 func (q *Query8[A, B, C, D, E, F, G, H]) GetColumns() ([]A, []B, []C, []D, []E, []F, []G, []H) {
-	return q.columnA.data.Interface().([]A)[:q.table.len:q.table.len],
-		q.columnB.data.Interface().([]B)[:q.table.len:q.table.len],
-		q.columnC.data.Interface().([]C)[:q.table.len:q.table.len],
-		q.columnD.data.Interface().([]D)[:q.table.len:q.table.len],
-		q.columnE.data.Interface().([]E)[:q.table.len:q.table.len],
-		q.columnF.data.Interface().([]F)[:q.table.len:q.table.len],
-		q.columnG.data.Interface().([]G)[:q.table.len:q.table.len],
-		q.columnH.data.Interface().([]H)[:q.table.len:q.table.len]
+	return columnSlice[A](q.columnA, 0, q.table.len),
+		columnSlice[B](q.columnB, 0, q.table.len),
+		columnSlice[C](q.columnC, 0, q.table.len),
+		columnSlice[D](q.columnD, 0, q.table.len),
+		columnSlice[E](q.columnE, 0, q.table.len),
+		columnSlice[F](q.columnF, 0, q.table.len),
+		columnSlice[G](q.columnG, 0, q.table.len),
+		columnSlice[H](q.columnH, 0, q.table.len)
 }
@@ -1,6 +1,8 @@
 package ecs
 
 import (
+	"os/exec"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -343,3 +345,209 @@ func TestQueryTables(t *testing.T) {
 	}
 	expectEqual(t, 2, cnt)
 }
+
+func TestQueryOptional(t *testing.T) {
+	world := NewWorld()
+
+	posMap := NewMap1[Position](world)
+	map2 := NewMap2[Position, Velocity](world)
+	velMap := NewMap1[Velocity](world)
+
+	posMap.NewBatch(10, &Position{1, 2})
+	map2.NewBatch(5, &Position{3, 4}, &Velocity{5, 6})
+	velMap.NewBatch(7, &Velocity{7, 8})
+
+	filter := NewFilter2[Position, Velocity](world).Optional(C[Velocity]())
+
+	withVel := 0
+	withoutVel := 0
+	query := filter.Query()
+	for query.Next() {
+		pos, vel := query.GetOptional()
+		if vel == nil {
+			expectEqual(t, Position{1, 2}, *pos)
+			withoutVel++
+		} else {
+			expectEqual(t, Position{3, 4}, *pos)
+			expectEqual(t, Velocity{5, 6}, *vel)
+			withVel++
+		}
+	}
+	expectEqual(t, 10, withoutVel)
+	expectEqual(t, 5, withVel)
+
+	query = filter.Query()
+	expectEqual(t, 15, query.Count())
+	query.Close()
+
+	tables := 0
+	query = filter.Query()
+	for query.NextTable() {
+		positions, velocities := query.GetColumns()
+		if len(positions) == 10 {
+			expectTrue(t, velocities == nil)
+		} else {
+			expectEqual(t, 5, len(velocities))
+		}
+		tables++
+	}
+	expectEqual(t, 2, tables)
+
+	// All components optional, matches all entities.
+	filter2 := NewFilter2[Position, Velocity](world).Optional(C[Position](), C[Velocity]())
+	query = filter2.Query()
+	expectEqual(t, 22, query.Count())
+	query.Close()
+
+	// Optional and exclusive, in any order.
+	filter3 := NewFilter2[Position, Velocity](world).Exclusive().Optional(C[Velocity]())
+	query = filter3.Query()
+	expectEqual(t, 15, query.Count())
+	query.Close()
+	filter3 = NewFilter2[Position, Velocity](world).Optional(C[Velocity]()).Exclusive()
+	query = filter3.Query()
+	expectEqual(t, 15, query.Count())
+	query.Close()
+	filter3 = NewFilter2[Position, Velocity](world).Optional(C[Velocity]()).Exclusive().Register()
+	query = filter3.Query()
+	expectEqual(t, 15, query.Count())
+	query.Close()
+
+	filter4 := NewFilter1[Position](world).Optional(C[Position]())
+	query4 := filter4.Query()
+	query4.ParallelTables(2, func(chunk *Chunk, entities []Entity, pos []Position) {
+		if len(pos) > 0 {
+			expectEqual(t, len(entities), len(pos))
+		}
+	})
+
+	expectPanicsWithValue(t, "optional component with ID 2 is not in the filter's parameters", func() {
+		NewFilter1[Position](world).With(C[Heading]()).Optional(C[Heading]())
+	})
+	expectPanicsWithValue(t, "component with ID 1 can't be optional and used for change detection", func() {
+		NewFilter2[Position, Velocity](world).Changed(C[Velocity]()).Optional(C[Velocity]())
+	})
+	expectPanicsWithValue(t, "component with ID 1 can't be optional and used for change detection", func() {
+		NewFilter2[Position, Velocity](world).Optional(C[Velocity]()).Added(C[Velocity]())
+	})
+	expectPanicsWithValue(t, "component with ID 1 can't be optional and used for change detection", func() {
+		NewFilter2[Position, Velocity](world).Optional(C[Velocity]()).Changed(C[Velocity]())
+	})
+	expectPanicsWithValue(t, "component with ID 1 can't be optional and used for change detection", func() {
+		NewFilter2[Position, Velocity](world).Added(C[Velocity]()).Optional(C[Velocity]())
+	})
+
+	filter5 := NewFilter2[Position, Velocity](world).Changed(C[Position]()).Optional(C[Velocity]())
+	filter5.Since(world.AdvanceTick())
+	query5 := filter5.Query()
+	cnt := 0
+	for query5.Next() {
+		cnt++
+	}
+	expectEqual(t, 0, cnt)
+}
+
+func TestQueryOptionalGetMut(t *testing.T) {
+	world := NewWorld()
+
+	NewMap1[Position](world).NewBatch(3, &Position{1, 2})
+	NewMap2[Position, Velocity](world).NewBatch(2, &Position{3, 4}, &Velocity{5, 6})
+
+	filter := NewFilter2[Position, Velocity](world).Optional(C[Velocity]())
+	withVel := 0
+	query := filter.Query()
+	for query.Next() {
+		pos, vel := query.GetMut()
+		pos.X = 10
+		if vel != nil {
+			vel.X = 10
+			withVel++
+		}
+	}
+	expectEqual(t, 2, withVel)
+
+	tick := world.AdvanceTick()
+	changed := NewFilter2[Position, Velocity](world).Optional(C[Position]()).Changed(C[Velocity]()).Since(tick - 1)
+	query = changed.Query()
+	expectEqual(t, 2, query.Count())
+	for query.Next() {
+		pos, vel := query.GetOptional()
+		expectEqual(t, 10.0, pos.X)
+		expectEqual(t, 10.0, vel.X)
+	}
+}
+
+func TestQueryAnyOf(t *testing.T) {
+	world := NewWorld()
+
+	NewMap2[Position, Velocity](world).NewBatch(5, &Position{}, &Velocity{})
+	NewMap2[Position, Heading](world).NewBatch(7, &Position{}, &Heading{})
+	NewMap1[Position](world).NewBatch(11, &Position{})
+	NewMap2[Velocity, Heading](world).NewBatch(13, &Velocity{}, &Heading{})
+
+	filter := NewFilter1[Position](world).AnyOf(C[Velocity](), C[Heading]())
+	query := filter.Query()
+	expectEqual(t, 12, query.Count())
+	query.Close()
+
+	filter = NewFilter1[Position](world).AnyOf(C[Velocity](), C[Heading]()).Register()
+	cnt := 0
+	query = filter.Query()
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, 12, cnt)
+
+	filter0 := NewFilter0(world).AnyOf(C[Velocity]()).AnyOf(C[Heading]())
+	query0 := filter0.Query()
+	expectEqual(t, 13, query0.Count())
+	query0.Close()
+
+	// AnyOf and exclusive, in any order.
+	filter = NewFilter1[Position](world).AnyOf(C[Velocity](), C[Heading]()).Exclusive()
+	query = filter.Query()
+	expectEqual(t, 12, query.Count())
+	query.Close()
+	filter = NewFilter1[Position](world).Exclusive().AnyOf(C[Velocity](), C[Heading]())
+	query = filter.Query()
+	expectEqual(t, 12, query.Count())
+	query.Close()
+
+	unsafeQuery := NewUnsafeFilter(world).
+		Where(Or(Has(ComponentID[Velocity](world)), Not(Has(ComponentID[Position](world))))).
+		Query()
+	cnt = 0
+	for unsafeQuery.Next() {
+		cnt++
+	}
+	expectEqual(t, 18, cnt)
+
+	filter = NewFilter1[Position](world).Exclusive().AnyOf(C[Velocity](), C[Heading]())
+	removed := 0
+	world.RemoveEntities(filter.Batch(), func(entity Entity) {
+		removed++
+	})
+	expectEqual(t, 12, removed)
+
+	// Filters can't be modified after first use.
+	expectPanicsWithValue(t, "can't modify a filter that was already queried", func() {
+		filter.AnyOf(C[Velocity]())
+	})
+}
+
+// TestQueryNextInlined guards against regressions of entity iteration performance,
+// as Next is only fast if it can be inlined.
+func TestQueryNextInlined(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping compiler invocation in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	out, err := exec.Command(goTool, "build", "-gcflags=-m", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	expectTrue(t, strings.Contains(string(out), "can inline (*Query0).Next\n"))
+}
@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// StorageKind is the kind of storage used for a component type.
+// See [ComponentOptions].
+type StorageKind uint8
+
+const (
+	// StorageTable stores components in the columns of archetype tables.
+	// This is the default.
+	StorageTable StorageKind = iota
+	// StorageSparse stores components in a sparse set per component type, keyed by entity ID.
+	//
+	// Adding and removing sparse components is fast, as entities are not moved between archetypes,
+	// and it does not cause archetype fragmentation.
+	// This is intended for frequently toggled, tag-like components such as `Selected` or `Dirty`.
+	//
+	// Sparse components are accessed via [Map], and can be used in filters and queries.
+	// However, they are not supported in other component operations, batch operations, relations,
+	// change detection and the unsafe API, and they don't trigger observers and hooks (see [RegisterHooks]).
+	// Using them in multi-component maps like [Map2], in change detection, observers or hooks panics.
+	// In filters, they are checked per entity, and filters using them don't support table-based iteration (see [Filter2.With]).
+	// Queries of non-registered filters iterate the smallest sparse set among the filter's required components.
+	// They are included in [World.Clone], [World.Snapshot] and [World.TransferEntities],
+	// but are not supported by package [github.com/mlange-42/ark/ecs/codec].
+	StorageSparse
+)
+
+// ComponentOptions are options for the explicit registration of a component type via [RegisterComponent].
+// All fields are optional.
+type ComponentOptions[T any] struct {
+	// Name of the component type, e.g. for identification in networking and save files.
+	// Package [github.com/mlange-42/ark/ecs/codec] matches component types by this name.
+	// Defaults to the fully qualified name of the Go type, like "main.Position".
+	Name string
+	// ID is the requested ID index of the component type.
+	// If nil, the lowest free ID is assigned.
+	ID *int
+	// Storage is the kind of storage used for the component type.
+	Storage StorageKind
+	// Cloner returns a copy of a component, for use by [World.Clone].
+	// By default, components are copied shallowly.
+	Cloner func(comp *T) T
+}
+
+// RegisterComponent explicitly registers the component type T, with the given options.
+// This allows for deterministic component IDs, e.g. across processes for networking and save files,
+// as well as for metadata that can be queried via [ComponentInfo].
+//
+// Component types are otherwise registered lazily when they are first used.
+// Explicit registration should happen during world initialization, before any other use of the world.
+// See also [FreezeComponents].
+//
+// Panics if T is already registered, if the requested ID is already in use or out of range,
+// if the registry is frozen, or if the world is locked.
+func RegisterComponent[T any](w *World, opts ComponentOptions[T]) ID {
+	w.checkLocked()
+	tp := reflect.TypeFor[T]()
+	s := &w.storage
+	if _, ok := s.registry.Components[tp]; ok {
+		panic(fmt.Sprintf("component type %s is already registered", tp.Name()))
+	}
+	if s.registry.frozen {
+		panic(fmt.Sprintf("can't register component type %s, as the component registry is frozen", tp.Name()))
+	}
+	switch opts.Storage {
+	case StorageTable:
+	case StorageSparse:
+		if isRelation(tp) {
+			panic(fmt.Sprintf("relation component type %s can't use sparse storage", tp.Name()))
+		}
+	default:
+		panic(fmt.Sprintf("unknown storage kind %d", opts.Storage))
+	}
+
+	var id idIndex
+	if opts.ID != nil {
+		reqID := *opts.ID
+		if reqID < 0 || reqID >= maskTotalBits {
+			panic(fmt.Sprintf("requested component ID %d is out of range [0, %d)", reqID, maskTotalBits))
+		}
+		id = idIndex(reqID)
+		if s.registry.Used.Get(id) {
+			panic(fmt.Sprintf("requested component ID %d is already in use", reqID))
+		}
+		s.registry.registerComponentID(tp, id)
+	} else {
+		id = s.registry.registerComponent(tp, maskTotalBits)
+	}
+	for i := len(s.components); i <= int(id); i++ {
+		s.AddComponent(idIndex(i))
+	}
+
+	if opts.Name != "" {
+		s.registry.Names[id] = opts.Name
+	}
+	if opts.Storage == StorageSparse {
+		s.setSparse(id)
+	}
+	if fn := opts.Cloner; fn != nil {
+		s.registry.Cloners[id] = func(dst, src unsafe.Pointer) {
+			*(*T)(dst) = fn((*T)(src))
+		}
+	}
+	return ID{id: id}
+}
+
+// FreezeComponents freezes the component registry of the world.
+// Afterwards, registration of new component types panics, including lazy registration on first use.
+// This helps to ensure that all component types are registered explicitly, see [RegisterComponent].
+//
+// Freezing can't be undone. It is kept on [World.Reset] and copied by [World.Clone].
+func FreezeComponents(w *World) {
+	w.storage.registry.frozen = true
+}
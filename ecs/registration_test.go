@@ -0,0 +1,113 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterComponent(t *testing.T) {
+	w := NewWorld(16)
+
+	three, minusOne := 3, -1
+	velID := RegisterComponent(w, ComponentOptions[Velocity]{ID: &three})
+	posID := RegisterComponent(w, ComponentOptions[Position]{Name: "pos", Storage: StorageTable})
+	headID := ComponentID[Heading](w)
+
+	expectEqual(t, id(3), velID)
+	expectEqual(t, id(0), posID)
+	expectEqual(t, id(1), headID)
+	expectEqual(t, velID, ComponentID[Velocity](w))
+	expectEqual(t, posID, ComponentID[Position](w))
+	expectSlicesEqual(t, []ID{posID, headID, velID}, ComponentIDs(w))
+	expectEqual(t, 4, len(w.storage.components))
+
+	info, ok := ComponentInfo(w, posID)
+	expectTrue(t, ok)
+	expectEqual(t, "pos", info.Name)
+	expectEqual(t, StorageTable, info.Storage)
+	expectFalse(t, info.HasCloner)
+
+	info, _ = ComponentInfo(w, velID)
+	expectEqual(t, "ecs.Velocity", info.Name)
+
+	velMap := NewMap2[Position, Velocity](w)
+	e := velMap.NewEntity(&Position{X: 1}, &Velocity{X: 2})
+	pos, vel := velMap.Get(e)
+	expectEqual(t, 1.0, pos.X)
+	expectEqual(t, 2.0, vel.X)
+
+	expectEqual(t, id(2), ComponentID[Label](w))
+
+	expectPanicsWithValue(t, "component type Position is already registered",
+		func() {
+			RegisterComponent(w, ComponentOptions[Position]{})
+		})
+	expectPanicsWithValue(t, "requested component ID 3 is already in use",
+		func() {
+			RegisterComponent(w, ComponentOptions[ChildOf]{ID: &three})
+		})
+	expectPanicsWithValue(t, fmt.Sprintf("requested component ID -1 is out of range [0, %d)", maskTotalBits),
+		func() {
+			RegisterComponent(w, ComponentOptions[ChildOf]{ID: &minusOne})
+		})
+	expectPanicsWithValue(t, "unknown storage kind 255",
+		func() {
+			RegisterComponent(w, ComponentOptions[ChildOf]{Storage: 255})
+		})
+	_, ok = w.storage.registry.Components[reflect.TypeFor[ChildOf]()]
+	expectFalse(t, ok)
+}
+
+func TestRegisterComponentCloner(t *testing.T) {
+	w := NewWorld(16)
+
+	RegisterComponent(w, ComponentOptions[SliceComp]{
+		Cloner: func(comp *SliceComp) SliceComp {
+			return SliceComp{Slice: append([]int(nil), comp.Slice...)}
+		},
+	})
+	info, _ := ComponentInfo(w, ComponentID[SliceComp](w))
+	expectTrue(t, info.HasCloner)
+
+	sliceMap := NewMap2[Position, SliceComp](w)
+	posMap := NewMap[Position](w)
+	posMap.NewBatch(5, &Position{})
+	e := sliceMap.NewEntity(&Position{}, &SliceComp{Slice: []int{1, 2}})
+
+	w2 := w.Clone()
+	sliceMap2 := NewMap[SliceComp](w2)
+	comp := sliceMap2.Get(e)
+	expectSlicesEqual(t, []int{1, 2}, comp.Slice)
+
+	comp.Slice[0] = 10
+	_, orig := sliceMap.Get(e)
+	expectEqual(t, 1, orig.Slice[0])
+
+	info, _ = ComponentInfo(w2, ComponentID[SliceComp](w2))
+	expectTrue(t, info.HasCloner)
+}
+
+func TestFreezeComponents(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+
+	FreezeComponents(w)
+	expectEqual(t, posID, ComponentID[Position](w))
+
+	expectPanicsWithValue(t, "can't register component type Velocity, as the component registry is frozen",
+		func() {
+			ComponentID[Velocity](w)
+		})
+	expectPanicsWithValue(t, "can't register component type Velocity, as the component registry is frozen",
+		func() {
+			RegisterComponent(w, ComponentOptions[Velocity]{})
+		})
+	expectSlicesEqual(t, []ID{posID}, ComponentIDs(w))
+
+	w2 := w.Clone()
+	expectPanicsWithValue(t, "can't register component type Velocity, as the component registry is frozen",
+		func() {
+			ComponentID[Velocity](w2)
+		})
+}
@@ -4,29 +4,31 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"unsafe"
 )
 
 // componentRegistry keeps track of type IDs.
 type registry struct {
-	Used       bitMask                // Mapping from IDs to used status.
-	Types      []reflect.Type         // Mapping from IDs to types.
-	IDs        []uint8                // List of IDs.
-	Components map[reflect.Type]uint8 // Mapping from types to IDs.
+	Used       bitMask                  // Mapping from IDs to used status.
+	Types      []reflect.Type           // Mapping from IDs to types.
+	IDs        []idIndex                // List of IDs.
+	Components map[reflect.Type]idIndex // Mapping from types to IDs.
+	count      int                      // Number of reserved IDs, i.e. the maximum ID plus 1.
 }
 
 // newComponentRegistry creates a new ComponentRegistry.
 func newRegistry() registry {
 	return registry{
-		Components: map[reflect.Type]uint8{},
+		Components: map[reflect.Type]idIndex{},
 		Types:      make([]reflect.Type, maskTotalBits),
 		Used:       bitMask{},
-		IDs:        []uint8{},
+		IDs:        []idIndex{},
 	}
 }
 
 // ComponentID returns the ID for a component type, and registers it if not already registered.
 // The second return value indicates if it is a newly created ID.
-func (r *registry) ComponentID(tp reflect.Type) (uint8, bool) {
+func (r *registry) ComponentID(tp reflect.Type) (idIndex, bool) {
 	if id, ok := r.Components[tp]; ok {
 		return id, false
 	}
@@ -34,38 +36,64 @@ func (r *registry) ComponentID(tp reflect.Type) (uint8, bool) {
 }
 
 // ComponentType returns the type of a component by ID.
-func (r *registry) ComponentType(id uint8) (reflect.Type, bool) {
+func (r *registry) ComponentType(id idIndex) (reflect.Type, bool) {
 	return r.Types[id], r.Used.Get(id)
 }
 
 // Count returns the total number of reserved IDs. It is the maximum ID plus 1.
 func (r *registry) Count() int {
-	return len(r.Components)
+	return r.count
 }
 
 // registerComponent registers a components and assigns an ID for it.
-func (r *registry) registerComponent(tp reflect.Type, totalBits int) uint8 {
-	val := len(r.Components)
+// Re-uses the lowest ID freed by [registry.unregisterComponent], if any.
+func (r *registry) registerComponent(tp reflect.Type, totalBits int) idIndex {
+	val := r.count
+	if len(r.IDs) < r.count {
+		for i := range r.count {
+			if !r.Used.Get(idIndex(i)) {
+				val = i
+				break
+			}
+		}
+	}
 	if val >= totalBits {
 		panic(fmt.Sprintf("exceeded the maximum of %d component types or resource types", totalBits))
 	}
-	newID := uint8(val)
-	id := id(val)
-	r.Components[tp], r.Types[newID] = newID, tp
-	r.Used.Set(id.id)
-	r.IDs = append(r.IDs, newID)
+	newID := idIndex(val)
+	r.registerComponentID(tp, newID)
 	return newID
 }
 
-// unregisterLastComponent unregistered the component type that was registered last.
-func (r *registry) unregisterLastComponent() {
-	newID := uint8(len(r.Components) - 1)
-	id := id8(newID)
-	tp, _ := r.ComponentType(newID)
+// registerComponentID registers a component with the given free ID.
+func (r *registry) registerComponentID(tp reflect.Type, id idIndex) {
+	r.Components[tp], r.Types[id] = id, tp
+	r.Used.Set(id)
+	// Keep IDs sorted, as freed IDs may be re-used.
+	r.IDs = append(r.IDs, id)
+	for i := len(r.IDs) - 1; i > 0 && r.IDs[i-1] > id; i-- {
+		r.IDs[i], r.IDs[i-1] = r.IDs[i-1], r.IDs[i]
+	}
+	r.count = max(r.count, int(id)+1)
+}
+
+// unregisterComponent unregisters the component type with the given ID.
+// The ID is free for re-use afterwards.
+func (r *registry) unregisterComponent(id idIndex) {
+	tp, _ := r.ComponentType(id)
 	delete(r.Components, tp)
-	r.Types[newID] = nil
-	r.Used.Clear(id.id)
-	r.IDs = r.IDs[:len(r.IDs)-1]
+	r.Types[id] = nil
+	r.Used.Clear(id)
+	ids := r.IDs[:0]
+	for _, other := range r.IDs {
+		if other != id {
+			ids = append(ids, other)
+		}
+	}
+	r.IDs = ids
+	for r.count > 0 && !r.Used.Get(idIndex(r.count-1)) {
+		r.count--
+	}
 }
 
 // componentRegistry keeps track of component IDs.
@@ -73,26 +101,46 @@ func (r *registry) unregisterLastComponent() {
 // are relation components and/or contain (or are) pointers.
 type componentRegistry struct {
 	registry
-	IsRelation []bool
-	IsTrivial  []bool
-	Archetypes []int  // Number of archetypes for each component.
-	version    uint32 // Generation to indicate changes to archetype count per component.
+	IsRelation        []bool
+	IsMultiRelation   []bool
+	IsSymmetric       []bool
+	IsTrivial         []bool
+	Cleanup           []CleanupPolicy
+	Names             []string
+	Storage           []StorageKind
+	IsTracked         []bool // Whether changes are tracked, see [storage.trackChanges].
+	Cloners           []func(dst, src unsafe.Pointer)
+	Archetypes        []int  // Number of archetypes for each component.
+	version           uint32 // Generation to indicate changes to archetype count per component.
+	unregistered      uint32 // Number of unregistered component types, to detect outdated snapshots.
+	hasSymmetric      bool   // Whether there are any symmetric relation components.
+	hasCleanup        bool   // Whether there are any relation components with a non-default cleanup policy.
+	frozen            bool   // Whether registration of new components is prohibited.
+	hasSparse         bool   // Whether there are any components with sparse storage.
+	hasMultiRelations bool   // Whether there are any multi-target relation components.
 }
 
 // newComponentRegistry creates a new ComponentRegistry.
 func newComponentRegistry() componentRegistry {
 	return componentRegistry{
-		registry:   newRegistry(),
-		IsRelation: make([]bool, maskTotalBits),
-		IsTrivial:  make([]bool, maskTotalBits),
-		Archetypes: make([]int, maskTotalBits),
-		version:    1,
+		registry:        newRegistry(),
+		IsRelation:      make([]bool, maskTotalBits),
+		IsMultiRelation: make([]bool, maskTotalBits),
+		IsSymmetric:     make([]bool, maskTotalBits),
+		IsTrivial:       make([]bool, maskTotalBits),
+		Cleanup:         make([]CleanupPolicy, maskTotalBits),
+		Names:           make([]string, maskTotalBits),
+		Storage:         make([]StorageKind, maskTotalBits),
+		IsTracked:       make([]bool, maskTotalBits),
+		Cloners:         make([]func(dst, src unsafe.Pointer), maskTotalBits),
+		Archetypes:      make([]int, maskTotalBits),
+		version:         1,
 	}
 }
 
 // ComponentID returns the ID for a component type, and registers it if not already registered.
 // The second return value indicates if it is a newly created ID.
-func (r *componentRegistry) ComponentID(tp reflect.Type) (uint8, bool) {
+func (r *componentRegistry) ComponentID(tp reflect.Type) (idIndex, bool) {
 	if id, ok := r.Components[tp]; ok {
 		return id, false
 	}
@@ -100,37 +148,75 @@ func (r *componentRegistry) ComponentID(tp reflect.Type) (uint8, bool) {
 }
 
 // registerComponent registers a components and assigns an ID for it.
-func (r *componentRegistry) registerComponent(tp reflect.Type, totalBits int) uint8 {
+func (r *componentRegistry) registerComponent(tp reflect.Type, totalBits int) idIndex {
 	newID := r.registry.registerComponent(tp, totalBits)
-	r.IsRelation[newID] = isRelation(tp)
-	r.IsTrivial[newID] = isTrivial(tp)
+	r.initComponent(tp, newID)
 	return newID
 }
 
-// unregisterLastComponent unregistered the component type that was registered last.
-func (r *componentRegistry) unregisterLastComponent() {
-	newID := uint8(len(r.Components) - 1)
-	r.registry.unregisterLastComponent()
-	r.IsRelation[newID] = false
+// registerComponentID registers a component with the given free ID.
+func (r *componentRegistry) registerComponentID(tp reflect.Type, id idIndex) {
+	r.registry.registerComponentID(tp, id)
+	r.initComponent(tp, id)
+}
+
+// initComponent sets the properties of a newly registered component.
+func (r *componentRegistry) initComponent(tp reflect.Type, id idIndex) {
+	r.IsRelation[id] = isRelation(tp)
+	r.IsMultiRelation[id] = isMultiRelation(tp)
+	if r.IsMultiRelation[id] {
+		r.hasMultiRelations = true
+	}
+	r.IsTrivial[id] = isTrivial(tp)
+	r.Names[id] = tp.String()
+}
+
+// unregisterComponent unregisters the component type with the given ID.
+// The ID is free for re-use afterwards.
+func (r *componentRegistry) unregisterComponent(id idIndex) {
+	r.registry.unregisterComponent(id)
+	r.IsRelation[id] = false
+	r.IsMultiRelation[id] = false
+	r.IsSymmetric[id] = false
+	r.IsTrivial[id] = false
+	r.Cleanup[id] = CleanupKeep
+	r.Names[id] = ""
+	r.Storage[id] = StorageTable
+	r.IsTracked[id] = false
+	r.Cloners[id] = nil
 }
 
 // addArchetype increments the archetype counter for an entity
 // and the registry's version number.
-func (r *componentRegistry) addArchetype(id uint8) {
+func (r *componentRegistry) addArchetype(id idIndex) {
 	r.Archetypes[id]++
 	r.version++
 }
 
+// removeArchetype decrements the archetype counter for an entity
+// and increments the registry's version number.
+func (r *componentRegistry) removeArchetype(id idIndex) {
+	r.Archetypes[id]--
+	r.version++
+}
+
 // Returns the ID of the component present in the smallest number of archetypes.
-func (r *componentRegistry) rareComponent(ids []ID) ID {
+// Only considers components contained in the given mask.
+// Returns false if none of the components is contained in the mask.
+func (r *componentRegistry) rareComponent(ids []ID, mask *bitMask) (ID, bool) {
 	minCount := math.MaxInt
 	var rareID ID
+	found := false
 	for _, id := range ids {
+		if !mask.Get(id.id) {
+			continue
+		}
 		count := r.Archetypes[id.id]
 		if count < minCount {
 			minCount = count
 			rareID = id
+			found = true
 		}
 	}
-	return rareID
+	return rareID, found
 }
@@ -12,21 +12,21 @@ func TestComponentRegistry(t *testing.T) {
 	rotType := reflect.TypeOf((*Velocity)(nil)).Elem()
 
 	reg.registerComponent(posType, maskTotalBits)
-	expectSlicesEqual(t, []uint8{uint8(0)}, reg.IDs)
+	expectSlicesEqual(t, []idIndex{0}, reg.IDs)
 
-	reg.registerComponent(rotType, maskTotalBits)
-	reg.unregisterLastComponent()
-	expectSlicesEqual(t, []uint8{uint8(0)}, reg.IDs)
+	rotID := reg.registerComponent(rotType, maskTotalBits)
+	reg.unregisterComponent(rotID)
+	expectSlicesEqual(t, []idIndex{0}, reg.IDs)
 
 	id0, _ := reg.ComponentID(posType)
 	id1, _ := reg.ComponentID(rotType)
-	expectEqual(t, uint8(0), id0)
-	expectEqual(t, uint8(1), id1)
+	expectEqual(t, idIndex(0), id0)
+	expectEqual(t, idIndex(1), id1)
 
-	expectSlicesEqual(t, []uint8{uint8(0), uint8(1)}, reg.IDs)
+	expectSlicesEqual(t, []idIndex{0, 1}, reg.IDs)
 
-	t1, _ := reg.ComponentType(uint8(0))
-	t2, _ := reg.ComponentType(uint8(1))
+	t1, _ := reg.ComponentType(idIndex(0))
+	t2, _ := reg.ComponentType(idIndex(1))
 
 	expectEqual(t, posType, t1)
 	expectEqual(t, rotType, t2)
@@ -73,8 +73,22 @@ func TestRareComponent(t *testing.T) {
 
 	reg.addArchetype(id0)
 
-	expectEqual(t, ID{1}, reg.rareComponent([]ID{{0}, {1}}))
-	expectEqual(t, ID{1}, reg.rareComponent([]ID{{1}, {0}}))
+	mask := newMask(ID{0}, ID{1})
+	id, ok := reg.rareComponent([]ID{{0}, {1}}, &mask)
+	expectTrue(t, ok)
+	expectEqual(t, ID{1}, id)
+	id, ok = reg.rareComponent([]ID{{1}, {0}}, &mask)
+	expectTrue(t, ok)
+	expectEqual(t, ID{1}, id)
+
+	mask = newMask(ID{0})
+	id, ok = reg.rareComponent([]ID{{0}, {1}}, &mask)
+	expectTrue(t, ok)
+	expectEqual(t, ID{0}, id)
+
+	mask = newMask()
+	_, ok = reg.rareComponent([]ID{{0}, {1}}, &mask)
+	expectFalse(t, ok)
 }
 
 func BenchmarkRegistryGet(b *testing.B) {
@@ -108,9 +122,10 @@ func BenchmarkRareComponent2(b *testing.B) {
 
 	reg.addArchetype(id0)
 	ids := []ID{{0}, {1}}
+	mask := newMask(ids...)
 
 	for b.Loop() {
-		reg.rareComponent(ids)
+		reg.rareComponent(ids, &mask)
 	}
 }
 
@@ -125,8 +140,9 @@ func BenchmarkRareComponent5(b *testing.B) {
 
 	reg.addArchetype(id0)
 	ids := []ID{{0}, {1}, {2}, {3}, {4}}
+	mask := newMask(ids...)
 
 	for b.Loop() {
-		reg.rareComponent(ids)
+		reg.rareComponent(ids, &mask)
 	}
 }
@@ -8,6 +8,9 @@ import (
 // relationType is the runtime type of RelationMarker
 var relationType = reflect.TypeFor[RelationMarker]()
 
+// multiRelationType is the runtime type of MultiRelationMarker
+var multiRelationType = reflect.TypeFor[MultiRelationMarker]()
+
 // RelationMarker is a marker for entity relation components.
 // It must be embedded as first field of a component that represent an entity relationship
 // (see the example).
@@ -16,6 +19,28 @@ var relationType = reflect.TypeFor[RelationMarker]()
 // E.g. to iterate over all entities that are the child of a certain parent entity.
 type RelationMarker struct{}
 
+// MultiRelationMarker is a marker for entity relation components that allow for multiple targets per entity.
+// It must be embedded as first field of a component, instead of [RelationMarker].
+//
+// An entity with a multi-target relation component can have any number of targets for it,
+// like an entity that likes several other entities.
+// All targets of an entity share the same component value.
+// Targets are given as multiple relations with the same component, or as multiple targets to e.g. [Map.NewEntity].
+// Use [Map.GetRelations], [Map.AddTargets] and [Map.RemoveTargets] to access and manipulate the targets.
+//
+// Setting relation targets, e.g. with [Map.SetRelation], replaces all targets of the component.
+// When a target entity is removed from the world, it is removed from the targets of all entities.
+//
+// In contrast to [RelationMarker], targets don't split archetypes into tables.
+// They are stored per entity, together with a reverse index from targets to their source entities.
+// Filters for a relation target match all entities that have the target among their targets,
+// and are checked per entity, using the reverse index where possible.
+// Filters for the zero entity match entities without any targets.
+// Due to that, batch operations and table-based iteration (e.g. [Query2.ParallelTables])
+// are not supported for queries with multi-target relation targets.
+// Cascade queries (see [Filter2.Cascade]) order entities individually.
+type MultiRelationMarker struct{}
+
 // relationID is a pair of relation component type and relation target.
 type relationID struct {
 	target    Entity
@@ -65,6 +90,17 @@ func RelIdx(index int, target Entity) Relation {
 	}
 }
 
+// RelWildcard creates a new [Relation] for a component type that matches any non-zero target.
+//
+// It can only be used in filters and queries, e.g. with [Filter2.Relations] or [Filter2.Query].
+// In contrast, [Rel] with the zero entity as target matches only entities without a target.
+// Use e.g. [Query2.GetRelation] to get the target of the current table during iteration.
+//
+// Using it for creating or changing entity relations panics.
+func RelWildcard[C any]() Relation {
+	return Rel[C](wildcard)
+}
+
 // RelID creates a new [Relation] for a component ID.
 //
 // It is used in Ark's unsafe, ID-based API.
@@ -188,3 +224,38 @@ func (r relationEntities) ToRelation(world *World, id ID, out []relationID) []re
 	}
 	return out
 }
+
+// hasRelationComponent returns whether any of the relations is for the given component.
+func hasRelationComponent(relations []relationID, component ID) bool {
+	return indexRelationComponent(relations, component) >= 0
+}
+
+// indexRelationComponent returns the index of the first relation for the given component, or -1.
+func indexRelationComponent(relations []relationID, component ID) int {
+	for i, rel := range relations {
+		if rel.component == component {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsRelation returns whether the relations contain the given relation.
+func containsRelation(relations []relationID, rel relationID) bool {
+	for _, r := range relations {
+		if r == rel {
+			return true
+		}
+	}
+	return false
+}
+
+// containsEntity returns whether the entities contain the given entity.
+func containsEntity(entities []Entity, entity Entity) bool {
+	for _, e := range entities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
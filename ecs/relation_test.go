@@ -1,6 +1,7 @@
 package ecs
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -309,3 +310,276 @@ func BenchmarkCreateRelIdx(b *testing.B) {
 		_ = RelIdx(1, Entity{})
 	}
 }
+
+func TestMultiRelation(t *testing.T) {
+	w := NewWorld(16)
+	likesMap := NewMap[Likes](w)
+	posLikesMap := NewMap2[Position, Likes](w)
+	filter := NewFilter1[Likes](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	c := w.NewEntity()
+
+	e1 := likesMap.NewEntity(&Likes{Weight: 1}, b, a, b)
+	e2 := posLikesMap.NewEntity(&Position{}, &Likes{}, Rel[Likes](a), Rel[Likes](b))
+	e3 := likesMap.NewEntity(&Likes{}, a, b)
+
+	expectEqual(t, 2, len(likesMap.GetRelations(e1)))
+	expectTrue(t, containsEntity(likesMap.GetRelations(e1), a))
+	expectTrue(t, containsEntity(likesMap.GetRelations(e1), b))
+	expectEqual(t, a, likesMap.GetRelation(e1))
+	expectEqual(t, 1, likesMap.Get(e1).Weight)
+	expectEqual(t, w.storage.entities[e1.id].table, w.storage.entities[e3.id].table)
+
+	expectEqual(t, 3, countLikes(filter.Query(Rel[Likes](a))))
+	expectEqual(t, 3, countLikes(filter.Query(Rel[Likes](b))))
+	expectEqual(t, 0, countLikes(filter.Query(Rel[Likes](c))))
+
+	likesMap.AddTargets(e1, c, a)
+	expectEqual(t, 3, len(likesMap.GetRelations(e1)))
+	expectEqual(t, 1, countLikes(filter.Query(Rel[Likes](c))))
+	expectEqual(t, 1, likesMap.Get(e1).Weight)
+
+	likesMap.RemoveTargets(e1, a)
+	expectEqual(t, 2, len(likesMap.GetRelations(e1)))
+	expectFalse(t, containsEntity(likesMap.GetRelations(e1), a))
+	expectEqual(t, b, likesMap.GetRelation(e1))
+	expectEqual(t, 2, countLikes(filter.Query(Rel[Likes](a))))
+
+	likesMap.SetRelation(e2, c)
+	expectSlicesEqual(t, []Entity{c}, likesMap.GetRelations(e2))
+	expectEqual(t, 2, countLikes(filter.Query(Rel[Likes](c))))
+
+	likesMap.RemoveTargets(e2, c)
+	expectEqual(t, 0, len(likesMap.GetRelations(e2)))
+	expectEqual(t, Entity{}, likesMap.GetRelation(e2))
+	expectTrue(t, likesMap.Has(e2))
+	expectEqual(t, 1, countLikes(filter.Query(Rel[Likes](Entity{}))))
+
+	likesMap.AddTargets(e2, a)
+	expectSlicesEqual(t, []Entity{a}, likesMap.GetRelations(e2))
+	expectEqual(t, 0, countLikes(filter.Query(Rel[Likes](Entity{}))))
+
+	// Removing a target only removes it from the targets.
+	w.RemoveEntity(b)
+	expectSlicesEqual(t, []Entity{c}, likesMap.GetRelations(e1))
+	expectSlicesEqual(t, []Entity{a}, likesMap.GetRelations(e2))
+	expectSlicesEqual(t, []Entity{a}, likesMap.GetRelations(e3))
+	expectEqual(t, 2, countLikes(filter.Query(Rel[Likes](a))))
+	expectEqual(t, 1, countTargeting(w.Targeting(c)))
+
+	w.RemoveEntity(a)
+	w.RemoveEntity(c)
+	expectEqual(t, 3, countLikes(filter.Query(Rel[Likes](Entity{}))))
+	expectEqual(t, 0, len(likesMap.GetRelations(e1)))
+
+	childMap := NewMap[ChildOf](w)
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a multi-target relation component", ComponentID[ChildOf](w).id), func() {
+		childMap.AddTargets(e1, e2)
+	})
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a multi-target relation component", ComponentID[ChildOf](w).id), func() {
+		childMap.RemoveTargets(e1, e2)
+	})
+}
+
+func TestMultiRelationMixed(t *testing.T) {
+	w := NewWorld(16)
+	mapper := NewMap3[Position, ChildOf, Likes](w)
+	childMap := NewMap[ChildOf](w)
+	likesMap := NewMap[Likes](w)
+	filter := NewFilter2[ChildOf, Likes](w)
+
+	parent := w.NewEntity()
+	a := w.NewEntity()
+	b := w.NewEntity()
+
+	e := mapper.NewEntity(&Position{}, &ChildOf{}, &Likes{}, Rel[ChildOf](parent), Rel[Likes](a), Rel[Likes](b))
+	mapper.NewBatch(5, &Position{}, &ChildOf{}, &Likes{}, Rel[ChildOf](parent), Rel[Likes](b))
+
+	expectEqual(t, parent, childMap.GetRelation(e))
+	expectSlicesEqual(t, []Entity{parent}, childMap.GetRelations(e))
+	expectEqual(t, 2, len(likesMap.GetRelations(e)))
+
+	expectEqual(t, 6, countChildLikes(filter.Query(Rel[ChildOf](parent))))
+	expectEqual(t, 1, countChildLikes(filter.Query(Rel[ChildOf](parent), Rel[Likes](a))))
+	expectEqual(t, 6, countChildLikes(filter.Query(Rel[ChildOf](parent), Rel[Likes](b))))
+
+	childMap.SetRelation(e, a)
+	expectEqual(t, a, childMap.GetRelation(e))
+	expectEqual(t, 2, len(likesMap.GetRelations(e)))
+
+	w.Unsafe().SetRelations(e, RelID(ComponentID[Likes](w), parent), RelID(ComponentID[Likes](w), b))
+	expectEqual(t, 2, len(likesMap.GetRelations(e)))
+	expectTrue(t, containsEntity(likesMap.GetRelations(e), parent))
+	expectEqual(t, a, childMap.GetRelation(e))
+
+	w.RemoveEntity(parent)
+	expectEqual(t, a, childMap.GetRelation(e))
+	expectSlicesEqual(t, []Entity{b}, likesMap.GetRelations(e))
+	expectEqual(t, 5, countChildLikes(filter.Query(Rel[ChildOf](Entity{}), Rel[Likes](b))))
+
+	expectPanicsWithValue(t, "relation targets must be fully specified", func() {
+		mapper.NewEntity(&Position{}, &ChildOf{}, &Likes{}, Rel[Likes](a), Rel[Likes](b))
+	})
+}
+
+func TestMultiRelationStorage(t *testing.T) {
+	w := NewWorld(16)
+	likesMap := NewMap[Likes](w)
+	posLikesMap := NewMap2[Position, Likes](w)
+	filter := NewFilter1[Likes](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+
+	e1 := likesMap.NewEntity(&Likes{}, a)
+	e2 := likesMap.NewEntity(&Likes{}, b)
+	e3 := likesMap.NewEntity(&Likes{})
+	posLikesMap.NewBatch(3, &Position{}, &Likes{}, Rel[Likes](b))
+
+	// Targets don't split tables.
+	expectEqual(t, w.storage.entities[e1.id].table, w.storage.entities[e2.id].table)
+	expectEqual(t, w.storage.entities[e1.id].table, w.storage.entities[e3.id].table)
+
+	// Queries for a target are driven by the target's sources.
+	query := filter.Query(Rel[Likes](b))
+	expectEqual(t, 4, len(query.driver))
+	expectEqual(t, 4, query.Count())
+	expectEqual(t, e2, query.EntityAt(0))
+	cnt := 0
+	for query.Next() {
+		expectEqual(t, b, query.GetRelation(0))
+		cnt++
+	}
+	expectEqual(t, 4, cnt)
+
+	expectEqual(t, 0, countLikes(filter.Query(Rel[Likes](w.NewEntity()))))
+
+	targeting := []Entity{}
+	tq := w.Targeting(b)
+	for tq.Next() {
+		targeting = append(targeting, tq.Entity())
+	}
+	expectEqual(t, 4, len(targeting))
+	expectTrue(t, containsEntity(targeting, e2))
+	expectTrue(t, w.storage.isTarget[a.id])
+
+	expectPanicsWithValue(t, "table-based iteration is not supported for filters with multi-target relation targets",
+		func() {
+			query := filter.Query(Rel[Likes](a))
+			defer query.Close()
+			query.NextTable()
+		})
+	expectPanicsWithValue(t, "table-based iteration is not supported for filters with multi-target relation targets",
+		func() {
+			query := filter.Query(RelWildcard[Likes]())
+			defer query.Close()
+			query.ParallelTables(2, func(chunk *Chunk, entities []Entity, likes []Likes) {})
+		})
+	expectPanicsWithValue(t, "table-based iteration is not supported for cascade queries on multi-target relations",
+		func() {
+			query := NewFilter1[Likes](w).Cascade(C[Likes]()).Query()
+			defer query.Close()
+			query.NextTable()
+		})
+	expectPanicsWithValue(t, "batch operations are not supported for multi-target relation targets",
+		func() {
+			filter.Batch(Rel[Likes](a))
+		})
+
+	// Table iteration without targets is supported.
+	query = filter.Query()
+	tables := 0
+	for query.NextTable() {
+		tables++
+	}
+	expectEqual(t, 2, tables)
+}
+
+func countLikes(query Query1[Likes]) int {
+	defer query.Close()
+	return query.Count()
+}
+
+func countChildLikes(query Query2[ChildOf, Likes]) int {
+	defer query.Close()
+	return query.Count()
+}
+
+func TestRelWildcard(t *testing.T) {
+	w := NewWorld(16)
+	mapper := NewMap2[Position, ChildOf](w)
+	childMap := NewMap[ChildOf](w)
+	posMap := NewMap[Position](w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	mapper.NewBatch(3, &Position{}, &ChildOf{}, RelIdx(1, parent1))
+	mapper.NewBatch(2, &Position{}, &ChildOf{}, RelIdx(1, parent2))
+	mapper.NewBatch(4, &Position{}, &ChildOf{}, RelIdx(1, Entity{}))
+	posMap.NewBatch(5, &Position{})
+
+	filter := NewFilter2[Position, ChildOf](w)
+	expectEqual(t, 9, countQuery2(filter.Query()))
+	expectEqual(t, 5, countQuery2(filter.Query(RelWildcard[ChildOf]())))
+	expectEqual(t, 4, countQuery2(filter.Query(Rel[ChildOf](Entity{}))))
+	expectEqual(t, 3, countQuery2(filter.Query(RelIdx(1, parent1))))
+
+	targets := []Entity{}
+	query := filter.Query(RelWildcard[ChildOf]())
+	for query.Next() {
+		target := query.GetRelation(1)
+		expectFalse(t, target.IsZero())
+		if !containsEntity(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	expectEqual(t, 2, len(targets))
+	expectTrue(t, containsEntity(targets, parent1))
+	expectTrue(t, containsEntity(targets, parent2))
+
+	cached := NewFilter2[Position, ChildOf](w).Relations(RelWildcard[ChildOf]()).Register()
+	expectEqual(t, 5, countQuery2(cached.Query()))
+	expectEqual(t, 2, countQuery2(cached.Query(RelIdx(1, parent2))))
+	mapper.NewEntity(&Position{}, &ChildOf{}, RelIdx(1, w.NewEntity()))
+	expectEqual(t, 6, countQuery2(cached.Query()))
+
+	childMap.SetRelationBatch(filter.Batch(RelWildcard[ChildOf]()), Entity{}, nil)
+	expectEqual(t, 0, countQuery2(filter.Query(RelWildcard[ChildOf]())))
+	expectEqual(t, 10, countQuery2(filter.Query(Rel[ChildOf](Entity{}))))
+
+	e := w.NewEntity()
+	expectPanicsWithValue(t, "relation targets must be fully specified, no wildcard allowed", func() {
+		childMap.Add(e, &ChildOf{}, wildcard)
+	})
+	expectPanicsWithValue(t, "relation targets must be fully specified, no wildcard allowed", func() {
+		mapper.NewEntity(&Position{}, &ChildOf{}, RelWildcard[ChildOf]())
+	})
+}
+
+func TestRelWildcardMulti(t *testing.T) {
+	w := NewWorld(16)
+	mapper := NewMap3[Position, ChildOf, Likes](w)
+	filter := NewFilter2[ChildOf, Likes](w)
+
+	parent := w.NewEntity()
+	a := w.NewEntity()
+	b := w.NewEntity()
+
+	mapper.NewBatch(2, &Position{}, &ChildOf{}, &Likes{}, Rel[ChildOf](parent), Rel[Likes](a), Rel[Likes](b))
+	mapper.NewBatch(3, &Position{}, &ChildOf{}, &Likes{}, Rel[ChildOf](Entity{}), Rel[Likes](b))
+	mapper.NewBatch(4, &Position{}, &ChildOf{}, &Likes{}, Rel[ChildOf](parent), Rel[Likes](Entity{}))
+
+	expectEqual(t, 5, countChildLikes(filter.Query(RelWildcard[Likes]())))
+	expectEqual(t, 4, countChildLikes(filter.Query(Rel[Likes](Entity{}))))
+	expectEqual(t, 2, countChildLikes(filter.Query(RelWildcard[ChildOf](), RelWildcard[Likes]())))
+	expectEqual(t, 2, countChildLikes(filter.Query(RelWildcard[ChildOf](), Rel[Likes](a))))
+	expectEqual(t, 3, countChildLikes(filter.Query(Rel[ChildOf](Entity{}), RelWildcard[Likes]())))
+}
+
+func countQuery2(query Query2[Position, ChildOf]) int {
+	defer query.Close()
+	return query.Count()
+}
@@ -56,3 +56,19 @@ func (g *Resource[T]) Get() *T {
 func (g *Resource[T]) Has() bool {
 	return g.world.Resources().Has(g.id)
 }
+
+// SetCloneFunc sets a function for copying the resource when cloning the world via [World.Clone].
+// Use it for resources that need a deep copy, or that should be shared between worlds.
+// Without a clone function, the resource is copied shallowly.
+// Setting the function to nil restores the default behavior.
+//
+// See also [ecs.Resources.SetCloneFunc].
+func (g *Resource[T]) SetCloneFunc(fn func(res *T) *T) {
+	if fn == nil {
+		g.world.Resources().SetCloneFunc(g.id, nil)
+		return
+	}
+	g.world.Resources().SetCloneFunc(g.id, func(res any) any {
+		return fn(res.(*T))
+	})
+}
@@ -9,15 +9,17 @@ import (
 //
 // Although this type provides an ID-based API, the recommended usage is via [Resource].
 type Resources struct {
-	registry  registry
-	resources []any
+	registry   registry
+	resources  []any
+	cloneFuncs []func(res any) any
 }
 
 // newResources creates a new Resources manager.
 func newResources() Resources {
 	return Resources{
-		registry:  newRegistry(),
-		resources: make([]any, maskTotalBits),
+		registry:   newRegistry(),
+		resources:  make([]any, maskTotalBits),
+		cloneFuncs: make([]func(res any) any, maskTotalBits),
 	}
 }
 
@@ -62,6 +64,36 @@ func (r *Resources) Has(id ResID) bool {
 	return r.resources[id.id] != nil
 }
 
+// SetCloneFunc sets a function for copying the resource of the given type when cloning the world via [World.Clone].
+// The function receives the original resource and must return a copy of the same type.
+// Without a clone function, resources are copied shallowly.
+// Setting the function to nil restores the default behavior.
+//
+// See [Resource.SetCloneFunc] for the recommended type-safe way.
+func (r *Resources) SetCloneFunc(id ResID, fn func(res any) any) {
+	r.cloneFuncs[id.id] = fn
+}
+
+// clone creates a copy of all resources, using the registered clone functions.
+func (r *Resources) clone() Resources {
+	res := newResources()
+	for _, id := range r.registry.IDs {
+		res.registry.ComponentID(r.registry.Types[id])
+	}
+	copy(res.cloneFuncs, r.cloneFuncs)
+	for i, resource := range r.resources {
+		if resource == nil {
+			continue
+		}
+		if fn := r.cloneFuncs[i]; fn != nil {
+			res.resources[i] = fn(resource)
+		} else {
+			res.resources[i] = copyResource(resource)
+		}
+	}
+	return res
+}
+
 // reset removes all resources.
 func (r *Resources) reset() {
 	for i := range r.resources {
@@ -0,0 +1,228 @@
+package ecs
+
+import "reflect"
+
+// Snapshot is a copy of the state of a [World], for later restoring via [World.Restore].
+//
+// A snapshot contains all entities, their components and relation targets, as well as all resources.
+// This includes components with sparse storage (see [StorageSparse]).
+// Create one with [World.Snapshot].
+//
+// Snapshots can only be restored into the world they were taken from.
+// They can be restored any number of times.
+type Snapshot struct {
+	world     *World
+	pool      entityPool
+	entities  []entityIndex
+	isTarget  []bool
+	tables    []tableSnapshot
+	sparse    []*sparseSet     // Copies of the sparse sets, indexed by component ID
+	multi     []*multiRelation // Copies of multi-target relation targets, indexed by component ID
+	resources []any
+
+	unregistered uint32 // Number of unregistered component types when the snapshot was taken
+}
+
+// tableSnapshot is a copy of the content of a non-empty table.
+type tableSnapshot struct {
+	archetype archetypeID
+	relations []relationID
+	entities  []Entity
+	columns   []reflect.Value // Copies of the column data, in the table's dense column order.
+}
+
+// Snapshot creates a copy of the world's current state.
+// Use [World.Restore] to reset the world to the state of the snapshot.
+//
+// Component data is copied column by column.
+// Component values and resources are copied shallowly,
+// i.e. pointers, slices and maps in them are shared with the world.
+func (w *World) Snapshot() *Snapshot {
+	s := &w.storage
+	snap := Snapshot{
+		world:        w,
+		unregistered: s.registry.unregistered,
+		pool:         s.entityPool.Clone(),
+		entities:     append([]entityIndex(nil), s.entities...),
+		isTarget:     append([]bool(nil), s.isTarget...),
+	}
+
+	for i := range s.tables {
+		table := &s.tables[i]
+		if table.isFree || table.len == 0 {
+			continue
+		}
+		snap.tables = append(snap.tables, newTableSnapshot(table))
+	}
+
+	if s.registry.hasSparse {
+		snap.sparse = make([]*sparseSet, len(s.sparse))
+		for i, set := range s.sparse {
+			if set != nil {
+				snap.sparse[i] = set.Clone(nil)
+			}
+		}
+	}
+
+	if s.registry.hasMultiRelations {
+		snap.multi = make([]*multiRelation, len(s.multi))
+		for i, m := range s.multi {
+			if m != nil {
+				snap.multi[i] = m.Clone()
+			}
+		}
+	}
+
+	snap.resources = make([]any, len(w.resources.resources))
+	for i, res := range w.resources.resources {
+		if res != nil {
+			snap.resources[i] = copyResource(res)
+		}
+	}
+
+	return &snap
+}
+
+// Restore resets the world to the state of the given [Snapshot].
+//
+// All entities, components, relation targets and resources are reset to their state at the time of the snapshot.
+// Entities keep their IDs and generations, so that stored entities remain valid.
+// Entity creation after restoring is deterministic, i.e. it re-issues the same entities as after taking the snapshot.
+// Thus, entities created after taking the snapshot should not be stored beyond restoring it,
+// as they may be recognized as alive again.
+// Resources present in the snapshot are written into the world's existing resource pointers, if possible.
+//
+// Observers are not notified.
+// For change detection, all restored components are marked as added at the current tick.
+// Registered components, archetypes, filters and observers are not affected.
+//
+// Panics if the world is locked, if the snapshot was taken from a different world,
+// or if component types were unregistered after taking it (see [World.UnregisterComponent]).
+func (w *World) Restore(snap *Snapshot) {
+	w.checkLocked()
+	if snap.world != w {
+		panic("can't restore a snapshot taken from a different world")
+	}
+	if snap.unregistered != w.storage.registry.unregistered {
+		panic("can't restore a snapshot taken before component types were unregistered")
+	}
+	s := &w.storage
+	s.clearTables()
+
+	s.entityPool.Restore(&snap.pool)
+	s.entities = append(s.entities[:0], snap.entities...)
+	s.isTarget = append(s.isTarget[:0], snap.isTarget...)
+
+	for i := range snap.tables {
+		s.restoreTable(&snap.tables[i])
+	}
+	for i, set := range s.sparse {
+		if set == nil {
+			continue
+		}
+		if i < len(snap.sparse) && snap.sparse[i] != nil {
+			set.Restore(snap.sparse[i])
+		} else {
+			set.Reset()
+		}
+	}
+	for i := range s.multi {
+		if i < len(snap.multi) && snap.multi[i] != nil {
+			s.multi[i] = snap.multi[i].Clone()
+		} else {
+			s.multi[i] = nil
+		}
+	}
+
+	for i := range w.resources.resources {
+		w.resources.resources[i] = restoreResource(w.resources.resources[i], snap.resources[i])
+	}
+}
+
+// newTableSnapshot copies the content of a table.
+func newTableSnapshot(table *table) tableSnapshot {
+	n := int(table.len)
+	snap := tableSnapshot{
+		archetype: table.archetype,
+		relations: append([]relationID(nil), table.relationIDs...),
+		entities:  append([]Entity(nil), table.entities.data.Interface().([]Entity)[:n]...),
+		columns:   make([]reflect.Value, len(table.columns)),
+	}
+	for i := range table.columns {
+		column := &table.columns[i]
+		data := reflect.MakeSlice(reflect.SliceOf(column.elemType), n, n)
+		reflect.Copy(data, column.data.Slice(0, n))
+		snap.columns[i] = data
+	}
+	return snap
+}
+
+// clearTables removes all entities from all tables, and frees all relation tables.
+func (s *storage) clearTables() {
+	for i := range s.archetypes {
+		archetype := &s.archetypes[i]
+		if !archetype.HasRelations() {
+			s.tables[archetype.tables.tables[0]].Reset()
+			continue
+		}
+		for _, id := range archetype.tables.tables {
+			table := &s.tables[id]
+			table.Reset()
+			s.cache.removeTable(table)
+		}
+		archetype.FreeAllTables(s)
+	}
+}
+
+// restoreTable restores the content of a table snapshot into the matching table.
+// Creates the table if it does not exist.
+func (s *storage) restoreTable(snap *tableSnapshot) {
+	archetype := &s.archetypes[snap.archetype]
+	table, ok := archetype.GetTable(s, snap.relations)
+	if !ok {
+		relations := append([]relationID(nil), snap.relations...)
+		table = s.createTable(archetype, relations)
+	}
+
+	n := uint32(len(snap.entities))
+	table.Extend(n)
+	table.len = n
+
+	copy(table.entities.data.Interface().([]Entity), snap.entities)
+	for i := range table.columns {
+		reflect.Copy(table.columns[i].data, snap.columns[i])
+	}
+	for i, e := range snap.entities {
+		s.entities[e.id] = entityIndex{table: table.id, row: uint32(i)}
+	}
+	table.SetAdded(0, n, nil, s.tick)
+}
+
+// copyResource creates a shallow copy of a resource.
+// Resources are expected to be pointers; other values are returned as they are.
+func copyResource(res any) any {
+	value := reflect.ValueOf(res)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return res
+	}
+	cp := reflect.New(value.Elem().Type())
+	cp.Elem().Set(value.Elem())
+	return cp.Interface()
+}
+
+// restoreResource restores a resource from a snapshot.
+// If possible, the snapshot's value is written into the current resource pointer.
+// Otherwise, a copy of the snapshot's resource is returned.
+func restoreResource(current any, snap any) any {
+	if snap == nil {
+		return nil
+	}
+	if current != nil && reflect.TypeOf(current) == reflect.TypeOf(snap) {
+		value := reflect.ValueOf(current)
+		if value.Kind() == reflect.Pointer && !value.IsNil() {
+			value.Elem().Set(reflect.ValueOf(snap).Elem())
+			return current
+		}
+	}
+	return copyResource(snap)
+}
@@ -0,0 +1,260 @@
+package ecs
+
+import (
+	"testing"
+)
+
+type snapshotResource struct {
+	Value int
+	Items []int
+}
+
+func TestWorldSnapshot(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+	mapper := NewMap2[Position, Velocity](w)
+	res := NewResource[snapshotResource](w)
+
+	res.Add(&snapshotResource{Value: 1})
+
+	entities := []Entity{}
+	for i := range 10 {
+		entities = append(entities, mapper.NewEntity(&Position{float64(i), 0}, &Velocity{0, float64(i)}))
+	}
+	for i := range 5 {
+		entities = append(entities, posMap.NewEntity(&Position{float64(i + 10), 0}))
+	}
+	w.RemoveEntity(entities[3])
+	removed := entities[3]
+
+	snap := w.Snapshot()
+
+	// Modify the world.
+	for _, e := range entities[:5] {
+		if w.Alive(e) {
+			posMap.Get(e).X = -1
+		}
+	}
+	w.RemoveEntity(entities[0])
+	velMap.Add(entities[10], &Velocity{1, 1})
+	newEntity := posMap.NewEntity(&Position{100, 100})
+	res.Get().Value = 2
+	resPtr := res.Get()
+	headRes := NewResource[Heading](w)
+	headRes.Add(&Heading{})
+
+	w.Restore(snap)
+
+	expectFalse(t, w.Alive(removed))
+	expectFalse(t, w.Alive(newEntity))
+	for i, e := range entities {
+		if e == removed {
+			continue
+		}
+		expectTrue(t, w.Alive(e))
+		if i < 10 {
+			expectEqual(t, Position{float64(i), 0}, *posMap.Get(e))
+			expectEqual(t, Velocity{0, float64(i)}, *velMap.Get(e))
+		} else {
+			expectEqual(t, Position{float64(i), 0}, *posMap.Get(e))
+			expectFalse(t, velMap.Has(e))
+		}
+	}
+
+	expectEqual(t, 1, res.Get().Value)
+	expectTrue(t, resPtr == res.Get())
+	expectFalse(t, headRes.Has())
+
+	query := NewFilter1[Position](w).Query()
+	expectEqual(t, 14, query.Count())
+	query.Close()
+
+	// Entity recycling continues as in the original world.
+	e := w.NewEntity()
+	expectEqual(t, removed.id, e.id)
+	expectEqual(t, removed.gen+1, e.gen)
+
+	// Snapshots can be restored multiple times.
+	posMap.Get(entities[1]).X = 99
+	w.Restore(snap)
+	expectEqual(t, Position{1, 0}, *posMap.Get(entities[1]))
+
+	// Entity creation is deterministic after restoring.
+	expectEqual(t, e, w.NewEntity())
+}
+
+func TestWorldSnapshotAlive(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	e1 := posMap.NewEntity(&Position{})
+	removed := posMap.NewEntity(&Position{})
+	w.RemoveEntity(removed)
+
+	snap := w.Snapshot()
+
+	// Grow the entity pool well beyond its size at the time of the snapshot.
+	recycled := w.NewEntity()
+	entities := []Entity{}
+	for range 100 {
+		entities = append(entities, posMap.NewEntity(&Position{}))
+	}
+
+	w.Restore(snap)
+
+	expectTrue(t, w.Alive(e1))
+	expectFalse(t, w.Alive(removed))
+	for _, e := range entities {
+		expectFalse(t, w.Alive(e))
+	}
+	// Entities recycled after the snapshot are re-issued with the same generation.
+	expectEqual(t, recycled, w.NewEntity())
+
+	w.Restore(snap)
+	for _, e := range entities {
+		expectFalse(t, w.Alive(e))
+	}
+	e := w.NewEntity()
+	expectEqual(t, recycled, e)
+	expectTrue(t, w.Alive(e))
+}
+
+func TestWorldSnapshotRelations(t *testing.T) {
+	w := NewWorld(4)
+	childMap := NewMap2[Position, ChildOf](w)
+	relMap := NewMap[ChildOf](w)
+	filter := NewFilter1[ChildOf](w).Register()
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	childMap.NewBatch(5, &Position{}, &ChildOf{}, Rel[ChildOf](parent1))
+	childMap.NewBatch(3, &Position{}, &ChildOf{}, Rel[ChildOf](parent2))
+
+	snap := w.Snapshot()
+
+	w.RemoveEntity(parent1)
+	parent3 := w.NewEntity()
+	childMap.NewEntity(&Position{}, &ChildOf{}, Rel[ChildOf](parent3))
+
+	w.Restore(snap)
+
+	expectTrue(t, w.Alive(parent1))
+
+	expectEqual(t, 5, countChildren(filter, parent1))
+	expectEqual(t, 3, countChildren(filter, parent2))
+
+	query := filter.Query()
+	cnt := 0
+	for query.Next() {
+		expectEqual(t, query.GetRelation(0), relMap.GetRelation(query.Entity()))
+		cnt++
+	}
+	expectEqual(t, 8, cnt)
+
+	// Cleanup still works after restore.
+	w.RemoveEntity(parent1)
+	expectEqual(t, 5, countChildren(filter, Entity{}))
+}
+
+func TestWorldSnapshotMultiRelations(t *testing.T) {
+	w := NewWorld(4)
+	likesMap := NewMap[Likes](w)
+	filter := NewFilter1[Likes](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	e := likesMap.NewEntity(&Likes{}, a, b)
+
+	snap := w.Snapshot()
+
+	w.RemoveEntity(a)
+	likesMap.AddTargets(e, w.NewEntity())
+
+	w.Restore(snap)
+	expectSlicesEqual(t, []Entity{a, b}, likesMap.GetRelations(e))
+	expectEqual(t, 1, countLikes(filter.Query(Rel[Likes](a))))
+
+	w.RemoveEntity(a)
+	expectSlicesEqual(t, []Entity{b}, likesMap.GetRelations(e))
+	expectEqual(t, 1, countLikes(filter.Query(Rel[Likes](b))))
+}
+
+func TestWorldSnapshotChanges(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	posMap.NewBatch(5, &Position{})
+
+	snap := w.Snapshot()
+	since := w.AdvanceTick()
+
+	w.Restore(snap)
+
+	filter := NewFilter0(w).Added(C[Position]()).Since(since)
+	query := filter.Query()
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, 5, cnt)
+}
+
+func TestWorldSnapshotErrors(t *testing.T) {
+	w1 := NewWorld(4)
+	w2 := NewWorld(4)
+
+	snap := w1.Snapshot()
+	expectPanicsWithValue(t, "can't restore a snapshot taken from a different world", func() {
+		w2.Restore(snap)
+	})
+
+	query := NewFilter0(w1).Query()
+	expectPanics(t, func() {
+		w1.Restore(snap)
+	})
+	query.Close()
+
+	w3 := NewWorld(4)
+	NewMap[Position](w3).NewEntity(&Position{})
+	NewMap[Velocity](w3)
+	snap = w3.Snapshot()
+	w3.UnregisterComponent(C[Velocity]())
+	expectPanicsWithValue(t, "can't restore a snapshot taken before component types were unregistered", func() {
+		w3.Restore(snap)
+	})
+}
+
+func TestWorldSnapshotResources(t *testing.T) {
+	w := NewWorld(4)
+	res := NewResource[snapshotResource](w)
+
+	snap := w.Snapshot()
+	res.Add(&snapshotResource{Value: 1, Items: []int{1}})
+
+	snap2 := w.Snapshot()
+	res.Get().Value = 2
+
+	w.Restore(snap)
+	expectFalse(t, res.Has())
+
+	w.Restore(snap2)
+	expectEqual(t, 1, res.Get().Value)
+	expectEqual(t, 1, len(res.Get().Items))
+
+	res.Get().Value = 5
+	w.Restore(snap2)
+	expectEqual(t, 1, res.Get().Value)
+
+	// Non-pointer and nil resources are kept as they are.
+	headID := ResourceID[Heading](w)
+	posID := ResourceID[Position](w)
+	w.Resources().Add(headID, Heading{H: 1})
+	w.Resources().Add(posID, (*Position)(nil))
+	snap3 := w.Snapshot()
+	w.Resources().Remove(headID)
+	w.Resources().Remove(posID)
+
+	w.Restore(snap3)
+	expectEqual(t, Heading{H: 1}, w.Resources().Get(headID).(Heading))
+	expectTrue(t, w.Resources().Get(posID).(*Position) == nil)
+}
@@ -0,0 +1,191 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Initial capacity of sparse sets.
+const sparseSetCapacity = 16
+
+// sparseSet stores the components of a component type with sparse storage (see [StorageSparse]),
+// keyed by entity ID.
+//
+// Components are stored densely, with a sparse index from entity IDs to dense indices.
+// Adding and removing components does not move entities between archetypes.
+type sparseSet struct {
+	indices  []uint32 // Dense index plus 1 for each entity ID, 0 if absent
+	entities []Entity // Entities in dense order
+	data     column   // Components in dense order
+	len      uint32   // Number of stored components
+}
+
+// newSparseSet creates a new sparse set for the given component type.
+func newSparseSet(tp reflect.Type, isTrivial bool) *sparseSet {
+	return &sparseSet{
+		entities: make([]Entity, 0, sparseSetCapacity),
+		data:     newColumn(0, tp, tp.Size(), false, isTrivial, Entity{}, sparseSetCapacity),
+	}
+}
+
+// Len returns the number of stored components.
+func (s *sparseSet) Len() int {
+	return int(s.len)
+}
+
+// Has returns whether the set contains a component for the given entity.
+func (s *sparseSet) Has(entity Entity) bool {
+	return int(entity.id) < len(s.indices) && s.indices[entity.id] != 0
+}
+
+// Get returns a pointer to the component of the given entity, or nil if the entity has none.
+func (s *sparseSet) Get(entity Entity) unsafe.Pointer {
+	if int(entity.id) >= len(s.indices) {
+		return nil
+	}
+	index := s.indices[entity.id]
+	if index == 0 {
+		return nil
+	}
+	return s.data.Get(uintptr(index - 1))
+}
+
+// Add adds a zero-initialized component for the given entity, and returns a pointer to it.
+//
+// Panics if the entity already has the component.
+func (s *sparseSet) Add(entity Entity) unsafe.Pointer {
+	if s.Has(entity) {
+		panic("entity already has the sparse component")
+	}
+	if int(entity.id) >= len(s.indices) {
+		indices := make([]uint32, capPow2(uint32(entity.id)+1))
+		copy(indices, s.indices)
+		s.indices = indices
+	}
+	if s.len == uint32(s.data.data.Len()) {
+		s.data.adjustCapacity(s.len, capPow2(s.len+1))
+	}
+	index := s.len
+	s.len++
+	s.entities = append(s.entities, entity)
+	s.indices[entity.id] = index + 1
+	return s.data.Get(uintptr(index))
+}
+
+// Remove swap-removes the component of the given entity.
+// Returns whether the entity had the component.
+func (s *sparseSet) Remove(entity Entity) bool {
+	if !s.Has(entity) {
+		return false
+	}
+	index := s.indices[entity.id] - 1
+	last := s.len - 1
+	if index != last {
+		s.data.Set(index, &s.data, last)
+		swapEntity := s.entities[last]
+		s.entities[index] = swapEntity
+		s.indices[swapEntity.id] = index + 1
+	}
+	s.data.Zero(uintptr(last))
+	s.entities = s.entities[:last]
+	s.indices[entity.id] = 0
+	s.len--
+	return true
+}
+
+// copyTo copies the component of entity from to entity to in set dst,
+// which must be for the same component type.
+// Does nothing if entity from has no component.
+func (s *sparseSet) copyTo(dst *sparseSet, from, to Entity) {
+	if !s.Has(from) {
+		return
+	}
+	dst.Add(to)
+	dst.data.Set(dst.len-1, &s.data, s.indices[from.id]-1)
+}
+
+// Reset removes all components.
+func (s *sparseSet) Reset() {
+	s.data.ZeroRange(0, s.len)
+	clear(s.indices)
+	s.entities = s.entities[:0]
+	s.len = 0
+}
+
+// Clone creates an independent copy of the set.
+// Components are copied using the given cloner, or shallowly if it is nil.
+func (s *sparseSet) Clone(cloner func(dst, src unsafe.Pointer)) *sparseSet {
+	clone := &sparseSet{
+		indices:  append([]uint32(nil), s.indices...),
+		entities: append([]Entity(nil), s.entities...),
+		data:     newColumn(0, s.data.elemType, s.data.itemSize, false, s.data.isTrivial, Entity{}, max(s.len, sparseSetCapacity)),
+		len:      s.len,
+	}
+	for i := range s.len {
+		if cloner != nil {
+			cloner(clone.data.Get(uintptr(i)), s.data.Get(uintptr(i)))
+		} else {
+			clone.data.Set(i, &s.data, i)
+		}
+	}
+	return clone
+}
+
+// Restore replaces the content of the set by the content of the given set,
+// which must be for the same component type.
+func (s *sparseSet) Restore(other *sparseSet) {
+	s.Reset()
+	for i, entity := range other.entities {
+		s.Add(entity)
+		s.data.Set(uint32(i), &other.data, uint32(i))
+	}
+}
+
+// setSparse switches a newly registered component to sparse storage.
+func (s *storage) setSparse(id idIndex) {
+	s.registry.Storage[id] = StorageSparse
+	s.sparse[id] = newSparseSet(s.registry.Types[id], s.registry.IsTrivial[id])
+	s.registry.hasSparse = true
+}
+
+// isSparse returns whether the given component uses sparse storage.
+func (s *storage) isSparse(id ID) bool {
+	return s.registry.Storage[id.id] == StorageSparse
+}
+
+// checkNotSparse panics if the given component uses sparse storage.
+func (s *storage) checkNotSparse(id ID, op string) {
+	if s.isSparse(id) {
+		panic(fmt.Sprintf("%s is not supported for sparse component with ID %d", op, id.id))
+	}
+}
+
+// removeSparse removes all sparse components of the given entity.
+func (s *storage) removeSparse(entity Entity) {
+	for _, set := range s.sparse {
+		if set != nil {
+			set.Remove(entity)
+		}
+	}
+}
+
+// sparseParams moves the sparse components among the given filter parameters
+// from the filter's mask to the filter's entity conditions.
+// Returns the sparse sets of the parameters, or nil if none of them uses sparse storage.
+func (s *storage) sparseParams(ids []ID, f *filter, conditions func() *entityConditions) []*sparseSet {
+	var sets []*sparseSet
+	for i, id := range ids {
+		if !s.isSparse(id) {
+			continue
+		}
+		if sets == nil {
+			sets = make([]*sparseSet, len(ids))
+		}
+		sets[i] = s.sparse[id.id]
+		f.mask.Clear(id.id)
+		c := conditions()
+		c.sparseWith = append(c.sparseWith, sets[i])
+	}
+	return sets
+}
@@ -0,0 +1,376 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSparseSet(t *testing.T) {
+	w := NewWorld(16)
+	set := newSparseSet(reflect.TypeFor[Position](), true)
+
+	entities := make([]Entity, 0, 40)
+	for range 40 {
+		entities = append(entities, w.NewEntity())
+	}
+
+	for i, e := range entities {
+		(*Position)(set.Add(e)).X = float64(i)
+	}
+	expectEqual(t, 40, set.Len())
+	expectTrue(t, set.Has(entities[5]))
+	expectEqual(t, 5.0, (*Position)(set.Get(entities[5])).X)
+	expectTrue(t, set.Get(Entity{id: 1000}) == nil)
+	expectFalse(t, set.Has(Entity{id: 1000}))
+
+	expectPanicsWithValue(t, "entity already has the sparse component",
+		func() {
+			set.Add(entities[0])
+		})
+
+	expectTrue(t, set.Remove(entities[5]))
+	expectFalse(t, set.Remove(entities[5]))
+	expectFalse(t, set.Has(entities[5]))
+	expectTrue(t, set.Get(entities[5]) == nil)
+	expectEqual(t, 39, set.Len())
+	expectEqual(t, 39.0, (*Position)(set.Get(entities[39])).X)
+
+	clone := set.Clone(nil)
+	expectTrue(t, set.Remove(entities[39]))
+	expectEqual(t, 39, clone.Len())
+	expectEqual(t, 39.0, (*Position)(clone.Get(entities[39])).X)
+
+	set.Restore(clone)
+	expectEqual(t, 39, set.Len())
+	expectEqual(t, 39.0, (*Position)(set.Get(entities[39])).X)
+	expectFalse(t, set.Has(entities[5]))
+
+	set.Reset()
+	expectEqual(t, 0, set.Len())
+	expectFalse(t, set.Has(entities[0]))
+}
+
+func TestMapSparse(t *testing.T) {
+	w := NewWorld(16)
+	RegisterComponent(w, ComponentOptions[Heading]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+
+	e1 := posMap.NewEntity(&Position{X: 1})
+	e2 := headMap.NewEntity(&Heading{H: 2})
+
+	expectTrue(t, headMap.Has(e2))
+	expectFalse(t, headMap.Has(e1))
+	expectTrue(t, headMap.Get(e1) == nil)
+	expectEqual(t, 2.0, headMap.Get(e2).H)
+	expectEqual(t, 2.0, headMap.GetMut(e2).H)
+	expectEqual(t, 1, w.storage.sparse[headMap.id.id].Len())
+
+	numArchetypes := len(w.storage.archetypes)
+	headMap.Add(e1, &Heading{H: 3})
+	expectEqual(t, numArchetypes, len(w.storage.archetypes))
+	expectTrue(t, headMap.HasUnchecked(e1))
+	expectEqual(t, 3.0, headMap.GetUnchecked(e1).H)
+	expectEqual(t, 1.0, posMap.Get(e1).X)
+
+	headMap.Set(e1, &Heading{H: 4})
+	expectEqual(t, 4.0, headMap.Get(e1).H)
+
+	headMap.Remove(e1)
+	expectFalse(t, headMap.Has(e1))
+	expectTrue(t, posMap.Has(e1))
+
+	expectPanicsWithValue(t, "entity does not have component with ID 0",
+		func() {
+			headMap.Remove(e1)
+		})
+	expectPanicsWithValue(t, "entity does not have component with ID 0",
+		func() {
+			headMap.Set(e1, &Heading{})
+		})
+	expectPanicsWithValue(t, "entity already has component with ID 0",
+		func() {
+			headMap.Add(e2, &Heading{})
+		})
+	expectPanicsWithValue(t, "batch creation is not supported for sparse component with ID 0",
+		func() {
+			headMap.NewBatch(10, &Heading{})
+		})
+	expectPanicsWithValue(t, "batch addition is not supported for sparse component with ID 0",
+		func() {
+			headMap.AddBatch(NewFilter0(w).Batch(), &Heading{})
+		})
+	expectPanicsWithValue(t, "batch removal is not supported for sparse component with ID 0",
+		func() {
+			headMap.RemoveBatch(NewFilter0(w).Batch(), nil)
+		})
+	expectPanicsWithValue(t, "Map2 is not supported for sparse component with ID 0",
+		func() {
+			NewMap2[Position, Heading](w)
+		})
+	expectPanicsWithValue(t, "Exchange2 is not supported for sparse component with ID 0",
+		func() {
+			NewExchange2[Position, Heading](w)
+		})
+	expectPanicsWithValue(t, "CommandMap is not supported for sparse component with ID 0",
+		func() {
+			NewCommandMap[Heading](NewCommandBuffer(w))
+		})
+	expectPanicsWithValue(t, "CommandMap2 is not supported for sparse component with ID 0",
+		func() {
+			NewCommandMap2[Position, Heading](NewCommandBuffer(w))
+		})
+	expectPanicsWithValue(t, "CommandExchange2 is not supported for sparse component with ID 0",
+		func() {
+			NewCommandExchange2[Position, Heading](NewCommandBuffer(w))
+		})
+
+	w.RemoveEntity(e2)
+	expectEqual(t, 0, w.storage.sparse[headMap.id.id].Len())
+	e3 := w.NewEntity()
+	expectEqual(t, e2.id, e3.id)
+	expectFalse(t, headMap.Has(e3))
+
+	query := NewFilter0(w).Query()
+	expectPanicsWithValue(t, "cannot modify a locked world: collect entities into a slice and apply changes after query iteration has completed",
+		func() {
+			headMap.Add(e3, &Heading{})
+		})
+	query.Close()
+}
+
+func TestFilterSparse(t *testing.T) {
+	w := NewWorld(16)
+	RegisterComponent(w, ComponentOptions[Heading]{Storage: StorageSparse})
+	RegisterComponent(w, ComponentOptions[Label]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	labelMap := NewMap[Label](w)
+
+	entities := []Entity{}
+	for i := range 10 {
+		e := posMap.NewEntity(&Position{X: float64(i)})
+		entities = append(entities, e)
+		if i%2 == 0 {
+			headMap.Add(e, &Heading{H: float64(i)})
+		}
+		if i%3 == 0 {
+			labelMap.Add(e, &Label{})
+		}
+	}
+
+	cnt := 0
+	filter := NewFilter2[Position, Heading](w)
+	query := filter.Query()
+	for query.Next() {
+		pos, head := query.Get()
+		expectEqual(t, pos.X, head.H)
+		cnt++
+	}
+	expectEqual(t, 5, cnt)
+
+	cnt = 0
+	filter2 := NewFilter1[Position](w).With(C[Heading]()).Without(C[Label]())
+	query2 := filter2.Query()
+	for query2.Next() {
+		pos := query2.Get()
+		expectTrue(t, int(pos.X)%2 == 0)
+		expectTrue(t, int(pos.X)%3 != 0)
+		cnt++
+	}
+	expectEqual(t, 3, cnt)
+
+	cnt = 0
+	withHead := 0
+	filter3 := NewFilter2[Position, Heading](w).Optional(C[Heading]())
+	query3 := filter3.Query()
+	for query3.Next() {
+		_, head := query3.GetOptional()
+		if head != nil {
+			withHead++
+		}
+		cnt++
+	}
+	expectEqual(t, 10, cnt)
+	expectEqual(t, 5, withHead)
+
+	cnt = 0
+	filter4 := NewFilter1[Heading](w).Register()
+	query4 := filter4.Query()
+	for query4.Next() {
+		head := query4.Get()
+		head.H = -1
+		cnt++
+	}
+	expectEqual(t, 5, cnt)
+	expectEqual(t, -1.0, headMap.Get(entities[4]).H)
+
+	for _, f := range []*Filter1[Position]{
+		NewFilter1[Position](w).With(C[Heading]()),
+		NewFilter1[Position](w).With(C[Heading]()).Register(),
+		NewFilter1[Position](w).Without(C[Heading]()),
+		NewFilter1[Position](w).With(C[Heading](), C[Label]()),
+	} {
+		query := f.Query()
+		count := query.Count()
+		found := []Entity{}
+		for query.Next() {
+			found = append(found, query.Entity())
+		}
+		expectEqual(t, count, len(found))
+		query = f.Query()
+		for i, e := range found {
+			expectEqual(t, e, query.EntityAt(i))
+		}
+		query.Close()
+	}
+
+	driven := NewFilter2[Position, Label](w).With(C[Heading]()).Query()
+	expectEqual(t, w.storage.sparse[labelMap.id.id].Len(), len(driven.driver))
+	cnt = 0
+	for driven.Next() {
+		pos, _ := driven.Get()
+		expectTrue(t, int(pos.X)%6 == 0)
+		expectEqual(t, pos, posMap.Get(driven.Entity()))
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	w.NewEntity()
+	labelMap.Add(w.NewEntity(), &Label{})
+	query5 := NewFilter1[Label](w).Query()
+	expectEqual(t, 5, query5.Count())
+	cnt = 0
+	for query5.Next() {
+		cnt++
+	}
+	expectEqual(t, 5, cnt)
+
+	expectPanicsWithValue(t, "table-based iteration is not supported for filters with sparse components",
+		func() {
+			query := NewFilter1[Heading](w).Query()
+			defer query.Close()
+			query.NextTable()
+		})
+	expectPanicsWithValue(t, "table-based iteration is not supported for filters with sparse components",
+		func() {
+			query := NewFilter1[Position](w).Without(C[Label]()).Query()
+			defer query.Close()
+			query.ParallelTables(2, func(chunk *Chunk, entities []Entity, pos []Position) {})
+		})
+
+	expectPanicsWithValue(t, "batch operations are not supported for filters with sparse components",
+		func() {
+			filter.Batch()
+		})
+	expectPanicsWithValue(t, "AnyOf is not supported for sparse component with ID 0",
+		func() {
+			NewFilter0(w).AnyOf(C[Heading]())
+		})
+	expectPanicsWithValue(t, "change detection is not supported for sparse component with ID 0",
+		func() {
+			NewFilter0(w).Changed(C[Heading]())
+		})
+	expectPanicsWithValue(t, "change detection is not supported for sparse component with ID 0",
+		func() {
+			NewFilter0(w).Added(C[Heading]())
+		})
+}
+
+func TestWorldSparse(t *testing.T) {
+	w := NewWorld(16)
+	headID := RegisterComponent(w, ComponentOptions[Heading]{
+		Storage: StorageSparse,
+	})
+	info, _ := ComponentInfo(w, headID)
+	expectEqual(t, StorageSparse, info.Storage)
+
+	expectPanicsWithValue(t, "relation component type ChildOf can't use sparse storage",
+		func() {
+			RegisterComponent(w, ComponentOptions[ChildOf]{Storage: StorageSparse})
+		})
+	expectPanicsWithValue(t, "RegisterHooks is not supported for sparse component with ID 0",
+		func() {
+			RegisterHooks(w, Hooks[Heading]{})
+		})
+	expectPanicsWithValue(t, "observing is not supported for sparse component with ID 0",
+		func() {
+			Observe(OnAddComponents).For(C[Heading]()).Do(func(e Entity) {}).Register(w)
+		})
+	expectPanicsWithValue(t, "observing is not supported for sparse component with ID 0",
+		func() {
+			Observe(OnCreateEntity).With(C[Heading]()).Do(func(e Entity) {}).Register(w)
+		})
+	expectPanicsWithValue(t, "observing is not supported for sparse component with ID 0",
+		func() {
+			Observe1[Heading](OnSetComponents).Do(func(e Entity, h *Heading) {}).Register(w)
+		})
+	expectFalse(t, w.storage.observers.HasObservers(OnAddComponents))
+
+	posMap := NewMap[Position](w)
+	headMap := NewMap[Heading](w)
+	e1 := posMap.NewEntity(&Position{})
+	e2 := posMap.NewEntity(&Position{})
+	headMap.Add(e1, &Heading{H: 1})
+
+	snap := w.Snapshot()
+
+	clone := w.Clone()
+	headMap2 := NewMap[Heading](clone)
+	expectEqual(t, 1.0, headMap2.Get(e1).H)
+	headMap2.Get(e1).H = 2
+	expectEqual(t, 1.0, headMap.Get(e1).H)
+
+	headMap.Remove(e1)
+	headMap.Add(e2, &Heading{H: 3})
+	w.Restore(snap)
+	expectTrue(t, headMap.Has(e1))
+	expectFalse(t, headMap.Has(e2))
+	expectEqual(t, 1.0, headMap.Get(e1).H)
+
+	expectPanicsWithValue(t, "can't unregister component with ID 0, as it is used by entities",
+		func() {
+			w.UnregisterComponent(C[Heading]())
+		})
+
+	w.RemoveEntities(NewFilter0(w).Batch(), nil)
+	expectEqual(t, 0, w.storage.sparse[headID.id].Len())
+
+	headMap.Add(w.NewEntity(), &Heading{})
+	w.Reset()
+	expectEqual(t, 0, w.storage.sparse[headID.id].Len())
+
+	w.UnregisterComponent(C[Heading]())
+	expectTrue(t, w.storage.sparse[headID.id] == nil)
+}
+
+func TestWorldSparseCloneSnapshot(t *testing.T) {
+	w := NewWorld(16)
+	RegisterComponent(w, ComponentOptions[SliceComp]{
+		Storage: StorageSparse,
+		Cloner: func(comp *SliceComp) SliceComp {
+			return SliceComp{Slice: append([]int(nil), comp.Slice...)}
+		},
+	})
+	sliceMap := NewMap[SliceComp](w)
+	e := sliceMap.NewEntity(&SliceComp{Slice: []int{1, 2}})
+
+	clone := w.Clone()
+	sliceMap2 := NewMap[SliceComp](clone)
+	sliceMap2.Get(e).Slice[0] = 10
+	expectSlicesEqual(t, []int{1, 2}, sliceMap.Get(e).Slice)
+
+	snap := w.Snapshot()
+
+	// Sparse components registered after the snapshot are cleared on restore.
+	RegisterComponent(w, ComponentOptions[Heading]{Storage: StorageSparse})
+	headMap := NewMap[Heading](w)
+	headMap.Add(e, &Heading{H: 1})
+
+	w.Restore(snap)
+	expectTrue(t, sliceMap.Has(e))
+	expectFalse(t, headMap.Has(e))
+	expectEqual(t, 0, w.storage.sparse[headMap.id.id].Len())
+}
@@ -0,0 +1,7 @@
+//go:build ark_large && !ark_tiny
+
+package stats
+
+// ComponentIndex is the type of component indices in [Archetype.ComponentIDs].
+// It is uint8 without build tag ark_large.
+type ComponentIndex = uint16
@@ -46,7 +46,7 @@ type Entities struct {
 // Archetype statistics.
 type Archetype struct {
 	// Component IDs.
-	ComponentIDs []uint8
+	ComponentIDs []ComponentIndex
 	// Component types for ComponentIDs.
 	// Note that this field is excluded from JSON marshalling and un-marshalling.
 	// Use ComponentTypeNames instead.
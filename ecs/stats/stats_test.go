@@ -16,14 +16,14 @@ func TestStats(t *testing.T) {
 			{
 				Size:               1,
 				Capacity:           128,
-				ComponentIDs:       []uint8{0},
+				ComponentIDs:       []ComponentIndex{0},
 				ComponentTypes:     []reflect.Type{reflect.TypeOf(1)},
 				ComponentTypeNames: []string{"int"},
 			},
 			{
 				Size:               1,
 				Capacity:           128,
-				ComponentIDs:       []uint8{0},
+				ComponentIDs:       []ComponentIndex{0},
 				ComponentTypes:     []reflect.Type{reflect.TypeOf(1)},
 				ComponentTypeNames: []string{"int"},
 			},
@@ -19,6 +19,7 @@ type storage struct {
 	componentIndex     [][]archetypeID           // Archetypes indexed by components IDs; each archetype appears under all its component IDs
 	relationArchetypes []archetypeID             // All archetypes with relationships
 	tables             []table                   // All tables
+	retiredTables      []tableID                 // Tables of removed archetypes, for re-use by other archetypes
 	components         []componentStorage        // Component storages for fast random/world access
 	cache              cache                     // Filter cache
 	entityPool         entityPool                // Entity pool for creation and recycling
@@ -27,6 +28,9 @@ type storage struct {
 	config             config                    // Storage configuration (initial capacities)
 	slices             *slices                   // Slices for internal re-use
 	observers          *observerManager          // Observer/event manager
+	sparse             []*sparseSet              // Sparse sets of components with sparse storage, indexed by component ID
+	multi              []*multiRelation          // Targets of multi-target relation components, indexed by component ID
+	tick               uint32                    // Current change tick
 }
 
 // componentStorage is an index for faster access of table columns by component ID.
@@ -105,6 +109,9 @@ func newStorage(numArchetypes int, capacity ...int) storage {
 		componentIndex: make([][]archetypeID, 0, maskTotalBits),
 		tables:         tables,
 		components:     make([]componentStorage, 0, maskTotalBits),
+		sparse:         make([]*sparseSet, maskTotalBits),
+		multi:          make([]*multiRelation, maskTotalBits),
+		tick:           1,
 	}
 }
 
@@ -133,6 +140,9 @@ func (s *storage) findOrCreateTable(oldTable *table, add []ID, remove []ID, rela
 			}
 		}
 		allRelations = append(allRelations, relations...)
+		if oldTable.hasMultiRelations && !relationRemoved {
+			relationRemoved = s.removesMultiRelation(oldTable, remove)
+		}
 	} else {
 		if len(relations) > 0 {
 			allRelations = append(allRelations, oldTable.relationIDs...)
@@ -219,6 +229,9 @@ func (s *storage) findOrCreateTableRemove(oldTable *table, remove []ID, outMask
 			relationRemoved = true
 		}
 	}
+	if oldTable.hasMultiRelations && !relationRemoved {
+		relationRemoved = s.removesMultiRelation(oldTable, remove)
+	}
 	table, ok := arch.GetTable(s, allRelations)
 	if !ok {
 		// Copy the slice, as it comes from the slice pool
@@ -230,8 +243,12 @@ func (s *storage) findOrCreateTableRemove(oldTable *table, remove []ID, outMask
 	return table, arch, relationRemoved
 }
 
-// AddComponent adds a component ID to the storage
-func (s *storage) AddComponent(id uint8) {
+// AddComponent adds a component ID to the storage.
+// Does nothing for re-used IDs of unregistered components, as they are already present.
+func (s *storage) AddComponent(id idIndex) {
+	if int(id) < len(s.components) {
+		return
+	}
 	if len(s.components) != int(id) {
 		panic("components can only be added to a storage sequentially")
 	}
@@ -241,6 +258,120 @@ func (s *storage) AddComponent(id uint8) {
 	s.componentIndex = append(s.componentIndex, []archetypeID{})
 }
 
+// unregisterComponent removes a component type that is not used by any entity,
+// incl. all archetypes that contain it, and frees its ID for re-use.
+func (s *storage) unregisterComponent(id ID) {
+	for _, archID := range s.componentIndex[id.id] {
+		for _, t := range s.archetypes[archID].tables.tables {
+			if s.tables[t].len > 0 {
+				panic(fmt.Sprintf("can't unregister component with ID %d, as it is used by entities", id.id))
+			}
+		}
+	}
+	if set := s.sparse[id.id]; set != nil && set.Len() > 0 {
+		panic(fmt.Sprintf("can't unregister component with ID %d, as it is used by entities", id.id))
+	}
+	if s.observers.references(id) {
+		panic(fmt.Sprintf("can't unregister component with ID %d, as it is used by observers", id.id))
+	}
+	if s.observers.hasComponentHooks(id) {
+		panic(fmt.Sprintf("can't unregister component with ID %d, as it has hooks", id.id))
+	}
+	s.sparse[id.id] = nil
+	s.multi[id.id] = nil
+	s.cache.removeComponent(id)
+	if s.registry.Archetypes[id.id] > 0 {
+		s.removeArchetypes(id)
+	}
+
+	mapping := s.graph.removeComponent(id)
+	for i := range s.archetypes {
+		arch := &s.archetypes[i]
+		arch.node = mapping[arch.node]
+	}
+
+	s.componentIndex[id.id] = s.componentIndex[id.id][:0]
+	s.registry.unregisterComponent(id.id)
+	s.registry.unregistered++
+}
+
+// removeArchetypes removes all archetypes that contain the given component.
+// Their tables must be empty, and are retired for re-use by other archetypes.
+//
+// Archetype IDs are compacted, and all references to them are updated.
+// The archetypes' data stays allocated, as pointers to it must remain stable.
+func (s *storage) removeArchetypes(id ID) {
+	mapping := make([]archetypeID, len(s.archetypes))
+	archetypes := s.archetypes[:0]
+	for i := range s.archetypes {
+		arch := s.archetypes[i]
+		if arch.mask.Get(id.id) {
+			s.retireTables(&arch)
+			for _, comp := range arch.components {
+				s.registry.removeArchetype(comp.id)
+			}
+			mapping[i] = maxArchetypeID
+			continue
+		}
+		mapping[i] = archetypeID(len(archetypes))
+		arch.id = mapping[i]
+		archetypes = append(archetypes, arch)
+	}
+	clear(s.archetypes[len(archetypes):])
+	s.archetypes = archetypes
+
+	for i := range s.tables {
+		table := &s.tables[i]
+		if table.archetype != maxArchetypeID {
+			table.archetype = mapping[table.archetype]
+		}
+	}
+	for i := range s.graph.nodes {
+		node := &s.graph.nodes[i]
+		if node.archetype != maxArchetypeID {
+			node.archetype = mapping[node.archetype]
+		}
+	}
+	s.allArchetypes = remapArchetypes(s.allArchetypes, mapping)
+	s.relationArchetypes = remapArchetypes(s.relationArchetypes, mapping)
+	for i := range s.componentIndex {
+		s.componentIndex[i] = remapArchetypes(s.componentIndex[i], mapping)
+	}
+}
+
+// retireTables removes all tables of the given archetype from the cache and the component index,
+// and releases their memory.
+// The table IDs are kept for re-use by other archetypes.
+func (s *storage) retireTables(arch *archetype) {
+	retire := func(id tableID) {
+		t := &s.tables[id]
+		s.cache.removeTable(t)
+		for _, comp := range t.ids {
+			s.components[comp.id].columns[id] = nil
+		}
+		*t = table{id: id, archetype: maxArchetypeID, isFree: true}
+		s.retiredTables = append(s.retiredTables, id)
+	}
+	for _, id := range arch.tables.tables {
+		retire(id)
+	}
+	for _, id := range arch.freeTables {
+		retire(id)
+	}
+}
+
+// remapArchetypes applies a mapping of archetype IDs to a list of IDs, in place.
+// Archetypes that are mapped to maxArchetypeID are removed from the list.
+func remapArchetypes(ids []archetypeID, mapping []archetypeID) []archetypeID {
+	result := ids[:0]
+	for _, id := range ids {
+		if newID := mapping[id]; newID != maxArchetypeID {
+			result = append(result, newID)
+		}
+	}
+	return result
+}
+
 // RemoveEntity removes the given entity from the world.
 func (s *storage) RemoveEntity(entity Entity) {
 	if !s.entityPool.Alive(entity) {
@@ -250,7 +381,7 @@ func (s *storage) RemoveEntity(entity Entity) {
 	table := &s.tables[index.table]
 
 	hasEntityObs := s.observers.HasObservers(OnRemoveEntity)
-	hasRelationObs := table.HasRelations() && s.observers.HasObservers(OnRemoveRelations)
+	hasRelationObs := (table.HasRelations() || table.hasMultiRelations) && s.observers.HasObservers(OnRemoveRelations)
 	if hasEntityObs || hasRelationObs {
 		l := s.lock()
 		if hasEntityObs {
@@ -267,6 +398,12 @@ func (s *storage) RemoveEntity(entity Entity) {
 	swapped := table.Remove(index.row)
 
 	s.entityPool.Recycle(entity)
+	if s.registry.hasSparse {
+		s.removeSparse(entity)
+	}
+	if table.hasMultiRelations {
+		s.removeAllMultiTargets(entity)
+	}
 
 	if swapped {
 		swapEntity := table.GetEntity(uintptr(index.row))
@@ -280,6 +417,51 @@ func (s *storage) RemoveEntity(entity Entity) {
 	}
 }
 
+// removeComponents removes the given components from an alive entity, and notifies observers.
+func (s *storage) removeComponents(entity Entity, rem []ID) {
+	index := s.entities[entity.id]
+	oldTable := &s.tables[index.table]
+
+	mask := s.archetypes[oldTable.archetype].mask
+	newTable, _, relRemoved := s.findOrCreateTableRemove(oldTable, rem, &mask)
+	newIndex := newTable.Add(entity)
+
+	// Get the old table and archetype again, as the pointer may have changed.
+	oldTable = &s.tables[oldTable.id]
+	oldArchetype := &s.archetypes[oldTable.archetype]
+
+	hasCompObs := s.observers.HasObservers(OnRemoveComponents)
+	hasRelObs := relRemoved && s.observers.HasObservers(OnRemoveRelations)
+	if hasCompObs || hasRelObs {
+		l := s.lock()
+		if hasCompObs {
+			s.observers.FireRemove(OnRemoveComponents, entity, &oldArchetype.mask, &mask)
+		}
+		if hasRelObs {
+			s.observers.FireRemove(OnRemoveRelations, entity, &oldArchetype.mask, &mask)
+		}
+		s.unlock(l)
+	}
+
+	for _, id := range oldArchetype.components {
+		if mask.Get(id.id) {
+			newTable.Set(id, newIndex, oldTable.Column(id), index.row)
+		}
+	}
+
+	swapped := oldTable.Remove(index.row)
+
+	if swapped {
+		swapEntity := oldTable.GetEntity(uintptr(index.row))
+		s.entities[swapEntity.id].row = index.row
+	}
+	s.entities[entity.id] = entityIndex{table: newTable.id, row: newIndex}
+
+	if oldTable.hasMultiRelations {
+		s.removeMultiTargets(entity, rem)
+	}
+}
+
 // Reset the storage.
 func (s *storage) Reset() {
 	s.entities = s.entities[:reservedEntities]
@@ -292,6 +474,16 @@ func (s *storage) Reset() {
 	for i := range s.archetypes {
 		s.archetypes[i].Reset(s)
 	}
+	for _, set := range s.sparse {
+		if set != nil {
+			set.Reset()
+		}
+	}
+	for _, m := range s.multi {
+		if m != nil {
+			m.Reset()
+		}
+	}
 }
 
 // get returns a pointer to the component of given ID for the given entity.
@@ -310,7 +502,15 @@ func (s *storage) get(entity Entity, component ID) unsafe.Pointer {
 func (s *storage) getUnchecked(entity Entity, component ID) unsafe.Pointer {
 	s.checkHasComponent(entity, component)
 	index := s.entities[entity.id]
-	return s.tables[index.table].Get(component, uintptr(index.row))
+	return s.tables[index.table].Column(component).Get(uintptr(index.row))
+}
+
+// markChanged marks the component of given ID for the given entity as changed at the current tick.
+//
+// Does NOT check whether the entity is alive.
+func (s *storage) markChanged(entity Entity, component ID) {
+	index := s.entities[entity.id]
+	s.tables[index.table].Column(component).setChanged(uintptr(index.row), s.tick)
 }
 
 // get returns whether the given entity has the given component.
@@ -340,8 +540,29 @@ func (s *storage) getRelation(entity Entity, comp ID) Entity {
 	if !s.entityPool.Alive(entity) {
 		panic("can't get relation for a dead entity")
 	}
+	return s.getRelationUnchecked(entity, comp)
+}
+
+// getRelations appends all relation targets of the given entity for the given component,
+// except the zero entity.
+//
+// Checks whether the entity is alive.
+// Also checks whether the entity has the component.
+func (s *storage) getRelations(entity Entity, comp ID, out []Entity) []Entity {
+	if !s.entityPool.Alive(entity) {
+		panic("can't get relations for a dead entity")
+	}
 	s.checkHasComponent(entity, comp)
-	return s.tables[s.entities[entity.id].table].GetRelation(comp)
+	if s.registry.IsMultiRelation[comp.id] {
+		if m := s.multi[comp.id]; m != nil {
+			out = append(out, m.Targets(entity)...)
+		}
+		return out
+	}
+	if target := s.tables[s.entities[entity.id].table].GetRelation(comp); !target.IsZero() {
+		out = append(out, target)
+	}
+	return out
 }
 
 // getRelationUnchecked returns the relation target target of the given entity for the given component.
@@ -351,6 +572,9 @@ func (s *storage) getRelation(entity Entity, comp ID) Entity {
 // Returns the zero entity if the component is not a relation.
 func (s *storage) getRelationUnchecked(entity Entity, comp ID) Entity {
 	s.checkHasComponent(entity, comp)
+	if s.registry.IsMultiRelation[comp.id] {
+		return s.firstMultiTarget(entity, comp)
+	}
 	return s.tables[s.entities[entity.id].table].GetRelation(comp)
 }
 
@@ -366,6 +590,8 @@ func (s *storage) registerTargets(relations []relationID) {
 
 // registerFilter registers a filter and relations.
 func (s *storage) registerFilter(filter *filter, relations []relationID) {
+	// Targets of multi-target relations are not cached, as they are checked per entity.
+	relations, _ = s.splitRelations(relations)
 	s.cache.register(s, filter, relations)
 }
 
@@ -414,11 +640,45 @@ func (s *storage) createEntities(table *table, count int) {
 			s.isTarget[entity.id] = false
 		}
 	}
+	table.SetAdded(uint32(startIdx), uint32(count), nil, s.tick)
+}
+
+// allocEntities allocates the given number of entities, without adding them to any table.
+// Use [storage.insertEntities] to add them to a table.
+func (s *storage) allocEntities(count int) []Entity {
+	entities := make([]Entity, count)
+	for i := range entities {
+		entity := s.entityPool.Get()
+		for int(entity.id) >= len(s.entities) {
+			s.entities = append(s.entities, entityIndex{})
+			s.isTarget = append(s.isTarget, false)
+		}
+		s.isTarget[entity.id] = false
+		entities[i] = entity
+	}
+	return entities
+}
+
+// insertEntities adds entities allocated with [storage.allocEntities] to the given table.
+// Components are not initialized.
+func (s *storage) insertEntities(table *table, entities []Entity) {
+	startIdx := table.Len()
+	table.Alloc(uint32(len(entities)))
+	for i, entity := range entities {
+		index := uint32(startIdx + i)
+		table.SetEntity(index, entity)
+		s.entities[entity.id] = entityIndex{table: table.id, row: index}
+	}
 }
 
 // createArchetype creates an archetype for the given node and adds it to the storage.
 func (s *storage) createArchetype(node *node) *archetype {
 	comps := node.mask.toTypes(&s.registry.registry)
+	if s.registry.hasSparse {
+		for _, id := range comps {
+			s.checkNotSparse(id, "table storage")
+		}
+	}
 	index := len(s.archetypes)
 
 	arch, data := newArchetype(archetypeID(index), node.id, &node.mask, comps, nil, &s.registry)
@@ -457,30 +717,46 @@ func (s *storage) createTable(archetype *archetype, relations []relationID) *tab
 		rel := &relations[i]
 		s.checkRelationComponent(rel.component)
 		s.checkRelationTarget(rel.target)
+		if rel.target.isWildcard() {
+			panic("relation targets must be fully specified, no wildcard allowed")
+		}
 	}
 
 	var newTableID tableID
-	recycled := false
+	recycled, retired := false, false
 	if id, ok := archetype.GetFreeTable(); ok {
 		newTableID = id
 		s.tables[newTableID].Recycle(targets, relations)
 		recycled = true
 	} else {
-		newTableID = tableID(len(s.tables))
 		cap := s.config.initialCapacity
 		if archetype.HasRelations() {
 			cap = s.config.initialCapacityRelations
 		}
-		s.tables = append(s.tables, newTable(
-			newTableID, archetype, uint32(cap), &s.registry,
-			targets, relations))
+		if n := len(s.retiredTables); n > 0 {
+			newTableID = s.retiredTables[n-1]
+			s.retiredTables = s.retiredTables[:n-1]
+			s.tables[newTableID] = newTable(
+				newTableID, archetype, uint32(cap), &s.registry,
+				targets, relations)
+			retired = true
+		} else {
+			newTableID = tableID(len(s.tables))
+			s.tables = append(s.tables, newTable(
+				newTableID, archetype, uint32(cap), &s.registry,
+				targets, relations))
+		}
 	}
 	archetype.AddTable(&s.tables[newTableID])
 
 	table := &s.tables[newTableID]
-	if !recycled {
+	if retired {
+		for _, id := range archetype.components {
+			s.components[id.id].columns[newTableID] = table.Column(id)
+		}
+	} else if !recycled {
 		for i := range s.components {
-			id := ID{id: uint8(i)}
+			id := ID{id: idIndex(i)}
 			comps := &s.components[i]
 			if archetype.mask.Get(id.id) {
 				comps.columns = append(comps.columns, table.Column(id))
@@ -495,7 +771,17 @@ func (s *storage) createTable(archetype *archetype, relations []relationID) *tab
 }
 
 // Removes empty archetypes that have a target relation to the given entity.
+//
+// This also keeps symmetric relations consistent, as the removed target is removed
+// from all relations pointing to it, just like the relations of the removed entity itself.
 func (s *storage) cleanupArchetypes(target Entity) {
+	if s.registry.hasMultiRelations {
+		s.cleanupMultiTargets(target)
+	}
+	if s.registry.hasCleanup {
+		s.applyCleanupPolicies(target)
+	}
+
 	newRelations := s.slices.relationsCleanup
 
 	hasAddRelObs := s.observers.HasObservers(OnAddRelations)
@@ -584,6 +870,47 @@ func (s *storage) moveEntities(src, dst *table, count uint32) {
 	src.Reset()
 }
 
+// moveTable moves all entities from a table to a table of a different archetype.
+// Components that are not in the new table's archetype are dropped.
+// Returns the start index of the entities in the new table and number of entities.
+func (s *storage) moveTable(oldTableID, newTableID tableID) (uint32, uint32) {
+	oldTable := &s.tables[oldTableID]
+
+	oldArchetype := &s.archetypes[oldTable.archetype]
+	oldIDs := oldArchetype.components
+
+	newTable := &s.tables[newTableID]
+	newArchetype := &s.archetypes[newTable.archetype]
+
+	mask := &newArchetype.mask
+
+	startIdx := uint32(newTable.Len())
+	count := oldTable.len
+
+	var i uint32
+	for i = range count {
+		idx := startIdx + i
+		entity := oldTable.GetEntity(uintptr(i))
+		index := &s.entities[entity.id]
+		index.table = newTable.id
+		index.row = idx
+	}
+
+	newTable.AddAllEntities(oldTable, count)
+	for _, id := range oldIDs {
+		if mask.Get(id.id) {
+			oldCol := oldTable.Column(id)
+			newCol := newTable.Column(id)
+			newCol.CopyToEnd(oldCol, newTable.len, count)
+		}
+	}
+
+	newTable.SetAdded(startIdx, count, &oldArchetype.mask, s.tick)
+	oldTable.Reset()
+
+	return startIdx, count
+}
+
 // getExchangeTargetsUnchecked returns the relations resulting from changing relations on a table.
 //
 // Does not check validity of relations.
@@ -0,0 +1,197 @@
+package ecs
+
+import "fmt"
+
+// SetSymmetric marks the relation component type C as symmetric.
+//
+// For symmetric relations, setting a relation from an entity A to a target B
+// automatically sets the same relation from B to A.
+// Likewise, removing the relation or changing its target is mirrored on the former target.
+// Component values are not mirrored, only relation targets.
+// Components added to a target for mirroring are initialized with their zero value.
+//
+// For exclusive relations (see [RelationMarker]), each entity has only a single partner.
+// Setting a new partner thus resets the relation of the former partner to the zero entity.
+// For batch operations that set the same target for multiple entities, the last entity wins.
+// Creating multiple entities with the same target in a batch panics.
+// Multi-target relations (see [MultiRelationMarker]) are not affected by these limitations.
+//
+// Symmetry is maintained for all operations, including batch operations and the [CommandBuffer].
+// When an entity is removed, it is removed from the relations of all other entities like for any relation target.
+//
+// Panics if C is not a relation component, or if the component is already in use by any entity.
+// Should be called during world initialization.
+func SetSymmetric[C any](w *World) {
+	w.checkLocked()
+	id := ComponentID[C](w)
+	w.storage.checkRelationComponent(id)
+	if w.storage.registry.Archetypes[id.id] > 0 {
+		panic(fmt.Sprintf("can't make component with ID %d symmetric, as it is already in use", id.id))
+	}
+	w.storage.registry.IsSymmetric[id.id] = true
+	w.storage.registry.hasSymmetric = true
+}
+
+// symmetricEntity is a helper for mirroring symmetric relations after batch operations.
+type symmetricEntity struct {
+	entity    Entity
+	relations []relationID
+}
+
+// involvesSymmetric checks whether any of the given components or relations is symmetric.
+func (s *storage) involvesSymmetric(ids []ID, relations []relationID) bool {
+	if !s.registry.hasSymmetric {
+		return false
+	}
+	for _, id := range ids {
+		if s.registry.IsSymmetric[id.id] {
+			return true
+		}
+	}
+	for _, rel := range relations {
+		if s.registry.IsSymmetric[rel.component.id] {
+			return true
+		}
+	}
+	return false
+}
+
+// symmetricRelations appends the symmetric relations with a non-zero target of the given entity.
+func (s *storage) symmetricRelations(entity Entity, out []relationID) []relationID {
+	table := &s.tables[s.entities[entity.id].table]
+	out = s.symmetricTableRelations(table, out)
+	if table.hasMultiRelations {
+		out = s.symmetricMultiRelations(table, entity, out)
+	}
+	return out
+}
+
+// symmetricTableRelations appends the symmetric relations with a non-zero target of the given table.
+func (s *storage) symmetricTableRelations(table *table, out []relationID) []relationID {
+	for _, rel := range table.relationIDs {
+		if s.registry.IsSymmetric[rel.component.id] && !rel.target.IsZero() {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// symmetricMultiRelations appends the symmetric multi-target relations of the given entity from the given table.
+func (s *storage) symmetricMultiRelations(table *table, entity Entity, out []relationID) []relationID {
+	for i := range table.columns {
+		id := table.ids[i]
+		if !table.columns[i].isMultiRelation || !s.registry.IsSymmetric[id.id] || s.multi[id.id] == nil {
+			continue
+		}
+		for _, target := range s.multi[id.id].Targets(entity) {
+			out = append(out, relationID{component: id, target: target})
+		}
+	}
+	return out
+}
+
+// collectSymmetric collects all entities matching the given batch, together with their symmetric relations.
+func (w *World) collectSymmetric(batch *Batch) []symmetricEntity {
+	s := &w.storage
+	result := []symmetricEntity{}
+	tables := s.getBatchTables(batch)
+	for _, tableID := range tables {
+		table := &s.tables[tableID]
+		relations := s.symmetricTableRelations(table, nil)
+		for i := range uintptr(table.len) {
+			entity := table.GetEntity(i)
+			if table.hasMultiRelations {
+				// Copy, as targets of multi-target relations differ per entity.
+				entityRelations := s.symmetricMultiRelations(table, entity, relations[:len(relations):len(relations)])
+				result = append(result, symmetricEntity{entity: entity, relations: entityRelations})
+				continue
+			}
+			result = append(result, symmetricEntity{entity: entity, relations: relations})
+		}
+	}
+	s.slices.tables = tables[:0]
+	return result
+}
+
+// mirrorSymmetricAll mirrors the symmetric relations of the collected entities.
+func (w *World) mirrorSymmetricAll(entities []symmetricEntity) {
+	for _, e := range entities {
+		w.mirrorSymmetric(e.entity, e.relations)
+	}
+}
+
+// mirrorSymmetric mirrors changes of the symmetric relations of an entity to their targets.
+// Argument old contains the symmetric relations of the entity before the change.
+func (w *World) mirrorSymmetric(entity Entity, old []relationID) {
+	current := w.storage.symmetricRelations(entity, nil)
+	for _, rel := range old {
+		if !containsRelation(current, rel) {
+			w.unlinkSymmetric(rel.target, rel.component, entity)
+		}
+	}
+	for _, rel := range current {
+		if !containsRelation(old, rel) {
+			w.linkSymmetric(rel.target, rel.component, entity)
+		}
+	}
+}
+
+// linkSymmetric adds the given entity to the targets of a symmetric relation of the target entity.
+// Adds the relation component to the target if it does not have it yet.
+func (w *World) linkSymmetric(target Entity, comp ID, entity Entity) {
+	s := &w.storage
+	if target == entity || !s.entityPool.Alive(target) {
+		return
+	}
+	if !s.hasUnchecked(target, comp) {
+		oldMask, newMask := w.add(target, []ID{comp}, []relationID{{component: comp, target: entity}})
+		s.observers.FireAddIfHas(OnAddComponents, target, oldMask, newMask)
+		s.observers.FireAddIfHas(OnAddRelations, target, oldMask, newMask)
+		return
+	}
+	targets := s.getRelations(target, comp, nil)
+	if containsEntity(targets, entity) {
+		return
+	}
+	relations := []relationID{{component: comp, target: entity}}
+	if s.registry.IsMultiRelation[comp.id] {
+		for _, t := range targets {
+			relations = append(relations, relationID{component: comp, target: t})
+		}
+	}
+	w.setRelations(target, relations)
+}
+
+// unlinkSymmetric removes the given entity from the targets of a symmetric relation of the target entity.
+// Sets the zero entity as target if no targets remain.
+func (w *World) unlinkSymmetric(target Entity, comp ID, entity Entity) {
+	s := &w.storage
+	if target == entity || !s.entityPool.Alive(target) || !s.hasUnchecked(target, comp) {
+		return
+	}
+	targets := s.getRelations(target, comp, nil)
+	if !containsEntity(targets, entity) {
+		return
+	}
+	relations := []relationID{}
+	for _, t := range targets {
+		if t != entity {
+			relations = append(relations, relationID{component: comp, target: t})
+		}
+	}
+	if len(relations) == 0 {
+		relations = append(relations, relationID{component: comp})
+	}
+	w.setRelations(target, relations)
+}
+
+// hasExclusiveSymmetric checks whether any of the given relations is
+// an exclusive symmetric relation with a non-zero target.
+func (s *storage) hasExclusiveSymmetric(relations []relationID) bool {
+	for _, rel := range relations {
+		if s.registry.IsSymmetric[rel.component.id] && !s.registry.IsMultiRelation[rel.component.id] && !rel.target.IsZero() {
+			return true
+		}
+	}
+	return false
+}
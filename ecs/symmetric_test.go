@@ -0,0 +1,174 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetSymmetric(t *testing.T) {
+	w := NewWorld(16)
+	SetSymmetric[PartnerOf](w)
+
+	info, _ := ComponentInfo(w, ComponentID[PartnerOf](w))
+	expectTrue(t, info.IsSymmetric)
+	info, _ = ComponentInfo(w, ComponentID[ChildOf](w))
+	expectFalse(t, info.IsSymmetric)
+
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a relation component", ComponentID[Position](w).id), func() {
+		SetSymmetric[Position](w)
+	})
+
+	childMap := NewMap[ChildOf](w)
+	childMap.NewEntity(&ChildOf{}, Entity{})
+	expectPanicsWithValue(t, fmt.Sprintf("can't make component with ID %d symmetric, as it is already in use", ComponentID[ChildOf](w).id), func() {
+		SetSymmetric[ChildOf](w)
+	})
+}
+
+func TestSymmetricRelation(t *testing.T) {
+	w := NewWorld(16)
+	SetSymmetric[PartnerOf](w)
+	partnerMap := NewMap[PartnerOf](w)
+	posMap := NewMap2[Position, PartnerOf](w)
+
+	a := w.NewEntity()
+	b := partnerMap.NewEntity(&PartnerOf{}, a)
+
+	expectEqual(t, a, partnerMap.GetRelation(b))
+	expectEqual(t, b, partnerMap.GetRelation(a))
+
+	c := w.NewEntity()
+	partnerMap.Add(c, &PartnerOf{}, Entity{})
+	expectEqual(t, Entity{}, partnerMap.GetRelation(c))
+
+	// Re-partnering resets the former partner.
+	partnerMap.SetRelation(c, a)
+	expectEqual(t, a, partnerMap.GetRelation(c))
+	expectEqual(t, c, partnerMap.GetRelation(a))
+	expectEqual(t, Entity{}, partnerMap.GetRelation(b))
+
+	partnerMap.SetRelation(c, Entity{})
+	expectEqual(t, Entity{}, partnerMap.GetRelation(a))
+	expectEqual(t, Entity{}, partnerMap.GetRelation(c))
+
+	partnerMap.SetRelation(a, b)
+	expectEqual(t, a, partnerMap.GetRelation(b))
+	partnerMap.Remove(a)
+	expectFalse(t, partnerMap.Has(a))
+	expectEqual(t, Entity{}, partnerMap.GetRelation(b))
+
+	// Exchange.
+	d := posMap.NewEntity(&Position{}, &PartnerOf{}, RelIdx(1, b))
+	expectEqual(t, d, partnerMap.GetRelation(b))
+	ex := NewExchange1[Velocity](w).Removes(C[PartnerOf]())
+	ex.Exchange(d, &Velocity{})
+	expectFalse(t, partnerMap.Has(d))
+	expectEqual(t, Entity{}, partnerMap.GetRelation(b))
+
+	// Removal of an entity.
+	partnerMap.SetRelation(c, b)
+	expectEqual(t, c, partnerMap.GetRelation(b))
+	w.RemoveEntity(b)
+	expectEqual(t, Entity{}, partnerMap.GetRelation(c))
+
+	// Self-relations are not mirrored.
+	partnerMap.SetRelation(c, c)
+	expectEqual(t, c, partnerMap.GetRelation(c))
+}
+
+func TestSymmetricMultiRelation(t *testing.T) {
+	w := NewWorld(16)
+	SetSymmetric[FriendOf](w)
+	friendMap := NewMap[FriendOf](w)
+
+	a := w.NewEntity()
+	b := w.NewEntity()
+	c := friendMap.NewEntity(&FriendOf{}, a, b)
+
+	expectSlicesEqual(t, []Entity{c}, friendMap.GetRelations(a))
+	expectSlicesEqual(t, []Entity{c}, friendMap.GetRelations(b))
+
+	friendMap.AddTargets(a, b)
+	expectEqual(t, 2, len(friendMap.GetRelations(a)))
+	expectTrue(t, containsEntity(friendMap.GetRelations(b), a))
+	expectTrue(t, containsEntity(friendMap.GetRelations(b), c))
+
+	friendMap.RemoveTargets(c, a)
+	expectSlicesEqual(t, []Entity{b}, friendMap.GetRelations(a))
+	expectSlicesEqual(t, []Entity{b}, friendMap.GetRelations(c))
+
+	friendMap.SetRelation(b, Entity{})
+	expectEqual(t, 0, len(friendMap.GetRelations(a)))
+	expectEqual(t, 0, len(friendMap.GetRelations(b)))
+	expectEqual(t, 0, len(friendMap.GetRelations(c)))
+}
+
+func TestSymmetricRelationBatch(t *testing.T) {
+	w := NewWorld(16)
+	SetSymmetric[FriendOf](w)
+	SetSymmetric[PartnerOf](w)
+	friendMap := NewMap[FriendOf](w)
+	partnerMap := NewMap2[Position, PartnerOf](w)
+	posMap := NewMap[Position](w)
+	filter := NewFilter1[Position](w).Without(C[FriendOf]())
+
+	a := w.NewEntity()
+	friendMap.NewBatch(5, &FriendOf{}, a)
+	expectEqual(t, 5, len(friendMap.GetRelations(a)))
+
+	expectPanicsWithValue(t, "can't create multiple entities with the same target of an exclusive symmetric relation", func() {
+		partnerMap.NewBatch(2, &Position{}, &PartnerOf{}, RelIdx(1, a))
+	})
+	partnerMap.NewBatch(1, &Position{}, &PartnerOf{}, RelIdx(1, a))
+	partner := NewMap[PartnerOf](w)
+	expectTrue(t, partner.Has(a))
+
+	posMap.NewBatch(5, &Position{})
+	friendMap.AddBatch(filter.Batch(), &FriendOf{}, a)
+	expectEqual(t, 11, len(friendMap.GetRelations(a)))
+
+	friendMap.RemoveBatch(filter.Batch(), nil)
+	expectEqual(t, 11, len(friendMap.GetRelations(a)))
+	friendMap.RemoveBatch(NewFilter1[Position](w).Batch(), nil)
+	expectEqual(t, 5, len(friendMap.GetRelations(a)))
+
+	b := w.NewEntity()
+	friendMap.SetRelationBatch(NewFilter1[FriendOf](w).Without(C[PartnerOf]()).Batch(), b, nil)
+	expectEqual(t, 5, len(friendMap.GetRelations(b)))
+	expectEqual(t, 0, len(friendMap.GetRelations(a)))
+}
+
+func TestSymmetricRelationCommandBuffer(t *testing.T) {
+	w := NewWorld(16)
+	SetSymmetric[PartnerOf](w)
+	partnerMap := NewMap[PartnerOf](w)
+	partnerID := ComponentID[PartnerOf](w)
+
+	a := w.NewEntity()
+	cmd := NewCommandBuffer(w)
+	cmd.NewEntityRel([]ID{partnerID}, RelID(partnerID, a))
+	cmd.NewEntityRel([]ID{partnerID}, RelID(partnerID, a))
+	cmd.Apply()
+
+	partner := partnerMap.GetRelation(a)
+	expectFalse(t, partner.IsZero())
+	expectEqual(t, a, partnerMap.GetRelation(partner))
+
+	count := 0
+	query := NewFilter1[PartnerOf](w).Query(RelIdx(0, a))
+	for query.Next() {
+		count++
+	}
+	expectEqual(t, 1, count)
+}
+
+func TestSymmetricRelationClone(t *testing.T) {
+	w := NewWorld(16)
+	SetSymmetric[PartnerOf](w)
+
+	clone := w.Clone()
+	partnerMap := NewMap[PartnerOf](clone)
+	a := clone.NewEntity()
+	b := partnerMap.NewEntity(&PartnerOf{}, a)
+	expectEqual(t, b, partnerMap.GetRelation(a))
+}
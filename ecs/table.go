@@ -28,6 +28,8 @@ type table struct {
 	len         uint32       // length of the table (number of rows)
 	cap         uint32       // capacity of the table (number of rows)
 	isFree      bool         // Whether the table is currently free
+
+	hasMultiRelations bool // Whether the table contains any multi-target relation components, with targets stored per entity
 }
 
 // newTable creates a new table.
@@ -39,18 +41,23 @@ func newTable(id tableID, archetype *archetype, capacity uint32, reg *componentR
 	for i, id := range archetype.components {
 		itemSize := uintptr(archetype.itemSizes[i])
 		columns[i] = newColumn(uint32(i), reg.Types[id.id], itemSize, archetype.isRelation[i], reg.IsTrivial[id.id], targets[i], capacity)
+		columns[i].isMultiRelation = archetype.isMultiRelation[i]
+		if reg.IsTracked[id.id] {
+			columns[i].trackChanges(0)
+		}
 		components[id.id] = &columns[i]
 	}
 
 	return table{
-		id:          id,
-		archetype:   archetype.id,
-		components:  components,
-		entities:    entities,
-		ids:         archetype.components,
-		columns:     columns,
-		relationIDs: relationIDs,
-		cap:         capacity,
+		id:                id,
+		archetype:         archetype.id,
+		components:        components,
+		entities:          entities,
+		ids:               archetype.components,
+		columns:           columns,
+		relationIDs:       relationIDs,
+		cap:               capacity,
+		hasMultiRelations: archetype.hasMultiRelations,
 	}
 }
 
@@ -167,22 +174,7 @@ func (t *table) adjustCapacity(cap uint32) {
 	t.entities.pointer = newPtr
 
 	for i := range t.columns {
-		column := &t.columns[i]
-		old := column.data
-		column.data = reflect.MakeSlice(reflect.SliceOf(column.elemType), int(t.cap), int(t.cap))
-		if column.isTrivial {
-			newPtr := column.data.Index(0).Addr().UnsafePointer()
-			if t.len > 0 {
-				copyPtr(column.pointer, newPtr, uintptr(t.len)*column.itemSize)
-			}
-			column.pointer = newPtr
-		} else {
-			column.pointer = column.data.Index(0).Addr().UnsafePointer()
-			if t.len > 0 {
-				// TODO: use typedmemmove?
-				reflect.Copy(column.data, old)
-			}
-		}
+		t.columns[i].adjustCapacity(t.len, t.cap)
 	}
 }
 
@@ -219,11 +211,13 @@ func (t *table) removeColumnsSwapped(index uint32, lastIndex uintptr) {
 			src := unsafe.Add(column.pointer, lastIndex*size)
 			dst := unsafe.Add(column.pointer, uintptr(index)*size)
 			copyPtr(src, dst, size)
+			column.moveTicks(lastIndex, uintptr(index))
 			column.Zero(lastIndex)
 			continue
 		}
 
 		copyValue(column, column, lastIndex, uintptr(index))
+		column.moveTicks(lastIndex, uintptr(index))
 		column.Zero(lastIndex)
 	}
 }
@@ -231,6 +225,7 @@ func (t *table) removeColumnsSwapped(index uint32, lastIndex uintptr) {
 func (t *table) zeroAllColumns(lastIndex uintptr) {
 	for i := range t.columns {
 		t.columns[i].Zero(lastIndex)
+		t.columns[i].zeroTicks(lastIndex)
 	}
 }
 
@@ -243,6 +238,18 @@ func (t *table) Reset() {
 	t.len = 0
 }
 
+// SetAdded marks components of the given rows as added and changed at the given tick.
+// Only components that are not in the given old mask are marked.
+// If the mask is nil, all components are marked.
+func (t *table) SetAdded(start, count uint32, oldMask *bitMask, tick uint32) {
+	for i := range t.columns {
+		if oldMask != nil && oldMask.Get(t.ids[i].id) {
+			continue
+		}
+		t.columns[i].setAdded(start, count, tick)
+	}
+}
+
 // AddAll adds all entities with components from another table with the same layout to this table.
 func (t *table) AddAll(from *table, count uint32) {
 	t.Alloc(count)
@@ -274,9 +281,6 @@ func (t *table) MatchesExact(relations []relationID) bool {
 		if !column.isRelation {
 			panic(fmt.Sprintf("component %d is not a relation component", rel.component.id))
 		}
-		//if rel.target == wildcard {
-		//	panic("relation targets must be fully specified, no wildcard allowed")
-		//}
 		if rel.target != column.target {
 			return false
 		}
@@ -295,10 +299,14 @@ func (t *table) Matches(relations []relationID) bool {
 
 func (t *table) matchesRelations(relations []relationID) bool {
 	for _, rel := range relations {
-		//if rel.target == wildcard {
-		//	continue
-		//}
-		if rel.target != t.components[rel.component.id].target {
+		column := t.components[rel.component.id]
+		if rel.target.isWildcard() {
+			if column.target.IsZero() {
+				return false
+			}
+			continue
+		}
+		if rel.target != column.target {
 			return false
 		}
 	}
@@ -0,0 +1,225 @@
+package ecs
+
+// TargetQuery is an iterator over all entities that have a relation to a certain target entity.
+// Create one with [World.Targeting] or [Unsafe.Targeting].
+//
+// Tables are looked up via the world's relation target index,
+// so the cost of creating the query is proportional to the number of matching tables,
+// and not to the number of entities in the world.
+// Entities with multi-target relations (see [MultiRelationMarker]) are looked up per entity,
+// and are iterated after the tables.
+//
+// Like other queries, target queries are one-time use iterators and lock the world until closed.
+type TargetQuery struct {
+	world    *World
+	table    *table
+	tables   []tableID
+	entities []Entity // Entities with multi-target relations to the target, not in tables
+	cursor   cursor
+	lock     uint8
+}
+
+// Targeting creates a [TargetQuery] over all entities that have a relation to the given target entity.
+//
+// If components are given, only relations of these components are considered.
+// Otherwise, all relation components are considered.
+// The query is empty for dead target entities.
+//
+// Panics if any of the given components is not a relation component.
+func (w *World) Targeting(target Entity, comps ...Comp) TargetQuery {
+	ids := make([]ID, len(comps))
+	for i, c := range comps {
+		ids[i] = w.componentID(c.tp)
+	}
+	return w.Unsafe().Targeting(target, ids...)
+}
+
+// IsTarget returns whether any entity has a relation to the given entity.
+func (w *World) IsTarget(entity Entity) bool {
+	s := &w.storage
+	if !s.isTargetCandidate(entity) {
+		return false
+	}
+	for _, archID := range s.relationArchetypes {
+		tables, ok := s.archetypes[archID].targetTables[entity.id]
+		if !ok {
+			continue
+		}
+		for _, id := range tables.tables {
+			if s.tables[id].len > 0 {
+				return true
+			}
+		}
+	}
+	return s.registry.hasMultiRelations && s.hasMultiTarget(entity)
+}
+
+// Targeting creates a [TargetQuery] over all entities that have a relation to the given target entity.
+//
+// If component IDs are given, only relations of these components are considered.
+// Otherwise, all relation components are considered.
+// The query is empty for dead target entities.
+//
+// Panics if any of the given components is not a relation component.
+func (u Unsafe) Targeting(target Entity, comps ...ID) TargetQuery {
+	s := &u.world.storage
+	for _, id := range comps {
+		s.checkRelationComponent(id)
+	}
+
+	var tables []tableID
+	var entities []Entity
+	if s.isTargetCandidate(target) {
+		tables = s.targetTables(target, comps, tables)
+		if s.registry.hasMultiRelations {
+			entities = s.targetEntities(target, comps, tables)
+		}
+	}
+
+	return TargetQuery{
+		world:    u.world,
+		tables:   tables,
+		entities: entities,
+		lock:     u.world.lockSafe(),
+		cursor: cursor{
+			archetype: -1,
+			table:     -1,
+			index:     0,
+			maxIndex:  -1,
+		},
+	}
+}
+
+// Next advances the query's cursor to the next entity.
+// Closes the query when there are no more entities.
+func (q *TargetQuery) Next() bool {
+	if int64(q.cursor.index) < q.cursor.maxIndex {
+		q.cursor.index++
+		return true
+	}
+	for q.cursor.table+1 < int32(len(q.tables)) {
+		q.cursor.table++
+		table := &q.world.storage.tables[q.tables[q.cursor.table]]
+		if table.len == 0 {
+			continue
+		}
+		q.table = table
+		q.cursor.index = 0
+		q.cursor.maxIndex = int64(table.len) - 1
+		return true
+	}
+	if q.cursor.archetype+1 < int32(len(q.entities)) {
+		q.cursor.archetype++
+		index := &q.world.storage.entities[q.entities[q.cursor.archetype].id]
+		q.table = &q.world.storage.tables[index.table]
+		q.cursor.index = uintptr(index.row)
+		q.cursor.maxIndex = -1
+		return true
+	}
+	q.Close()
+	return false
+}
+
+// Entity returns the current entity.
+func (q *TargetQuery) Entity() Entity {
+	return q.table.GetEntity(q.cursor.index)
+}
+
+// Count returns the number of entities matching this query.
+//
+// Does not iterate or close the query.
+func (q *TargetQuery) Count() int {
+	count := len(q.entities)
+	for _, id := range q.tables {
+		count += int(q.world.storage.tables[id].len)
+	}
+	return count
+}
+
+// Close closes the query and unlocks the world.
+//
+// Automatically called when iteration completes.
+// Needs to be called only if breaking out of the query iteration or not iterating at all.
+func (q *TargetQuery) Close() {
+	if q.cursor.table < -1 {
+		return
+	}
+	q.cursor.archetype = -2
+	q.cursor.table = -2
+	q.tables = nil
+	q.entities = nil
+	q.table = nil
+	q.world.unlockSafe(q.lock)
+}
+
+// isTargetCandidate returns whether the given entity may be the target of any relation.
+// Uses the target bookkeeping that is maintained for relation cleanup.
+// The zero entity is always a candidate, as it is the target of relations with removed targets.
+func (s *storage) isTargetCandidate(entity Entity) bool {
+	if entity.IsZero() {
+		return true
+	}
+	return s.entityPool.Alive(entity) && s.isTarget[entity.id]
+}
+
+// targetTables appends all tables with a relation to the given target entity.
+// If components are given, only relations of these components are considered.
+func (s *storage) targetTables(target Entity, comps []ID, out []tableID) []tableID {
+	for _, archID := range s.relationArchetypes {
+		archetype := &s.archetypes[archID]
+		if len(comps) == 0 {
+			if tables, ok := archetype.targetTables[target.id]; ok {
+				out = append(out, tables.tables...)
+			}
+			continue
+		}
+		start := len(out)
+		for _, id := range comps {
+			index := archetype.componentsMap[id.id]
+			if index < 0 {
+				continue
+			}
+			tables, ok := archetype.relationTables[index][target.id]
+			if !ok {
+				continue
+			}
+			for _, table := range tables.tables {
+				if !containsTable(out[start:], table) {
+					out = append(out, table)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// targetEntities returns all entities with a multi-target relation to the given target entity
+// that are not in any of the given tables.
+// If components are given, only relations of these components are considered.
+func (s *storage) targetEntities(target Entity, comps []ID, tables []tableID) []Entity {
+	var out []Entity
+	for i, m := range s.multi {
+		if m == nil || (len(comps) > 0 && !containsID(comps, ID{id: idIndex(i)})) {
+			continue
+		}
+		// Entities may have the same target for multiple components.
+		dedupe := len(out) > 0
+		for _, entity := range m.Sources(target) {
+			if containsTable(tables, s.entities[entity.id].table) || (dedupe && containsEntity(out, entity)) {
+				continue
+			}
+			out = append(out, entity)
+		}
+	}
+	return out
+}
+
+// containsTable returns whether the given table ID is in the slice.
+func containsTable(tables []tableID, table tableID) bool {
+	for _, t := range tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
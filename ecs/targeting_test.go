@@ -0,0 +1,100 @@
+package ecs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWorldTargeting(t *testing.T) {
+	w := NewWorld(16)
+	childMap := NewMap2[Position, ChildOf](w)
+	child2Map := NewMap2[Position, ChildOf2](w)
+	bothMap := NewMap2[ChildOf, ChildOf2](w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+	notTarget := w.NewEntity()
+
+	childMap.NewBatch(5, &Position{}, &ChildOf{}, Rel[ChildOf](parent1))
+	childMap.NewBatch(4, &Position{}, &ChildOf{}, Rel[ChildOf](parent2))
+	child2Map.NewBatch(3, &Position{}, &ChildOf2{}, Rel[ChildOf2](parent1))
+	bothMap.NewBatch(2, &ChildOf{}, &ChildOf2{}, Rel[ChildOf](parent1), Rel[ChildOf2](parent1))
+	bothMap.NewBatch(1, &ChildOf{}, &ChildOf2{}, Rel[ChildOf](parent1), Rel[ChildOf2](parent2))
+
+	expectEqual(t, 11, countTargeting(w.Targeting(parent1)))
+	expectEqual(t, 5, countTargeting(w.Targeting(parent2)))
+	expectEqual(t, 0, countTargeting(w.Targeting(notTarget)))
+
+	expectEqual(t, 8, countTargeting(w.Targeting(parent1, C[ChildOf]())))
+	expectEqual(t, 5, countTargeting(w.Targeting(parent1, C[ChildOf2]())))
+	expectEqual(t, 11, countTargeting(w.Targeting(parent1, C[ChildOf](), C[ChildOf2]())))
+	expectEqual(t, 1, countTargeting(w.Targeting(parent2, C[ChildOf2]())))
+
+	relMap := NewMap[ChildOf](w)
+	query := w.Targeting(parent1, C[ChildOf]())
+	expectEqual(t, 8, query.Count())
+	expectTrue(t, w.IsLocked())
+	for query.Next() {
+		expectEqual(t, parent1, relMap.GetRelation(query.Entity()))
+	}
+	expectFalse(t, w.IsLocked())
+
+	query = w.Targeting(parent1)
+	query.Next()
+	query.Close()
+	expectFalse(t, w.IsLocked())
+
+	// Removed targets.
+	w.RemoveEntity(parent2)
+	expectEqual(t, 0, countTargeting(w.Targeting(parent2)))
+	expectEqual(t, 5, countTargeting(w.Targeting(Entity{})))
+
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a relation component", ComponentID[Position](w).id), func() {
+		w.Targeting(parent1, C[Position]())
+	})
+}
+
+func TestWorldIsTarget(t *testing.T) {
+	w := NewWorld(16)
+	childMap := NewMap2[Position, ChildOf](w)
+
+	parent := w.NewEntity()
+	other := w.NewEntity()
+	expectFalse(t, w.IsTarget(parent))
+
+	child := childMap.NewEntity(&Position{}, &ChildOf{}, Rel[ChildOf](parent))
+	expectTrue(t, w.IsTarget(parent))
+	expectFalse(t, w.IsTarget(other))
+	expectFalse(t, w.IsTarget(child))
+
+	childMap.Remove(child)
+	expectFalse(t, w.IsTarget(parent))
+
+	childMap.Add(child, &Position{}, &ChildOf{}, Rel[ChildOf](parent))
+	expectTrue(t, w.IsTarget(parent))
+	w.RemoveEntity(parent)
+	expectFalse(t, w.IsTarget(parent))
+}
+
+func TestUnsafeTargeting(t *testing.T) {
+	w := NewWorld(16)
+	childID := ComponentID[ChildOf](w)
+	posID := ComponentID[Position](w)
+
+	parent := w.NewEntity()
+	u := w.Unsafe()
+	for range 5 {
+		u.NewEntityRel([]ID{posID, childID}, RelID(childID, parent))
+	}
+
+	expectEqual(t, 5, countTargeting(u.Targeting(parent)))
+	expectEqual(t, 5, countTargeting(u.Targeting(parent, childID)))
+}
+
+func countTargeting(query TargetQuery) int {
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	return cnt
+}
@@ -0,0 +1,231 @@
+package ecs
+
+import "sync/atomic"
+
+// changeTracker holds the change detection settings of a filter.
+// See [Filter2.Changed] and [Filter2.Added].
+type changeTracker struct {
+	changed []ID   // Components that must have changed since the given tick.
+	added   []ID   // Components that must have been added since the given tick.
+	since   uint32 // Tick after which changes are considered.
+}
+
+// tracks returns whether the given component is used for change detection.
+// Always returns false for a nil tracker.
+func (t *changeTracker) tracks(id ID) bool {
+	if t == nil {
+		return false
+	}
+	for _, other := range t.changed {
+		if other == id {
+			return true
+		}
+	}
+	for _, other := range t.added {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTable returns whether the given table may contain any changed rows.
+// Always returns true for a nil tracker.
+func (t *changeTracker) matchesTable(table *table) bool {
+	if t == nil {
+		return true
+	}
+	for _, id := range t.changed {
+		if atomic.LoadUint32(&table.components[id.id].tick) <= t.since {
+			return false
+		}
+	}
+	for _, id := range t.added {
+		if table.components[id.id].addedTick <= t.since {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesRow returns whether the given table row was changed or added as required by the tracker.
+// Always returns true for a nil tracker.
+func (t *changeTracker) matchesRow(table *table, row uintptr) bool {
+	if t == nil {
+		return true
+	}
+	for _, id := range t.changed {
+		if table.components[id.id].changedTick(row) <= t.since {
+			return false
+		}
+	}
+	for _, id := range t.added {
+		if table.components[id.id].addedTicks[row] <= t.since {
+			return false
+		}
+	}
+	return true
+}
+
+// trackChanges enables change tracking for the given component.
+// Ticks are only allocated and recorded for tracked components.
+//
+// Existing components count as added and changed at the current tick.
+func (s *storage) trackChanges(id ID) {
+	if s.registry.IsTracked[id.id] {
+		return
+	}
+	s.registry.IsTracked[id.id] = true
+	for _, column := range s.components[id.id].columns {
+		if column != nil {
+			column.trackChanges(s.tick)
+		}
+	}
+}
+
+// trackChanges allocates the row ticks of the column,
+// with all rows marked as added and changed at the given tick.
+func (c *column) trackChanges(tick uint32) {
+	cap := c.data.Len()
+	c.ticks = make([]uint32, cap)
+	c.addedTicks = make([]uint32, cap)
+	for i := range cap {
+		c.ticks[i] = tick
+		c.addedTicks[i] = tick
+	}
+	c.tick, c.addedTick = tick, tick
+	c.isTracked = true
+}
+
+// changedTick returns the effective change tick of the given row.
+func (c *column) changedTick(row uintptr) uint32 {
+	return max(c.ticks[row], atomic.LoadUint32(&c.allTick))
+}
+
+// setChanged marks the given row as changed at the given tick.
+// The column tick is written atomically, to allow for concurrent queries.
+func (c *column) setChanged(row uintptr, tick uint32) {
+	if !c.isTracked {
+		return
+	}
+	c.ticks[row] = tick
+	atomic.StoreUint32(&c.tick, tick)
+}
+
+// setAdded marks the given rows as added and changed at the given tick.
+func (c *column) setAdded(start, count uint32, tick uint32) {
+	if count == 0 || !c.isTracked {
+		return
+	}
+	end := start + count
+	for i := start; i < end; i++ {
+		c.ticks[i] = tick
+		c.addedTicks[i] = tick
+	}
+	c.tick = tick
+	c.addedTick = tick
+}
+
+// markAll marks all rows as changed at the given tick.
+// This is concurrency-safe, to allow for concurrent queries.
+func (c *column) markAll(tick uint32) {
+	if !c.isTracked {
+		return
+	}
+	atomic.StoreUint32(&c.allTick, tick)
+	atomic.StoreUint32(&c.tick, tick)
+}
+
+// markRange marks the rows from start to end (exclusive) as changed at the given tick.
+// This is concurrency-safe for disjoint ranges, to allow for parallel iteration.
+func (c *column) markRange(start, end uint32, tick uint32) {
+	if !c.isTracked {
+		return
+	}
+	for i := start; i < end; i++ {
+		c.ticks[i] = tick
+	}
+	atomic.StoreUint32(&c.tick, tick)
+}
+
+// copyTicks copies the ticks of a row from another column.
+func (c *column) copyTicks(index uint32, src *column, srcIndex uint32) {
+	if !c.isTracked {
+		return
+	}
+	var tick, added uint32
+	if src.isTracked {
+		tick = src.changedTick(uintptr(srcIndex))
+		added = src.addedTicks[srcIndex]
+	}
+	c.ticks[index] = tick
+	c.addedTicks[index] = added
+	c.tick = max(c.tick, tick)
+	c.addedTick = max(c.addedTick, added)
+}
+
+// copyTicksToEnd copies the ticks of the first count rows from another column, starting at the given index.
+func (c *column) copyTicksToEnd(src *column, start, count uint32) {
+	if !c.isTracked {
+		return
+	}
+	if !src.isTracked {
+		clear(c.ticks[start : start+count])
+		clear(c.addedTicks[start : start+count])
+		return
+	}
+	allTick := atomic.LoadUint32(&src.allTick)
+	for i := range count {
+		tick := max(src.ticks[i], allTick)
+		added := src.addedTicks[i]
+		c.ticks[start+i] = tick
+		c.addedTicks[start+i] = added
+		c.tick = max(c.tick, tick)
+		c.addedTick = max(c.addedTick, added)
+	}
+}
+
+// moveTicks moves the ticks of a row to another row in the same column.
+func (c *column) moveTicks(from, to uintptr) {
+	if !c.isTracked {
+		return
+	}
+	c.ticks[to] = c.ticks[from]
+	c.addedTicks[to] = c.addedTicks[from]
+	c.zeroTicks(from)
+}
+
+// zeroTicks resets the ticks of the given row.
+func (c *column) zeroTicks(row uintptr) {
+	if !c.isTracked {
+		return
+	}
+	c.ticks[row] = 0
+	c.addedTicks[row] = 0
+}
+
+// resetTicks resets all row ticks and the column ticks.
+func (c *column) resetTicks(len uint32) {
+	if !c.isTracked {
+		return
+	}
+	clear(c.ticks[:len])
+	clear(c.addedTicks[:len])
+	c.tick = 0
+	c.allTick = 0
+	c.addedTick = 0
+}
+
+// adjustTicks changes the capacity of the row ticks.
+func (c *column) adjustTicks(len uint32, cap uint32) {
+	if !c.isTracked {
+		return
+	}
+	ticks := make([]uint32, cap)
+	copy(ticks, c.ticks[:len])
+	c.ticks = ticks
+
+	added := make([]uint32, cap)
+	copy(added, c.addedTicks[:len])
+	c.addedTicks = added
+}
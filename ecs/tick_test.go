@@ -0,0 +1,207 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestWorldAdvanceTick(t *testing.T) {
+	w := NewWorld(16)
+
+	expectEqual(t, 1, w.ChangeTick())
+	expectEqual(t, 1, w.AdvanceTick())
+	expectEqual(t, 2, w.ChangeTick())
+}
+
+func TestFilterChanged(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+
+	entities := make([]Entity, 0, 10)
+	for i := range 10 {
+		entities = append(entities, posMap.NewEntity(&Position{float64(i), 0}))
+	}
+	velMap.NewEntity(&Velocity{})
+	expectFalse(t, w.storage.registry.IsTracked[posMap.id.id])
+	expectTrue(t, w.storage.tables[w.storage.entities[entities[0].id].table].Column(posMap.id).ticks == nil)
+
+	filter := NewFilter1[Velocity](w).Changed(C[Position]())
+	expectTrue(t, w.storage.registry.IsTracked[posMap.id.id])
+	expectEqual(t, 0, countTracked(filter.Since(0)))
+
+	// Tracking starts with all existing components marked as changed.
+	filter2 := NewFilter0(w).Changed(C[Position]()).Since(0)
+	expectEqual(t, 10, countTracked0(filter2))
+
+	since := w.AdvanceTick()
+	expectEqual(t, 0, countTracked0(filter2.Since(since)))
+
+	posMap.Set(entities[3], &Position{100, 0})
+	_ = posMap.GetMut(entities[7])
+	_ = posMap.Get(entities[8])
+	_ = posMap.GetUnchecked(entities[9])
+	expectEqual(t, 2, countTracked0(filter2))
+
+	query := filter2.Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectSlicesEqual(t, []Entity{entities[3], entities[7]}, found)
+
+	// Moving entities between tables keeps ticks.
+	velMap.Add(entities[0], &Velocity{})
+	velMap.Add(entities[3], &Velocity{})
+	expectEqual(t, 1, countTracked(filter.Since(since)))
+	expectEqual(t, 2, countTracked0(filter2))
+
+	// Swap-remove keeps ticks.
+	w.RemoveEntity(entities[1])
+	expectEqual(t, 2, countTracked0(filter2))
+
+	since = w.AdvanceTick()
+	filter2.Since(since)
+	expectEqual(t, 0, countTracked0(filter2))
+
+	// Plain query access does not mark components as changed.
+	query2 := NewFilter1[Position](w).Without(C[Velocity]()).Query()
+	for query2.Next() {
+		_ = query2.Get()
+	}
+	expectEqual(t, 0, countTracked0(filter2))
+
+	query2 = NewFilter1[Position](w).Without(C[Velocity]()).Query()
+	for query2.Next() {
+		if query2.Entity() == entities[5] {
+			_ = query2.GetMut()
+		}
+	}
+	expectEqual(t, 1, countTracked0(filter2))
+
+	// Table access marks the entire table.
+	query2 = NewFilter1[Position](w).Without(C[Velocity]()).Query()
+	for query2.NextTable() {
+		query2.MarkChanged()
+	}
+	expectEqual(t, 7, countTracked0(filter2))
+
+	expectPanicsWithValue(t, "filter does not use change detection, use Changed or Added first", func() {
+		NewFilter1[Position](w).Since(0)
+	})
+}
+
+func TestFilterAdded(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	velMap := NewMap[Velocity](w)
+	mapper := NewMap2[Position, Velocity](w)
+
+	filter := NewFilter1[Position](w).Added(C[Velocity]())
+	filterPos := NewFilter0(w).Added(C[Position]())
+	for i := range 10 {
+		posMap.NewEntity(&Position{float64(i), 0})
+	}
+	since := w.AdvanceTick()
+	filter.Since(since)
+	filterPos.Since(since)
+	expectEqual(t, 0, countTracked(filter))
+
+	e := mapper.NewEntity(&Position{}, &Velocity{})
+	expectEqual(t, 1, countTracked(filter))
+	expectEqual(t, 1, countTracked0(filterPos))
+
+	velMap.AddBatch(NewFilter1[Position](w).Without(C[Velocity]()).Batch(), &Velocity{})
+	expectEqual(t, 11, countTracked(filter))
+	expectEqual(t, 1, countTracked0(filterPos))
+
+	since = w.AdvanceTick()
+	filter.Since(since)
+	filterPos.Since(since)
+	expectEqual(t, 0, countTracked(filter))
+
+	posMap.Set(e, &Position{1, 2})
+	expectEqual(t, 0, countTracked0(filterPos))
+
+	w.CopyEntity(e)
+	expectEqual(t, 1, countTracked(filter))
+	expectEqual(t, 1, countTracked0(filterPos))
+}
+
+func TestFilterChangedTables(t *testing.T) {
+	w := NewWorld(4)
+	posMap := NewMap[Position](w)
+	mapper := NewMap2[Position, Heading](w)
+	filter := NewFilter1[Position](w).Changed(C[Position]())
+
+	posMap.NewBatch(10, &Position{})
+	e := mapper.NewEntity(&Position{}, &Heading{})
+	since := w.AdvanceTick()
+
+	posMap.Set(e, &Position{1, 2})
+
+	query := filter.Since(since).Query()
+	tables := 0
+	for query.NextTable() {
+		tables++
+		expectSlicesEqual(t, []Entity{e}, query.Entities())
+	}
+	expectEqual(t, 1, tables)
+}
+
+func TestFilterChangedWriteAccess(t *testing.T) {
+	w := NewWorld(4)
+	filter := NewFilter0(w).Changed(C[Position]())
+	mapper := NewMap2[Position, Velocity](w)
+
+	entities := []Entity{}
+	for range 5 {
+		entities = append(entities, mapper.NewEntity(&Position{}, &Velocity{}))
+	}
+	since := w.AdvanceTick()
+	filter.Since(since)
+
+	column := w.storage.tables[w.storage.entities[entities[0].id].table].Column(ComponentID[Velocity](w))
+	expectTrue(t, column.ticks == nil)
+
+	_, _ = mapper.Get(entities[0])
+	_, _ = mapper.GetUnchecked(entities[0])
+	expectEqual(t, 0, countTracked0(filter))
+	_, _ = mapper.GetMut(entities[1])
+	expectEqual(t, 1, countTracked0(filter))
+
+	query := NewFilter2[Position, Velocity](w).Query()
+	query.ParallelTables(2, func(chunk *Chunk, entities []Entity, pos []Position, vel []Velocity) {})
+	expectEqual(t, 1, countTracked0(filter))
+
+	query = NewFilter2[Position, Velocity](w).Query()
+	query.ParallelTables(2, func(chunk *Chunk, e []Entity, pos []Position, vel []Velocity) {
+		if e[0] == entities[2] || e[0] == entities[3] {
+			chunk.MarkChanged()
+		}
+	})
+	expectEqual(t, 3, countTracked0(filter))
+
+	query = NewFilter2[Position, Velocity](w).Query()
+	query.ParallelTables(2, func(chunk *Chunk, e []Entity, pos []Position, vel []Velocity) {
+		chunk.MarkChanged()
+	})
+	expectEqual(t, 5, countTracked0(filter))
+}
+
+func countTracked[T any](filter *Filter1[T]) int {
+	query := filter.Query()
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	return cnt
+}
+
+func countTracked0(filter *Filter0) int {
+	query := filter.Query()
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	return cnt
+}
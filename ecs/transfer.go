@@ -0,0 +1,249 @@
+package ecs
+
+import "fmt"
+
+// ComponentMapping translates component IDs of one world to the IDs of the same component types in another world.
+// Create it with [NewComponentMapping].
+type ComponentMapping struct {
+	ids  []ID    // Destination IDs, indexed by source ID
+	used bitMask // Source IDs that have a mapping
+}
+
+// NewComponentMapping creates a [ComponentMapping] from world src to world dst.
+//
+// Component types are matched by their Go type, as obtained via [ComponentIDs] and [ComponentInfo].
+// Component types of src that are not yet registered in dst are registered there, with the same storage kind.
+//
+// Panics if a component type uses a different storage kind (see [StorageKind]) in the worlds.
+func NewComponentMapping(src, dst *World) ComponentMapping {
+	m := ComponentMapping{
+		ids: make([]ID, src.storage.registry.Count()),
+	}
+	for _, id := range ComponentIDs(src) {
+		info, _ := ComponentInfo(src, id)
+		_, registered := dst.storage.registry.Components[info.Type]
+		dstID := TypeID(dst, info.Type)
+		if !registered && info.Storage == StorageSparse {
+			dst.storage.setSparse(dstID.id)
+		}
+		if dst.storage.registry.Storage[dstID.id] != info.Storage {
+			panic(fmt.Sprintf("component type %s uses different storage kinds in the worlds", info.Type.Name()))
+		}
+		m.ids[id.id] = dstID
+		m.used.Set(id.id)
+	}
+	return m
+}
+
+// Map translates a component ID of the source world to the ID of the same component type in the destination world.
+//
+// Panics if the ID has no mapping, i.e. if it was not registered in the source world when the mapping was created.
+func (m *ComponentMapping) Map(id ID) ID {
+	if int(id.id) >= len(m.ids) || !m.used.Get(id.id) {
+		panic(fmt.Sprintf("no mapping for component with ID %d", id.id))
+	}
+	return m.ids[id.id]
+}
+
+// MapIDs translates a list of component IDs. See [ComponentMapping.Map].
+func (m *ComponentMapping) MapIDs(ids IDs) IDs {
+	data := make([]ID, len(ids.data))
+	for i, id := range ids.data {
+		data[i] = m.Map(id)
+	}
+	return newIDs(data)
+}
+
+// mapMask translates a component mask.
+func (m *ComponentMapping) mapMask(mask *bitMask) bitMask {
+	var result bitMask
+	for i := range m.ids {
+		if mask.Get(idIndex(i)) {
+			result.Set(m.Map(ID{id: idIndex(i)}).id)
+		}
+	}
+	return result
+}
+
+// TransferEntities moves all entities matching the given batch filter from this world to world dst,
+// including their components.
+// Entities are created in dst in bulk, per table, and removed from this world afterwards.
+// The given callback function is called with each moved entity and its new counterpart in dst.
+// The function can be nil.
+//
+// Component IDs are translated between the worlds using a [ComponentMapping].
+// Component values are copied shallowly, and are marked as added in dst.
+// Observers are notified like for entity creation in dst and entity removal in this world.
+//
+// Components with sparse storage (see [StorageSparse]) are transferred as well.
+//
+// Relation targets that are among the moved entities are replaced by their counterparts in dst.
+// Other relation targets are not valid in the destination world,
+// so relations can only target moved entities or the zero entity.
+//
+// Panics if dst is this world, if any of the worlds is locked,
+// if a component type uses different storage kinds in the worlds,
+// or if any of the entities has a relation target that is not moved.
+func (w *World) TransferEntities(dst *World, batch Batch, fn func(old, new Entity)) {
+	if dst == w {
+		panic("can't transfer entities to the same world")
+	}
+	w.checkLocked()
+	dst.checkLocked()
+
+	mapping := NewComponentMapping(w, dst)
+	s, ds := &w.storage, &dst.storage
+
+	tables := s.getBatchTables(&batch)
+	s.slices.tables = tables[:0]
+	tables = append([]tableID(nil), tables...)
+
+	// Offsets of the tables' entities among all moved entities.
+	offsets := make(map[tableID]int, len(tables))
+	total := 0
+	for _, tableID := range tables {
+		offsets[tableID] = total
+		total += s.tables[tableID].Len()
+	}
+	for _, tableID := range tables {
+		table := &s.tables[tableID]
+		if table.Len() == 0 {
+			continue
+		}
+		for _, rel := range table.relationIDs {
+			if rel.target.IsZero() {
+				continue
+			}
+			if _, ok := offsets[s.entities[rel.target.id].table]; !ok {
+				panic("can't transfer entities with a relation target that is not transferred")
+			}
+		}
+		if !table.hasMultiRelations {
+			continue
+		}
+		for _, m := range s.multi {
+			if m == nil {
+				continue
+			}
+			for j := range uintptr(table.len) {
+				for _, target := range m.Targets(table.GetEntity(j)) {
+					if _, ok := offsets[s.entities[target.id].table]; !ok {
+						panic("can't transfer entities with a relation target that is not transferred")
+					}
+				}
+			}
+		}
+	}
+
+	hasObs := ds.observers.HasObservers(OnCreateEntity)
+	shouldLock := hasObs || fn != nil
+	var lock, srcLock uint8
+	if shouldLock {
+		lock = dst.lock()
+		srcLock = w.lock()
+	}
+
+	// Entities are allocated up front, so that relation targets can be translated
+	// independent of the order of tables.
+	entities := ds.allocEntities(total)
+	newEntity := func(old Entity) Entity {
+		index := s.entities[old.id]
+		return entities[offsets[index.table]+int(index.row)]
+	}
+
+	// Pairs of source and destination sets of sparse components.
+	var sparse [][2]*sparseSet
+	for i, set := range s.sparse {
+		if set != nil && set.Len() > 0 {
+			id := mapping.Map(ID{id: idIndex(i)})
+			sparse = append(sparse, [2]*sparseSet{set, ds.sparse[id.id]})
+		}
+	}
+
+	created := make([]tableID, len(tables))
+	for i, tableID := range tables {
+		from := &s.tables[tableID]
+		count := from.Len()
+		if count == 0 {
+			continue
+		}
+		ids := make([]ID, len(from.ids))
+		for i, id := range from.ids {
+			ids[i] = mapping.Map(id)
+		}
+		var relations []relationID
+		for _, rel := range from.relationIDs {
+			target := rel.target
+			if !target.IsZero() {
+				target = newEntity(target)
+			}
+			relations = append(relations, relationID{component: mapping.Map(rel.component), target: target})
+		}
+		ds.registerTargets(relations)
+
+		var outMask bitMask
+		to, _ := ds.findOrCreateTableAdd(&ds.tables[0], ids, relations, &outMask)
+		start := to.Len()
+		offset := offsets[tableID]
+		ds.insertEntities(to, entities[offset:offset+count])
+		for i, id := range from.ids {
+			to.Column(ids[i]).CopyToEnd(from.Column(id), to.len, uint32(count))
+		}
+		to.SetAdded(uint32(start), uint32(count), nil, ds.tick)
+		for _, sets := range sparse {
+			for j := range count {
+				sets[0].copyTo(sets[1], from.GetEntity(uintptr(j)), entities[offset+j])
+			}
+		}
+		if from.hasMultiRelations {
+			for k, m := range s.multi {
+				if m == nil {
+					continue
+				}
+				dm := ds.multiRelation(mapping.Map(ID{id: idIndex(k)}))
+				for j := range count {
+					targets := m.Targets(from.GetEntity(uintptr(j)))
+					if len(targets) == 0 {
+						continue
+					}
+					newTargets := make([]Entity, len(targets))
+					for t, target := range targets {
+						newTargets[t] = newEntity(target)
+						ds.isTarget[newTargets[t].id] = true
+					}
+					dm.Set(entities[offset+j], normalizeTargets(newTargets))
+				}
+			}
+		}
+		created[i] = to.id
+	}
+
+	// Callbacks and observers are notified after all entities are complete.
+	for i, tableID := range tables {
+		from := &s.tables[tableID]
+		count := from.Len()
+		if count == 0 {
+			continue
+		}
+		to := &ds.tables[created[i]]
+		start := offsets[tableID]
+		if fn != nil {
+			for j := range count {
+				fn(from.GetEntity(uintptr(j)), entities[start+j])
+			}
+		}
+		if hasObs {
+			mask := mapping.mapMask(&s.archetypes[from.archetype].mask)
+			row := ds.entities[entities[start].id].row
+			ds.observers.FireCreateEntityBatch(to, int(row), &mask)
+		}
+	}
+
+	if shouldLock {
+		w.unlock(srcLock)
+		dst.unlock(lock)
+	}
+
+	w.RemoveEntities(batch, nil)
+	dst.flushEvents()
+}
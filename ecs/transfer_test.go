@@ -0,0 +1,279 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestComponentMapping(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+
+	ComponentID[Velocity](dst)
+	posID := ComponentID[Position](src)
+	velID := ComponentID[Velocity](src)
+	headID := ComponentID[Heading](src)
+
+	mapping := NewComponentMapping(src, dst)
+	expectEqual(t, ComponentID[Position](dst), mapping.Map(posID))
+	expectEqual(t, ComponentID[Velocity](dst), mapping.Map(velID))
+	expectEqual(t, ComponentID[Heading](dst), mapping.Map(headID))
+	expectEqual(t, id(0), mapping.Map(velID))
+	expectEqual(t, id(1), mapping.Map(posID))
+
+	ids := mapping.MapIDs(newIDs([]ID{posID, velID}))
+	expectEqual(t, 2, ids.Len())
+	expectEqual(t, id(1), ids.Get(0))
+	expectEqual(t, id(0), ids.Get(1))
+
+	mask := newMask(posID, headID)
+	mapped := mapping.mapMask(&mask)
+	expected := newMask(mapping.Map(posID), mapping.Map(headID))
+	expectTrue(t, mapped.Equals(&expected))
+
+	expectPanicsWithValue(t, "no mapping for component with ID 3",
+		func() {
+			mapping.Map(id(3))
+		})
+	ComponentID[Label](src)
+	expectPanicsWithValue(t, "no mapping for component with ID 3",
+		func() {
+			mapping.Map(id(3))
+		})
+	RegisterComponent(src, ComponentOptions[CompA]{Storage: StorageSparse})
+	mapping = NewComponentMapping(src, dst)
+	info, _ := ComponentInfo(dst, mapping.Map(ComponentID[CompA](src)))
+	expectEqual(t, StorageSparse, info.Storage)
+
+	ComponentID[CompB](src)
+	RegisterComponent(dst, ComponentOptions[CompB]{Storage: StorageSparse})
+	expectPanicsWithValue(t, "component type CompB uses different storage kinds in the worlds",
+		func() {
+			NewComponentMapping(src, dst)
+		})
+}
+
+func TestWorldTransferEntities(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+	ComponentID[Velocity](dst)
+
+	mapper := NewMap2[Position, Velocity](src)
+	posMap := NewMap[Position](src)
+	headMap := NewMap[Heading](src)
+
+	for i := range 10 {
+		mapper.NewEntity(&Position{X: float64(i)}, &Velocity{Y: float64(i)})
+	}
+	for i := range 5 {
+		posMap.NewEntity(&Position{X: float64(i + 10)})
+	}
+	kept := headMap.NewEntity(&Heading{H: 1})
+	// Empty tables are skipped.
+	src.RemoveEntity(NewMap2[Position, Heading](src).NewEntity(&Position{}, &Heading{}))
+
+	created := 0
+	Observe(OnCreateEntity).
+		Do(func(e Entity) {
+			created++
+			expectTrue(t, dst.IsLocked())
+		}).
+		Register(dst)
+	removed := 0
+	Observe(OnRemoveEntity).
+		Do(func(e Entity) { removed++ }).
+		Register(src)
+
+	dstMapper := NewMap2[Position, Velocity](dst)
+	dstPosMap := NewMap[Position](dst)
+
+	moved := map[Entity]Entity{}
+	filter := NewFilter1[Position](src)
+	src.TransferEntities(dst, filter.Batch(), func(old, new Entity) {
+		expectTrue(t, src.Alive(old))
+		moved[old] = new
+	})
+
+	expectEqual(t, 15, len(moved))
+	expectEqual(t, 15, created)
+	expectEqual(t, 15, removed)
+	expectTrue(t, src.Alive(kept))
+	expectTrue(t, headMap.Has(kept))
+
+	for old, new := range moved {
+		expectFalse(t, src.Alive(old))
+		expectTrue(t, dst.Alive(new))
+		pos := dstPosMap.Get(new)
+		if pos.X < 10 {
+			p, v := dstMapper.Get(new)
+			expectEqual(t, p.X, v.Y)
+		} else {
+			expectFalse(t, NewMap[Velocity](dst).Has(new))
+		}
+	}
+
+	cnt := 0
+	query := NewFilter1[Position](dst).Added().Query()
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, 15, cnt)
+
+	query2 := filter.Query()
+	expectEqual(t, 0, query2.Count())
+	query2.Close()
+}
+
+func TestWorldTransferEntitiesPanics(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+
+	childMap := NewMap[ChildOf](src)
+	posMap := NewMap[Position](src)
+	parent := posMap.NewEntity(&Position{})
+	childMap.NewEntity(&ChildOf{}, parent)
+	childMap.NewEntity(&ChildOf{}, Entity{})
+
+	expectPanicsWithValue(t, "can't transfer entities to the same world",
+		func() {
+			src.TransferEntities(src, NewFilter0(src).Batch(), nil)
+		})
+	expectPanicsWithValue(t, "can't transfer entities with a relation target that is not transferred",
+		func() {
+			src.TransferEntities(dst, NewFilter1[ChildOf](src).Batch(), nil)
+		})
+
+	src.TransferEntities(dst, NewFilter1[ChildOf](src).Batch(RelIdx(0, Entity{})), nil)
+	childQuery := NewFilter1[ChildOf](dst).Query()
+	expectEqual(t, 1, childQuery.Count())
+	childQuery.Close()
+
+	query := NewFilter0(dst).Query()
+	expectPanicsWithValue(t, "cannot modify a locked world: collect entities into a slice and apply changes after query iteration has completed",
+		func() {
+			src.TransferEntities(dst, NewFilter0(src).Batch(), nil)
+		})
+	query.Close()
+}
+
+func TestWorldTransferEntitiesRelations(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+
+	posMap := NewMap[Position](src)
+	childMap := NewMap2[Position, ChildOf](src)
+
+	parent1 := posMap.NewEntity(&Position{X: 1})
+	parent2 := posMap.NewEntity(&Position{X: 2})
+	child1 := childMap.NewEntity(&Position{X: 11}, &ChildOf{}, RelIdx(1, parent1))
+	child2 := childMap.NewEntity(&Position{X: 12}, &ChildOf{}, RelIdx(1, parent2))
+	grandchild := childMap.NewEntity(&Position{X: 21}, &ChildOf{}, RelIdx(1, child1))
+
+	moved := map[Entity]Entity{}
+	src.TransferEntities(dst, NewFilter1[Position](src).Batch(), func(old, new Entity) {
+		moved[old] = new
+	})
+	expectEqual(t, 5, len(moved))
+
+	dstChildMap := NewMap2[Position, ChildOf](dst)
+	expectEqual(t, moved[parent1], dstChildMap.GetRelation(moved[child1], 1))
+	expectEqual(t, moved[parent2], dstChildMap.GetRelation(moved[child2], 1))
+	expectEqual(t, moved[child1], dstChildMap.GetRelation(moved[grandchild], 1))
+	pos, _ := dstChildMap.Get(moved[grandchild])
+	expectEqual(t, 21.0, pos.X)
+
+	query := NewFilter1[ChildOf](dst).Query(RelIdx(0, moved[parent1]))
+	expectEqual(t, 1, query.Count())
+	query.Close()
+
+	dst.RemoveEntity(moved[parent1])
+	expectTrue(t, dstChildMap.GetRelation(moved[child1], 1).IsZero())
+}
+
+func TestWorldTransferEntitiesMultiRelations(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+
+	posMap := NewMap[Position](src)
+	likesMap := NewMap2[Position, Likes](src)
+
+	a := posMap.NewEntity(&Position{X: 1})
+	b := posMap.NewEntity(&Position{X: 2})
+	e := likesMap.NewEntity(&Position{X: 3}, &Likes{}, Rel[Likes](a), Rel[Likes](b))
+	other := src.NewEntity()
+	f := likesMap.NewEntity(&Position{X: 4}, &Likes{}, Rel[Likes](other))
+
+	expectPanicsWithValue(t, "can't transfer entities with a relation target that is not transferred", func() {
+		src.TransferEntities(dst, NewFilter1[Position](src).Batch(), nil)
+	})
+	NewMap[Likes](src).RemoveTargets(f, other)
+
+	moved := map[Entity]Entity{}
+	src.TransferEntities(dst, NewFilter1[Position](src).Batch(), func(old, new Entity) {
+		moved[old] = new
+	})
+	expectEqual(t, 4, len(moved))
+
+	dstLikesMap := NewMap[Likes](dst)
+	targets := dstLikesMap.GetRelations(moved[e])
+	expectEqual(t, 2, len(targets))
+	expectTrue(t, containsEntity(targets, moved[a]))
+	expectTrue(t, containsEntity(targets, moved[b]))
+
+	query := NewFilter1[Likes](dst).Query(Rel[Likes](moved[b]))
+	expectEqual(t, 1, query.Count())
+	query.Close()
+
+	dst.RemoveEntity(moved[a])
+	expectSlicesEqual(t, []Entity{moved[b]}, dstLikesMap.GetRelations(moved[e]))
+}
+
+func TestWorldTransferEntitiesSparse(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+	RegisterComponent(src, ComponentOptions[Heading]{Storage: StorageSparse})
+
+	posMap := NewMap[Position](src)
+	headMap := NewMap[Heading](src)
+	e1 := posMap.NewEntity(&Position{X: 1})
+	e2 := posMap.NewEntity(&Position{X: 2})
+	headMap.Add(e1, &Heading{H: 10})
+
+	moved := map[Entity]Entity{}
+	src.TransferEntities(dst, NewFilter1[Position](src).Batch(), func(old, new Entity) {
+		moved[old] = new
+	})
+	expectEqual(t, 0, src.storage.sparse[headMap.id.id].Len())
+
+	dstHeadMap := NewMap[Heading](dst)
+	info, _ := ComponentInfo(dst, dstHeadMap.id)
+	expectEqual(t, StorageSparse, info.Storage)
+	expectEqual(t, 10.0, dstHeadMap.Get(moved[e1]).H)
+	expectFalse(t, dstHeadMap.Has(moved[e2]))
+}
+
+func TestWorldTransferEntitiesTracked(t *testing.T) {
+	src := NewWorld(16)
+	dst := NewWorld(16)
+
+	NewMap[Position](src).NewBatch(5, &Position{})
+	NewMap2[Position, Velocity](src).NewBatch(5, &Position{}, &Velocity{})
+	// Velocity is tracked in both worlds, Position only in the destination.
+	srcChanged := NewFilter1[Velocity](src).Changed(C[Velocity]())
+	srcChanged.Since(src.AdvanceTick())
+
+	dstChanged := NewFilter1[Position](dst).Changed(C[Position]())
+	dstAdded := NewFilter0(dst).Added(C[Velocity]())
+	since := dst.AdvanceTick()
+	dstChanged.Since(since)
+	dstAdded.Since(since)
+
+	src.TransferEntities(dst, NewFilter1[Position](src).Batch(), nil)
+	expectEqual(t, 10, countTracked(dstChanged))
+	expectEqual(t, 5, countTracked0(dstAdded))
+
+	since = dst.AdvanceTick()
+	dstChanged.Since(since)
+	dstAdded.Since(since)
+	expectEqual(t, 0, countTracked(dstChanged))
+	expectEqual(t, 0, countTracked0(dstAdded))
+}
@@ -5,21 +5,23 @@ import "reflect"
 // ID is the component identifier.
 // It is not relevant when using the default generic API.
 type ID struct {
-	id uint8
+	id idIndex
 }
 
 // id creates a component ID from an int value. For testing.
 func id(id int) ID {
-	return ID{uint8(id)}
+	return ID{idIndex(id)}
 }
 
 // id creates a component ID from an uint8 value. For testing.
 func id8(id uint8) ID {
-	return ID{id}
+	return ID{idIndex(id)}
 }
 
 // Index returns the internal component index of this component ID.
-func (id ID) Index() uint8 {
+//
+// The index is an uint8, or an uint16 with build tag ark_large.
+func (id ID) Index() idIndex {
 	return id.id
 }
 
@@ -48,11 +50,13 @@ func (ids *IDs) Len() int {
 // ResID is the resource identifier.
 // It is not relevant when using the default generic API.
 type ResID struct {
-	id uint8
+	id idIndex
 }
 
 // Index returns the internal component index of this resource ID.
-func (id ResID) Index() uint8 {
+//
+// The index is an uint8, or an uint16 with build tag ark_large.
+func (id ResID) Index() idIndex {
 	return id.id
 }
 
@@ -81,7 +85,13 @@ type EntityDump struct {
 // CompInfo provides information about a registered component.
 // Returned by [ComponentInfo].
 type CompInfo struct {
-	Type       reflect.Type
-	ID         ID
-	IsRelation bool
+	Type            reflect.Type
+	ID              ID
+	IsRelation      bool
+	IsMultiRelation bool
+	IsSymmetric     bool
+	IsTrivial       bool
+	Name            string      // Name of the component type, see [ComponentOptions].
+	Storage         StorageKind // Storage kind of the component type, see [ComponentOptions].
+	HasCloner       bool        // Whether the component type has a cloner, see [ComponentOptions].
 }
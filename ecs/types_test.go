@@ -28,6 +28,19 @@ type ChildOf2 struct {
 	RelationMarker
 }
 
+type Likes struct {
+	MultiRelationMarker
+	Weight int
+}
+
+type PartnerOf struct {
+	RelationMarker
+}
+
+type FriendOf struct {
+	MultiRelationMarker
+}
+
 type SliceComp struct {
 	Slice []int
 }
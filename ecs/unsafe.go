@@ -1,6 +1,11 @@
 package ecs
 
-import "unsafe"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unsafe"
+)
 
 // Unsafe provides access to Ark's unsafe ID-based API.
 // Get an instance via [World.Unsafe].
@@ -16,6 +21,7 @@ type Unsafe struct {
 func (u Unsafe) NewEntity(ids ...ID) Entity {
 	entity, mask := u.world.newEntity(ids, nil)
 	u.world.storage.observers.FireCreateEntityIfHas(entity, mask)
+	u.world.flushEvents()
 	return entity
 }
 
@@ -27,6 +33,7 @@ func (u Unsafe) NewEntityRel(ids []ID, relations ...Relation) Entity {
 	if len(relations) > 0 {
 		u.world.storage.observers.FireCreateEntityRelIfHas(entity, mask)
 	}
+	u.world.flushEvents()
 	return entity
 }
 
@@ -81,6 +88,7 @@ func (u Unsafe) GetRelationUnchecked(entity Entity, comp ID) Entity {
 func (u Unsafe) SetRelations(entity Entity, relations ...Relation) {
 	u.cachedRelations = relationSlice(relations).ToRelationIDsForUnsafe(u.world, u.cachedRelations[:0])
 	u.world.setRelations(entity, u.cachedRelations)
+	u.world.flushEvents()
 }
 
 // Add the given components to an entity.
@@ -90,6 +98,7 @@ func (u Unsafe) Add(entity Entity, comp ...ID) {
 	}
 	oldMask, newMask := u.world.add(entity, comp, nil)
 	u.world.storage.observers.FireAddIfHas(OnAddComponents, entity, oldMask, newMask)
+	u.world.flushEvents()
 }
 
 // AddRel adds the given components and relation targets to an entity.
@@ -103,6 +112,7 @@ func (u Unsafe) AddRel(entity Entity, comps []ID, relations ...Relation) {
 	if len(relations) > 0 {
 		u.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 	}
+	u.world.flushEvents()
 }
 
 // Remove the given components from an entity.
@@ -111,6 +121,7 @@ func (u Unsafe) Remove(entity Entity, comp ...ID) {
 		panic("can't remove components from a dead entity")
 	}
 	u.world.remove(entity, comp)
+	u.world.flushEvents()
 }
 
 // Exchange the given components on entity.
@@ -127,6 +138,7 @@ func (u Unsafe) Exchange(entity Entity, add []ID, remove []ID, relations ...Rela
 			u.world.storage.observers.FireAddIfHas(OnAddRelations, entity, oldMask, newMask)
 		}
 	}
+	u.world.flushEvents()
 }
 
 // IDs returns all component IDs of an entity.
@@ -204,3 +216,172 @@ func (u Unsafe) LoadEntities(data *EntityDump) {
 		u.world.storage.entities[entity.id] = entityIndex{table: table.id, row: tableIdx}
 	}
 }
+
+// DumpTables calls the given function for each non-empty table of the world, for fast serialization.
+//
+// The function receives the component IDs of the table, the relation targets of these components
+// (the zero entity for non-relation components and multi-target relation components), the table's entities,
+// and a slice value for each component, holding the component data of the table.
+// The slices point to the world's storage and must only be read, and not be used after the function returns.
+//
+// The world is locked during the function calls.
+//
+// See also [Unsafe.LoadTable], [Unsafe.DumpEntities] and [Unsafe.DumpTargets].
+func (u Unsafe) DumpTables(fn func(ids []ID, targets []Entity, entities []Entity, columns []reflect.Value)) {
+	s := &u.world.storage
+	lock := u.world.lockSafe()
+	defer u.world.unlockSafe(lock)
+
+	for i := range s.archetypes {
+		archetype := &s.archetypes[i]
+		for _, id := range archetype.tables.tables {
+			table := &s.tables[id]
+			if table.len == 0 {
+				continue
+			}
+			targets := make([]Entity, len(table.columns))
+			columns := make([]reflect.Value, len(table.columns))
+			for j := range table.columns {
+				column := &table.columns[j]
+				targets[j] = column.target
+				columns[j] = column.data.Slice(0, int(table.len))
+			}
+			entities := table.entities.data.Interface().([]Entity)[:table.len]
+			fn(table.ids, targets, entities, columns)
+		}
+	}
+}
+
+// DumpTargets calls the given function for each entity with targets of a multi-target relation component
+// (see [MultiRelationMarker]), for fast serialization.
+//
+// The function receives the component ID, the entity and its targets, sorted by entity ID.
+// The targets slice points to the world's storage and must only be read, and not be used after the function returns.
+//
+// The world is locked during the function calls.
+//
+// See also [Unsafe.LoadTargets] and [Unsafe.DumpTables].
+func (u Unsafe) DumpTargets(fn func(comp ID, entity Entity, targets []Entity)) {
+	s := &u.world.storage
+	lock := u.world.lockSafe()
+	defer u.world.unlockSafe(lock)
+
+	for i, m := range s.multi {
+		if m == nil {
+			continue
+		}
+		comp := ID{id: idIndex(i)}
+		entities := make([]entityID, 0, len(m.targets))
+		for entity := range m.targets {
+			entities = append(entities, entity)
+		}
+		// Sort for deterministic output.
+		sort.Slice(entities, func(i, j int) bool { return entities[i] < entities[j] })
+		for _, entity := range entities {
+			fn(comp, s.entityPool.entities[entity], m.targets[entity])
+		}
+	}
+}
+
+// LoadTable moves the given entities into the table for the given components and relation targets,
+// for fast deserialization.
+// Returns a slice value for each of the given components, for filling in the entities' component data.
+//
+// The entities must be alive and must not have any components, as after [Unsafe.LoadEntities].
+// Targets must contain the relation target for each relation component,
+// and the zero entity for non-relation components and multi-target relation components.
+// Targets of multi-target relation components are loaded with [Unsafe.LoadTargets].
+//
+// Observers are not notified.
+// For change detection, the components are marked as added at the current tick.
+//
+// Panics if the world is locked, if the entities are dead or have components,
+// or if the targets don't match the components.
+//
+// See also [Unsafe.DumpTables].
+func (u Unsafe) LoadTable(ids []ID, targets []Entity, entities []Entity) []reflect.Value {
+	u.world.checkLocked()
+	if len(ids) != len(targets) {
+		panic("number of relation targets must match the number of components")
+	}
+	s := &u.world.storage
+
+	var relations []relationID
+	for i, id := range ids {
+		if !s.registry.IsRelation[id.id] {
+			if !targets[i].IsZero() {
+				panic(fmt.Sprintf("component with ID %d is not a relation component", id.id))
+			}
+			continue
+		}
+		if s.registry.IsMultiRelation[id.id] {
+			if !targets[i].IsZero() {
+				panic(fmt.Sprintf("component with ID %d is a multi-target relation component, use LoadTargets", id.id))
+			}
+			continue
+		}
+		s.checkRelationTarget(targets[i])
+		relations = append(relations, relationID{component: id, target: targets[i]})
+	}
+
+	mask := bitMask{}
+	oldTable := &s.tables[0]
+	table, _ := s.findOrCreateTableAdd(oldTable, ids, relations, &mask)
+
+	for _, e := range entities {
+		if !s.entityPool.Alive(e) {
+			panic("can't load a dead entity into a table")
+		}
+		index := &s.entities[e.id]
+		if index.table != oldTable.id {
+			panic("can't load an entity that already has components into a table")
+		}
+		if oldTable.Remove(index.row) {
+			swapEntity := oldTable.GetEntity(uintptr(index.row))
+			s.entities[swapEntity.id].row = index.row
+		}
+	}
+
+	start := table.len
+	count := uint32(len(entities))
+	table.Alloc(count)
+	for i, e := range entities {
+		row := start + uint32(i)
+		table.SetEntity(row, e)
+		s.entities[e.id] = entityIndex{table: table.id, row: row}
+	}
+	table.SetAdded(start, count, nil, s.tick)
+	s.registerTargets(relations)
+
+	columns := make([]reflect.Value, len(ids))
+	for i, id := range ids {
+		columns[i] = table.components[id.id].data.Slice(int(start), int(start+count))
+	}
+	return columns
+}
+
+// LoadTargets sets the targets of a multi-target relation component (see [MultiRelationMarker]) for an entity,
+// for fast deserialization. Replaces any previous targets of the entity.
+//
+// The entity must have the component, as after [Unsafe.LoadTable].
+// Observers are not notified.
+//
+// Panics if the world is locked, if the entity is dead or does not have the component,
+// if the component is not a multi-target relation component, or if any of the targets is dead.
+//
+// See also [Unsafe.DumpTargets].
+func (u Unsafe) LoadTargets(comp ID, entity Entity, targets []Entity) {
+	u.world.checkLocked()
+	s := &u.world.storage
+	s.checkMultiRelationComponent(comp)
+	if !s.has(entity, comp) {
+		tp, _ := s.registry.ComponentType(comp.id)
+		panic(fmt.Sprintf("entity has no component of type %s to set relation target for", tp.Name()))
+	}
+	relations := make([]relationID, len(targets))
+	for i, target := range targets {
+		relations[i] = relationID{component: comp, target: target}
+	}
+	s.setMultiTargets(entity, relations)
+	s.registerTargets(relations)
+}
@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -245,3 +246,157 @@ func TestUnsafeEntityDumpFail(t *testing.T) {
 			w2.Unsafe().LoadEntities(&eData)
 		})
 }
+
+func TestUnsafeTableDump(t *testing.T) {
+	w := NewWorld(4)
+	posID := ComponentID[Position](w)
+	childID := ComponentID[ChildOf](w)
+	mapper := NewMap2[Position, ChildOf](w)
+	posMap := NewMap[Position](w)
+
+	parent := w.NewEntity()
+	for i := range 5 {
+		mapper.NewEntity(&Position{float64(i), 0}, &ChildOf{}, Rel[ChildOf](parent))
+	}
+	for i := range 3 {
+		posMap.NewEntity(&Position{float64(i + 10), 0})
+	}
+
+	eData := w.Unsafe().DumpEntities()
+	w2 := NewWorld(4)
+	_ = ComponentID[ChildOf](w2)
+	_ = ComponentID[Position](w2)
+	w2.Unsafe().LoadEntities(&eData)
+
+	tables := 0
+	w.Unsafe().DumpTables(func(ids []ID, targets []Entity, entities []Entity, columns []reflect.Value) {
+		expectTrue(t, w.IsLocked())
+		expectEqual(t, len(ids), len(targets))
+		expectEqual(t, len(ids), len(columns))
+		tables++
+		if len(ids) == 0 {
+			return
+		}
+
+		newIDs := make([]ID, len(ids))
+		for i, id := range ids {
+			info, _ := ComponentInfo(w, id)
+			newIDs[i] = TypeID(w2, info.Type)
+			expectEqual(t, len(entities), columns[i].Len())
+		}
+		newColumns := w2.Unsafe().LoadTable(newIDs, targets, entities)
+		for i := range columns {
+			reflect.Copy(newColumns[i], columns[i])
+		}
+	})
+	expectEqual(t, 3, tables)
+	expectFalse(t, w.IsLocked())
+
+	posMap2 := NewMap[Position](w2)
+	childMap2 := NewMap[ChildOf](w2)
+
+	query := NewUnsafeFilter(w, posID).Query()
+	for query.Next() {
+		e := query.Entity()
+		expectTrue(t, w2.Alive(e))
+		expectEqual(t, *(*Position)(query.Get(posID)), *posMap2.Get(e))
+		if query.Has(childID) {
+			expectEqual(t, parent, childMap2.GetRelation(e))
+		} else {
+			expectFalse(t, childMap2.Has(e))
+		}
+	}
+
+	filter := NewFilter1[ChildOf](w2)
+	q := filter.Query(RelIdx(0, parent))
+	expectEqual(t, 5, q.Count())
+	q.Close()
+
+	w2.RemoveEntity(parent)
+	q = filter.Query(RelIdx(0, Entity{}))
+	expectEqual(t, 5, q.Count())
+	q.Close()
+}
+
+func TestUnsafeLoadTableFail(t *testing.T) {
+	w := NewWorld(4)
+	posID := ComponentID[Position](w)
+	childID := ComponentID[ChildOf](w)
+	likesID := ComponentID[Likes](w)
+
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	e3 := w.Unsafe().NewEntity(posID)
+	w.RemoveEntity(e2)
+	u := w.Unsafe()
+
+	expectPanicsWithValue(t, "number of relation targets must match the number of components", func() {
+		u.LoadTable([]ID{posID}, nil, []Entity{e1})
+	})
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a relation component", posID.id), func() {
+		u.LoadTable([]ID{posID}, []Entity{e1}, []Entity{e1})
+	})
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is a multi-target relation component, use LoadTargets", likesID.id), func() {
+		u.LoadTable([]ID{likesID}, []Entity{e3}, []Entity{e1})
+	})
+	expectPanicsWithValue(t, "can't use a dead entity as relation target, except for the zero entity", func() {
+		u.LoadTable([]ID{childID}, []Entity{e2}, []Entity{e1})
+	})
+	expectPanicsWithValue(t, "can't load a dead entity into a table", func() {
+		u.LoadTable([]ID{posID}, []Entity{{}}, []Entity{e2})
+	})
+	expectPanicsWithValue(t, "can't load an entity that already has components into a table", func() {
+		u.LoadTable([]ID{posID}, []Entity{{}}, []Entity{e3})
+	})
+}
+
+func TestUnsafeTargetsDump(t *testing.T) {
+	w := NewWorld(4)
+	likesID := ComponentID[Likes](w)
+	mapper := NewMap1[Likes](w)
+
+	t1 := w.NewEntity()
+	t2 := w.NewEntity()
+	e1 := mapper.NewEntity(&Likes{}, Rel[Likes](t2), Rel[Likes](t1))
+	e2 := mapper.NewEntity(&Likes{}, Rel[Likes](t1))
+	_ = mapper.NewEntity(&Likes{})
+
+	eData := w.Unsafe().DumpEntities()
+	w2 := NewWorld(4)
+	likesID2 := ComponentID[Likes](w2)
+	w2.Unsafe().LoadEntities(&eData)
+
+	w.Unsafe().DumpTables(func(ids []ID, targets []Entity, entities []Entity, columns []reflect.Value) {
+		if len(ids) == 0 {
+			return
+		}
+		expectSlicesEqual(t, []ID{likesID}, ids)
+		expectSlicesEqual(t, []Entity{{}}, targets)
+		w2.Unsafe().LoadTable([]ID{likesID2}, targets, entities)
+	})
+
+	entities := []Entity{}
+	w.Unsafe().DumpTargets(func(comp ID, entity Entity, targets []Entity) {
+		expectTrue(t, w.IsLocked())
+		expectEqual(t, likesID, comp)
+		entities = append(entities, entity)
+		w2.Unsafe().LoadTargets(likesID2, entity, targets)
+	})
+	expectFalse(t, w.IsLocked())
+	expectSlicesEqual(t, []Entity{e1, e2}, entities)
+
+	likesMap2 := NewMap[Likes](w2)
+	expectSlicesEqual(t, []Entity{t1, t2}, likesMap2.GetRelations(e1))
+	expectSlicesEqual(t, []Entity{t1}, likesMap2.GetRelations(e2))
+
+	query := NewFilter1[Likes](w2).Query(RelIdx(0, t1))
+	expectEqual(t, 2, query.Count())
+	query.Close()
+
+	expectPanicsWithValue(t, fmt.Sprintf("component with ID %d is not a multi-target relation component", ComponentID[Position](w2).id), func() {
+		w2.Unsafe().LoadTargets(ComponentID[Position](w2), e1, []Entity{t1})
+	})
+	expectPanicsWithValue(t, "entity has no component of type Likes to set relation target for", func() {
+		w2.Unsafe().LoadTargets(likesID2, t1, []Entity{t2})
+	})
+}
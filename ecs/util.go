@@ -22,6 +22,19 @@ func capPow2(required uint32) uint32 {
 	return required + 1
 }
 
+// sameSlices returns whether both slices contain the same elements in the same order.
+func sameSlices[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // get the component for an entity from a component storage.
 //
 // Returns nil if the entity does not have the component.
@@ -33,6 +46,20 @@ func get[T any](storage *componentStorage, index *entityIndex) *T {
 	return (*T)(col.Get(uintptr(index.row)))
 }
 
+// getMut gets the component for an entity from a component storage,
+// and marks it as changed at the given tick.
+//
+// Returns nil if the entity does not have the component.
+func getMut[T any](storage *componentStorage, index *entityIndex, tick uint32) *T {
+	col := storage.columns[index.table]
+	if col == nil {
+		return nil
+	}
+	row := uintptr(index.row)
+	col.setChanged(row, tick)
+	return (*T)(col.Get(row))
+}
+
 // copyPtr copies from one pointer to another.
 // This is not GC-safe. Use only for trivial/value types.
 func copyPtr(src, dst unsafe.Pointer, itemSize uintptr) {
@@ -118,7 +145,17 @@ func isRelation(tp reflect.Type) bool {
 		return false
 	}
 	field := tp.Field(0)
-	return field.Type == relationType && field.Name == relationType.Name()
+	return (field.Type == relationType && field.Name == relationType.Name()) ||
+		(field.Type == multiRelationType && field.Name == multiRelationType.Name())
+}
+
+// isMultiRelation determines whether a type is a relation component with multiple targets.
+func isMultiRelation(tp reflect.Type) bool {
+	if tp.Kind() != reflect.Struct || tp.NumField() == 0 {
+		return false
+	}
+	field := tp.Field(0)
+	return field.Type == multiRelationType && field.Name == multiRelationType.Name()
 }
 
 // isTrivial checks if a type is "trivial" (contains no pointers, slices, maps, strings, or channels).
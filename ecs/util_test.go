@@ -54,6 +54,17 @@ func TestCopyPtr(t *testing.T) {
 	}
 }
 
+func TestIsRelation(t *testing.T) {
+	expectTrue(t, isRelation(reflect.TypeFor[ChildOf]()))
+	expectTrue(t, isRelation(reflect.TypeFor[Likes]()))
+	expectFalse(t, isRelation(reflect.TypeFor[Position]()))
+	expectFalse(t, isRelation(reflect.TypeFor[int]()))
+
+	expectTrue(t, isMultiRelation(reflect.TypeFor[Likes]()))
+	expectFalse(t, isMultiRelation(reflect.TypeFor[ChildOf]()))
+	expectFalse(t, isMultiRelation(reflect.TypeFor[struct{}]()))
+}
+
 func TestIsTrivial(t *testing.T) {
 	expectTrue(t, isTrivial(reflect.TypeFor[[5]int]()))
 	expectTrue(t, isTrivial(reflect.TypeFor[struct{}]()))
@@ -1,6 +1,7 @@
 package ecs
 
 import (
+	"fmt"
 	"reflect"
 	"time"
 
@@ -35,6 +36,7 @@ func (w *World) NewEntity() Entity {
 
 	entity, _ := w.storage.createEntity(0)
 	w.storage.observers.FireCreateEntityIfHas(entity, &w.storage.archetypes[0].mask)
+	w.flushEvents()
 	return entity
 }
 
@@ -69,6 +71,7 @@ func (w *World) NewEntities(count int, fn func(entity Entity)) {
 	if shouldLock {
 		w.unlock(lock)
 	}
+	w.flushEvents()
 }
 
 // CopyEntity copies an entity with all its components.
@@ -96,11 +99,16 @@ func (w *World) CopyEntity(e Entity) Entity {
 	archetype := &s.archetypes[table.archetype]
 
 	table.CopyAll(table, idx, index.row)
+	table.SetAdded(idx, 1, nil, s.tick)
+	if table.hasMultiRelations {
+		s.copyMultiTargets(e, entity)
+	}
 
 	w.storage.observers.FireCreateEntityIfHas(entity, &archetype.mask)
-	if archetype.HasRelations() {
+	if archetype.HasRelations() || archetype.hasMultiRelations {
 		w.storage.observers.FireCreateEntityRelIfHas(entity, &archetype.mask)
 	}
+	w.flushEvents()
 	return entity
 }
 
@@ -117,6 +125,7 @@ func (w *World) Alive(entity Entity) bool {
 func (w *World) RemoveEntity(entity Entity) {
 	w.checkLocked()
 	w.storage.RemoveEntity(entity)
+	w.flushEvents()
 }
 
 // RemoveEntities removes all entities matching the given batch filter,
@@ -156,7 +165,7 @@ func (w *World) RemoveEntities(batch Batch, fn func(entity Entity)) {
 		if hasRelationObs {
 			for _, tableID := range tables {
 				table := &w.storage.tables[tableID]
-				if !table.HasRelations() {
+				if !table.HasRelations() && !table.hasMultiRelations {
 					continue
 				}
 				mask := &w.storage.archetypes[table.archetype].mask
@@ -177,6 +186,12 @@ func (w *World) RemoveEntities(batch Batch, fn func(entity Entity)) {
 			}
 			w.storage.entities[entity.id].table = maxTableID
 			w.storage.entityPool.Recycle(entity)
+			if w.storage.registry.hasSparse {
+				w.storage.removeSparse(entity)
+			}
+			if table.hasMultiRelations {
+				w.storage.removeAllMultiTargets(entity)
+			}
 		}
 		table.Reset()
 	}
@@ -192,6 +207,28 @@ func (w *World) RemoveEntities(batch Batch, fn func(entity Entity)) {
 	if shouldLock {
 		w.unlock(lock)
 	}
+	w.flushEvents()
+}
+
+// ChangeTick returns the world's current change tick.
+//
+// All component changes and additions are recorded with the current change tick,
+// for component types that are used in change detection (see [Filter2.Changed]).
+// See [World.AdvanceTick] for details.
+func (w *World) ChangeTick() uint32 {
+	return w.storage.tick
+}
+
+// AdvanceTick advances the world's change tick by one, and returns the previous tick.
+//
+// Systems that use change detection (see [Filter2.Changed] and [Filter2.Added])
+// should call this at the end of each run, and store the returned tick.
+// The stored tick is then passed to [Filter2.Since] on the next run,
+// to process only components that were changed after the previous run.
+func (w *World) AdvanceTick() uint32 {
+	tick := w.storage.tick
+	w.storage.tick++
+	return tick
 }
 
 // IsLocked returns whether the world is locked by any queries.
@@ -333,3 +370,23 @@ func (w *World) Shrink(stopAfter ...time.Duration) bool {
 	}
 	return w.storage.Shrink(limit)
 }
+
+// UnregisterComponent removes a component type from the world, and frees its [ID] for re-use by other component types.
+// Intended for dropping component types owned by plugins that are unloaded at runtime.
+//
+// Archetypes that contain the component type are removed, so all entities with the component must be removed before.
+// Registered filters that refer to the component type are removed from the filter cache.
+// Filters, maps and IDs that refer to the component type must not be used afterwards.
+// Observers and hooks that refer to the component type must be removed before.
+// Snapshots taken before can't be restored, see [World.Restore].
+//
+// Panics if the component type is not registered, if any entity has the component,
+// if any observer or hook refers to it, or if the world is locked.
+func (w *World) UnregisterComponent(comp Comp) {
+	w.checkLocked()
+	id, ok := w.storage.registry.Components[comp.tp]
+	if !ok {
+		panic(fmt.Sprintf("can't unregister component type %s, as it is not registered", comp.tp.Name()))
+	}
+	w.storage.unregisterComponent(ID{id: id})
+}
@@ -51,3 +51,17 @@ func ExampleWorld_Shrink_limit() {
 	}
 	// Output:
 }
+
+func ExampleWorld_Clone() {
+	// Create a world
+	world := ecs.NewWorld()
+
+	// ... set up and warm up the world
+
+	// Create independent copies, e.g. for running an ensemble of simulations
+	for range 4 {
+		clone := world.Clone()
+		_ = clone
+	}
+	// Output:
+}
@@ -1,7 +1,9 @@
 package ecs
 
 import (
+	"fmt"
 	"reflect"
+	"unsafe"
 )
 
 // batchTable is a helper struct for collecting tables for batch processing.
@@ -18,7 +20,11 @@ func (w *World) newEntity(ids []ID, relations []relationID) (Entity, *bitMask) {
 	w.checkLocked()
 	s := &w.storage
 	mask := bitMask{}
-	newTable, newArch := s.findOrCreateTableAdd(&s.tables[0], ids, relations, &mask)
+	tableRelations, multiRelations := s.splitRelations(relations)
+	newTable, newArch := s.findOrCreateTableAdd(&s.tables[0], ids, tableRelations, &mask)
+	if multiRelations != nil {
+		s.checkMultiTargets(&mask, multiRelations)
+	}
 
 	entity := s.entityPool.Get()
 	idx := s.tables[newTable.id].Add(entity)
@@ -28,20 +34,49 @@ func (w *World) newEntity(ids []ID, relations []relationID) (Entity, *bitMask) {
 	} else {
 		s.entities[entity.id] = entityIndex{table: newTable.id, row: idx}
 	}
+	s.tables[newTable.id].SetAdded(idx, 1, nil, s.tick)
 
+	if multiRelations != nil {
+		s.setMultiTargets(entity, multiRelations)
+	}
 	w.storage.registerTargets(relations)
 
+	if s.involvesSymmetric(nil, relations) {
+		w.mirrorSymmetric(entity, nil)
+	}
+
 	return entity, &newArch.mask
 }
 
 // newEntities creates multiple new entities.
 // Returns the table containing the entities, and their start index in the table.
 func (w *World) newEntities(count int, ids []ID, relations []relationID) (tableID, int) {
+	hasSymmetric := w.storage.involvesSymmetric(nil, relations)
+	if hasSymmetric && count > 1 && w.storage.hasExclusiveSymmetric(relations) {
+		panic("can't create multiple entities with the same target of an exclusive symmetric relation")
+	}
 	mask := bitMask{}
-	newTable, _ := w.storage.findOrCreateTableAdd(&w.storage.tables[0], ids, relations, &mask)
+	tableRelations, multiRelations := w.storage.splitRelations(relations)
+	newTable, _ := w.storage.findOrCreateTableAdd(&w.storage.tables[0], ids, tableRelations, &mask)
+	if multiRelations != nil {
+		w.storage.checkMultiTargets(&mask, multiRelations)
+	}
 	startIdx := newTable.Len()
 	w.storage.createEntities(newTable, count)
+	if multiRelations != nil {
+		for i := range count {
+			w.storage.setMultiTargets(newTable.GetEntity(uintptr(startIdx+i)), multiRelations)
+		}
+	}
 	w.storage.registerTargets(relations)
+
+	if hasSymmetric {
+		entities := make([]symmetricEntity, count)
+		for i := range entities {
+			entities[i].entity = newTable.GetEntity(uintptr(startIdx + i))
+		}
+		w.mirrorSymmetricAll(entities)
+	}
 	return newTable.id, startIdx
 }
 
@@ -56,13 +91,22 @@ func (w *World) add(entity Entity, add []ID, relations []relationID) (*bitMask,
 	if len(add) == 0 {
 		panic("at least one component required to add")
 	}
+	var oldSymmetric []relationID
+	hasSymmetric := w.storage.involvesSymmetric(nil, relations)
+	if hasSymmetric {
+		oldSymmetric = w.storage.symmetricRelations(entity, nil)
+	}
 
 	index := w.storage.entities[entity.id]
 	oldTable := &w.storage.tables[index.table]
 	oldArchetype := &w.storage.archetypes[oldTable.archetype]
 
 	mask := oldArchetype.mask
-	newTable, newArch := w.storage.findOrCreateTableAdd(oldTable, add, relations, &mask)
+	tableRelations, multiRelations := w.storage.splitRelations(relations)
+	newTable, newArch := w.storage.findOrCreateTableAdd(oldTable, add, tableRelations, &mask)
+	if multiRelations != nil {
+		w.storage.checkMultiTargets(&mask, multiRelations)
+	}
 	newIndex := newTable.Add(entity)
 
 	// Get the old table and archetype again, as the pointer may have changed.
@@ -74,6 +118,7 @@ func (w *World) add(entity Entity, add []ID, relations []relationID) (*bitMask,
 			newTable.Set(id, newIndex, oldTable.Column(id), index.row)
 		}
 	}
+	newTable.SetAdded(newIndex, 1, &oldArchetype.mask, w.storage.tick)
 
 	swapped := oldTable.Remove(index.row)
 
@@ -83,8 +128,15 @@ func (w *World) add(entity Entity, add []ID, relations []relationID) (*bitMask,
 	}
 	w.storage.entities[entity.id] = entityIndex{table: newTable.id, row: newIndex}
 
+	if multiRelations != nil {
+		w.storage.setMultiTargets(entity, multiRelations)
+	}
 	w.storage.registerTargets(relations)
 
+	if hasSymmetric {
+		w.mirrorSymmetric(entity, oldSymmetric)
+	}
+
 	return &oldArchetype.mask, &newArch.mask
 }
 
@@ -98,45 +150,17 @@ func (w *World) remove(entity Entity, rem []ID) {
 	if len(rem) == 0 {
 		panic("at least one component required to remove")
 	}
-
-	index := w.storage.entities[entity.id]
-	oldTable := &w.storage.tables[index.table]
-	oldArchetype := &w.storage.archetypes[oldTable.archetype]
-
-	mask := oldArchetype.mask
-	newTable, _, relRemoved := w.storage.findOrCreateTableRemove(oldTable, rem, &mask)
-	newIndex := newTable.Add(entity)
-
-	// Get the old table and archetype again, as the pointer may have changed.
-	oldTable = &w.storage.tables[oldTable.id]
-	oldArchetype = &w.storage.archetypes[oldTable.archetype]
-
-	hasCompObs := w.storage.observers.HasObservers(OnRemoveComponents)
-	hasRelObs := relRemoved && w.storage.observers.HasObservers(OnRemoveRelations)
-	if hasCompObs || hasRelObs {
-		l := w.lock()
-		if hasCompObs {
-			w.storage.observers.FireRemove(OnRemoveComponents, entity, &oldArchetype.mask, &mask)
-		}
-		if hasRelObs {
-			w.storage.observers.FireRemove(OnRemoveRelations, entity, &oldArchetype.mask, &mask)
-		}
-		w.unlock(l)
-	}
-
-	for _, id := range oldArchetype.components {
-		if mask.Get(id.id) {
-			newTable.Set(id, newIndex, oldTable.Column(id), index.row)
-		}
+	var oldSymmetric []relationID
+	hasSymmetric := w.storage.involvesSymmetric(rem, nil)
+	if hasSymmetric {
+		oldSymmetric = w.storage.symmetricRelations(entity, nil)
 	}
 
-	swapped := oldTable.Remove(index.row)
+	w.storage.removeComponents(entity, rem)
 
-	if swapped {
-		swapEntity := oldTable.GetEntity(uintptr(index.row))
-		w.storage.entities[swapEntity.id].row = index.row
+	if hasSymmetric {
+		w.mirrorSymmetric(entity, oldSymmetric)
 	}
-	w.storage.entities[entity.id] = entityIndex{table: newTable.id, row: newIndex}
 }
 
 // remove components on an entity.
@@ -150,13 +174,22 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relations []relation
 	if len(add) == 0 && len(rem) == 0 {
 		panic("at least one component required to add or remove")
 	}
+	var oldSymmetric []relationID
+	hasSymmetric := w.storage.involvesSymmetric(rem, relations)
+	if hasSymmetric {
+		oldSymmetric = w.storage.symmetricRelations(entity, nil)
+	}
 
 	index := w.storage.entities[entity.id]
 	oldTable := &w.storage.tables[index.table]
 	oldArchetype := &w.storage.archetypes[oldTable.archetype]
 
 	mask := oldArchetype.mask
-	newTable, newArch, relRemoved := w.storage.findOrCreateTable(oldTable, add, rem, relations, &mask)
+	tableRelations, multiRelations := w.storage.splitRelations(relations)
+	newTable, newArch, relRemoved := w.storage.findOrCreateTable(oldTable, add, rem, tableRelations, &mask)
+	if multiRelations != nil {
+		w.storage.checkMultiTargets(&mask, multiRelations)
+	}
 	newIndex := newTable.Add(entity)
 
 	// Get the old table and archetype again, as the pointer may have changed.
@@ -183,6 +216,7 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relations []relation
 			newTable.Set(id, newIndex, oldTable.Column(id), index.row)
 		}
 	}
+	newTable.SetAdded(newIndex, 1, &oldArchetype.mask, w.storage.tick)
 
 	swapped := oldTable.Remove(index.row)
 
@@ -192,8 +226,18 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relations []relation
 	}
 	w.storage.entities[entity.id] = entityIndex{table: newTable.id, row: newIndex}
 
+	if oldTable.hasMultiRelations && len(rem) > 0 {
+		w.storage.removeMultiTargets(entity, rem)
+	}
+	if multiRelations != nil {
+		w.storage.setMultiTargets(entity, multiRelations)
+	}
 	w.storage.registerTargets(relations)
 
+	if hasSymmetric {
+		w.mirrorSymmetric(entity, oldSymmetric)
+	}
+
 	return &oldArchetype.mask, &newArch.mask
 }
 
@@ -207,9 +251,14 @@ func (w *World) exchangeBatch(batch *Batch, add []ID, rem []ID,
 	if len(add) == 0 && len(rem) == 0 {
 		panic("at least one component required to add or remove")
 	}
+	var symmetric []symmetricEntity
+	if w.storage.involvesSymmetric(rem, relations) {
+		symmetric = w.collectSymmetric(batch)
+	}
 	lock := w.lock()
 
 	relRemoved := false
+	tableRelations, multiRelations := w.storage.splitRelations(relations)
 	tables := w.storage.getBatchTables(batch)
 	batchTables := w.storage.slices.batches
 	for _, tableID := range tables {
@@ -220,7 +269,10 @@ func (w *World) exchangeBatch(batch *Batch, add []ID, rem []ID,
 		}
 		oldArchetype := &w.storage.archetypes[table.archetype]
 		mask := oldArchetype.mask
-		newTable, _, relRemovedTable := w.storage.findOrCreateTable(table, add, rem, relations, &mask)
+		newTable, _, relRemovedTable := w.storage.findOrCreateTable(table, add, rem, tableRelations, &mask)
+		if multiRelations != nil {
+			w.storage.checkMultiTargets(&mask, multiRelations)
+		}
 		if relRemovedTable {
 			relRemoved = true
 		}
@@ -254,7 +306,21 @@ func (w *World) exchangeBatch(batch *Batch, add []ID, rem []ID,
 	for i := range batchTables {
 		batch := &batchTables[i]
 
+		oldTable := &w.storage.tables[batch.oldTable]
+		removesMulti := oldTable.hasMultiRelations && w.storage.removesMultiRelation(oldTable, rem)
 		start, len := w.exchangeTable(batch.oldTable, batch.newTable, relations)
+		if removesMulti || multiRelations != nil {
+			table := &w.storage.tables[batch.newTable]
+			for i := start; i < start+len; i++ {
+				entity := table.GetEntity(uintptr(i))
+				if removesMulti {
+					w.storage.removeMultiTargets(entity, rem)
+				}
+				if multiRelations != nil {
+					w.storage.setMultiTargets(entity, multiRelations)
+				}
+			}
+		}
 		if fn != nil {
 			fn(batch.newTable, start, len)
 		}
@@ -284,45 +350,15 @@ func (w *World) exchangeBatch(batch *Batch, add []ID, rem []ID,
 	}
 	w.storage.slices.batches = batchTables[:0]
 	w.unlock(lock)
+
+	w.mirrorSymmetricAll(symmetric)
 }
 
 // exchangeTable performs batch-exchange on a single table.
 // Returns the start index of the entities in the new table and number of entities.
 func (w *World) exchangeTable(oldTableID, newTableID tableID, relations []relationID) (uint32, uint32) {
-	oldTable := &w.storage.tables[oldTableID]
-
-	oldArchetype := &w.storage.archetypes[oldTable.archetype]
-	oldIDs := oldArchetype.components
-
-	newTable := &w.storage.tables[newTableID]
-	newArchetype := &w.storage.archetypes[newTable.archetype]
-
-	mask := &newArchetype.mask
-
-	startIdx := uint32(newTable.Len())
-	count := oldTable.len
-
-	var i uint32
-	for i = range count {
-		idx := startIdx + i
-		entity := oldTable.GetEntity(uintptr(i))
-		index := &w.storage.entities[entity.id]
-		index.table = newTable.id
-		index.row = idx
-	}
-
-	newTable.AddAllEntities(oldTable, count)
-	for _, id := range oldIDs {
-		if mask.Get(id.id) {
-			oldCol := oldTable.Column(id)
-			newCol := newTable.Column(id)
-			newCol.CopyToEnd(oldCol, newTable.len, count)
-		}
-	}
-
-	oldTable.Reset()
+	startIdx, count := w.storage.moveTable(oldTableID, newTableID)
 	w.storage.registerTargets(relations)
-
 	return startIdx, count
 }
 
@@ -338,6 +374,12 @@ func (w *World) setRelations(entity Entity, relations []relationID) {
 	}
 	hasObserver := w.storage.observers.HasObservers(OnAddRelations) || w.storage.observers.HasObservers(OnRemoveRelations)
 
+	var oldSymmetric []relationID
+	hasSymmetric := w.storage.involvesSymmetric(nil, relations)
+	if hasSymmetric {
+		oldSymmetric = w.storage.symmetricRelations(entity, nil)
+	}
+
 	index := &w.storage.entities[entity.id]
 	oldTable := &w.storage.tables[index.table]
 
@@ -346,17 +388,31 @@ func (w *World) setRelations(entity Entity, relations []relationID) {
 	if hasObserver {
 		maskPointer = &changeMask
 	}
-	newRelations, changed := w.storage.getExchangeTargets(oldTable, relations, maskPointer)
-	if !changed {
+	tableRelations, multiRelations := w.storage.splitRelations(relations)
+	var newRelations []relationID
+	changed := false
+	if len(tableRelations) > 0 {
+		newRelations, changed = w.storage.getExchangeTargets(oldTable, tableRelations, maskPointer)
+	}
+	multiChanged := false
+	if multiRelations != nil {
+		w.storage.checkMultiTargets(&w.storage.archetypes[oldTable.archetype].mask, multiRelations)
+		multiChanged = w.storage.changesMultiTargets(entity, multiRelations, maskPointer)
+	}
+	if !changed && !multiChanged {
 		return
 	}
 
-	oldArch := &w.storage.archetypes[oldTable.archetype]
-	newTable, ok := oldArch.GetTable(&w.storage, newRelations)
-	if !ok {
-		newTable = w.storage.createTable(oldArch, newRelations)
-		// Get the old table again, as pointers may have changed.
-		oldTable = &w.storage.tables[oldTable.id]
+	newTable := oldTable
+	if changed {
+		oldArch := &w.storage.archetypes[oldTable.archetype]
+		var ok bool
+		newTable, ok = oldArch.GetTable(&w.storage, newRelations)
+		if !ok {
+			newTable = w.storage.createTable(oldArch, newRelations)
+			// Get the old table again, as pointers may have changed.
+			oldTable = &w.storage.tables[oldTable.id]
+		}
 	}
 
 	if w.storage.observers.HasObservers(OnRemoveRelations) {
@@ -366,17 +422,22 @@ func (w *World) setRelations(entity Entity, relations []relationID) {
 		w.unlock(lock)
 	}
 
-	newIndex := newTable.Add(entity)
+	if changed {
+		newIndex := newTable.Add(entity)
 
-	newTable.CopyAll(oldTable, newIndex, index.row)
+		newTable.CopyAll(oldTable, newIndex, index.row)
 
-	swapped := oldTable.Remove(index.row)
+		swapped := oldTable.Remove(index.row)
 
-	if swapped {
-		swapEntity := oldTable.GetEntity(uintptr(index.row))
-		w.storage.entities[swapEntity.id].row = index.row
+		if swapped {
+			swapEntity := oldTable.GetEntity(uintptr(index.row))
+			w.storage.entities[swapEntity.id].row = index.row
+		}
+		w.storage.entities[entity.id] = entityIndex{table: newTable.id, row: newIndex}
+	}
+	if multiChanged {
+		w.storage.setMultiTargets(entity, multiRelations)
 	}
-	w.storage.entities[entity.id] = entityIndex{table: newTable.id, row: newIndex}
 
 	w.storage.registerTargets(relations)
 
@@ -384,6 +445,10 @@ func (w *World) setRelations(entity Entity, relations []relationID) {
 		newMask := &w.storage.archetypes[newTable.archetype].mask
 		w.storage.observers.FireSetRelations(OnAddRelations, entity, &changeMask, newMask)
 	}
+
+	if hasSymmetric {
+		w.mirrorSymmetric(entity, oldSymmetric)
+	}
 }
 
 // setRelationsBatch batch-changes entity relations.
@@ -393,6 +458,10 @@ func (w *World) setRelationsBatch(batch *Batch, relations []relationID, fn func(
 	if len(relations) == 0 {
 		panic("no relations specified")
 	}
+	var symmetric []symmetricEntity
+	if w.storage.involvesSymmetric(nil, relations) {
+		symmetric = w.collectSymmetric(batch)
+	}
 	lock := w.lock()
 	hasObserver := w.storage.observers.HasObservers(OnAddRelations) || w.storage.observers.HasObservers(OnRemoveRelations)
 
@@ -420,6 +489,8 @@ func (w *World) setRelationsBatch(batch *Batch, relations []relationID, fn func(
 	w.storage.registerTargets(relations)
 
 	w.unlock(lock)
+
+	w.mirrorSymmetricAll(symmetric)
 }
 
 // setRelationsTable batch-changes entity relations for a single table.
@@ -429,18 +500,39 @@ func (w *World) setRelationsTable(oldTable *table, oldLen int, relations []relat
 	if hasObserver {
 		maskPointer = &changeMask
 	}
-	newRelations, changed := w.storage.getExchangeTargets(oldTable, relations, maskPointer)
+	tableRelations, multiRelations := w.storage.splitRelations(relations)
+	var newRelations []relationID
+	changed := false
+	if len(tableRelations) > 0 {
+		newRelations, changed = w.storage.getExchangeTargets(oldTable, tableRelations, maskPointer)
+	}
+	multiChanged := false
+	if multiRelations != nil {
+		w.storage.checkMultiTargets(&w.storage.archetypes[oldTable.archetype].mask, multiRelations)
+		for i := range uintptr(oldLen) {
+			if w.storage.changesMultiTargets(oldTable.GetEntity(i), multiRelations, maskPointer) {
+				multiChanged = true
+				if maskPointer == nil {
+					break
+				}
+			}
+		}
+	}
 
-	if !changed {
+	if !changed && !multiChanged {
 		return
 	}
 
-	oldArch := &w.storage.archetypes[oldTable.archetype]
-	newTable, ok := oldArch.GetTable(&w.storage, newRelations)
-	if !ok {
-		newTable = w.storage.createTable(oldArch, newRelations)
-		// Get the old table again, as pointers may have changed.
-		oldTable = &w.storage.tables[oldTable.id]
+	newTable := oldTable
+	if changed {
+		oldArch := &w.storage.archetypes[oldTable.archetype]
+		var ok bool
+		newTable, ok = oldArch.GetTable(&w.storage, newRelations)
+		if !ok {
+			newTable = w.storage.createTable(oldArch, newRelations)
+			// Get the old table again, as pointers may have changed.
+			oldTable = &w.storage.tables[oldTable.id]
+		}
 	}
 
 	// TODO: move this before the entire batch?
@@ -449,8 +541,16 @@ func (w *World) setRelationsTable(oldTable *table, oldLen int, relations []relat
 		w.storage.observers.FireSetRelationsBatch(OnRemoveRelations, oldTable, 0, int(oldTable.len), &changeMask, newMask)
 	}
 
-	startIdx := newTable.Len()
-	w.storage.moveEntities(oldTable, newTable, uint32(oldLen))
+	startIdx := 0
+	if changed {
+		startIdx = newTable.Len()
+		w.storage.moveEntities(oldTable, newTable, uint32(oldLen))
+	}
+	if multiChanged {
+		for i := range oldLen {
+			w.storage.setMultiTargets(newTable.GetEntity(uintptr(startIdx+i)), multiRelations)
+		}
+	}
 
 	if fn != nil {
 		fn(newTable.id, startIdx, oldLen)
@@ -469,9 +569,13 @@ func (w *World) componentID(tp reflect.Type) ID {
 	id, newID := w.storage.registry.ComponentID(tp)
 	if newID {
 		if w.IsLocked() {
-			w.storage.registry.unregisterLastComponent()
+			w.storage.registry.unregisterComponent(id)
 			panic("attempt to register a new component in a locked world")
 		}
+		if w.storage.registry.frozen {
+			w.storage.registry.unregisterComponent(id)
+			panic(fmt.Sprintf("can't register component type %s, as the component registry is frozen", tp.Name()))
+		}
 		w.storage.AddComponent(id)
 	}
 	return ID{id: id}
@@ -522,15 +626,16 @@ func (w *World) checkLocked() {
 }
 
 // emitEvent distributes an event to the [observerManager].
-func (w *World) emitEvent(e *Event, entity Entity) {
+// The payload is nil for events without payload.
+func (w *World) emitEvent(e *Event, entity Entity, payload unsafe.Pointer) {
 	if !w.storage.observers.HasObservers(e.eventType) {
 		return
 	}
-	w.emitEventSlowPath(e, entity)
+	w.emitEventSlowPath(e, entity, payload)
 }
 
 // emitEventSlowPath is the slow path of emitEvent if there are observers for the event type.
-func (w *World) emitEventSlowPath(e *Event, entity Entity) {
+func (w *World) emitEventSlowPath(e *Event, entity Entity, payload unsafe.Pointer) {
 	var mask *bitMask
 	if entity.IsZero() {
 		if !e.mask.IsZero() {
@@ -547,5 +652,6 @@ func (w *World) emitEventSlowPath(e *Event, entity Entity) {
 	if !mask.Contains(&e.mask) {
 		panic("entity does not have the required event components")
 	}
-	w.storage.observers.FireCustom(e.eventType, entity, &e.mask, mask)
+	w.storage.observers.FireCustom(e.eventType, entity, &e.mask, mask, payload)
+	w.flushEvents()
 }
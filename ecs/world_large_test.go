@@ -0,0 +1,62 @@
+//go:build ark_large && !ark_tiny
+
+package ecs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorldLargeComponents(t *testing.T) {
+	w := NewWorld(16)
+
+	ids := make([]ID, 0, 600)
+	for i := range 600 {
+		tp := reflect.ArrayOf(i+1, reflect.TypeFor[uint8]())
+		ids = append(ids, TypeID(w, tp))
+	}
+	expectEqual(t, idIndex(599), ids[599].id)
+
+	u := w.Unsafe()
+	e1 := u.NewEntity(ids[0], ids[300], ids[599])
+	e2 := u.NewEntity(ids[300], ids[400])
+	e3 := u.NewEntity(ids[0])
+
+	expectTrue(t, u.Has(e1, ids[599]))
+	expectFalse(t, u.Has(e2, ids[599]))
+
+	filter := NewUnsafeFilter(w, ids[300])
+	query := filter.Query()
+	expectEqual(t, 2, query.Count())
+	query.Close()
+
+	u.Add(e3, ids[599])
+	u.Remove(e1, ids[300])
+
+	filter = NewUnsafeFilter(w, ids[599])
+	query = filter.Query()
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Entity())
+	}
+	expectEqual(t, 2, len(found))
+	expectTrue(t, containsEntity(found, e1))
+	expectTrue(t, containsEntity(found, e3))
+
+	expectSlicesEqual(t, []ID{ids[0], ids[599]}, u.IDs(e1).data)
+
+	stats := w.Stats()
+	expectEqual(t, 600, len(stats.ComponentTypes))
+}
+
+func TestWorldLargeMaxComponents(t *testing.T) {
+	w := NewWorld(16)
+
+	for i := range maskTotalBits {
+		TypeID(w, reflect.ArrayOf(i+1, reflect.TypeFor[uint8]()))
+	}
+	expectPanicsWithValue(t, "exceeded the maximum of 1024 component types or resource types",
+		func() {
+			TypeID(w, reflect.ArrayOf(maskTotalBits+1, reflect.TypeFor[uint8]()))
+		})
+}
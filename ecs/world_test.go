@@ -494,11 +494,11 @@ func TestWorldReset(t *testing.T) {
 
 	obs := world.storage.observers
 	expectEqual(t, maxObserverID, o.id)
-	expectTrue(t, obs.allComps[OnCreateEntity].IsZero())
-	expectTrue(t, obs.allWith[OnCreateEntity].IsZero())
-	expectFalse(t, obs.anyNoComps[OnCreateEntity])
-	expectFalse(t, obs.anyNoWith[OnCreateEntity])
-	expectEqual(t, 0, len(obs.observers[OnCreateEntity]))
+	expectTrue(t, obs.allComps[OnCreateEntity.index()].IsZero())
+	expectTrue(t, obs.allWith[OnCreateEntity.index()].IsZero())
+	expectFalse(t, obs.anyNoComps[OnCreateEntity.index()])
+	expectFalse(t, obs.anyNoWith[OnCreateEntity.index()])
+	expectEqual(t, 0, len(obs.observers[OnCreateEntity.index()]))
 	expectEqual(t, 0, len(obs.indices))
 
 	world.Reset()
@@ -913,3 +913,232 @@ func TestSharedComponents(t *testing.T) {
 	expectEqual(t, 200, (*builder.Get(e1)).X)
 	expectEqual(t, 200, (*builder.Get(e2)).X)
 }
+
+func TestWorldUnregisterComponent(t *testing.T) {
+	w := NewWorld(16)
+	posMap := NewMap[Position](w)
+	posID := ComponentID[Position](w)
+	velID := ComponentID[Velocity](w)
+	headID := ComponentID[Heading](w)
+
+	// Graph nodes containing the component, without archetypes.
+	mask := bitMask{}
+	w.storage.graph.Find(0, []ID{velID, headID}, nil, &mask)
+
+	velFilter := NewFilter1[Position](w).Without(C[Velocity]()).Register()
+	posFilter := NewFilter1[Position](w).Register()
+
+	e := posMap.NewEntity(&Position{X: 1})
+	numNodes := len(w.storage.graph.nodes)
+
+	w.UnregisterComponent(C[Velocity]())
+
+	expectEqual(t, maxCacheID, velFilter.filter.cache)
+	expectTrue(t, posFilter.filter.cache != maxCacheID)
+	expectEqual(t, numNodes-2, len(w.storage.graph.nodes))
+	for i := range w.storage.graph.nodes {
+		node := &w.storage.graph.nodes[i]
+		expectEqual(t, nodeID(i), node.id)
+		expectFalse(t, node.mask.Get(velID.id))
+		expectFalse(t, node.neighbors.used.Get(velID.id))
+	}
+	_, ok := ComponentInfo(w, velID)
+	expectFalse(t, ok)
+	expectSlicesEqual(t, []ID{posID, headID}, ComponentIDs(w))
+
+	// The freed ID is re-used.
+	labelID := ComponentID[Label](w)
+	expectEqual(t, velID, labelID)
+	expectSlicesEqual(t, []ID{posID, labelID, headID}, ComponentIDs(w))
+
+	labelMap := NewMap[Label](w)
+	labelMap.Add(e, &Label{})
+	expectTrue(t, labelMap.Has(e))
+	expectEqual(t, 1.0, posMap.Get(e).X)
+
+	cnt := 0
+	query := posFilter.Query()
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, 1, cnt)
+
+	expectPanicsWithValue(t, "can't unregister component type Velocity, as it is not registered",
+		func() {
+			w.UnregisterComponent(C[Velocity]())
+		})
+	expectPanicsWithValue(t, "can't unregister component with ID 1, as it is used by entities",
+		func() {
+			w.UnregisterComponent(C[Label]())
+		})
+
+	query = posFilter.Query()
+	expectPanicsWithValue(t, "cannot modify a locked world: collect entities into a slice and apply changes after query iteration has completed",
+		func() {
+			w.UnregisterComponent(C[Heading]())
+		})
+	query.Close()
+}
+
+func TestWorldUnregisterComponentUsed(t *testing.T) {
+	w := NewWorld(16)
+	posMap := NewMap[Position](w)
+	velMap := NewMap2[Position, Velocity](w)
+	childMap := NewMap2[Velocity, ChildOf](w)
+	headMap := NewMap[Heading](w)
+	velID := ComponentID[Velocity](w)
+	childID := ComponentID[ChildOf](w)
+
+	parent := posMap.NewEntity(&Position{})
+	e1 := velMap.NewEntity(&Position{X: 1}, &Velocity{X: 2})
+	e2 := childMap.NewEntity(&Velocity{}, &ChildOf{}, RelIdx(1, parent))
+	e3 := headMap.NewEntity(&Heading{H: 3})
+
+	posFilter := NewFilter1[Position](w).Register()
+	allFilter := NewFilter0(w).Register()
+
+	expectPanicsWithValue(t, "can't unregister component with ID 1, as it is used by entities",
+		func() {
+			w.UnregisterComponent(C[Velocity]())
+		})
+
+	NewMap[Velocity](w).Remove(e1)
+	w.RemoveEntity(e2)
+	numArchetypes := len(w.storage.archetypes)
+	numTables := len(w.storage.tables)
+
+	w.UnregisterComponent(C[Velocity]())
+
+	// Archetypes (Position, Velocity) and (Velocity, ChildOf) were removed.
+	expectEqual(t, numArchetypes-2, len(w.storage.archetypes))
+	expectEqual(t, 2, len(w.storage.retiredTables))
+	expectEqual(t, 0, w.storage.registry.Archetypes[velID.id])
+	expectEqual(t, 0, w.storage.registry.Archetypes[childID.id])
+	expectEqual(t, 0, len(w.storage.componentIndex[childID.id]))
+	expectEqual(t, 0, len(w.storage.relationArchetypes))
+	expectEqual(t, len(w.storage.archetypes), len(w.storage.allArchetypes))
+	for i := range w.storage.archetypes {
+		arch := &w.storage.archetypes[i]
+		expectEqual(t, archetypeID(i), arch.id)
+		expectEqual(t, arch.id, w.storage.graph.nodes[arch.node].archetype)
+		for _, tableID := range arch.tables.tables {
+			expectEqual(t, arch.id, w.storage.tables[tableID].archetype)
+		}
+	}
+	expectTrue(t, posFilter.filter.cache != maxCacheID)
+	expectEqual(t, 3, len(w.storage.getRegisteredFilter(allFilter.filter.cache).tables.tables))
+
+	expectEqual(t, 1.0, posMap.Get(e1).X)
+	expectEqual(t, 3.0, headMap.Get(e3).H)
+	expectTrue(t, w.Alive(parent))
+
+	// Retired tables are re-used.
+	labelID := ComponentID[Label](w)
+	expectEqual(t, velID, labelID)
+	labelMap := NewMap[Label](w)
+	labelMap.Add(e1, &Label{})
+	expectEqual(t, numTables, len(w.storage.tables))
+	expectEqual(t, 1, len(w.storage.retiredTables))
+	expectTrue(t, labelMap.Has(e1))
+	expectFalse(t, labelMap.Has(parent))
+	expectEqual(t, 1.0, posMap.Get(e1).X)
+
+	cnt := 0
+	query := posFilter.Query()
+	for query.Next() {
+		cnt++
+	}
+	expectEqual(t, 2, cnt)
+
+	query2 := NewFilter1[Heading](w).Query()
+	expectEqual(t, 1, query2.Count())
+	query2.Close()
+}
+
+func TestWorldUnregisterComponentFreeTables(t *testing.T) {
+	w := NewWorld(16)
+	childMap := NewMap2[Velocity, ChildOf](w)
+
+	parent := w.NewEntity()
+	e := childMap.NewEntity(&Velocity{}, &ChildOf{}, RelIdx(1, parent))
+	arch := &w.storage.archetypes[w.storage.tables[w.storage.entities[e.id].table].archetype]
+	w.RemoveEntity(e)
+	w.RemoveEntity(parent)
+
+	expectEqual(t, 1, len(arch.freeTables))
+	numTables := len(arch.tables.tables) + len(arch.freeTables)
+
+	w.UnregisterComponent(C[Velocity]())
+	expectEqual(t, numTables, len(w.storage.retiredTables))
+}
+
+func TestWorldUnregisterComponentObservers(t *testing.T) {
+	w := NewWorld(16)
+	velID := ComponentID[Velocity](w)
+	msgObs := fmt.Sprintf("can't unregister component with ID %d, as it is used by observers", velID.id)
+	msgHooks := fmt.Sprintf("can't unregister component with ID %d, as it has hooks", velID.id)
+
+	obs := Observe(OnAddComponents).For(C[Velocity]()).Do(func(e Entity) {}).Register(w)
+	expectPanicsWithValue(t, msgObs, func() { w.UnregisterComponent(C[Velocity]()) })
+	obs.Unregister(w)
+
+	obs = Observe(OnCreateEntity).With(C[Velocity]()).Do(func(e Entity) {}).Register(w)
+	expectPanicsWithValue(t, msgObs, func() { w.UnregisterComponent(C[Velocity]()) })
+	obs.Unregister(w)
+
+	obs = Observe(OnCreateEntity).Without(C[Velocity]()).Do(func(e Entity) {}).Register(w)
+	expectPanicsWithValue(t, msgObs, func() { w.UnregisterComponent(C[Velocity]()) })
+	obs.Unregister(w)
+
+	typed := Observe1[Velocity](OnSetComponents).Do(func(e Entity, v *Velocity) {}).Register(w)
+	expectPanicsWithValue(t, msgObs, func() { w.UnregisterComponent(C[Velocity]()) })
+	typed.Unregister(w)
+
+	RegisterHooks(w, Hooks[Velocity]{OnAdd: func(e Entity, v *Velocity) {}})
+	expectPanicsWithValue(t, msgHooks, func() { w.UnregisterComponent(C[Velocity]()) })
+	RegisterHooks(w, Hooks[Velocity]{})
+
+	w.UnregisterComponent(C[Velocity]())
+	_, ok := w.storage.registry.Components[C[Velocity]().tp]
+	expectFalse(t, ok)
+}
+
+func TestWorldUnregisterComponentRollback(t *testing.T) {
+	w := NewWorld(16)
+	NewMap[Position](w).NewEntity(&Position{})
+	snap := w.Snapshot()
+
+	w.storage.registry.frozen = true
+	expectPanics(t, func() { ComponentID[Velocity](w) })
+	w.storage.registry.frozen = false
+
+	query := NewFilter0(w).Query()
+	expectPanics(t, func() { ComponentID[Velocity](w) })
+	query.Close()
+
+	expectEqual(t, 0, w.storage.registry.unregistered)
+	w.Restore(snap)
+	query = NewFilter0(w).Query()
+	expectEqual(t, 1, query.Count())
+	query.Close()
+}
+
+func TestWorldUnregisterComponentLast(t *testing.T) {
+	w := NewWorld(16)
+	posID := ComponentID[Position](w)
+	velID := ComponentID[Velocity](w)
+	headID := ComponentID[Heading](w)
+
+	w.UnregisterComponent(C[Velocity]())
+	w.UnregisterComponent(C[Heading]())
+	expectEqual(t, 1, w.storage.registry.Count())
+	expectSlicesEqual(t, []ID{posID}, ComponentIDs(w))
+
+	expectEqual(t, velID, ComponentID[Label](w))
+	expectEqual(t, headID, ComponentID[Heading](w))
+	expectEqual(t, 3, len(w.storage.components))
+
+	w.UnregisterComponent(C[Heading]())
+	expectEqual(t, 2, w.storage.registry.Count())
+	expectEqual(t, 3, len(w.storage.components))
+}
@@ -0,0 +1,66 @@
+// Demonstrates how to process query tables in parallel chunks.
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Position component
+type Position struct {
+	X float64
+	Y float64
+}
+
+// Velocity component
+type Velocity struct {
+	X float64
+	Y float64
+}
+
+// Total number of entities
+const numEntities = 100_000
+
+// Number of parallel workers to use
+const numWorkers = 4
+
+func main() {
+	// Create a world
+	world := ecs.NewWorld()
+
+	// Create a builder for entities
+	builder := ecs.NewMap2[Position, Velocity](world)
+
+	// Create entities
+	builder.NewBatch(numEntities, &Position{}, &Velocity{X: 1, Y: 0})
+
+	// Create a filter
+	filter := ecs.NewFilter2[Position, Velocity](world)
+
+	// Take starting time
+	start := time.Now()
+
+	// Time loop
+	iterations := 1000
+	for range iterations {
+		// Get a fresh query from the filter
+		query := filter.Query()
+		// Process the query's tables in chunks, distributed over numWorkers goroutines.
+		// The world stays locked until all workers have finished.
+		query.ParallelTables(numWorkers, runChunk)
+	}
+
+	// Print elapsed time
+	fmt.Printf("%s per iteration with %d entities", time.Since(start)/time.Duration(iterations), numEntities)
+}
+
+// The actual iteration over a chunk of a table, executed in parallel
+func runChunk(chunk *ecs.Chunk, entities []ecs.Entity, positions []Position, velocities []Velocity) {
+	for i := range positions {
+		pos, vel := &positions[i], &velocities[i]
+		pos.X += vel.X
+		pos.Y += vel.Y
+	}
+}